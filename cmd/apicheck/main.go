@@ -0,0 +1,50 @@
+// Command apicheck compares the exported API of the module's public
+// packages with the API files under api/ and fails on any difference.
+// Run it from the module root:
+//
+//	go run ./cmd/apicheck       # check
+//	go run ./cmd/apicheck -w    # record compatible changes
+//
+// Removing or changing a stable API is refused unless -breaking is given,
+// which is reserved for a new major version.
+package main
+
+import (
+    "flag"
+    "fmt"
+    "os"
+
+    "di-example/internal/apicheck"
+)
+
+func main() {
+    root := flag.String("root", ".", "module root")
+    write := flag.Bool("w", false, "record the current API in the API files")
+    breaking := flag.Bool("breaking", false, "with -w, record incompatible changes too")
+    flag.Parse()
+
+    if *write {
+        if err := apicheck.Record(*root, *breaking); err != nil {
+            fmt.Fprintf(os.Stderr, "apicheck: %v\n", err)
+            os.Exit(1)
+        }
+        return
+    }
+
+    reports, err := apicheck.Check(*root)
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "apicheck: %v\n", err)
+        os.Exit(1)
+    }
+    failed := false
+    for _, report := range reports {
+        if !report.OK() {
+            fmt.Fprint(os.Stderr, report)
+            failed = true
+        }
+    }
+    if failed {
+        fmt.Fprintln(os.Stderr, "apicheck: run go run ./cmd/apicheck -w to record compatible changes")
+        os.Exit(1)
+    }
+}
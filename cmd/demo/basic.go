@@ -0,0 +1,130 @@
+package main
+
+import (
+    "context"
+    "encoding/json"
+    "flag"
+    "fmt"
+    "io"
+    "time"
+
+    "di-example/internal/models"
+    "di-example/internal/services"
+    "di-example/pkg/container"
+    "di-example/pkg/logger"
+    "di-example/pkg/reflection"
+)
+
+// runBasic registers the application services, injects them into a struct
+// and inspects the result
+func runBasic(args []string, out io.Writer) error {
+    flags := flag.NewFlagSet("basic", flag.ContinueOnError)
+    printSchema := flags.Bool("config-schema", false, "print the JSON schema of the application config and exit")
+    printManifest := flags.Bool("wiring-manifest", false, "print the registration manifest for CI wiring diffs and exit")
+    if err := flags.Parse(args); err != nil {
+        return err
+    }
+
+    log := logger.Get()
+    log.Info("Starting application")
+
+    // Create new DI container
+    log.Info("Initializing DI container")
+    di := container.NewContainer()
+
+    // Make sure Fatal logs and logger.Exit still stop the container
+    di.StopOnExit(5 * time.Second)
+
+    // Inversion of Control (IoC)
+    // The Container manages service lifecycle
+    // Services are registered and resolved through the container
+    log.Info("Registering services in container")
+    registrations := []struct {
+        qualifier string
+        service   interface{}
+    }{
+        {"userService", services.NewUserService()},
+        {"emailService", services.NewEmailService()},
+        {"configService", services.NewConfigService()},
+    }
+    for _, registration := range registrations {
+        if err := di.Register(registration.qualifier, registration.service); err != nil {
+            return fmt.Errorf("failed to register %s: %w", registration.qualifier, err)
+        }
+    }
+
+    // Register application configuration
+    if err := di.Register("appConfig", &models.Config{Environment: "development"}, container.AsConfig()); err != nil {
+        return fmt.Errorf("failed to register appConfig: %w", err)
+    }
+
+    // Let ops validate config files before deploying
+    if *printSchema {
+        schemas, err := di.ConfigSchemas()
+        if err != nil {
+            return fmt.Errorf("failed to generate config schema: %w", err)
+        }
+        return writeIndented(out, schemas)
+    }
+
+    // Let CI diff the wiring against the committed manifest
+    if *printManifest {
+        return writeIndented(out, di.RegistrationManifest())
+    }
+
+    // Fail fast if a di tag compiled into the binary has no registration
+    if err := di.Build(); err != nil {
+        return fmt.Errorf("failed to build container: %w", err)
+    }
+
+    // Start the container lifecycle
+    ctx := context.Background()
+    if err := di.Start(ctx); err != nil {
+        return fmt.Errorf("failed to start container: %w", err)
+    }
+    defer func() {
+        if err := di.Stop(ctx); err != nil {
+            log.Errorw("Failed to stop container", "error", err)
+        }
+    }()
+
+    // Dependency Injection
+    // Field injection through struct tags
+    // Resolution of dependencies
+    log.Info("Injecting dependencies")
+    injectable := &models.Injectable{}
+    if err := di.InjectStruct(injectable); err != nil {
+        return fmt.Errorf("failed to inject dependencies: %w", err)
+    }
+
+    // Inspect the injectable struct
+    inspector := reflection.NewInspector()
+    info, err := inspector.InspectStruct(injectable)
+    if err != nil {
+        return fmt.Errorf("failed to inspect struct: %w", err)
+    }
+    fmt.Fprintln(out, "=== Struct Inspection Results ===")
+    fmt.Fprintln(out, inspector.PrettyPrint(info))
+
+    // Test services
+    fmt.Fprintln(out, "=== Testing Injected Services ===")
+    if us, ok := injectable.UserService.(services.UserService); ok {
+        fmt.Fprintf(out, "UserService result: %s\n", us.GetUser(123))
+    }
+    if es, ok := injectable.EmailService.(services.EmailService); ok {
+        fmt.Fprintf(out, "EmailService error: %v\n", es.SendEmail("test@example.com", "Hello from DI!"))
+    }
+    if cs, ok := injectable.ConfigService.(services.ConfigService); ok {
+        fmt.Fprintf(out, "ConfigService result: %s\n", cs.GetConfig())
+    }
+
+    log.Info("Application completed successfully")
+    return nil
+}
+
+// writeIndented prints value as indented JSON
+func writeIndented(out io.Writer, value interface{}) error {
+    encoder := json.NewEncoder(out)
+    encoder.SetIndent("", "  ")
+    return encoder.Encode(value)
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+    "fmt"
+    "io"
+    "strings"
+
+    "di-example/pkg/container"
+)
+
+// greeter is the service type of the handlers group
+type greeter interface {
+    Greet(name string) string
+}
+
+type plainGreeter struct {
+    greeting string
+}
+
+func (g plainGreeter) Greet(name string) string {
+    return g.greeting + ", " + name
+}
+
+// loudGreeter is a decorator shouting the greeting
+type loudGreeter struct {
+    inner greeter
+}
+
+func (g loudGreeter) Greet(name string) string {
+    return strings.ToUpper(g.inner.Greet(name)) + "!"
+}
+
+// frontDesk reads its greeter through a Hot field updated by Swap
+type frontDesk struct {
+    Greeter container.Hot[greeter] `di:"hello"`
+}
+
+// runDecorators decorates a group, then hot swaps one of its members
+func runDecorators(args []string, out io.Writer) error {
+    di := container.NewContainer()
+    if err := di.Register("hello", greeter(plainGreeter{"hello"}), container.InGroup("handlers")); err != nil {
+        return err
+    }
+    err := di.DecorateGroup("handlers", func(qualifier string, service interface{}) (interface{}, error) {
+        return loudGreeter{inner: service.(greeter)}, nil
+    })
+    if err != nil {
+        return err
+    }
+    // Members registered later are decorated too
+    if err := di.Register("welcome", greeter(plainGreeter{"welcome"}), container.InGroup("handlers")); err != nil {
+        return err
+    }
+
+    members, err := di.ResolveGroup("handlers")
+    if err != nil {
+        return err
+    }
+    for _, member := range members {
+        fmt.Fprintln(out, member.(greeter).Greet("gopher"))
+    }
+
+    desk := &frontDesk{}
+    if err := di.InjectStruct(desk); err != nil {
+        return err
+    }
+    fmt.Fprintf(out, "before swap: %s\n", desk.Greeter.Load().Greet("gopher"))
+    if _, err := di.Swap("hello", greeter(plainGreeter{"good morning"})); err != nil {
+        return err
+    }
+    fmt.Fprintf(out, "after swap: %s\n", desk.Greeter.Load().Greet("gopher"))
+    return nil
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+    "fmt"
+    "io"
+
+    "di-example/pkg/container"
+)
+
+// runGraph registers lazily built services that resolve their own
+// dependencies, then traces the first resolution of the top service
+func runGraph(args []string, out io.Writer) error {
+    di := container.NewContainer()
+    if err := di.Register("config", map[string]string{"dsn": "postgres://demo"}); err != nil {
+        return err
+    }
+
+    // Every factory resolves what it needs on first use
+    dependencies := map[string][]string{
+        "database": {"config"},
+        "cache":    {"config"},
+        "users":    {"database", "cache"},
+        "api":      {"users", "config"},
+    }
+    for _, qualifier := range []string{"database", "cache", "users", "api"} {
+        qualifier, needs := qualifier, dependencies[qualifier]
+        err := di.RegisterFactory(qualifier, func(c *container.Container) (interface{}, error) {
+            for _, need := range needs {
+                if _, err := c.Resolve(need); err != nil {
+                    return nil, err
+                }
+            }
+            return qualifier + " instance", nil
+        }, container.DependsOn(needs...))
+        if err != nil {
+            return err
+        }
+    }
+
+    di.Trace("api")
+    if _, err := di.Resolve("api"); err != nil {
+        return err
+    }
+    report, ok := di.LastTrace("api")
+    if !ok {
+        return fmt.Errorf("no trace recorded for api")
+    }
+
+    fmt.Fprintln(out, "=== Resolution tree ===")
+    fmt.Fprint(out, report)
+    fmt.Fprintln(out, "=== Registrations ===")
+    for _, descriptor := range di.Descriptors() {
+        fmt.Fprintln(out, descriptor)
+    }
+    return nil
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+    "context"
+    "fmt"
+    "io"
+
+    "di-example/pkg/container"
+)
+
+// runLifecycle starts and stops hooks stage by stage while printing the
+// container events
+func runLifecycle(args []string, out io.Writer) error {
+    di := container.NewContainer()
+    di.OnEvent(func(event container.Event) {
+        fmt.Fprintf(out, "event: %s %s\n", event.Kind, event.Qualifier)
+    })
+
+    hooks := []struct {
+        name  string
+        stage int
+    }{
+        {"http", container.StageTransport},
+        {"database", container.StageInfrastructure},
+        {"billing", container.StageDomain},
+    }
+    for _, hook := range hooks {
+        name := hook.name
+        if err := di.Register(name, name, container.InStage(hook.stage)); err != nil {
+            return err
+        }
+        di.Append(container.Hook{
+            Name:  name,
+            Stage: hook.stage,
+            OnStart: func(ctx context.Context) error {
+                fmt.Fprintf(out, "start %s\n", name)
+                return nil
+            },
+            OnStop: func(ctx context.Context) error {
+                fmt.Fprintf(out, "stop %s\n", name)
+                return nil
+            },
+        })
+    }
+
+    ctx := context.Background()
+    if err := di.Start(ctx); err != nil {
+        return err
+    }
+    for _, phase := range di.StartupReport().Phases {
+        fmt.Fprintf(out, "phase %s done\n", phase.Name)
+    }
+    return di.Stop(ctx)
+}
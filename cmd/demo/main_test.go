@@ -0,0 +1,47 @@
+package main
+
+import (
+    "bytes"
+    "testing"
+
+    "di-example/pkg/logger"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+// Every subcommand is an acceptance test for the capability it demonstrates
+func TestCommands(t *testing.T) {
+    logger.Initialize(false)
+
+    tests := []struct {
+        command string
+        args    []string
+        want    []string
+    }{
+        {"basic", nil, []string{"UserService result: USER-123", "ConfigService result: Environment: development"}},
+        {"basic", []string{"-wiring-manifest"}, []string{`"qualifier": "appConfig"`}},
+        {"scopes", nil, []string{"commits: 1, rollbacks: 1", "ROLLBACK"}},
+        {"lifecycle", nil, []string{"start database\nstart billing\nstart http", "stop http\nstop billing\nstop database", "event: started"}},
+        {"decorators", nil, []string{"WELCOME, GOPHER!", "after swap: GOOD MORNING, GOPHER!"}},
+        {"graph", nil, []string{"api (", "    database (", "users string (singleton)"}},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.command, func(t *testing.T) {
+            var out bytes.Buffer
+            require.NoError(t, commands[tt.command].run(tt.args, &out))
+            for _, want := range tt.want {
+                assert.Contains(t, out.String(), want)
+            }
+        })
+    }
+}
+
+func TestUsageListsCommands(t *testing.T) {
+    var out bytes.Buffer
+    usage(&out)
+    for name := range commands {
+        assert.Contains(t, out.String(), name)
+    }
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+    "context"
+    "database/sql"
+    "errors"
+    "fmt"
+    "io"
+
+    "di-example/internal/modules"
+    "di-example/internal/modules/sqlfake"
+    "di-example/pkg/container"
+)
+
+// orderRepository is a scoped repository sharing its scope's transaction
+type orderRepository struct {
+    Tx *sql.Tx `di:"database.tx"`
+}
+
+func (r *orderRepository) Insert(item string) error {
+    _, err := r.Tx.Exec("INSERT INTO orders(item) VALUES (?)", item)
+    return err
+}
+
+// placeOrder is a unit of work using the scope's repository
+func placeOrder(s *container.Scope, item string) error {
+    orders, err := s.Resolve("orders")
+    if err != nil {
+        return err
+    }
+    if err := orders.(*orderRepository).Insert(item); err != nil {
+        return err
+    }
+    if item == "" {
+        return errors.New("order has no item")
+    }
+    return nil
+}
+
+// runScopes runs two units of work against the in-memory database: the
+// first commits, the second fails and rolls back
+func runScopes(args []string, out io.Writer) error {
+    di := container.NewContainer()
+    di.SetProfile(modules.TestProfile)
+    if err := di.Install(modules.All("postgres", "unused")...); err != nil {
+        return err
+    }
+    err := di.RegisterScoped("orders", func(s *container.Scope) (interface{}, error) {
+        repo := &orderRepository{}
+        return repo, s.InjectStruct(repo)
+    })
+    if err != nil {
+        return err
+    }
+
+    ctx := context.Background()
+    for _, item := range []string{"book", ""} {
+        err := modules.UnitOfWork(ctx, di, func(s *container.Scope) error {
+            return placeOrder(s, item)
+        })
+        fmt.Fprintf(out, "order %q: error %v\n", item, err)
+    }
+
+    service, err := di.Resolve(modules.RecorderQualifier)
+    if err != nil {
+        return err
+    }
+    recorder := service.(*sqlfake.Recorder)
+    fmt.Fprintf(out, "commits: %d, rollbacks: %d\n", recorder.Commits(), recorder.Rollbacks())
+    for _, statement := range recorder.Statements() {
+        fmt.Fprintf(out, "  %s\n", statement)
+    }
+    return nil
+}
@@ -0,0 +1,64 @@
+// Command digen generates container registration code for the constructors
+// of a package annotated with //di:provide. Use it from go:generate:
+//
+//	//go:generate go run di-example/cmd/digen -dir .
+//
+// With -module it instead writes a container.Module registering every
+// exported NewXxx constructor returning an interface, with qualifiers
+// derived from the constructor names (NewUserService registers
+// userService), to onboard an existing package without annotations:
+//
+//	//go:generate go run di-example/cmd/digen -module billing
+//
+// With -index it instead prints a JSON index of every package under dir,
+// mapping qualifiers to their providers and consuming di tags, for editor
+// plugins offering "go to provider" navigation:
+//
+//	go run di-example/cmd/digen -index -dir . > di-index.json
+package main
+
+import (
+    "encoding/json"
+    "flag"
+    "fmt"
+    "os"
+
+    "di-example/internal/digen"
+)
+
+func main() {
+    dir := flag.String("dir", ".", "package directory to scan")
+    output := flag.String("out", "di_gen.go", "name of the generated file inside dir")
+    printIndex := flag.Bool("index", false, "print a JSON wiring index of every package under dir instead of generating code")
+    module := flag.String("module", "", "generate a module with this name registering every NewXxx constructor returning an interface")
+    moduleOutput := flag.String("module-out", digen.ModuleFile, "name of the generated module file inside dir")
+    flag.Parse()
+
+    if *module != "" {
+        if err := digen.RunModule(*dir, *module, *moduleOutput); err != nil {
+            fmt.Fprintf(os.Stderr, "digen: %v\n", err)
+            os.Exit(1)
+        }
+        return
+    }
+
+    if *printIndex {
+        index, err := digen.BuildIndex(*dir)
+        if err != nil {
+            fmt.Fprintf(os.Stderr, "digen: %v\n", err)
+            os.Exit(1)
+        }
+        encoder := json.NewEncoder(os.Stdout)
+        encoder.SetIndent("", "  ")
+        if err := encoder.Encode(index); err != nil {
+            fmt.Fprintf(os.Stderr, "digen: %v\n", err)
+            os.Exit(1)
+        }
+        return
+    }
+
+    if err := digen.Run(*dir, *output); err != nil {
+        fmt.Fprintf(os.Stderr, "digen: %v\n", err)
+        os.Exit(1)
+    }
+}
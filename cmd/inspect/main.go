@@ -0,0 +1,29 @@
+// Command inspect prints the inspection report of every exported struct
+// type of a package, with the values of their fields and their di tag
+// usage, so teams can audit injectable structs without writing ad-hoc
+// programs:
+//
+//	go run ./cmd/inspect -dir internal/models
+//	go run ./cmd/inspect -dir internal/models -json > models.json
+//
+// The package must belong to the module the command runs in.
+package main
+
+import (
+    "flag"
+    "fmt"
+    "os"
+
+    "di-example/internal/inspectshim"
+)
+
+func main() {
+    dir := flag.String("dir", ".", "package directory to inspect")
+    jsonOutput := flag.Bool("json", false, "print a JSON report instead of text")
+    flag.Parse()
+
+    if err := inspectshim.Run(*dir, *jsonOutput, os.Stdout); err != nil {
+        fmt.Fprintf(os.Stderr, "inspect: %v\n", err)
+        os.Exit(1)
+    }
+}
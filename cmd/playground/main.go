@@ -0,0 +1,122 @@
+//go:build js && wasm
+
+// Command playground compiles the container to WebAssembly and exposes it to
+// a small browser UI (index.html) for registering services, injecting
+// structs built at runtime and viewing the resulting wiring.
+//
+// Build it with:
+//
+//	GOOS=js GOARCH=wasm go build -o cmd/playground/playground.wasm ./cmd/playground
+//	cp "$(go env GOROOT)/lib/wasm/wasm_exec.js" cmd/playground/
+//
+// and serve the cmd/playground directory with any static file server.
+package main
+
+import (
+    "fmt"
+    "reflect"
+    "sort"
+    "strings"
+    "syscall/js"
+
+    "di-example/pkg/container"
+    "di-example/pkg/logger"
+    "di-example/pkg/reflection"
+)
+
+// playground holds the state shared by the JavaScript callbacks
+type playground struct {
+    di         *container.Container
+    inspector  *reflection.Inspector
+    qualifiers []string            // Registered qualifiers in order
+    consumers  map[string][]string // Qualifier -> fields that received it
+}
+
+func main() {
+    logger.Initialize(false)
+    defer logger.Sync()
+
+    p := &playground{
+        di:        container.NewContainer(),
+        inspector: reflection.NewInspector(),
+        consumers: make(map[string][]string),
+    }
+
+    js.Global().Set("diRegister", js.FuncOf(p.register))
+    js.Global().Set("diInject", js.FuncOf(p.inject))
+    js.Global().Set("diGraph", js.FuncOf(p.graph))
+
+    // Keep the Go runtime alive so the callbacks stay valid
+    select {}
+}
+
+// register(qualifier, value) registers a string service
+func (p *playground) register(this js.Value, args []js.Value) interface{} {
+    if len(args) != 2 {
+        return "usage: diRegister(qualifier, value)"
+    }
+
+    qualifier := args[0].String()
+    if err := p.di.Register(qualifier, args[1].String()); err != nil {
+        return err.Error()
+    }
+    p.qualifiers = append(p.qualifiers, qualifier)
+    return fmt.Sprintf("registered %s", qualifier)
+}
+
+// inject(structName, "Field=qualifier,...") builds a struct type at runtime,
+// injects it and returns the inspection report
+func (p *playground) inject(this js.Value, args []js.Value) interface{} {
+    if len(args) != 2 {
+        return "usage: diInject(structName, 'Field=qualifier,...')"
+    }
+
+    var fields []reflect.StructField
+    for _, spec := range strings.Split(args[1].String(), ",") {
+        name, qualifier, ok := strings.Cut(strings.TrimSpace(spec), "=")
+        if !ok || name == "" {
+            return fmt.Sprintf("invalid field spec %q, want Field=qualifier", spec)
+        }
+        fields = append(fields, reflect.StructField{
+            Name: strings.ToUpper(name[:1]) + name[1:], // Fields must be exported to be injected
+            Type: reflect.TypeOf((*interface{})(nil)).Elem(),
+            Tag:  reflect.StructTag(fmt.Sprintf(`di:"%s"`, qualifier)),
+        })
+    }
+
+    target := reflect.New(reflect.StructOf(fields))
+    if err := p.di.InjectStruct(target.Interface()); err != nil {
+        return err.Error()
+    }
+
+    structName := args[0].String()
+    for _, field := range fields {
+        qualifier := field.Tag.Get("di")
+        if target.Elem().FieldByName(field.Name).IsNil() {
+            continue
+        }
+        p.consumers[qualifier] = append(p.consumers[qualifier], structName+"."+field.Name)
+    }
+
+    info, err := p.inspector.InspectStruct(target.Interface())
+    if err != nil {
+        return err.Error()
+    }
+    info.Name = structName // Runtime struct types are anonymous
+    return p.inspector.PrettyPrint(info)
+}
+
+// graph() renders every registered qualifier with the fields consuming it
+func (p *playground) graph(this js.Value, args []js.Value) interface{} {
+    qualifiers := append([]string(nil), p.qualifiers...)
+    sort.Strings(qualifiers)
+
+    var builder strings.Builder
+    for _, qualifier := range qualifiers {
+        builder.WriteString(qualifier + "\n")
+        for _, consumer := range p.consumers[qualifier] {
+            builder.WriteString("  <- " + consumer + "\n")
+        }
+    }
+    return builder.String()
+}
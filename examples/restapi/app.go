@@ -0,0 +1,307 @@
+package main
+
+import (
+    "context"
+    "database/sql"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "net"
+    "net/http"
+    "os"
+
+    "di-example/internal/modules"
+    "di-example/pkg/container"
+)
+
+// Qualifiers registered by the example
+const (
+    RequestIDQualifier       = "request.id"
+    OrderRepositoryQualifier = "orders.repository"
+    ServerQualifier          = "http.server"
+)
+
+// RequestIDHeader carries the ID seeded into every request scope
+const RequestIDHeader = "X-Request-ID"
+
+// Config is the application config: the defaults of its tags, overridden
+// by the -config file
+type Config struct {
+    Addr               string `json:"addr" default:"127.0.0.1:8080"`
+    DebugAddr          string `json:"debugAddr" default:"127.0.0.1:6060"`
+    MaxScopedInstances int    `json:"maxScopedInstances" default:"8"`
+}
+
+// loadConfig reads a JSON config file; fields it leaves out keep their
+// defaults. An empty path selects the defaults.
+func loadConfig(path string) (Config, error) {
+    var cfg Config
+    if path == "" {
+        return cfg, nil
+    }
+    file, err := os.Open(path)
+    if err != nil {
+        return cfg, fmt.Errorf("failed to open config: %w", err)
+    }
+    defer file.Close()
+
+    decoder := json.NewDecoder(file)
+    decoder.DisallowUnknownFields()
+    if err := decoder.Decode(&cfg); err != nil {
+        return cfg, fmt.Errorf("failed to decode config %s: %w", path, err)
+    }
+    return cfg, nil
+}
+
+// newContainer wires the application. The test profile selects the
+// in-memory database, so the example runs without a database server.
+func newContainer(cfg Config, routesFile string) (*container.Container, error) {
+    di := container.NewContainer()
+    di.SetProfile(modules.TestProfile)
+    if err := container.RegisterOptions(di, cfg, container.AsConfig()); err != nil {
+        return nil, err
+    }
+
+    installed := append(modules.All("postgres", "unused"), modules.Router(routesFile), Orders(), Server())
+    if err := di.Install(installed...); err != nil {
+        return nil, err
+    }
+    return di, nil
+}
+
+// Order is the resource served by the API
+type Order struct {
+    Item      string `json:"item"`
+    Quantity  int    `json:"quantity"`
+    RequestID string `json:"requestID,omitempty"`
+}
+
+// errInvalidOrder rejects an order, rolling back its transaction
+var errInvalidOrder = errors.New("quantity must be positive")
+
+// requestIDKey is the context key of the request ID seeded into scopes
+type requestIDKey struct{}
+
+// Orders registers the scoped order repository, the order handlers and
+// the health check. Every request scope is seeded with the request ID and
+// limited to Config.MaxScopedInstances scoped instances.
+func Orders() container.Module {
+    return container.Module{
+        Name: "orders",
+        Setup: func(c *container.Container) error {
+            cfg, err := container.Options[Config](c)
+            if err != nil {
+                return err
+            }
+            c.UseScope(
+                container.MaxScopedInstances(cfg.MaxScopedInstances),
+                container.SeedScope(RequestIDQualifier, func(s *container.Scope) (interface{}, error) {
+                    id, _ := s.Context().Value(requestIDKey{}).(string)
+                    return id, nil
+                }),
+            )
+
+            if err := c.RegisterScoped(OrderRepositoryQualifier, func(s *container.Scope) (interface{}, error) {
+                repo := &orderRepository{}
+                return repo, s.InjectStruct(repo)
+            }); err != nil {
+                return err
+            }
+            if err := c.Provide("orders.list", newListOrders); err != nil {
+                return err
+            }
+            if err := c.Provide("orders.create", newCreateOrder); err != nil {
+                return err
+            }
+            if err := c.Provide("database.check", newDatabaseCheck); err != nil {
+                return err
+            }
+            return c.Provide("health", newHealth)
+        },
+    }
+}
+
+// orderRepository stores orders in the transaction of its request scope
+type orderRepository struct {
+    Tx        *sql.Tx `di:"database.tx"`
+    RequestID string  `di:"request.id"`
+}
+
+func (r *orderRepository) List() ([]Order, error) {
+    rows, err := r.Tx.Query("SELECT item, quantity, request_id FROM orders")
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    orders := []Order{}
+    for rows.Next() {
+        var order Order
+        if err := rows.Scan(&order.Item, &order.Quantity, &order.RequestID); err != nil {
+            return nil, err
+        }
+        orders = append(orders, order)
+    }
+    return orders, rows.Err()
+}
+
+func (r *orderRepository) Insert(order *Order) error {
+    order.RequestID = r.RequestID
+    if _, err := r.Tx.Exec("INSERT INTO orders(item, quantity, request_id) VALUES (?, ?, ?)", order.Item, order.Quantity, order.RequestID); err != nil {
+        return err
+    }
+    // Checked after the insert, like a database constraint
+    if order.Quantity < 1 {
+        return errInvalidOrder
+    }
+    return nil
+}
+
+// inScope runs work with the order repository of a new request scope,
+// committing the scope's transaction only if work succeeds
+func inScope(c *container.Container, r *http.Request, work func(repo *orderRepository) error) error {
+    ctx := context.WithValue(r.Context(), requestIDKey{}, r.Header.Get(RequestIDHeader))
+    return modules.UnitOfWork(ctx, c, func(s *container.Scope) error {
+        service, err := s.Resolve(OrderRepositoryQualifier)
+        if err != nil {
+            return err
+        }
+        return work(service.(*orderRepository))
+    })
+}
+
+// newListOrders serves GET /orders
+func newListOrders(c *container.Container) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        var orders []Order
+        err := inScope(c, r, func(repo *orderRepository) (err error) {
+            orders, err = repo.List()
+            return err
+        })
+        if err != nil {
+            http.Error(w, err.Error(), http.StatusInternalServerError)
+            return
+        }
+        writeJSON(w, http.StatusOK, orders)
+    }
+}
+
+// newCreateOrder serves POST /orders
+func newCreateOrder(c *container.Container) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        var order Order
+        if err := json.NewDecoder(r.Body).Decode(&order); err != nil {
+            http.Error(w, "invalid order: "+err.Error(), http.StatusBadRequest)
+            return
+        }
+        err := inScope(c, r, func(repo *orderRepository) error {
+            return repo.Insert(&order)
+        })
+        switch {
+        case errors.Is(err, errInvalidOrder):
+            http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+        case err != nil:
+            http.Error(w, err.Error(), http.StatusInternalServerError)
+        default:
+            writeJSON(w, http.StatusCreated, order)
+        }
+    }
+}
+
+// databaseCheck is the self-test of the database
+type databaseCheck struct {
+    db *sql.DB
+}
+
+func newDatabaseCheck(db *sql.DB) *databaseCheck {
+    return &databaseCheck{db: db}
+}
+
+func (d *databaseCheck) SelfTest(ctx context.Context) error {
+    return d.db.PingContext(ctx)
+}
+
+// newHealth serves GET /healthz from the self-tests of all services
+func newHealth(c *container.Container) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        if err := c.SelfTest(r.Context()).Err(); err != nil {
+            http.Error(w, err.Error(), http.StatusServiceUnavailable)
+            return
+        }
+        fmt.Fprintln(w, "ok")
+    }
+}
+
+// writeJSON writes value with status
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+    w.Header().Set("Content-Type", "application/json")
+    w.WriteHeader(status)
+    _ = json.NewEncoder(w).Encode(value)
+}
+
+// Server registers the HTTP server under ServerQualifier. It serves the
+// router on Config.Addr and the container's debug handler on
+// Config.DebugAddr from the time the container starts until it stops.
+func Server() container.Module {
+    return container.Module{
+        Name: "http",
+        Setup: func(c *container.Container) error {
+            return c.Provide(ServerQualifier, newServer, container.InStage(container.StageTransport))
+        },
+    }
+}
+
+// serverDeps are the dependencies of the server, filled by their di tags
+type serverDeps struct {
+    Options Config         `di:"options"`
+    Router  *http.ServeMux `di:"http.router"`
+}
+
+// HTTPServer serves the API and debug listeners
+type HTTPServer struct {
+    api       *http.Server
+    debug     *http.Server
+    cfg       Config
+    apiAddr   net.Addr
+    debugAddr net.Addr
+}
+
+func newServer(c *container.Container, deps serverDeps) *HTTPServer {
+    return &HTTPServer{
+        api:   &http.Server{Handler: deps.Router},
+        debug: &http.Server{Handler: c.DebugHandler()},
+        cfg:   deps.Options,
+    }
+}
+
+// OnStart listens on both addresses and serves in the background
+func (s *HTTPServer) OnStart(ctx context.Context) error {
+    api, err := net.Listen("tcp", s.cfg.Addr)
+    if err != nil {
+        return fmt.Errorf("failed to listen on %s: %w", s.cfg.Addr, err)
+    }
+    debug, err := net.Listen("tcp", s.cfg.DebugAddr)
+    if err != nil {
+        api.Close()
+        return fmt.Errorf("failed to listen on %s: %w", s.cfg.DebugAddr, err)
+    }
+    s.apiAddr, s.debugAddr = api.Addr(), debug.Addr()
+    go s.api.Serve(api)
+    go s.debug.Serve(debug)
+    return nil
+}
+
+// OnStop stops accepting requests and waits for those in flight
+func (s *HTTPServer) OnStop(ctx context.Context) error {
+    return errors.Join(s.api.Shutdown(ctx), s.debug.Shutdown(ctx))
+}
+
+// Addr returns the address of the API listener once started
+func (s *HTTPServer) Addr() net.Addr {
+    return s.apiAddr
+}
+
+// DebugAddr returns the address of the debug listener once started
+func (s *HTTPServer) DebugAddr() net.Addr {
+    return s.debugAddr
+}
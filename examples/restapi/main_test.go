@@ -0,0 +1,131 @@
+package main
+
+import (
+    "context"
+    "encoding/json"
+    "io"
+    "net/http"
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+
+    "di-example/internal/modules"
+    "di-example/internal/modules/sqlfake"
+    "di-example/pkg/container"
+    "di-example/pkg/logger"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+// startAPI starts the application on free ports and stops it after the test
+func startAPI(t *testing.T, cfg Config) (*container.Container, *HTTPServer) {
+    logger.Initialize(false)
+    cfg.Addr, cfg.DebugAddr = "127.0.0.1:0", "127.0.0.1:0"
+
+    di, err := newContainer(cfg, "routes.txt")
+    require.NoError(t, err)
+    require.NoError(t, di.Start(context.Background()))
+    t.Cleanup(func() {
+        assert.NoError(t, di.Stop(context.Background()))
+    })
+
+    server, err := container.ResolveAs[*HTTPServer](di, ServerQualifier)
+    require.NoError(t, err)
+    return di, server
+}
+
+// call sends a request and returns the status and body of the response
+func call(t *testing.T, method, url, body string) (int, string) {
+    request, err := http.NewRequest(method, url, strings.NewReader(body))
+    require.NoError(t, err)
+    request.Header.Set(RequestIDHeader, "req-1")
+    response, err := http.DefaultClient.Do(request)
+    require.NoError(t, err)
+    defer response.Body.Close()
+    data, err := io.ReadAll(response.Body)
+    require.NoError(t, err)
+    return response.StatusCode, string(data)
+}
+
+func TestRESTAPI(t *testing.T) {
+    di, server := startAPI(t, Config{})
+    api := "http://" + server.Addr().String()
+
+    status, body := call(t, http.MethodPost, api+"/orders", `{"item":"book","quantity":2}`)
+    assert.Equal(t, http.StatusCreated, status)
+    var order Order
+    require.NoError(t, json.Unmarshal([]byte(body), &order))
+    assert.Equal(t, Order{Item: "book", Quantity: 2, RequestID: "req-1"}, order)
+
+    status, body = call(t, http.MethodPost, api+"/orders", `{"item":"book","quantity":0}`)
+    assert.Equal(t, http.StatusUnprocessableEntity, status)
+    assert.Contains(t, body, "quantity must be positive")
+
+    status, _ = call(t, http.MethodPost, api+"/orders", `not json`)
+    assert.Equal(t, http.StatusBadRequest, status)
+
+    status, body = call(t, http.MethodGet, api+"/orders", "")
+    assert.Equal(t, http.StatusOK, status)
+    assert.JSONEq(t, `[]`, body)
+
+    status, body = call(t, http.MethodGet, api+"/healthz", "")
+    assert.Equal(t, http.StatusOK, status)
+    assert.Equal(t, "ok\n", body)
+
+    // One transaction per request scope: committed, rolled back, committed
+    recorder, err := container.ResolveAs[*sqlfake.Recorder](di, modules.RecorderQualifier)
+    require.NoError(t, err)
+    assert.Equal(t, 2, recorder.Commits())
+    assert.Equal(t, 1, recorder.Rollbacks())
+}
+
+func TestRESTAPI_DebugEndpoint(t *testing.T) {
+    _, server := startAPI(t, Config{MaxScopedInstances: 4})
+    debug := "http://" + server.DebugAddr().String()
+
+    status, body := call(t, http.MethodGet, debug+"/config/schema", "")
+    assert.Equal(t, http.StatusOK, status)
+    assert.Contains(t, body, `"maxScopedInstances"`)
+
+    status, body = call(t, http.MethodGet, debug+"/modules", "")
+    assert.Equal(t, http.StatusOK, status)
+    for _, module := range []string{"orders", "router", "http", "tx"} {
+        assert.Contains(t, body, `"`+module+`"`)
+    }
+}
+
+func TestRESTAPI_ScopeBudget(t *testing.T) {
+    // A request scope builds the repository and its transaction
+    _, server := startAPI(t, Config{MaxScopedInstances: 1})
+
+    status, body := call(t, http.MethodPost, "http://"+server.Addr().String()+"/orders", `{"item":"book","quantity":1}`)
+    assert.Equal(t, http.StatusInternalServerError, status)
+    assert.Contains(t, body, "scope budget of 1 instances exhausted")
+}
+
+func TestRESTAPI_Wiring(t *testing.T) {
+    logger.Initialize(false)
+    di, err := newContainer(Config{}, "routes.txt")
+    require.NoError(t, err)
+
+    // Fails CI on wiring problems before anything is built
+    require.NoError(t, di.Validate(serverDeps{}))
+}
+
+func TestLoadConfig(t *testing.T) {
+    cfg, err := loadConfig("")
+    require.NoError(t, err)
+    assert.Equal(t, Config{}, cfg)
+
+    path := filepath.Join(t.TempDir(), "config.json")
+    require.NoError(t, os.WriteFile(path, []byte(`{"addr": ":9090"}`), 0o644))
+    cfg, err = loadConfig(path)
+    require.NoError(t, err)
+    assert.Equal(t, Config{Addr: ":9090"}, cfg)
+
+    require.NoError(t, os.WriteFile(path, []byte(`{"port": 9090}`), 0o644))
+    _, err = loadConfig(path)
+    assert.ErrorContains(t, err, `unknown field "port"`)
+}
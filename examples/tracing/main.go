@@ -0,0 +1,62 @@
+// Command tracing demonstrates a trace ID flowing from an HTTP request
+// through container-wired services: the tracing middleware opens a scope
+// per request, and the WelcomeService, UserService and EmailService of that
+// scope log through a logger scoped to the request's trace, so every log
+// line of the request carries the same traceID field.
+package main
+
+import (
+    "fmt"
+    "net/http"
+    "net/http/httptest"
+    "strconv"
+
+    "di-example/internal/tracing"
+    "di-example/pkg/container"
+    "di-example/pkg/logger"
+)
+
+func main() {
+    logger.Initialize(true)
+    defer logger.Sync()
+    log := logger.Get()
+
+    // Wire the middleware and the request scoped services
+    di := container.NewContainer()
+    if err := di.Install(tracing.Module()); err != nil {
+        log.Fatalw("Failed to install tracing module", "error", err)
+    }
+
+    middleware, err := container.ResolveAs[tracing.Middleware](di, tracing.MiddlewareQualifier)
+    if err != nil {
+        log.Fatalw("Failed to resolve middleware", "error", err)
+    }
+
+    handler := middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        id, err := strconv.Atoi(r.URL.Query().Get("user"))
+        if err != nil {
+            http.Error(w, "invalid user id", http.StatusBadRequest)
+            return
+        }
+        welcome, err := tracing.Scope(r.Context()).Resolve(tracing.WelcomeQualifier)
+        if err != nil {
+            http.Error(w, err.Error(), http.StatusInternalServerError)
+            return
+        }
+        if err := welcome.(*tracing.WelcomeService).Welcome(id); err != nil {
+            http.Error(w, err.Error(), http.StatusInternalServerError)
+            return
+        }
+        fmt.Fprintln(w, "welcome sent")
+    }))
+
+    // Simulate an incoming request carrying a trace ID
+    request := httptest.NewRequest(http.MethodPost, "/welcome?user=42", nil)
+    request.Header.Set(tracing.HeaderName, "demo-trace-0001")
+    recorder := httptest.NewRecorder()
+    handler.ServeHTTP(recorder, request)
+
+    log.Infow("Request completed",
+        "status", recorder.Code,
+        "traceID", recorder.Header().Get(tracing.HeaderName))
+}
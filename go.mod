@@ -1,6 +1,6 @@
 module di-example
 
-go 1.22.8
+go 1.24
 
 require (
 	github.com/stretchr/testify v1.8.1
@@ -0,0 +1,623 @@
+// Package apicheck records the exported API of the module's public packages
+// and reports incompatible changes to it, in the spirit of apidiff.
+//
+// Every exported declaration is a stable API unless its doc comment has a
+// paragraph starting with "Experimental:", which also covers the methods and
+// fields of an experimental type:
+//
+//	// Scope is a child container for a unit of work.
+//	//
+//	// Experimental: scopes may change in any release.
+//	type Scope struct { ... }
+//
+// The API of each package is recorded in a file under api/ at the module
+// root, one feature per line. Removing or changing a recorded stable feature
+// is incompatible and needs a new major version; adding features and
+// changing experimental ones only needs the file updated with
+// "go run ./cmd/apicheck -w". Stable features may not mention experimental
+// types, so the stable API never depends on an experimental one.
+//
+// An alias of a type from another package, as v1 of the container declares
+// for every v2 type, records the fields and methods of the aliased type
+// under the alias name, so moving a type behind an alias is compatible.
+//
+// The check is hand-rolled instead of built on golang.org/x/exp/apidiff to
+// keep the module free of that dependency, and experimental APIs stay next
+// to the stable ones, marked by their doc comments, instead of moving to an
+// experimental subpackage: scopes, decorators and the other experimental
+// features are part of the container's engine and cannot be split out
+// without an incompatible change to the stable API.
+package apicheck
+
+import (
+    "bufio"
+    "bytes"
+    "fmt"
+    "go/ast"
+    "go/build"
+    "go/parser"
+    "go/printer"
+    "go/token"
+    "os"
+    "path/filepath"
+    "sort"
+    "strings"
+    "unicode"
+)
+
+// Level is the stability of an API feature
+type Level string
+
+const (
+    Stable       Level = "stable"       // Kept compatible within a major version
+    Experimental Level = "experimental" // May change or go away in any release
+)
+
+// experimentalMarker starts the doc comment paragraph of experimental APIs
+const experimentalMarker = "Experimental:"
+
+// Package is a public package whose API is recorded
+type Package struct {
+    Dir  string   // Directory relative to the module root
+    File string   // API file relative to the module root
+    Tags []string // Build tags the package is gated behind, if any
+}
+
+// Packages lists the public packages of the module, every package under
+// pkg/
+var Packages = []Package{
+    {Dir: "pkg/container", File: "api/container.txt"},
+    {Dir: "pkg/container/containertest", File: "api/container-containertest.txt"},
+    {Dir: "pkg/container/dobridge", File: "api/container-dobridge.txt"},
+    {Dir: "pkg/container/lite", File: "api/container-lite.txt", Tags: []string{"dilite"}},
+    {Dir: "pkg/container/otelbridge", File: "api/container-otelbridge.txt"},
+    {Dir: "pkg/container/v2", File: "api/container-v2.txt"},
+    {Dir: "pkg/logger", File: "api/logger.txt"},
+    {Dir: "pkg/reflection", File: "api/reflection.txt"},
+}
+
+// Feature is one exported declaration, e.g. "func NewContainer() *Container"
+// or "field Hook.OnStart func(context.Context) error". Parameter names are
+// left out, so renaming them is not a change.
+type Feature struct {
+    Level Level
+    Decl  string
+}
+
+func (f Feature) String() string {
+    return fmt.Sprintf("%s %s", f.Level, f.Decl)
+}
+
+// Report lists the differences between the recorded and the current API
+type Report struct {
+    Package      string
+    Incompatible []string // Stable features removed, changed or made experimental
+    Unrecorded   []string // Compatible changes missing from the API file
+    Leaks        []string // Stable features exposing experimental types
+}
+
+// OK reports whether the current API matches the recorded one
+func (r Report) OK() bool {
+    return len(r.Incompatible) == 0 && len(r.Unrecorded) == 0 && len(r.Leaks) == 0
+}
+
+func (r Report) String() string {
+    var b strings.Builder
+    for _, line := range r.Incompatible {
+        fmt.Fprintf(&b, "%s: incompatible: %s\n", r.Package, line)
+    }
+    for _, line := range r.Unrecorded {
+        fmt.Fprintf(&b, "%s: not recorded: %s\n", r.Package, line)
+    }
+    for _, line := range r.Leaks {
+        fmt.Fprintf(&b, "%s: leak: %s\n", r.Package, line)
+    }
+    return b.String()
+}
+
+// Compare reports how current differs from recorded
+func Compare(pkg string, recorded, current []Feature) Report {
+    levels := make(map[string]Level, len(current))
+    for _, f := range current {
+        levels[f.Decl] = f.Level
+    }
+    known := make(map[Feature]bool, len(recorded))
+    for _, f := range recorded {
+        known[f] = true
+    }
+
+    report := Report{Package: pkg}
+    for _, f := range recorded {
+        level, ok := levels[f.Decl]
+        switch {
+        case f.Level == Stable && !ok:
+            report.Incompatible = append(report.Incompatible, "removed or changed "+f.Decl)
+        case f.Level == Stable && level == Experimental:
+            report.Incompatible = append(report.Incompatible, "made experimental "+f.Decl)
+        case !ok:
+            report.Unrecorded = append(report.Unrecorded, "removed "+f.String())
+        }
+    }
+    for _, f := range current {
+        if !known[f] {
+            report.Unrecorded = append(report.Unrecorded, "added "+f.String())
+        }
+    }
+    report.Leaks = leaks(current)
+    return report
+}
+
+// leaks finds stable features whose declaration mentions an experimental
+// type, which would tie the stable API to it
+func leaks(features []Feature) []string {
+    experimental := make(map[string]bool)
+    for _, f := range features {
+        if f.Level == Experimental && strings.HasPrefix(f.Decl, "type ") {
+            name, _, _ := strings.Cut(strings.TrimPrefix(f.Decl, "type "), " ")
+            name, _, _ = strings.Cut(name, "[")
+            experimental[name] = true
+        }
+    }
+
+    var found []string
+    for _, f := range features {
+        if f.Level != Stable {
+            continue
+        }
+        words := strings.FieldsFunc(f.Decl, func(r rune) bool {
+            return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+        })
+        for _, word := range words {
+            if experimental[word] {
+                found = append(found, fmt.Sprintf("%s uses experimental type %s", f.Decl, word))
+                break
+            }
+        }
+    }
+    return found
+}
+
+// Extract returns the exported API of the package in dir, sorted, for the
+// default build context with tags set
+func Extract(dir string, tags ...string) ([]Feature, error) {
+    ctx := build.Default
+    ctx.BuildTags = append(append([]string(nil), ctx.BuildTags...), tags...)
+    pkg, err := ctx.ImportDir(dir, 0)
+    if err != nil {
+        return nil, fmt.Errorf("failed to load package %s: %w", dir, err)
+    }
+
+    fset := token.NewFileSet()
+    var files []*ast.File
+    for _, name := range pkg.GoFiles {
+        file, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.ParseComments)
+        if err != nil {
+            return nil, fmt.Errorf("failed to parse %s: %w", name, err)
+        }
+        files = append(files, file)
+    }
+
+    e := &extractor{fset: fset, experimental: make(map[string]bool), imports: make(map[string]string)}
+    // Types first, so methods know whether their receiver is experimental
+    for _, file := range files {
+        for _, decl := range file.Decls {
+            if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.TYPE {
+                for _, spec := range gen.Specs {
+                    spec := spec.(*ast.TypeSpec)
+                    if isExperimental(gen.Doc) || isExperimental(spec.Doc) {
+                        e.experimental[spec.Name.Name] = true
+                    }
+                }
+            }
+        }
+    }
+    for _, file := range files {
+        for _, spec := range file.Imports {
+            path := strings.Trim(spec.Path.Value, `"`)
+            name := filepath.Base(path)
+            if spec.Name != nil {
+                name = spec.Name.Name
+            }
+            e.imports[name] = path
+        }
+        for _, decl := range file.Decls {
+            e.decl(decl)
+        }
+    }
+    if err := e.expandAliases(ctx, dir, tags); err != nil {
+        return nil, err
+    }
+
+    sort.Slice(e.features, func(i, j int) bool {
+        return e.features[i].Decl < e.features[j].Decl
+    })
+    return e.features, nil
+}
+
+// extractor collects the features of one package
+type extractor struct {
+    fset         *token.FileSet
+    experimental map[string]bool   // Experimental type names
+    imports      map[string]string // Import paths by package name
+    aliases      []alias           // Aliases of types from other packages
+    features     []Feature
+}
+
+// alias is a type declared as an alias of a type of another package, e.g.
+// "type Container = v2.Container"
+type alias struct {
+    name   string
+    path   string // Import path of the aliased type's package
+    target string // Name of the aliased type
+    level  Level
+}
+
+// expandAliases adds the features of each aliased type under its alias
+// name, extracting the packages the aliases point to
+func (e *extractor) expandAliases(ctx build.Context, dir string, tags []string) error {
+    extracted := make(map[string][]Feature)
+    for _, a := range e.aliases {
+        features, ok := extracted[a.path]
+        if !ok {
+            pkg, err := ctx.Import(a.path, dir, build.FindOnly)
+            if err != nil {
+                return fmt.Errorf("failed to find package %s: %w", a.path, err)
+            }
+            if features, err = Extract(pkg.Dir, tags...); err != nil {
+                return err
+            }
+            extracted[a.path] = features
+        }
+        for _, f := range features {
+            decl, ok := renameOwner(f.Decl, a.target, a.name)
+            if !ok {
+                continue
+            }
+            level := f.Level
+            if a.level == Experimental {
+                level = Experimental
+            }
+            e.features = append(e.features, Feature{Level: level, Decl: decl})
+        }
+    }
+    return nil
+}
+
+// renameOwner returns decl with the type declaring it renamed from name to
+// alias, and false when decl is not a type, field or method of name
+func renameOwner(decl, name, alias string) (string, bool) {
+    kind, rest, _ := strings.Cut(decl, " ")
+    switch kind {
+    case "type":
+        if after, ok := strings.CutPrefix(rest, name); ok && (after == "" || after[0] == ' ' || after[0] == '[') {
+            return "type " + alias + after, true
+        }
+    case "field", "embedded", "method":
+        if after, ok := strings.CutPrefix(rest, name+"."); ok {
+            return kind + " " + alias + "." + after, true
+        }
+        for _, recv := range []string{"(", "(*"} {
+            if after, ok := strings.CutPrefix(rest, recv+name); ok && (after[0] == ')' || after[0] == '[') {
+                return kind + " " + recv + alias + after, true
+            }
+        }
+    }
+    return "", false
+}
+
+func (e *extractor) add(level Level, format string, args ...interface{}) {
+    e.features = append(e.features, Feature{Level: level, Decl: fmt.Sprintf(format, args...)})
+}
+
+func (e *extractor) decl(decl ast.Decl) {
+    switch decl := decl.(type) {
+    case *ast.FuncDecl:
+        if !decl.Name.IsExported() {
+            return
+        }
+        level := levelOf(decl.Doc)
+        if decl.Recv == nil {
+            e.add(level, "func %s%s%s", decl.Name.Name, e.typeParams(decl.Type.TypeParams), e.signature(decl.Type))
+            return
+        }
+        recv := decl.Recv.List[0].Type
+        base := receiverName(recv)
+        if !ast.IsExported(base) {
+            return
+        }
+        if e.experimental[base] {
+            level = Experimental
+        }
+        e.add(level, "method (%s) %s%s", e.print(recv), decl.Name.Name, e.signature(decl.Type))
+
+    case *ast.GenDecl:
+        var implicit ast.Expr // Type repeated by constants without values, e.g. after iota
+        for _, spec := range decl.Specs {
+            switch spec := spec.(type) {
+            case *ast.TypeSpec:
+                e.typeSpec(spec)
+            case *ast.ValueSpec:
+                level := levelOf(decl.Doc)
+                if isExperimental(spec.Doc) {
+                    level = Experimental
+                }
+                valueType := spec.Type
+                if decl.Tok == token.CONST {
+                    if len(spec.Values) > 0 {
+                        implicit = spec.Type
+                    }
+                    valueType = implicit
+                }
+                for _, name := range spec.Names {
+                    if !name.IsExported() {
+                        continue
+                    }
+                    if valueType != nil {
+                        e.add(level, "%s %s %s", decl.Tok, name.Name, e.print(valueType))
+                    } else {
+                        e.add(level, "%s %s", decl.Tok, name.Name)
+                    }
+                }
+            }
+        }
+    }
+}
+
+// typeSpec adds a type and, for structs and interfaces, its exported
+// fields and methods
+func (e *extractor) typeSpec(spec *ast.TypeSpec) {
+    name := spec.Name.Name
+    if !ast.IsExported(name) {
+        return
+    }
+    level := Stable
+    if e.experimental[name] {
+        level = Experimental
+    }
+    params := e.typeParams(spec.TypeParams)
+
+    switch t := spec.Type.(type) {
+    case *ast.StructType:
+        e.add(level, "type %s%s struct", name, params)
+        for _, field := range t.Fields.List {
+            if len(field.Names) == 0 {
+                e.add(level, "embedded %s.%s", name, e.print(field.Type))
+            }
+            for _, fieldName := range field.Names {
+                if fieldName.IsExported() {
+                    e.add(level, "field %s.%s %s", name, fieldName.Name, e.typeString(field.Type))
+                }
+            }
+        }
+    case *ast.InterfaceType:
+        e.add(level, "type %s%s interface", name, params)
+        for _, method := range t.Methods.List {
+            if len(method.Names) == 0 {
+                e.add(level, "embedded %s.%s", name, e.print(method.Type))
+            }
+            for _, methodName := range method.Names {
+                e.add(level, "method %s.%s%s", name, methodName.Name, e.signature(method.Type.(*ast.FuncType)))
+            }
+        }
+    default:
+        if path, target, ok := e.aliasOf(spec); ok {
+            e.aliases = append(e.aliases, alias{name: name, path: path, target: target, level: level})
+            return
+        }
+        if spec.Assign.IsValid() {
+            e.add(level, "type %s%s = %s", name, params, e.typeString(spec.Type))
+        } else {
+            e.add(level, "type %s%s %s", name, params, e.typeString(spec.Type))
+        }
+    }
+}
+
+// aliasOf returns the import path and name of the type spec aliases, and
+// false unless spec is an alias of a type of another package
+func (e *extractor) aliasOf(spec *ast.TypeSpec) (string, string, bool) {
+    if !spec.Assign.IsValid() {
+        return "", "", false
+    }
+    expr := spec.Type
+    switch t := expr.(type) {
+    case *ast.IndexExpr:
+        expr = t.X
+    case *ast.IndexListExpr:
+        expr = t.X
+    }
+    selector, ok := expr.(*ast.SelectorExpr)
+    if !ok {
+        return "", "", false
+    }
+    pkg, ok := selector.X.(*ast.Ident)
+    if !ok {
+        return "", "", false
+    }
+    path, ok := e.imports[pkg.Name]
+    return path, selector.Sel.Name, ok
+}
+
+// typeParams prints type parameters, e.g. "[T any]"
+func (e *extractor) typeParams(params *ast.FieldList) string {
+    if params == nil || len(params.List) == 0 {
+        return ""
+    }
+    var parts []string
+    for _, field := range params.List {
+        var names []string
+        for _, name := range field.Names {
+            names = append(names, name.Name)
+        }
+        parts = append(parts, strings.Join(names, ", ")+" "+e.print(field.Type))
+    }
+    return "[" + strings.Join(parts, ", ") + "]"
+}
+
+// signature prints the parameter and result types of a function, without
+// names, e.g. "(string, ...RegisterOption) error"
+func (e *extractor) signature(fn *ast.FuncType) string {
+    params := "(" + strings.Join(e.fieldTypes(fn.Params), ", ") + ")"
+    results := e.fieldTypes(fn.Results)
+    switch {
+    case len(results) == 0:
+        return params
+    case len(results) == 1:
+        return params + " " + results[0]
+    }
+    return params + " (" + strings.Join(results, ", ") + ")"
+}
+
+// fieldTypes returns one type per parameter or result
+func (e *extractor) fieldTypes(list *ast.FieldList) []string {
+    if list == nil {
+        return nil
+    }
+    var types []string
+    for _, field := range list.List {
+        count := len(field.Names)
+        if count == 0 {
+            count = 1
+        }
+        for i := 0; i < count; i++ {
+            types = append(types, e.typeString(field.Type))
+        }
+    }
+    return types
+}
+
+// typeString prints a type, leaving parameter names out of function types
+func (e *extractor) typeString(expr ast.Expr) string {
+    if fn, ok := expr.(*ast.FuncType); ok {
+        return "func" + e.signature(fn)
+    }
+    return e.print(expr)
+}
+
+// print formats a node on a single line
+func (e *extractor) print(node ast.Node) string {
+    var buf bytes.Buffer
+    printer.Fprint(&buf, e.fset, node)
+    return strings.Join(strings.Fields(buf.String()), " ")
+}
+
+// receiverName returns the type name of a method receiver, e.g. "Hot" for
+// *Hot[T]
+func receiverName(expr ast.Expr) string {
+    for {
+        switch t := expr.(type) {
+        case *ast.StarExpr:
+            expr = t.X
+        case *ast.IndexExpr:
+            expr = t.X
+        case *ast.IndexListExpr:
+            expr = t.X
+        case *ast.Ident:
+            return t.Name
+        default:
+            return ""
+        }
+    }
+}
+
+// isExperimental reports whether a doc comment has an Experimental: paragraph
+func isExperimental(doc *ast.CommentGroup) bool {
+    if doc == nil {
+        return false
+    }
+    for _, line := range strings.Split(doc.Text(), "\n") {
+        if strings.HasPrefix(line, experimentalMarker) {
+            return true
+        }
+    }
+    return false
+}
+
+func levelOf(doc *ast.CommentGroup) Level {
+    if isExperimental(doc) {
+        return Experimental
+    }
+    return Stable
+}
+
+// ReadFile reads an API file; a missing file records no API
+func ReadFile(path string) ([]Feature, error) {
+    data, err := os.ReadFile(path)
+    if os.IsNotExist(err) {
+        return nil, nil
+    }
+    if err != nil {
+        return nil, err
+    }
+
+    var features []Feature
+    scanner := bufio.NewScanner(bytes.NewReader(data))
+    for line := 1; scanner.Scan(); line++ {
+        text := scanner.Text()
+        if text == "" || strings.HasPrefix(text, "#") {
+            continue
+        }
+        level, decl, _ := strings.Cut(text, " ")
+        if Level(level) != Stable && Level(level) != Experimental {
+            return nil, fmt.Errorf("%s:%d: unknown level %q", path, line, level)
+        }
+        features = append(features, Feature{Level: Level(level), Decl: decl})
+    }
+    return features, scanner.Err()
+}
+
+// WriteFile records features as the API of the package in dir
+func WriteFile(path, dir string, features []Feature) error {
+    var b strings.Builder
+    fmt.Fprintf(&b, "# API of %s, recorded by cmd/apicheck. Do not edit.\n", dir)
+    fmt.Fprintf(&b, "# Stable features are kept compatible within a major version.\n")
+    for _, f := range features {
+        fmt.Fprintln(&b, f)
+    }
+    if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+        return err
+    }
+    return os.WriteFile(path, []byte(b.String()), 0o644)
+}
+
+// Check compares the API of every public package under root with its API
+// file
+func Check(root string) ([]Report, error) {
+    var reports []Report
+    for _, pkg := range Packages {
+        current, err := Extract(filepath.Join(root, pkg.Dir), pkg.Tags...)
+        if err != nil {
+            return nil, err
+        }
+        recorded, err := ReadFile(filepath.Join(root, pkg.File))
+        if err != nil {
+            return nil, err
+        }
+        reports = append(reports, Compare(pkg.Dir, recorded, current))
+    }
+    return reports, nil
+}
+
+// Record writes the API file of every public package under root. It
+// refuses incompatible changes unless breaking is set, for a new major
+// version.
+func Record(root string, breaking bool) error {
+    reports, err := Check(root)
+    if err != nil {
+        return err
+    }
+    for i, pkg := range Packages {
+        if len(reports[i].Leaks) > 0 || len(reports[i].Incompatible) > 0 && !breaking {
+            return fmt.Errorf("incompatible API changes in %s:\n%s", pkg.Dir, reports[i])
+        }
+    }
+    for _, pkg := range Packages {
+        features, err := Extract(filepath.Join(root, pkg.Dir), pkg.Tags...)
+        if err != nil {
+            return err
+        }
+        if err := WriteFile(filepath.Join(root, pkg.File), pkg.Dir, features); err != nil {
+            return err
+        }
+    }
+    return nil
+}
@@ -0,0 +1,144 @@
+package apicheck
+
+import (
+    "io/fs"
+    "path/filepath"
+    "strings"
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+func TestExtract(t *testing.T) {
+    features, err := Extract(filepath.Join("testdata", "example"))
+    require.NoError(t, err)
+
+    var lines []string
+    for _, f := range features {
+        lines = append(lines, f.String())
+    }
+    assert.Equal(t, []string{
+        "stable const Fast Mode",
+        "stable const Limit",
+        "stable const Safe Mode",
+        "stable field Box.Value T",
+        "stable field Service.Handler func(context.Context, string) error",
+        "stable field Service.Name string",
+        "experimental field Trial.Enabled bool",
+        "stable func NewService(string, ...func(*Service)) *Service",
+        "experimental func Preview() Trial",
+        "stable method (*Box[T]) Get() T",
+        "stable method (*Service) Run(context.Context) (int, error)",
+        "experimental method (Trial) Try()",
+        "stable method Runner.Run(context.Context) (int, error)",
+        "stable type Box[T any] struct",
+        "stable type Mode int",
+        "stable type Runner interface",
+        "stable type Service struct",
+        "experimental type Trial struct",
+    }, lines)
+}
+
+func TestExtract_ExpandsAliases(t *testing.T) {
+    features, err := Extract(filepath.Join("testdata", "wrapper"))
+    require.NoError(t, err)
+
+    var lines []string
+    for _, f := range features {
+        lines = append(lines, f.String())
+    }
+    assert.Equal(t, []string{
+        "stable field Box.Value T",
+        "stable field Service.Handler func(context.Context, string) error",
+        "stable field Service.Name string",
+        "experimental field Trial.Enabled bool",
+        "stable method (*Box[T]) Get() T",
+        "stable method (*Service) Run(context.Context) (int, error)",
+        "experimental method (Trial) Try()",
+        "stable type Box[T any] struct",
+        "stable type Count = int",
+        "stable type Service struct",
+        "experimental type Trial struct",
+    }, lines)
+}
+
+func TestCompare(t *testing.T) {
+    recorded := []Feature{
+        {Stable, "func Kept()"},
+        {Stable, "func Removed()"},
+        {Stable, "func Demoted()"},
+        {Experimental, "func Dropped()"},
+    }
+    current := []Feature{
+        {Stable, "func Kept()"},
+        {Experimental, "func Demoted()"},
+        {Stable, "func Added()"},
+    }
+
+    report := Compare("example", recorded, current)
+    assert.False(t, report.OK())
+    assert.Equal(t, []string{"removed or changed func Removed()", "made experimental func Demoted()"}, report.Incompatible)
+    assert.Equal(t, []string{
+        "removed experimental func Dropped()",
+        "added experimental func Demoted()",
+        "added stable func Added()",
+    }, report.Unrecorded)
+
+    assert.True(t, Compare("example", current, current).OK())
+}
+
+func TestCompare_ReportsLeaks(t *testing.T) {
+    current := []Feature{
+        {Experimental, "type Trial struct"},
+        {Stable, "func Preview() Trial"},
+        {Stable, "func Trials() int"},
+    }
+
+    report := Compare("example", current, current)
+    assert.Equal(t, []string{"func Preview() Trial uses experimental type Trial"}, report.Leaks)
+}
+
+// TestPackagesCoverPkg keeps every package under pkg/ in Packages
+func TestPackagesCoverPkg(t *testing.T) {
+    listed := make(map[string]bool, len(Packages))
+    for _, pkg := range Packages {
+        listed[pkg.Dir] = true
+    }
+
+    root := filepath.Join("..", "..")
+    err := filepath.WalkDir(filepath.Join(root, "pkg"), func(path string, entry fs.DirEntry, err error) error {
+        if err != nil || !entry.IsDir() {
+            return err
+        }
+        if entry.Name() == "testdata" {
+            return filepath.SkipDir
+        }
+        sources, err := filepath.Glob(filepath.Join(path, "*.go"))
+        if err != nil {
+            return err
+        }
+        for _, source := range sources {
+            if !strings.HasSuffix(source, "_test.go") {
+                dir, err := filepath.Rel(root, path)
+                if err != nil {
+                    return err
+                }
+                assert.True(t, listed[filepath.ToSlash(dir)], "public package %s is missing from Packages", dir)
+                break
+            }
+        }
+        return nil
+    })
+    require.NoError(t, err)
+}
+
+// TestRecordedAPI keeps the public packages in line with their API files
+func TestRecordedAPI(t *testing.T) {
+    reports, err := Check(filepath.Join("..", ".."))
+    require.NoError(t, err)
+    for _, report := range reports {
+        assert.True(t, report.OK(), "API of %s differs from the recorded one; "+
+            "run go run ./cmd/apicheck -w to record compatible changes:\n%s", report.Package, report)
+    }
+}
@@ -0,0 +1,67 @@
+// Package example exercises the API extraction of apicheck
+package example
+
+import "context"
+
+// Mode selects a behavior
+type Mode int
+
+const (
+    Fast Mode = iota
+    Safe
+    internalMode
+)
+
+// Limit caps the work done
+const Limit = 10
+
+// Service does work
+type Service struct {
+    Name    string
+    Handler func(ctx context.Context, name string) error
+    count   int
+}
+
+// NewService returns a service named name
+func NewService(name string, opts ...func(*Service)) *Service {
+    return &Service{Name: name}
+}
+
+// Run runs the service
+func (s *Service) Run(ctx context.Context) (int, error) {
+    return s.count, nil
+}
+
+// Runner runs
+type Runner interface {
+    Run(ctx context.Context) (int, error)
+}
+
+// Box holds a value
+type Box[T any] struct {
+    Value T
+}
+
+// Get returns the value
+func (b *Box[T]) Get() T {
+    return b.Value
+}
+
+// Trial is a new idea.
+//
+// Experimental: may go away.
+type Trial struct {
+    Enabled bool
+}
+
+// Try tries it
+func (t Trial) Try() {}
+
+// Preview returns a trial.
+//
+// Experimental: see Trial.
+func Preview() Trial {
+    return Trial{}
+}
+
+func helper() {}
@@ -0,0 +1,16 @@
+// Package wrapper exercises the extraction of aliases by apicheck
+package wrapper
+
+import "di-example/internal/apicheck/testdata/example"
+
+// Service aliases example.Service
+type Service = example.Service
+
+// Box aliases example.Box
+type Box[T any] = example.Box[T]
+
+// Trial aliases example.Trial
+type Trial = example.Trial
+
+// Count is a local alias
+type Count = int
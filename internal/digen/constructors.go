@@ -0,0 +1,242 @@
+package digen
+
+import (
+    "bufio"
+    "bytes"
+    "fmt"
+    "go/ast"
+    "go/format"
+    "go/parser"
+    "go/token"
+    "os"
+    "path/filepath"
+    "sort"
+    "strings"
+    "unicode"
+    "unicode/utf8"
+
+    "di-example/internal/inspectshim"
+)
+
+// ModuleFile is the file RunModule writes by default
+const ModuleFile = "di_module_gen.go"
+
+// ModuleVar is the variable holding the generated module
+const ModuleVar = "Module"
+
+// Constructor is an exported NewXxx function of a package
+type Constructor struct {
+    Func      string
+    Qualifier string // Derived from the name: NewUserService registers userService
+    Position  token.Position
+}
+
+// ConstructorPackage lists the constructors that could return interfaces.
+// Whether they do is only known once the package is compiled, see
+// InterfaceConstructors.
+type ConstructorPackage struct {
+    Name         string
+    ImportPath   string
+    Constructors []Constructor // Sorted by qualifier
+}
+
+// ScanConstructors finds the exported NewXxx functions of the package in
+// dir that Container.Provide accepts: not generic, not variadic, returning
+// a service and optionally an error.
+func ScanConstructors(dir string) (*ConstructorPackage, error) {
+    fset := token.NewFileSet()
+    pkgs, err := parser.ParseDir(fset, dir, func(info os.FileInfo) bool {
+        return !strings.HasSuffix(info.Name(), "_test.go")
+    }, parser.ParseComments)
+    if err != nil {
+        return nil, err
+    }
+    if len(pkgs) != 1 {
+        return nil, fmt.Errorf("expected exactly one package in %s, found %d", dir, len(pkgs))
+    }
+
+    result := &ConstructorPackage{}
+    for name, pkg := range pkgs {
+        if name == "main" {
+            return nil, fmt.Errorf("cannot generate a module for package main in %s: it cannot be imported", dir)
+        }
+        result.Name = name
+        for _, file := range pkg.Files {
+            if ast.IsGenerated(file) {
+                continue
+            }
+            if obj := file.Scope.Lookup(ModuleVar); obj != nil {
+                return nil, fmt.Errorf("%s: package %s already declares %s", fset.Position(obj.Pos()), name, ModuleVar)
+            }
+            for _, decl := range file.Decls {
+                fn, ok := decl.(*ast.FuncDecl)
+                if !ok || !isConstructor(fn) {
+                    continue
+                }
+                result.Constructors = append(result.Constructors, Constructor{
+                    Func:      fn.Name.Name,
+                    Qualifier: constructorQualifier(fn.Name.Name),
+                    Position:  fset.Position(fn.Pos()),
+                })
+            }
+        }
+    }
+    sort.Slice(result.Constructors, func(a, b int) bool {
+        if result.Constructors[a].Qualifier != result.Constructors[b].Qualifier {
+            return result.Constructors[a].Qualifier < result.Constructors[b].Qualifier
+        }
+        return result.Constructors[a].Func < result.Constructors[b].Func
+    })
+
+    _, result.ImportPath, err = inspectshim.ImportPath(dir)
+    if err != nil {
+        return nil, err
+    }
+    return result, nil
+}
+
+// isConstructor reports whether fn is a NewXxx function Provide accepts
+func isConstructor(fn *ast.FuncDecl) bool {
+    suffix, ok := strings.CutPrefix(fn.Name.Name, "New")
+    if !ok || fn.Recv != nil || fn.Type.TypeParams.NumFields() > 0 {
+        return false
+    }
+    if first, _ := utf8.DecodeRuneInString(suffix); !unicode.IsUpper(first) {
+        return false
+    }
+    if params := fn.Type.Params.List; len(params) > 0 {
+        if _, variadic := params[len(params)-1].Type.(*ast.Ellipsis); variadic {
+            return false
+        }
+    }
+    switch fn.Type.Results.NumFields() {
+    case 1:
+        return true
+    case 2:
+        ident, ok := fn.Type.Results.List[len(fn.Type.Results.List)-1].Type.(*ast.Ident)
+        return ok && ident.Name == "error"
+    }
+    return false
+}
+
+// constructorQualifier derives the qualifier of a constructor by dropping
+// New and lowercasing the leading word: NewUserService gives userService,
+// NewHTTPClient gives httpClient and NewDB gives db
+func constructorQualifier(name string) string {
+    runes := []rune(strings.TrimPrefix(name, "New"))
+    upper := 0
+    for upper < len(runes) && unicode.IsUpper(runes[upper]) {
+        upper++
+    }
+    // In HTTPClient the C starts the next word
+    if upper > 1 && upper < len(runes) {
+        upper--
+    }
+    for i := 0; i < upper; i++ {
+        runes[i] = unicode.ToLower(runes[i])
+    }
+    return string(runes)
+}
+
+// GenerateConstructorShim renders a program printing, one per line, the
+// constructors of pkg whose service is an interface. Go cannot tell from
+// the syntax alone whether an imported type is an interface.
+func GenerateConstructorShim(pkg *ConstructorPackage) ([]byte, error) {
+    var buf bytes.Buffer
+    fmt.Fprintf(&buf, "// Code generated by digen. DO NOT EDIT.\n\n")
+    fmt.Fprintf(&buf, "package main\n\n")
+    fmt.Fprintf(&buf, "import (\n\"fmt\"\n\"reflect\"\n\ntarget %q\n)\n\n", pkg.ImportPath)
+    fmt.Fprintf(&buf, "func main() {\nfor name, ctor := range map[string]interface{}{\n")
+    for _, ctor := range pkg.Constructors {
+        fmt.Fprintf(&buf, "%q: target.%s,\n", ctor.Func, ctor.Func)
+    }
+    fmt.Fprintf(&buf, "} {\nif reflect.TypeOf(ctor).Out(0).Kind() == reflect.Interface {\nfmt.Println(name)\n}\n}\n}\n")
+    return format.Source(buf.Bytes())
+}
+
+// InterfaceConstructors scans dir and keeps the constructors returning an
+// interface, running a shim importing the package to find them
+func InterfaceConstructors(dir string) (*ConstructorPackage, error) {
+    pkg, err := ScanConstructors(dir)
+    if err != nil || len(pkg.Constructors) == 0 {
+        return pkg, err
+    }
+    code, err := GenerateConstructorShim(pkg)
+    if err != nil {
+        return nil, err
+    }
+    root, _, err := inspectshim.ImportPath(dir)
+    if err != nil {
+        return nil, err
+    }
+
+    var out bytes.Buffer
+    if err := inspectshim.RunShim(root, pkg.ImportPath, code, nil, &out); err != nil {
+        return nil, err
+    }
+    interfaces := make(map[string]bool)
+    scanner := bufio.NewScanner(&out)
+    for scanner.Scan() {
+        interfaces[strings.TrimSpace(scanner.Text())] = true
+    }
+
+    kept := pkg.Constructors[:0]
+    for _, ctor := range pkg.Constructors {
+        if interfaces[ctor.Func] {
+            kept = append(kept, ctor)
+        }
+    }
+    pkg.Constructors = kept
+    return pkg, nil
+}
+
+// GenerateModule renders a container.Module named name that registers
+// every constructor of pkg with Container.Provide
+func GenerateModule(pkg *ConstructorPackage, name string) ([]byte, error) {
+    if len(pkg.Constructors) == 0 {
+        return nil, fmt.Errorf("package %s has no NewXxx constructors returning an interface", pkg.ImportPath)
+    }
+    for i := 1; i < len(pkg.Constructors); i++ {
+        if previous, ctor := pkg.Constructors[i-1], pkg.Constructors[i]; previous.Qualifier == ctor.Qualifier {
+            return nil, fmt.Errorf("%s: %s and %s both derive qualifier %s", ctor.Position, previous.Func, ctor.Func, ctor.Qualifier)
+        }
+    }
+
+    var buf bytes.Buffer
+    fmt.Fprintf(&buf, "// Code generated by digen. DO NOT EDIT.\n\n")
+    fmt.Fprintf(&buf, "package %s\n\n", pkg.Name)
+    fmt.Fprintf(&buf, "import \"di-example/pkg/container\"\n\n")
+    fmt.Fprintf(&buf, "// %s registers every NewXxx constructor of the package returning an\n// interface, under the qualifier derived from its name\n", ModuleVar)
+    fmt.Fprintf(&buf, "var %s = container.Module{\nName: %q,\nSetup: func(c *container.Container) error {\n", ModuleVar, name)
+    for _, ctor := range pkg.Constructors {
+        fmt.Fprintf(&buf, "if err := c.Provide(%q, %s); err != nil {\nreturn err\n}\n", ctor.Qualifier, ctor.Func)
+    }
+    fmt.Fprintf(&buf, "return nil\n},\n}\n")
+    return format.Source(buf.Bytes())
+}
+
+// RunModule generates the module of the package in dir, named after the
+// package unless name is set, and writes it to dir/output. A module
+// generated earlier is removed first: it may refer to constructors that no
+// longer exist, which would keep the shim from compiling.
+func RunModule(dir, name, output string) error {
+    path := filepath.Join(dir, output)
+    if previous, err := os.ReadFile(path); err == nil && bytes.HasPrefix(previous, []byte("// Code generated by digen.")) {
+        if err := os.Remove(path); err != nil {
+            return err
+        }
+    }
+
+    pkg, err := InterfaceConstructors(dir)
+    if err != nil {
+        return err
+    }
+    if name == "" {
+        name = pkg.Name
+    }
+    code, err := GenerateModule(pkg, name)
+    if err != nil {
+        return err
+    }
+    return os.WriteFile(path, code, 0o644)
+}
@@ -0,0 +1,127 @@
+package digen
+
+import (
+    "os"
+    "path/filepath"
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+const billingSource = `package billing
+
+import "io"
+
+type Invoices interface{ Total() int }
+
+type invoices struct{}
+
+func (invoices) Total() int { return 0 }
+
+type Ledger struct{}
+
+func NewInvoices() Invoices { return invoices{} }
+
+func NewHTTPClient(inv Invoices) (io.Closer, error) { return nil, nil }
+
+func NewLedger() *Ledger { return &Ledger{} }
+
+func NewVariadic(opts ...int) Invoices { return nil }
+
+func NewGeneric[T any]() Invoices { return nil }
+
+func Newsletter() Invoices { return nil }
+
+func (invoices) NewCopy() Invoices { return nil }
+`
+
+func writeModulePackage(t *testing.T, source string) string {
+    root := t.TempDir()
+    require.NoError(t, os.WriteFile(filepath.Join(root, "go.mod"), []byte("module example.com/app\n\ngo 1.22\n"), 0o644))
+    dir := filepath.Join(root, "billing")
+    require.NoError(t, os.MkdirAll(dir, 0o755))
+    require.NoError(t, os.WriteFile(filepath.Join(dir, "billing.go"), []byte(source), 0o644))
+    return dir
+}
+
+func TestConstructorQualifier(t *testing.T) {
+    for name, want := range map[string]string{
+        "NewUserService": "userService",
+        "NewHTTPClient":  "httpClient",
+        "NewDB":          "db",
+        "NewX":           "x",
+    } {
+        assert.Equal(t, want, constructorQualifier(name), name)
+    }
+}
+
+func TestScanConstructors(t *testing.T) {
+    pkg, err := ScanConstructors(writeModulePackage(t, billingSource))
+    require.NoError(t, err)
+    assert.Equal(t, "billing", pkg.Name)
+    assert.Equal(t, "example.com/app/billing", pkg.ImportPath)
+
+    var funcs []string
+    for _, ctor := range pkg.Constructors {
+        funcs = append(funcs, ctor.Func+"="+ctor.Qualifier)
+    }
+    assert.Equal(t, []string{"NewHTTPClient=httpClient", "NewInvoices=invoices", "NewLedger=ledger"}, funcs)
+}
+
+func TestScanConstructorsErrors(t *testing.T) {
+    _, err := ScanConstructors(writeModulePackage(t, "package main\n\nfunc main() {}\n"))
+    assert.ErrorContains(t, err, "cannot generate a module for package main")
+
+    _, err = ScanConstructors(writeModulePackage(t, "package billing\n\nvar Module = 1\n"))
+    assert.ErrorContains(t, err, "package billing already declares Module")
+
+    _, err = ScanConstructors(writePackage(t, "package billing\n"))
+    assert.ErrorContains(t, err, "no go.mod found")
+}
+
+func TestGenerateModule(t *testing.T) {
+    pkg := &ConstructorPackage{
+        Name:       "billing",
+        ImportPath: "example.com/app/billing",
+        Constructors: []Constructor{
+            {Func: "NewInvoices", Qualifier: "invoices"},
+            {Func: "NewUserService", Qualifier: "userService"},
+        },
+    }
+    code, err := GenerateModule(pkg, "billing")
+    require.NoError(t, err)
+    out := string(code)
+    assert.Contains(t, out, "// Code generated by digen. DO NOT EDIT.")
+    assert.Contains(t, out, "package billing")
+    assert.Contains(t, out, `Name: "billing",`)
+    assert.Contains(t, out, `if err := c.Provide("invoices", NewInvoices); err != nil {`)
+    assert.Contains(t, out, `if err := c.Provide("userService", NewUserService); err != nil {`)
+
+    pkg.Constructors = append(pkg.Constructors, Constructor{Func: "NewUserservice", Qualifier: "userService"})
+    _, err = GenerateModule(pkg, "billing")
+    assert.ErrorContains(t, err, "NewUserService and NewUserservice both derive qualifier userService")
+
+    pkg.Constructors = nil
+    _, err = GenerateModule(pkg, "billing")
+    assert.EqualError(t, err, "package example.com/app/billing has no NewXxx constructors returning an interface")
+}
+
+func TestRunModule(t *testing.T) {
+    if testing.Short() {
+        t.Skip("runs the go tool")
+    }
+
+    dir := writeModulePackage(t, billingSource)
+    // A stale module is replaced, not compiled into the shim
+    require.NoError(t, os.WriteFile(filepath.Join(dir, ModuleFile), []byte("// Code generated by digen. DO NOT EDIT.\n\npackage billing\n\nvar Module = NewGone\n"), 0o644))
+
+    require.NoError(t, RunModule(dir, "", ModuleFile))
+    code, err := os.ReadFile(filepath.Join(dir, ModuleFile))
+    require.NoError(t, err)
+    out := string(code)
+    assert.Contains(t, out, `Name: "billing",`)
+    assert.Contains(t, out, `c.Provide("invoices", NewInvoices)`)
+    assert.Contains(t, out, `c.Provide("httpClient", NewHTTPClient)`)
+    assert.NotContains(t, out, "NewLedger") // Returns a struct pointer
+}
@@ -0,0 +1,620 @@
+// Package digen generates container registration code from //di:provide
+// annotations on constructor functions:
+//
+//	//di:provide qualifier=userService singleton
+//	func NewUserService() UserService { ... }
+//
+// Supported annotation options are qualifier=<name> (required), the
+// lifetimes singleton (default) and weak, stage=<n>, and the limits
+// bulkhead=<max concurrent calls> and rate=<calls per second>, which guard
+// the service with container.WithLimits.
+//
+// A //di:compose annotation on an interface that only embeds other
+// interfaces generates a proxy struct implementing it from one registered
+// service per embedded interface, in order:
+//
+//	//di:compose qualifier=store from=reader,writer
+//	type ReadWriteStore interface { Reader; Writer }
+//
+// The embedded interfaces must not share methods.
+//
+// digen also records the di tags of the package's struct fields and declares
+// them in the container manifest, which Container.Build checks against the
+// registered qualifiers.
+//
+// RunModule onboards a package without annotations: it writes a
+// container.Module providing every exported NewXxx constructor returning an
+// interface, under a qualifier derived from the constructor's name.
+//
+// BuildIndex combines the providers, Register calls and di tags of a whole
+// module into a JSON-friendly index for editor navigation.
+package digen
+
+import (
+    "bytes"
+    "fmt"
+    "go/ast"
+    "go/format"
+    "go/parser"
+    "go/token"
+    "go/types"
+    "math"
+    "os"
+    "path/filepath"
+    "reflect"
+    "sort"
+    "strconv"
+    "strings"
+)
+
+// Directive is the comment prefix marking an annotated constructor
+const Directive = "//di:provide"
+
+// ComposeDirective is the comment prefix marking a composed interface
+const ComposeDirective = "//di:compose"
+
+// Provider is an annotated constructor found in a package
+type Provider struct {
+    Func       string // Constructor name
+    Qualifier  string
+    Lifetime   string // "singleton" or "weak"
+    Stage      int
+    HasStage   bool
+    Bulkhead   int     // Maximum concurrent calls, 0 for unlimited
+    Rate       float64 // Calls per second, 0 for unlimited
+    ReturnsErr bool // Whether the constructor returns (T, error)
+    Position   token.Position
+}
+
+// Composite is an interface annotated with //di:compose
+type Composite struct {
+    Interface string // Interface name
+    Qualifier string
+    Parts     []Part // One per embedded interface, in order
+    Position  token.Position
+}
+
+// Part is an embedded interface of a Composite and the service providing it
+type Part struct {
+    Type      string // Embedded interface as written, e.g. "io.Reader"
+    Field     string // Unqualified name, used as the proxy field
+    Qualifier string
+    Import    string // Import spec of a qualified type, e.g. `"io"`
+}
+
+// Reference is a di tagged struct field found in a package
+type Reference struct {
+    Qualifier string // Qualifier after applying the Inject marker prefix
+    Site      string // "pkg.Type.Field"
+    Optional  bool
+    Position  token.Position
+}
+
+// Registration is a Register or RegisterWeak call with a literal qualifier
+// found outside generated code
+type Registration struct {
+    Qualifier string
+    Func      string // Enclosing function
+    Position  token.Position
+}
+
+// Package is the result of scanning a package directory
+type Package struct {
+    Name          string
+    Providers     []Provider
+    Composites    []Composite
+    References    []Reference
+    Registrations []Registration
+}
+
+// Scan parses the non-test Go files in dir and collects annotated constructors
+func Scan(dir string) (*Package, error) {
+    fset := token.NewFileSet()
+    pkgs, err := parser.ParseDir(fset, dir, func(info os.FileInfo) bool {
+        return !strings.HasSuffix(info.Name(), "_test.go")
+    }, parser.ParseComments)
+    if err != nil {
+        return nil, err
+    }
+    if len(pkgs) != 1 {
+        return nil, fmt.Errorf("expected exactly one package in %s, found %d", dir, len(pkgs))
+    }
+
+    result := &Package{}
+    for name, pkg := range pkgs {
+        result.Name = name
+        for _, file := range pkg.Files {
+            providers, err := scanFile(fset, file)
+            if err != nil {
+                return nil, err
+            }
+            result.Providers = append(result.Providers, providers...)
+            composites, err := scanComposites(fset, file)
+            if err != nil {
+                return nil, err
+            }
+            result.Composites = append(result.Composites, composites...)
+            result.References = append(result.References, scanReferences(fset, name, file)...)
+            if !ast.IsGenerated(file) {
+                result.Registrations = append(result.Registrations, scanRegistrations(fset, file)...)
+            }
+        }
+    }
+
+    sort.Slice(result.Providers, func(a, b int) bool {
+        return result.Providers[a].Qualifier < result.Providers[b].Qualifier
+    })
+    sort.Slice(result.Composites, func(a, b int) bool {
+        return result.Composites[a].Qualifier < result.Composites[b].Qualifier
+    })
+    sort.Slice(result.References, func(a, b int) bool {
+        return result.References[a].Site < result.References[b].Site
+    })
+    sort.Slice(result.Registrations, func(a, b int) bool {
+        return result.Registrations[a].Qualifier < result.Registrations[b].Qualifier
+    })
+    return result, nil
+}
+
+// scanFile collects annotated top-level functions of one file
+func scanFile(fset *token.FileSet, file *ast.File) ([]Provider, error) {
+    var providers []Provider
+    for _, decl := range file.Decls {
+        fn, ok := decl.(*ast.FuncDecl)
+        if !ok || fn.Doc == nil || fn.Recv != nil {
+            continue
+        }
+        for _, comment := range fn.Doc.List {
+            if !strings.HasPrefix(comment.Text, Directive) {
+                continue
+            }
+
+            provider, err := parseDirective(strings.TrimPrefix(comment.Text, Directive))
+            if err != nil {
+                return nil, fmt.Errorf("%s: %w", fset.Position(comment.Pos()), err)
+            }
+            if err := checkSignature(fn); err != nil {
+                return nil, fmt.Errorf("%s: %s: %w", fset.Position(fn.Pos()), fn.Name.Name, err)
+            }
+
+            provider.Func = fn.Name.Name
+            provider.ReturnsErr = fn.Type.Results.NumFields() == 2
+            provider.Position = fset.Position(fn.Pos())
+            providers = append(providers, provider)
+        }
+    }
+    return providers, nil
+}
+
+// scanComposites collects interfaces annotated with //di:compose
+func scanComposites(fset *token.FileSet, file *ast.File) ([]Composite, error) {
+    var composites []Composite
+    for _, decl := range file.Decls {
+        gen, ok := decl.(*ast.GenDecl)
+        if !ok || gen.Tok != token.TYPE {
+            continue
+        }
+        for _, spec := range gen.Specs {
+            typeSpec := spec.(*ast.TypeSpec)
+            doc := typeSpec.Doc
+            if doc == nil && len(gen.Specs) == 1 {
+                doc = gen.Doc
+            }
+            if doc == nil {
+                continue
+            }
+            for _, comment := range doc.List {
+                if !strings.HasPrefix(comment.Text, ComposeDirective) {
+                    continue
+                }
+                composite, err := parseComposite(typeSpec, strings.TrimPrefix(comment.Text, ComposeDirective), fileImports(file))
+                if err != nil {
+                    return nil, fmt.Errorf("%s: %s: %w", fset.Position(comment.Pos()), typeSpec.Name.Name, err)
+                }
+                composite.Position = fset.Position(typeSpec.Pos())
+                composites = append(composites, composite)
+            }
+        }
+    }
+    return composites, nil
+}
+
+// fileImports maps the package names used in file to their import specs
+func fileImports(file *ast.File) map[string]string {
+    imports := make(map[string]string)
+    for _, spec := range file.Imports {
+        path, err := strconv.Unquote(spec.Path.Value)
+        if err != nil {
+            continue
+        }
+        name := path[strings.LastIndex(path, "/")+1:]
+        importSpec := spec.Path.Value
+        if spec.Name != nil {
+            name = spec.Name.Name
+            importSpec = name + " " + spec.Path.Value
+        }
+        imports[name] = importSpec
+    }
+    return imports
+}
+
+// parseComposite checks a composed interface against its annotation options
+func parseComposite(spec *ast.TypeSpec, text string, imports map[string]string) (Composite, error) {
+    composite := Composite{Interface: spec.Name.Name}
+    var from []string
+    for _, option := range strings.Fields(text) {
+        key, value, hasValue := strings.Cut(option, "=")
+        switch {
+        case key == "qualifier" && hasValue:
+            composite.Qualifier = value
+        case key == "from" && hasValue:
+            from = strings.Split(value, ",")
+        default:
+            return composite, fmt.Errorf("unknown di:compose option %q", option)
+        }
+    }
+    if composite.Qualifier == "" || len(from) == 0 {
+        return composite, fmt.Errorf("di:compose requires qualifier=<name> and from=<qualifier>,...")
+    }
+
+    iface, ok := spec.Type.(*ast.InterfaceType)
+    if !ok || spec.TypeParams != nil {
+        return composite, fmt.Errorf("di:compose applies to non-generic interfaces")
+    }
+    for _, field := range iface.Methods.List {
+        if len(field.Names) > 0 || typeName(field.Type) == "" {
+            return composite, fmt.Errorf("composed interfaces may only embed interfaces")
+        }
+        part := Part{
+            Type:  types.ExprString(field.Type),
+            Field: typeName(field.Type),
+        }
+        if selector, ok := field.Type.(*ast.SelectorExpr); ok {
+            if pkg, ok := selector.X.(*ast.Ident); ok {
+                part.Import = imports[pkg.Name]
+            }
+        }
+        composite.Parts = append(composite.Parts, part)
+    }
+    if len(from) != len(composite.Parts) {
+        return composite, fmt.Errorf("from lists %d qualifiers for %d embedded interfaces", len(from), len(composite.Parts))
+    }
+    for i := range composite.Parts {
+        composite.Parts[i].Qualifier = from[i]
+    }
+    return composite, nil
+}
+
+// scanReferences collects the di tags of the struct types declared in file,
+// applying the prefix and optional defaults of an embedded Inject marker
+func scanReferences(fset *token.FileSet, pkgName string, file *ast.File) []Reference {
+    var refs []Reference
+    ast.Inspect(file, func(node ast.Node) bool {
+        spec, ok := node.(*ast.TypeSpec)
+        if !ok {
+            return true
+        }
+        structType, ok := spec.Type.(*ast.StructType)
+        if !ok {
+            return true
+        }
+
+        prefix, optional := markerDefaults(structType)
+        for _, field := range structType.Fields.List {
+            tag, ok := diTag(field)
+            if !ok || len(field.Names) == 0 {
+                continue
+            }
+            qualifier, options, _ := strings.Cut(tag, ",")
+            // Option structs are resolved by type and fall back to defaults,
+            // di:"" fields are matched by type at runtime and di:"map:group"
+            // fields name a group, which may be empty
+            if q := strings.TrimSpace(qualifier); q == "options" || q == "" || strings.HasPrefix(q, "map:") {
+                continue
+            }
+            // Fields guarded by ifPresent=<qualifier> are only injected when the guard exists
+            guarded := strings.Contains(","+options, ",ifPresent=")
+            // Field options override the marker, and required wins over optional
+            fieldOptional := optional
+            if strings.Contains(","+options+",", ",optional,") {
+                fieldOptional = true
+            }
+            if strings.Contains(","+options+",", ",required,") {
+                fieldOptional = false
+            }
+            for _, name := range field.Names {
+                refs = append(refs, Reference{
+                    Qualifier: prefix + strings.TrimSpace(qualifier),
+                    Site:      pkgName + "." + spec.Name.Name + "." + name.Name,
+                    Optional:  fieldOptional || guarded || isOptionalType(field.Type),
+                    Position:  fset.Position(name.Pos()),
+                })
+            }
+        }
+        return true
+    })
+    return refs
+}
+
+// scanRegistrations collects Register and RegisterWeak calls whose
+// qualifier is a string literal, e.g. c.Register("users", users)
+func scanRegistrations(fset *token.FileSet, file *ast.File) []Registration {
+    var registrations []Registration
+    for _, decl := range file.Decls {
+        fn, ok := decl.(*ast.FuncDecl)
+        if !ok || fn.Body == nil {
+            continue
+        }
+        ast.Inspect(fn.Body, func(node ast.Node) bool {
+            call, ok := node.(*ast.CallExpr)
+            if !ok || len(call.Args) == 0 {
+                return true
+            }
+            selector, ok := call.Fun.(*ast.SelectorExpr)
+            if !ok || (selector.Sel.Name != "Register" && selector.Sel.Name != "RegisterWeak") {
+                return true
+            }
+            literal, ok := call.Args[0].(*ast.BasicLit)
+            if !ok || literal.Kind != token.STRING {
+                return true
+            }
+            qualifier, err := strconv.Unquote(literal.Value)
+            if err != nil {
+                return true
+            }
+            registrations = append(registrations, Registration{
+                Qualifier: qualifier,
+                Func:      fn.Name.Name,
+                Position:  fset.Position(call.Pos()),
+            })
+            return true
+        })
+    }
+    return registrations
+}
+
+// markerDefaults reads the prefix and optional options of an embedded Inject
+// marker. Only an explicit optional counts; required wins if both are given.
+func markerDefaults(structType *ast.StructType) (string, bool) {
+    for _, field := range structType.Fields.List {
+        if len(field.Names) != 0 || typeName(field.Type) != "Inject" {
+            continue
+        }
+        tag, _ := diTag(field)
+
+        prefix, optional, required := "", false, false
+        for _, option := range strings.Split(tag, ",") {
+            key, value, _ := strings.Cut(strings.TrimSpace(option), "=")
+            switch key {
+            case "prefix":
+                prefix = value
+            case "optional":
+                optional = true
+            case "required":
+                required = true
+            }
+        }
+        return prefix, optional && !required
+    }
+    return "", false
+}
+
+// diTag returns the value of the di key in a field's struct tag
+func diTag(field *ast.Field) (string, bool) {
+    if field.Tag == nil {
+        return "", false
+    }
+    raw, err := strconv.Unquote(field.Tag.Value)
+    if err != nil {
+        return "", false
+    }
+    return reflect.StructTag(raw).Lookup("di")
+}
+
+// isOptionalType reports whether expr is Optional[T] or container.Optional[T]
+func isOptionalType(expr ast.Expr) bool {
+    index, ok := expr.(*ast.IndexExpr)
+    return ok && typeName(index.X) == "Optional"
+}
+
+// typeName returns the unqualified name of an identifier or selector
+func typeName(expr ast.Expr) string {
+    switch expr := expr.(type) {
+    case *ast.Ident:
+        return expr.Name
+    case *ast.SelectorExpr:
+        return expr.Sel.Name
+    }
+    return ""
+}
+
+// parseDirective parses the options following //di:provide
+func parseDirective(text string) (Provider, error) {
+    provider := Provider{Lifetime: "singleton"}
+    for _, option := range strings.Fields(text) {
+        key, value, hasValue := strings.Cut(option, "=")
+        switch {
+        case key == "qualifier" && hasValue:
+            provider.Qualifier = value
+        case key == "stage" && hasValue:
+            stage, err := strconv.Atoi(value)
+            if err != nil {
+                return provider, fmt.Errorf("invalid stage %q", value)
+            }
+            provider.Stage, provider.HasStage = stage, true
+        case key == "bulkhead" && hasValue:
+            bulkhead, err := strconv.Atoi(value)
+            if err != nil || bulkhead < 1 {
+                return provider, fmt.Errorf("invalid bulkhead %q", value)
+            }
+            provider.Bulkhead = bulkhead
+        case key == "rate" && hasValue:
+            rate, err := strconv.ParseFloat(value, 64)
+            if err != nil || !(rate > 0) || math.IsInf(rate, 0) {
+                return provider, fmt.Errorf("invalid rate %q", value)
+            }
+            provider.Rate = rate
+        case (key == "singleton" || key == "weak") && !hasValue:
+            provider.Lifetime = key
+        default:
+            return provider, fmt.Errorf("unknown di:provide option %q", option)
+        }
+    }
+    if provider.Qualifier == "" {
+        return provider, fmt.Errorf("di:provide requires qualifier=<name>")
+    }
+    return provider, nil
+}
+
+// checkSignature accepts constructors without parameters returning T or (T, error)
+func checkSignature(fn *ast.FuncDecl) error {
+    if fn.Type.TypeParams.NumFields() > 0 || fn.Type.Params.NumFields() > 0 {
+        return fmt.Errorf("annotated constructors must not take parameters")
+    }
+    switch fn.Type.Results.NumFields() {
+    case 1:
+        return nil
+    case 2:
+        if ident, ok := fn.Type.Results.List[len(fn.Type.Results.List)-1].Type.(*ast.Ident); ok && ident.Name == "error" {
+            return nil
+        }
+    }
+    return fmt.Errorf("annotated constructors must return T or (T, error)")
+}
+
+// Generate renders the registration code for pkg. RegisterProviders is only
+// emitted when the package has annotated constructors or interfaces, and
+// the manifest init only when it has di tags.
+func Generate(pkg *Package) ([]byte, error) {
+    var buf bytes.Buffer
+
+    fmt.Fprintf(&buf, "// Code generated by digen. DO NOT EDIT.\n\n")
+    fmt.Fprintf(&buf, "package %s\n\n", pkg.Name)
+    if imports := compositeImports(pkg.Composites); len(imports) > 0 {
+        fmt.Fprintf(&buf, "import (\n\"di-example/pkg/container\"\n%s\n)\n\n", strings.Join(imports, "\n"))
+    } else {
+        fmt.Fprintf(&buf, "import \"di-example/pkg/container\"\n\n")
+    }
+
+    if len(pkg.Providers) > 0 || len(pkg.Composites) > 0 {
+        generateProviders(&buf, pkg.Providers, pkg.Composites)
+    }
+    if len(pkg.References) > 0 {
+        generateReferences(&buf, pkg.References)
+    }
+    return format.Source(buf.Bytes())
+}
+
+// generateProviders renders RegisterProviders and the composite proxies
+func generateProviders(buf *bytes.Buffer, providers []Provider, composites []Composite) {
+    for _, composite := range composites {
+        generateProxy(buf, composite)
+    }
+
+    fmt.Fprintf(buf, "// RegisterProviders registers every constructor annotated with //di:provide\n")
+    fmt.Fprintf(buf, "// and every interface annotated with //di:compose\n")
+    fmt.Fprintf(buf, "func RegisterProviders(c *container.Container) error {\n")
+
+    for _, provider := range providers {
+        options := ""
+        if provider.HasStage {
+            options = fmt.Sprintf(", container.InStage(%d)", provider.Stage)
+        }
+        if provider.Bulkhead > 0 || provider.Rate > 0 {
+            options += fmt.Sprintf(", container.WithLimits(container.Limits{MaxConcurrent: %d, Rate: %s})",
+                provider.Bulkhead, strconv.FormatFloat(provider.Rate, 'g', -1, 64))
+        }
+
+        switch {
+        case provider.Lifetime == "weak" && provider.ReturnsErr:
+            fmt.Fprintf(buf, "if err := c.RegisterWeak(%q, func() (interface{}, error) { return %s() }%s); err != nil {\nreturn err\n}\n",
+                provider.Qualifier, provider.Func, options)
+        case provider.Lifetime == "weak":
+            fmt.Fprintf(buf, "if err := c.RegisterWeak(%q, func() (interface{}, error) { return %s(), nil }%s); err != nil {\nreturn err\n}\n",
+                provider.Qualifier, provider.Func, options)
+        case provider.ReturnsErr:
+            fmt.Fprintf(buf, "{\nservice, err := %s()\nif err != nil {\nreturn err\n}\nif err := c.Register(%q, service%s); err != nil {\nreturn err\n}\n}\n",
+                provider.Func, provider.Qualifier, options)
+        default:
+            fmt.Fprintf(buf, "if err := c.Register(%q, %s()%s); err != nil {\nreturn err\n}\n",
+                provider.Qualifier, provider.Func, options)
+        }
+    }
+
+    for _, composite := range composites {
+        generateComposite(buf, composite)
+    }
+
+    fmt.Fprintf(buf, "return nil\n}\n")
+}
+
+// compositeImports returns the import specs needed by the proxies
+func compositeImports(composites []Composite) []string {
+    seen := make(map[string]bool)
+    var specs []string
+    for _, composite := range composites {
+        for _, part := range composite.Parts {
+            if part.Import != "" && !seen[part.Import] {
+                seen[part.Import] = true
+                specs = append(specs, part.Import)
+            }
+        }
+    }
+    sort.Strings(specs) // format.Source orders them by path
+    return specs
+}
+
+// proxyName returns the unexported proxy type of a composite
+func proxyName(composite Composite) string {
+    return strings.ToLower(composite.Interface[:1]) + composite.Interface[1:] + "Proxy"
+}
+
+// generateProxy renders the struct embedding the parts of a composite
+func generateProxy(buf *bytes.Buffer, composite Composite) {
+    fmt.Fprintf(buf, "// %s implements %s by embedding the services composing it\n", proxyName(composite), composite.Interface)
+    fmt.Fprintf(buf, "type %s struct {\n", proxyName(composite))
+    for _, part := range composite.Parts {
+        fmt.Fprintf(buf, "%s\n", part.Type)
+    }
+    fmt.Fprintf(buf, "}\n\n")
+}
+
+// generateComposite renders the lazy registration of a composite proxy
+func generateComposite(buf *bytes.Buffer, composite Composite) {
+    qualifiers := make([]string, len(composite.Parts))
+    for i, part := range composite.Parts {
+        qualifiers[i] = strconv.Quote(part.Qualifier)
+    }
+
+    fmt.Fprintf(buf, "if err := c.RegisterFactory(%q, func(c *container.Container) (interface{}, error) {\n", composite.Qualifier)
+    fmt.Fprintf(buf, "proxy := &%s{}\n", proxyName(composite))
+    for _, part := range composite.Parts {
+        fmt.Fprintf(buf, "{\npart, err := container.ResolveAs[%s](c, %q)\nif err != nil {\nreturn nil, err\n}\nproxy.%s = part\n}\n",
+            part.Type, part.Qualifier, part.Field)
+    }
+    fmt.Fprintf(buf, "return %s(proxy), nil\n}, container.DependsOn(%s)); err != nil {\nreturn err\n}\n",
+        composite.Interface, strings.Join(qualifiers, ", "))
+}
+
+// generateReferences renders an init function declaring the package's di tags
+func generateReferences(buf *bytes.Buffer, refs []Reference) {
+    fmt.Fprintf(buf, "\nfunc init() {\ncontainer.DeclareReferences(\n")
+    for _, ref := range refs {
+        fmt.Fprintf(buf, "container.Reference{Qualifier: %q, Site: %q, Optional: %t},\n",
+            ref.Qualifier, ref.Site, ref.Optional)
+    }
+    fmt.Fprintf(buf, ")\n}\n")
+}
+
+// Run scans dir and writes the generated code to dir/output
+func Run(dir, output string) error {
+    pkg, err := Scan(dir)
+    if err != nil {
+        return err
+    }
+    code, err := Generate(pkg)
+    if err != nil {
+        return err
+    }
+    return os.WriteFile(filepath.Join(dir, output), code, 0o644)
+}
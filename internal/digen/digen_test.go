@@ -0,0 +1,270 @@
+package digen
+
+import (
+    "go/token"
+    "os"
+    "path/filepath"
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+func writePackage(t *testing.T, source string) string {
+    dir := t.TempDir()
+    require.NoError(t, os.WriteFile(filepath.Join(dir, "services.go"), []byte(source), 0o644))
+    return dir
+}
+
+func TestScanAndGenerate(t *testing.T) {
+    dir := writePackage(t, `package services
+
+//di:provide qualifier=userService singleton
+func NewUserService() interface{} { return nil }
+
+//di:provide qualifier=templates weak stage=1
+func LoadTemplates() (interface{}, error) { return nil, nil }
+
+// NewHelper is not annotated
+func NewHelper() interface{} { return nil }
+`)
+
+    pkg, err := Scan(dir)
+    require.NoError(t, err)
+    assert.Equal(t, "services", pkg.Name)
+    require.Len(t, pkg.Providers, 2)
+
+    templates := pkg.Providers[0]
+    assert.Equal(t, "templates", templates.Qualifier)
+    assert.Equal(t, "weak", templates.Lifetime)
+    assert.True(t, templates.HasStage)
+    assert.Equal(t, 1, templates.Stage)
+    assert.True(t, templates.ReturnsErr)
+
+    code, err := Generate(pkg)
+    require.NoError(t, err)
+    assert.Contains(t, string(code), "// Code generated by digen. DO NOT EDIT.")
+    assert.Contains(t, string(code), `c.Register("userService", NewUserService())`)
+    assert.Contains(t, string(code), `c.RegisterWeak("templates", func() (interface{}, error) { return LoadTemplates() }, container.InStage(1))`)
+}
+
+func TestScanAndGenerateLimits(t *testing.T) {
+    dir := writePackage(t, `package services
+
+//di:provide qualifier=payments bulkhead=4 rate=2.5 stage=1
+func NewPayments() func(amount int) error { return nil }
+
+//di:provide qualifier=mailer rate=10
+func NewMailer() func(to string) error { return nil }
+`)
+
+    pkg, err := Scan(dir)
+    require.NoError(t, err)
+    require.Len(t, pkg.Providers, 2)
+
+    mailer, payments := pkg.Providers[0], pkg.Providers[1]
+    assert.Equal(t, 4, payments.Bulkhead)
+    assert.Equal(t, 2.5, payments.Rate)
+    assert.Equal(t, 0, mailer.Bulkhead)
+
+    code, err := Generate(pkg)
+    require.NoError(t, err)
+    assert.Contains(t, string(code), `c.Register("payments", NewPayments(), container.InStage(1), container.WithLimits(container.Limits{MaxConcurrent: 4, Rate: 2.5}))`)
+    assert.Contains(t, string(code), `c.Register("mailer", NewMailer(), container.WithLimits(container.Limits{MaxConcurrent: 0, Rate: 10}))`)
+}
+
+func TestScanErrors(t *testing.T) {
+    tests := []struct {
+        name   string
+        source string
+        want   string
+    }{
+        {
+            name:   "missing qualifier",
+            source: "package p\n\n//di:provide singleton\nfunc New() int { return 0 }\n",
+            want:   "requires qualifier",
+        },
+        {
+            name:   "unknown option",
+            source: "package p\n\n//di:provide qualifier=a eager\nfunc New() int { return 0 }\n",
+            want:   "unknown di:provide option",
+        },
+        {
+            name:   "constructor with parameters",
+            source: "package p\n\n//di:provide qualifier=a\nfunc New(x int) int { return x }\n",
+            want:   "must not take parameters",
+        },
+        {
+            name:   "second result not error",
+            source: "package p\n\n//di:provide qualifier=a\nfunc New() (int, int) { return 0, 0 }\n",
+            want:   "must return T or (T, error)",
+        },
+        {
+            name:   "invalid bulkhead",
+            source: "package p\n\n//di:provide qualifier=a bulkhead=0\nfunc New() int { return 0 }\n",
+            want:   "invalid bulkhead",
+        },
+        {
+            name:   "invalid rate",
+            source: "package p\n\n//di:provide qualifier=a rate=fast\nfunc New() int { return 0 }\n",
+            want:   "invalid rate",
+        },
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            _, err := Scan(writePackage(t, tt.source))
+            require.Error(t, err)
+            assert.Contains(t, err.Error(), tt.want)
+        })
+    }
+}
+
+func TestScanReferences(t *testing.T) {
+    dir := writePackage(t, `package handlers
+
+import "di-example/pkg/container"
+
+type Plain struct {
+    Users interface{} `+"`di:\"userService\"`"+`
+    Other int
+}
+
+type Web struct {
+    container.Inject `+"`di:\"prefix=web.,optional\"`"+`
+    Users interface{} `+"`di:\"users\"`"+`
+    Auth interface{} `+"`di:\"auth,required\"`"+`
+}
+
+type Strict struct {
+    container.Inject `+"`di:\"optional,required\"`"+`
+    Cache container.Optional[int] `+"`di:\"cache\"`"+`
+    Mailer interface{} `+"`di:\"mailer\"`"+`
+    Clock interface{} `+"`di:\"clock,optional\"`"+`
+    Metrics interface{} `+"`di:\"metricsSink,ifPresent=featureMetrics\"`"+`
+    Options struct{} `+"`di:\"options\"`"+`
+    Plugins map[string]interface{} `+"`di:\"map:plugins\"`"+`
+}
+`)
+
+    pkg, err := Scan(dir)
+    require.NoError(t, err)
+    assert.Empty(t, pkg.Providers)
+
+    // Positions point at the field names
+    assert.Equal(t, filepath.Join(dir, "services.go"), pkg.References[0].Position.Filename)
+    assert.Equal(t, 6, pkg.References[0].Position.Line)
+    for i := range pkg.References {
+        pkg.References[i].Position = token.Position{}
+    }
+
+    assert.Equal(t, []Reference{
+        {Qualifier: "userService", Site: "handlers.Plain.Users"},
+        {Qualifier: "cache", Site: "handlers.Strict.Cache", Optional: true},
+        {Qualifier: "clock", Site: "handlers.Strict.Clock", Optional: true},
+        {Qualifier: "mailer", Site: "handlers.Strict.Mailer"},
+        {Qualifier: "metricsSink", Site: "handlers.Strict.Metrics", Optional: true},
+        {Qualifier: "web.auth", Site: "handlers.Web.Auth"},
+        {Qualifier: "web.users", Site: "handlers.Web.Users", Optional: true},
+    }, pkg.References)
+
+    code, err := Generate(pkg)
+    require.NoError(t, err)
+    assert.NotContains(t, string(code), "RegisterProviders")
+    assert.Contains(t, string(code), `container.Reference{Qualifier: "web.users", Site: "handlers.Web.Users", Optional: true}`)
+}
+
+func TestScanAndGenerateComposite(t *testing.T) {
+    dir := writePackage(t, `package store
+
+import (
+    "io"
+    kv "example.com/kv"
+)
+
+type Reader interface { Read(key string) string }
+
+// ReadWriteStore reads and writes
+//
+//di:compose qualifier=store from=reader,writer,lister
+type ReadWriteStore interface {
+    Reader
+    io.Writer
+    kv.Lister
+}
+`)
+
+    pkg, err := Scan(dir)
+    require.NoError(t, err)
+    require.Len(t, pkg.Composites, 1)
+    composite := pkg.Composites[0]
+    assert.Equal(t, "ReadWriteStore", composite.Interface)
+    assert.Equal(t, "store", composite.Qualifier)
+    assert.Equal(t, []Part{
+        {Type: "Reader", Field: "Reader", Qualifier: "reader"},
+        {Type: "io.Writer", Field: "Writer", Qualifier: "writer", Import: `"io"`},
+        {Type: "kv.Lister", Field: "Lister", Qualifier: "lister", Import: `kv "example.com/kv"`},
+    }, composite.Parts)
+
+    code, err := Generate(pkg)
+    require.NoError(t, err)
+    assert.Contains(t, string(code), "import (\n\t\"di-example/pkg/container\"\n\tkv \"example.com/kv\"\n\t\"io\"\n)")
+    assert.Contains(t, string(code), "type readWriteStoreProxy struct {\n\tReader\n\tio.Writer\n\tkv.Lister\n}")
+    assert.Contains(t, string(code), `part, err := container.ResolveAs[io.Writer](c, "writer")`)
+    assert.Contains(t, string(code), "proxy.Lister = part")
+    assert.Contains(t, string(code), `return ReadWriteStore(proxy), nil`)
+    assert.Contains(t, string(code), `container.DependsOn("reader", "writer", "lister")`)
+}
+
+func TestScanCompositeErrors(t *testing.T) {
+    tests := []struct {
+        name   string
+        source string
+        want   string
+    }{
+        {
+            name:   "missing from",
+            source: "package p\n\n//di:compose qualifier=a\ntype A interface{ B }\n",
+            want:   "requires qualifier=<name> and from=",
+        },
+        {
+            name:   "own methods",
+            source: "package p\n\n//di:compose qualifier=a from=b\ntype A interface{ Run() }\n",
+            want:   "may only embed interfaces",
+        },
+        {
+            name:   "count mismatch",
+            source: "package p\n\n//di:compose qualifier=a from=b,c\ntype A interface{ B }\n",
+            want:   "from lists 2 qualifiers for 1 embedded interfaces",
+        },
+        {
+            name:   "not an interface",
+            source: "package p\n\n//di:compose qualifier=a from=b\ntype A struct{}\n",
+            want:   "applies to non-generic interfaces",
+        },
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            _, err := Scan(writePackage(t, tt.source))
+            require.Error(t, err)
+            assert.Contains(t, err.Error(), tt.want)
+        })
+    }
+}
+
+// TestGeneratedFilesUpToDate fails when a package's di_gen.go differs from
+// what go generate writes now
+func TestGeneratedFilesUpToDate(t *testing.T) {
+    for _, dir := range []string{"services", "models"} {
+        dir := filepath.Join("..", dir)
+        pkg, err := Scan(dir)
+        require.NoError(t, err)
+        want, err := Generate(pkg)
+        require.NoError(t, err)
+
+        got, err := os.ReadFile(filepath.Join(dir, "di_gen.go"))
+        require.NoError(t, err)
+        assert.Equal(t, string(want), string(got), "%s/di_gen.go is stale; run go generate ./...", dir)
+    }
+}
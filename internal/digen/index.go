@@ -0,0 +1,161 @@
+package digen
+
+import (
+    "fmt"
+    "go/token"
+    "io/fs"
+    "os"
+    "path/filepath"
+    "sort"
+    "strings"
+)
+
+// Location is a source position relative to the index root
+type Location struct {
+    File   string `json:"file"`
+    Line   int    `json:"line"`
+    Column int    `json:"column"`
+}
+
+// ProviderLocation is where a qualifier is provided: an annotated
+// constructor or a Register call
+type ProviderLocation struct {
+    Location
+    Kind string `json:"kind"` // "constructor" or "register"
+    Name string `json:"name"` // Constructor, or function making the Register call
+}
+
+// ConsumerLocation is a di tagged field consuming a qualifier
+type ConsumerLocation struct {
+    Location
+    Site     string `json:"site"`
+    Optional bool   `json:"optional,omitempty"`
+}
+
+// QualifierIndex lists the providers and consumers of one qualifier
+type QualifierIndex struct {
+    Providers []ProviderLocation `json:"providers"`
+    Consumers []ConsumerLocation `json:"consumers"`
+}
+
+// Index maps qualifiers to their source locations so editor plugins can
+// offer "go to provider" from a di tag and "find consumers" from a provider
+type Index struct {
+    Root       string                     `json:"root"`
+    Qualifiers map[string]*QualifierIndex `json:"qualifiers"`
+}
+
+// BuildIndex scans every package under root, skipping hidden directories,
+// vendor and testdata
+func BuildIndex(root string) (*Index, error) {
+    root, err := filepath.Abs(root)
+    if err != nil {
+        return nil, err
+    }
+    index := &Index{Root: root, Qualifiers: make(map[string]*QualifierIndex)}
+
+    err = filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+        if err != nil {
+            return err
+        }
+        if !entry.IsDir() {
+            return nil
+        }
+        name := entry.Name()
+        if path != root && (strings.HasPrefix(name, ".") || name == "vendor" || name == "testdata") {
+            return filepath.SkipDir
+        }
+        if !hasGoFiles(path) {
+            return nil
+        }
+
+        pkg, err := Scan(path)
+        if err != nil {
+            return fmt.Errorf("failed to scan %s: %w", path, err)
+        }
+        index.add(pkg)
+        return nil
+    })
+    if err != nil {
+        return nil, err
+    }
+
+    for _, entry := range index.Qualifiers {
+        sort.Slice(entry.Providers, func(a, b int) bool { return entry.Providers[a].less(entry.Providers[b].Location) })
+        sort.Slice(entry.Consumers, func(a, b int) bool { return entry.Consumers[a].less(entry.Consumers[b].Location) })
+    }
+    return index, nil
+}
+
+// hasGoFiles reports whether dir directly contains non-test Go files
+func hasGoFiles(dir string) bool {
+    entries, err := os.ReadDir(dir)
+    if err != nil {
+        return false
+    }
+    for _, entry := range entries {
+        name := entry.Name()
+        if !entry.IsDir() && strings.HasSuffix(name, ".go") && !strings.HasSuffix(name, "_test.go") {
+            return true
+        }
+    }
+    return false
+}
+
+// add records the providers and consumers of one scanned package
+func (i *Index) add(pkg *Package) {
+    for _, provider := range pkg.Providers {
+        entry := i.entry(provider.Qualifier)
+        entry.Providers = append(entry.Providers, ProviderLocation{
+            Location: i.location(provider.Position),
+            Kind:     "constructor",
+            Name:     provider.Func,
+        })
+    }
+    for _, registration := range pkg.Registrations {
+        entry := i.entry(registration.Qualifier)
+        entry.Providers = append(entry.Providers, ProviderLocation{
+            Location: i.location(registration.Position),
+            Kind:     "register",
+            Name:     registration.Func,
+        })
+    }
+    for _, ref := range pkg.References {
+        entry := i.entry(ref.Qualifier)
+        entry.Consumers = append(entry.Consumers, ConsumerLocation{
+            Location: i.location(ref.Position),
+            Site:     ref.Site,
+            Optional: ref.Optional,
+        })
+    }
+}
+
+// entry returns the index of qualifier, creating it if needed
+func (i *Index) entry(qualifier string) *QualifierIndex {
+    entry, ok := i.Qualifiers[qualifier]
+    if !ok {
+        entry = &QualifierIndex{Providers: []ProviderLocation{}, Consumers: []ConsumerLocation{}}
+        i.Qualifiers[qualifier] = entry
+    }
+    return entry
+}
+
+// location converts a position to one relative to the index root
+func (i *Index) location(position token.Position) Location {
+    file := position.Filename
+    if rel, err := filepath.Rel(i.Root, file); err == nil {
+        file = filepath.ToSlash(rel)
+    }
+    return Location{File: file, Line: position.Line, Column: position.Column}
+}
+
+// less orders locations by file, then line, then column
+func (l Location) less(other Location) bool {
+    if l.File != other.File {
+        return l.File < other.File
+    }
+    if l.Line != other.Line {
+        return l.Line < other.Line
+    }
+    return l.Column < other.Column
+}
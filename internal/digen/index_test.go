@@ -0,0 +1,77 @@
+package digen
+
+import (
+    "os"
+    "path/filepath"
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, path, source string) {
+    require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+    require.NoError(t, os.WriteFile(path, []byte(source), 0o644))
+}
+
+func TestBuildIndex(t *testing.T) {
+    root := t.TempDir()
+    writeFile(t, filepath.Join(root, "services", "services.go"), `package services
+
+//di:provide qualifier=users
+func NewUsers() interface{} { return nil }
+`)
+    writeFile(t, filepath.Join(root, "services", "di_gen.go"), `// Code generated by digen. DO NOT EDIT.
+
+package services
+
+func RegisterProviders(c interface{ Register(string, interface{}) error }) error {
+    return c.Register("users", NewUsers())
+}
+`)
+    writeFile(t, filepath.Join(root, "handlers", "handlers.go"), `package handlers
+
+type Web struct {
+    Users  interface{} `+"`di:\"users\"`"+`
+    Mailer interface{} `+"`di:\"mailer\"`"+`
+}
+`)
+    writeFile(t, filepath.Join(root, "main.go"), `package main
+
+type registry interface {
+    Register(qualifier string, service interface{}) error
+}
+
+func wire(c registry, qualifier string) {
+    c.Register("mailer", "smtp")
+    c.Register(qualifier, "dynamic") // Not a literal, not indexed
+}
+
+func main() {}
+`)
+    writeFile(t, filepath.Join(root, "testdata", "broken.go"), "not go")
+
+    index, err := BuildIndex(root)
+    require.NoError(t, err)
+    require.Len(t, index.Qualifiers, 2)
+
+    users := index.Qualifiers["users"]
+    assert.Equal(t, []ProviderLocation{{
+        Location: Location{File: "services/services.go", Line: 4, Column: 1},
+        Kind:     "constructor",
+        Name:     "NewUsers",
+    }}, users.Providers) // The generated Register call is not a second provider
+    assert.Equal(t, []ConsumerLocation{{
+        Location: Location{File: "handlers/handlers.go", Line: 4, Column: 5},
+        Site:     "handlers.Web.Users",
+    }}, users.Consumers)
+
+    mailer := index.Qualifiers["mailer"]
+    assert.Equal(t, []ProviderLocation{{
+        Location: Location{File: "main.go", Line: 8, Column: 5},
+        Kind:     "register",
+        Name:     "wire",
+    }}, mailer.Providers)
+    require.Len(t, mailer.Consumers, 1)
+    assert.Equal(t, "handlers.Web.Mailer", mailer.Consumers[0].Site)
+}
@@ -0,0 +1,223 @@
+// Package inspectshim reports on the exported struct types of any package
+// in the module. Go cannot load types at run time, so it generates a small
+// main package (the shim) importing the target package, runs it with
+// go run and relays its output:
+//
+//	shim := Generate(pkg)   // &pkg.Type{} for every exported struct
+//	go run ./_inspect-xxxx  // inside the module so internal packages resolve
+//
+// The shim inspects zero values with reflection.Inspector and aggregates
+// their di tags with reflection.AggregateTagUsage.
+package inspectshim
+
+import (
+    "bufio"
+    "bytes"
+    "fmt"
+    "go/ast"
+    "go/format"
+    "go/parser"
+    "go/token"
+    "io"
+    "os"
+    "os/exec"
+    "path/filepath"
+    "reflect"
+    "sort"
+    "strings"
+
+    "di-example/pkg/reflection"
+)
+
+// Package is a package to inspect
+type Package struct {
+    Name       string
+    ImportPath string
+    Structs    []string // Exported non-generic struct types, sorted
+}
+
+// Scan finds the exported struct types of the package in dir and its
+// import path within the enclosing module
+func Scan(dir string) (*Package, error) {
+    fset := token.NewFileSet()
+    pkgs, err := parser.ParseDir(fset, dir, func(info os.FileInfo) bool {
+        return !strings.HasSuffix(info.Name(), "_test.go")
+    }, 0)
+    if err != nil {
+        return nil, err
+    }
+    if len(pkgs) != 1 {
+        return nil, fmt.Errorf("expected exactly one package in %s, found %d", dir, len(pkgs))
+    }
+
+    result := &Package{}
+    for name, pkg := range pkgs {
+        if name == "main" {
+            return nil, fmt.Errorf("cannot inspect package main in %s: it cannot be imported", dir)
+        }
+        result.Name = name
+        for _, file := range pkg.Files {
+            result.Structs = append(result.Structs, exportedStructs(file)...)
+        }
+    }
+    sort.Strings(result.Structs)
+
+    _, result.ImportPath, err = ImportPath(dir)
+    if err != nil {
+        return nil, err
+    }
+    return result, nil
+}
+
+// exportedStructs lists the exported non-generic struct types of file
+func exportedStructs(file *ast.File) []string {
+    var names []string
+    for _, decl := range file.Decls {
+        gen, ok := decl.(*ast.GenDecl)
+        if !ok || gen.Tok != token.TYPE {
+            continue
+        }
+        for _, spec := range gen.Specs {
+            typeSpec := spec.(*ast.TypeSpec)
+            if _, ok := typeSpec.Type.(*ast.StructType); ok && typeSpec.Name.IsExported() && typeSpec.TypeParams == nil {
+                names = append(names, typeSpec.Name.Name)
+            }
+        }
+    }
+    return names
+}
+
+// ImportPath returns the root of the module enclosing dir and the import
+// path of dir within it
+func ImportPath(dir string) (root, path string, err error) {
+    abs, err := filepath.Abs(dir)
+    if err != nil {
+        return "", "", err
+    }
+    for root = abs; ; root = filepath.Dir(root) {
+        modulePath, err := modulePathOf(filepath.Join(root, "go.mod"))
+        if err == nil {
+            rel, err := filepath.Rel(root, abs)
+            if err != nil {
+                return "", "", err
+            }
+            if rel == "." {
+                return root, modulePath, nil
+            }
+            return root, modulePath + "/" + filepath.ToSlash(rel), nil
+        }
+        if !os.IsNotExist(err) {
+            return "", "", err
+        }
+        if filepath.Dir(root) == root {
+            return "", "", fmt.Errorf("no go.mod found above %s", abs)
+        }
+    }
+}
+
+// modulePathOf reads the module directive of a go.mod file
+func modulePathOf(goMod string) (string, error) {
+    file, err := os.Open(goMod)
+    if err != nil {
+        return "", err
+    }
+    defer file.Close()
+
+    scanner := bufio.NewScanner(file)
+    for scanner.Scan() {
+        if path, ok := strings.CutPrefix(strings.TrimSpace(scanner.Text()), "module "); ok {
+            return strings.Trim(strings.TrimSpace(path), `"`), nil
+        }
+    }
+    if err := scanner.Err(); err != nil {
+        return "", err
+    }
+    return "", fmt.Errorf("%s has no module directive", goMod)
+}
+
+// libraryModule is the module path of this library, derived from the
+// reflection package so the shim imports follow a rename of the module.
+// The shim imports the library from the target module, which must require
+// it unless it is this module.
+var libraryModule = strings.TrimSuffix(reflect.TypeOf(reflection.Inspector{}).PkgPath(), "/pkg/reflection")
+
+// Generate renders the shim for pkg. Run with -json, it prints a JSON
+// report instead of text.
+func Generate(pkg *Package) ([]byte, error) {
+    if len(pkg.Structs) == 0 {
+        return nil, fmt.Errorf("package %s has no exported struct types", pkg.ImportPath)
+    }
+
+    var buf bytes.Buffer
+    fmt.Fprintf(&buf, "// Code generated by cmd/inspect. DO NOT EDIT.\n\n")
+    fmt.Fprintf(&buf, "package main\n\n")
+    fmt.Fprintf(&buf, "import (\n\"encoding/json\"\n\"fmt\"\n\"os\"\n\n")
+    fmt.Fprintf(&buf, "target %q\n%q\n%q\n)\n\n", pkg.ImportPath, libraryModule+"/pkg/logger", libraryModule+"/pkg/reflection")
+
+    fmt.Fprintf(&buf, "func main() {\nlogger.Initialize(false)\ninspector := reflection.NewInspector()\n\n")
+    fmt.Fprintf(&buf, "var infos []*reflection.StructInfo\nfor _, value := range []interface{}{\n")
+    for _, name := range pkg.Structs {
+        fmt.Fprintf(&buf, "&target.%s{},\n", name)
+    }
+    fmt.Fprintf(&buf, "} {\ninfo, err := inspector.InspectStruct(value)\nif err != nil {\nfmt.Fprintln(os.Stderr, err)\nos.Exit(1)\n}\ninfos = append(infos, info)\n}\n\n")
+
+    fmt.Fprintf(&buf, "// Registrations are unknown without a container\n")
+    fmt.Fprintf(&buf, "usage := reflection.AggregateTagUsage(infos, nil)\nusage.Unregistered = nil\n\n")
+
+    fmt.Fprintf(&buf, "if len(os.Args) > 1 && os.Args[1] == \"-json\" {\n")
+    fmt.Fprintf(&buf, "// Values JSON cannot encode, such as channels, are printed instead\n")
+    fmt.Fprintf(&buf, "for _, info := range infos {\nfor i := range info.Fields {\nif _, err := json.Marshal(info.Fields[i].Value); err != nil {\ninfo.Fields[i].Value = fmt.Sprint(info.Fields[i].Value)\n}\n}\n}\n")
+    fmt.Fprintf(&buf, "encoder := json.NewEncoder(os.Stdout)\nencoder.SetIndent(\"\", \"  \")\n")
+    fmt.Fprintf(&buf, "report := map[string]interface{}{\"package\": %q, \"structs\": infos, \"usage\": usage.Qualifiers}\n", pkg.ImportPath)
+    fmt.Fprintf(&buf, "if err := encoder.Encode(report); err != nil {\nfmt.Fprintln(os.Stderr, err)\nos.Exit(1)\n}\nreturn\n}\n\n")
+
+    fmt.Fprintf(&buf, "for _, info := range infos {\nfmt.Println(inspector.PrettyPrint(info))\n}\nfmt.Print(usage.Report())\n}\n")
+    return format.Source(buf.Bytes())
+}
+
+// Run inspects the package in dir, writing the report to out. The shim is
+// run with RunShim and removed afterwards.
+func Run(dir string, jsonOutput bool, out io.Writer) error {
+    pkg, err := Scan(dir)
+    if err != nil {
+        return err
+    }
+    code, err := Generate(pkg)
+    if err != nil {
+        return err
+    }
+
+    root, _, err := ImportPath(dir)
+    if err != nil {
+        return err
+    }
+    var args []string
+    if jsonOutput {
+        args = append(args, "-json")
+    }
+    return RunShim(root, pkg.ImportPath, code, args, out)
+}
+
+// RunShim runs the shim code with args from a temporary directory at the
+// module root, which the go tool ignores in ./... patterns, writing its
+// output to out. target names the inspected package in errors.
+func RunShim(root, target string, code []byte, args []string, out io.Writer) error {
+    shimDir, err := os.MkdirTemp(root, "_inspect-")
+    if err != nil {
+        return err
+    }
+    defer os.RemoveAll(shimDir)
+    if err := os.WriteFile(filepath.Join(shimDir, "main.go"), code, 0o644); err != nil {
+        return err
+    }
+
+    var stderr bytes.Buffer
+    cmd := exec.Command("go", append([]string{"run", "./" + filepath.Base(shimDir)}, args...)...)
+    cmd.Dir = root
+    cmd.Stdout = out
+    cmd.Stderr = &stderr
+    if err := cmd.Run(); err != nil {
+        return fmt.Errorf("inspection shim for %s failed: %w\n%s", target, err, stderr.String())
+    }
+    return nil
+}
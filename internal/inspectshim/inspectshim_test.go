@@ -0,0 +1,86 @@
+package inspectshim
+
+import (
+    "bytes"
+    "encoding/json"
+    "os"
+    "path/filepath"
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+func writeModule(t *testing.T, source string) string {
+    root := t.TempDir()
+    require.NoError(t, os.WriteFile(filepath.Join(root, "go.mod"), []byte("module example.com/app\n\ngo 1.22\n"), 0o644))
+    dir := filepath.Join(root, "internal", "handlers")
+    require.NoError(t, os.MkdirAll(dir, 0o755))
+    require.NoError(t, os.WriteFile(filepath.Join(dir, "handlers.go"), []byte(source), 0o644))
+    return dir
+}
+
+func TestScanAndGenerate(t *testing.T) {
+    dir := writeModule(t, `package handlers
+
+type Web struct {
+    Users interface{} `+"`di:\"userService\"`"+`
+}
+
+type Admin struct{}
+
+type hidden struct{}
+
+type Page[T any] struct{ Value T }
+
+type Handler interface{ Serve() }
+`)
+
+    pkg, err := Scan(dir)
+    require.NoError(t, err)
+    assert.Equal(t, &Package{
+        Name:       "handlers",
+        ImportPath: "example.com/app/internal/handlers",
+        Structs:    []string{"Admin", "Web"},
+    }, pkg)
+
+    code, err := Generate(pkg)
+    require.NoError(t, err)
+    assert.Contains(t, string(code), "// Code generated by cmd/inspect. DO NOT EDIT.")
+    assert.Contains(t, string(code), `target "example.com/app/internal/handlers"`)
+    assert.Contains(t, string(code), "&target.Admin{},\n\t\t&target.Web{},")
+    // The library is imported by its own module path, not the target's
+    assert.Contains(t, string(code), `"di-example/pkg/reflection"`)
+}
+
+func TestScanErrors(t *testing.T) {
+    _, err := Scan(writeModule(t, "package main\n\nfunc main() {}\n"))
+    assert.ErrorContains(t, err, "cannot inspect package main")
+
+    pkg, err := Scan(writeModule(t, "package handlers\n\ntype Handler interface{ Serve() }\n"))
+    require.NoError(t, err)
+    _, err = Generate(pkg)
+    assert.EqualError(t, err, "package example.com/app/internal/handlers has no exported struct types")
+}
+
+func TestRun(t *testing.T) {
+    if testing.Short() {
+        t.Skip("runs the go tool")
+    }
+
+    var out bytes.Buffer
+    require.NoError(t, Run("../models", false, &out))
+    assert.Contains(t, out.String(), "Struct: Injectable")
+    assert.Contains(t, out.String(), "  - userService: 1 references from 1 structs (Injectable)")
+    assert.NotContains(t, out.String(), "Unregistered")
+
+    out.Reset()
+    require.NoError(t, Run("../models", true, &out))
+    var report struct {
+        Package string
+        Structs []struct{ Name string }
+    }
+    require.NoError(t, json.Unmarshal(out.Bytes(), &report))
+    assert.Equal(t, "di-example/internal/models", report.Package)
+    assert.Equal(t, "Config", report.Structs[0].Name)
+}
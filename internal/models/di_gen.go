@@ -0,0 +1,13 @@
+// Code generated by digen. DO NOT EDIT.
+
+package models
+
+import "di-example/pkg/container"
+
+func init() {
+	container.DeclareReferences(
+		container.Reference{Qualifier: "configService", Site: "models.Injectable.ConfigService", Optional: false},
+		container.Reference{Qualifier: "emailService", Site: "models.Injectable.EmailService", Optional: false},
+		container.Reference{Qualifier: "userService", Site: "models.Injectable.UserService", Optional: false},
+	)
+}
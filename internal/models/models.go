@@ -1,5 +1,7 @@
 package models
 
+//go:generate go run di-example/cmd/digen -dir .
+
 // User represents a basic user in the system
 type User struct {
     ID    int
@@ -9,9 +11,9 @@ type User struct {
 
 // Config represents application configuration
 type Config struct {
-    Environment string
-    Debug       bool
-    APIKey      string
+    Environment string `json:"environment" default:"development"`
+    Debug       bool   `json:"debug,omitempty"`
+    APIKey      string `json:"apiKey"`
 }
 
 // Injectable is a struct that will demonstrate dependency injection
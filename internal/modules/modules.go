@@ -0,0 +1,144 @@
+// Package modules bundles the application's registrations into container
+// modules. Every module has a test variant registering the same qualifiers
+// with in-memory implementations, selected by the "test" profile, so
+// integration tests reuse the production wiring.
+package modules
+
+import (
+    "context"
+    "database/sql"
+    "fmt"
+    "sync"
+
+    "di-example/internal/modules/sqlfake"
+    "di-example/internal/services"
+    "di-example/pkg/container"
+)
+
+// TestProfile is the container profile selecting the test variants
+const TestProfile = "test"
+
+// Qualifiers registered by the modules
+const (
+    DatabaseQualifier = "database"
+    EmailQualifier    = "emailService"
+    RecorderQualifier = "database.recorder"
+    TxQualifier       = "database.tx"
+)
+
+// Database opens a *sql.DB with the given driver and DSN and closes it when
+// the container stops
+func Database(driverName, dsn string) container.Module {
+    return container.Module{
+        Name:     "database",
+        Profiles: []string{"!" + TestProfile},
+        Setup: func(c *container.Container) error {
+            db, err := sql.Open(driverName, dsn)
+            if err != nil {
+                return fmt.Errorf("failed to open database: %w", err)
+            }
+            return registerDatabase(c, db)
+        },
+    }
+}
+
+// DatabaseTest registers an in-memory sqlfake database under the same
+// qualifier, plus its recorder so tests can assert executed statements
+func DatabaseTest() container.Module {
+    return container.Module{
+        Name:     "database-test",
+        Profiles: []string{TestProfile},
+        Setup: func(c *container.Container) error {
+            db, recorder, err := sqlfake.Open()
+            if err != nil {
+                return err
+            }
+            if err := c.Register(RecorderQualifier, recorder); err != nil {
+                return err
+            }
+            return registerDatabase(c, db)
+        },
+    }
+}
+
+// registerDatabase registers db and closes it when the container stops
+func registerDatabase(c *container.Container, db *sql.DB) error {
+    if err := c.Register(DatabaseQualifier, db, container.InStage(container.StageInfrastructure)); err != nil {
+        return err
+    }
+    c.Append(container.Hook{
+        Name:  "database",
+        Stage: container.StageInfrastructure,
+        OnStart: func(ctx context.Context) error {
+            return db.PingContext(ctx)
+        },
+        OnStop: func(ctx context.Context) error {
+            return db.Close()
+        },
+    })
+    return nil
+}
+
+// Email registers the SMTP email service
+func Email() container.Module {
+    return container.Module{
+        Name:     "email",
+        Profiles: []string{"!" + TestProfile},
+        Setup: func(c *container.Container) error {
+            return c.Register(EmailQualifier, services.NewEmailService())
+        },
+    }
+}
+
+// EmailTest registers a RecordingEmailService under the email qualifier
+func EmailTest() container.Module {
+    return container.Module{
+        Name:     "email-test",
+        Profiles: []string{TestProfile},
+        Setup: func(c *container.Container) error {
+            return c.Register(EmailQualifier, &RecordingEmailService{})
+        },
+    }
+}
+
+// All returns every module with its test variant; the container profile
+// decides which of each pair is installed
+func All(driverName, dsn string) []container.Module {
+    return []container.Module{
+        Database(driverName, dsn),
+        DatabaseTest(),
+        Email(),
+        EmailTest(),
+        Tx(),
+    }
+}
+
+// EmailMessage is a message captured by RecordingEmailService
+type EmailMessage struct {
+    To      string
+    Message string
+}
+
+// RecordingEmailService implements services.EmailService by recording
+// messages instead of sending them
+type RecordingEmailService struct {
+    mu   sync.Mutex
+    sent []EmailMessage
+}
+
+var _ services.EmailService = (*RecordingEmailService)(nil)
+
+// SendEmail records the message
+func (r *RecordingEmailService) SendEmail(to, message string) error {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+    r.sent = append(r.sent, EmailMessage{To: to, Message: message})
+    return nil
+}
+
+// Sent returns the recorded messages in order
+func (r *RecordingEmailService) Sent() []EmailMessage {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+    return append([]EmailMessage(nil), r.sent...)
+}
@@ -0,0 +1,82 @@
+package modules
+
+import (
+    "context"
+    "database/sql"
+    "testing"
+
+    "di-example/internal/modules/sqlfake"
+    "di-example/internal/services"
+    "di-example/pkg/container"
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+func TestTestProfileWiring(t *testing.T) {
+    c := container.NewContainer()
+    c.SetProfile(TestProfile)
+    require.NoError(t, c.Install(All("postgres", "postgres://unused")...))
+    require.NoError(t, c.Start(context.Background()))
+    defer c.Stop(context.Background())
+
+    // Consumers see the same qualifiers as in production
+    target := &struct {
+        DB    *sql.DB               `di:"database"`
+        Email services.EmailService `di:"emailService"`
+    }{}
+    require.NoError(t, c.InjectStruct(target))
+
+    _, err := target.DB.Exec("INSERT INTO users(name) VALUES (?)", "gopher")
+    require.NoError(t, err)
+    require.NoError(t, target.Email.SendEmail("gopher@example.com", "hi"))
+
+    recorder, err := c.Resolve(RecorderQualifier)
+    require.NoError(t, err)
+    assert.Equal(t, []string{"INSERT INTO users(name) VALUES (?)"}, recorder.(*sqlfake.Recorder).Statements())
+
+    email := target.Email.(*RecordingEmailService)
+    assert.Equal(t, []EmailMessage{{To: "gopher@example.com", Message: "hi"}}, email.Sent())
+}
+
+func TestDefaultProfileWiring(t *testing.T) {
+    c := container.NewContainer()
+    require.NoError(t, c.Install(All(sqlfake.DriverName, "not-opened")...))
+
+    service, err := c.Resolve(EmailQualifier)
+    require.NoError(t, err)
+    _, isFake := service.(*RecordingEmailService)
+    assert.False(t, isFake)
+
+    _, err = c.Resolve(RecorderQualifier)
+    assert.Error(t, err)
+
+    // The production database uses the given driver and DSN
+    _, err = c.Resolve(DatabaseQualifier)
+    require.NoError(t, err)
+    assert.Error(t, c.Start(context.Background()))
+}
+
+func TestSQLFakeTransactions(t *testing.T) {
+    db, recorder, err := sqlfake.Open()
+    require.NoError(t, err)
+    defer db.Close()
+
+    tx, err := db.Begin()
+    require.NoError(t, err)
+    _, err = tx.Exec("UPDATE users SET name = ?", "x")
+    require.NoError(t, err)
+    require.NoError(t, tx.Commit())
+
+    tx, err = db.Begin()
+    require.NoError(t, err)
+    require.NoError(t, tx.Rollback())
+
+    rows, err := db.Query("SELECT 1")
+    require.NoError(t, err)
+    assert.False(t, rows.Next())
+    require.NoError(t, rows.Close())
+
+    assert.Equal(t, 1, recorder.Commits())
+    assert.Equal(t, 1, recorder.Rollbacks())
+    assert.Equal(t, []string{"BEGIN", "UPDATE users SET name = ?", "COMMIT", "BEGIN", "ROLLBACK", "SELECT 1"}, recorder.Statements())
+}
@@ -0,0 +1,144 @@
+package modules
+
+import (
+    "bufio"
+    "errors"
+    "fmt"
+    "net/http"
+    "os"
+    "strings"
+
+    "di-example/pkg/container"
+)
+
+// RouterQualifier is the qualifier of the *http.ServeMux built by Router
+const RouterQualifier = "http.router"
+
+// Route maps a ServeMux pattern to the qualifier of its handler
+type Route struct {
+    Pattern string // e.g. "GET /users/{id}" or "/healthz"
+    Handler string
+    Line    int // Line of the route in the routes file
+}
+
+// Router registers an *http.ServeMux under RouterQualifier built from a
+// routes file, one route per line:
+//
+//	# method and path, then the handler qualifier
+//	GET  /users/{id}  users.show
+//	POST /users       users.create
+//	/healthz          health
+//
+// Handlers are resolved from the container and must be an http.Handler or
+// a func(http.ResponseWriter, *http.Request). The file is parsed when the
+// module is installed; the router is built, and every handler checked,
+// when the container is built or started.
+func Router(routesFile string) container.Module {
+    return container.Module{
+        Name: "router",
+        Setup: func(c *container.Container) error {
+            routes, err := ReadRoutes(routesFile)
+            if err != nil {
+                return err
+            }
+
+            handlers := make([]string, 0, len(routes))
+            for _, route := range routes {
+                handlers = append(handlers, route.Handler)
+            }
+            return c.Provide(RouterQualifier, func(c *container.Container) (*http.ServeMux, error) {
+                return buildRouter(c, routes)
+            }, container.DependsOn(handlers...))
+        },
+    }
+}
+
+// ReadRoutes parses a routes file. Blank lines and lines starting with #
+// are skipped; duplicate patterns are rejected.
+func ReadRoutes(path string) ([]Route, error) {
+    file, err := os.Open(path)
+    if err != nil {
+        return nil, fmt.Errorf("failed to open routes file: %w", err)
+    }
+    defer file.Close()
+
+    var routes []Route
+    seen := make(map[string]int)
+    scanner := bufio.NewScanner(file)
+    for line := 1; scanner.Scan(); line++ {
+        fields := strings.Fields(scanner.Text())
+        if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+            continue
+        }
+
+        var route Route
+        switch len(fields) {
+        case 2:
+            route = Route{Pattern: fields[0], Handler: fields[1], Line: line}
+        case 3:
+            route = Route{Pattern: fields[0] + " " + fields[1], Handler: fields[2], Line: line}
+        default:
+            return nil, fmt.Errorf("%s:%d: want [METHOD] PATH HANDLER, got %q", path, line, scanner.Text())
+        }
+        if !strings.HasPrefix(route.Pattern[strings.LastIndex(route.Pattern, " ")+1:], "/") {
+            return nil, fmt.Errorf("%s:%d: path must start with /", path, line)
+        }
+        if first, dup := seen[route.Pattern]; dup {
+            return nil, fmt.Errorf("%s:%d: route %s already defined on line %d", path, line, route.Pattern, first)
+        }
+        seen[route.Pattern] = line
+        routes = append(routes, route)
+    }
+    if err := scanner.Err(); err != nil {
+        return nil, fmt.Errorf("failed to read routes file: %w", err)
+    }
+    return routes, nil
+}
+
+// buildRouter resolves the handler of every route and registers it on a new
+// ServeMux. Every route is checked; the errors are joined.
+func buildRouter(c *container.Container, routes []Route) (*http.ServeMux, error) {
+    mux := http.NewServeMux()
+    var errs []error
+    for _, route := range routes {
+        handler, err := routeHandler(c, route)
+        if err == nil {
+            err = handle(mux, route.Pattern, handler)
+        }
+        if err != nil {
+            errs = append(errs, fmt.Errorf("route %s (line %d): %w", route.Pattern, route.Line, err))
+        }
+    }
+    if err := errors.Join(errs...); err != nil {
+        return nil, err
+    }
+    return mux, nil
+}
+
+// routeHandler resolves the handler of route and checks its signature
+func routeHandler(c *container.Container, route Route) (http.Handler, error) {
+    service, err := c.Resolve(route.Handler)
+    if err != nil {
+        return nil, err
+    }
+
+    switch handler := service.(type) {
+    case http.Handler:
+        return handler, nil
+    case func(http.ResponseWriter, *http.Request):
+        return http.HandlerFunc(handler), nil
+    }
+    return nil, fmt.Errorf("handler %s is a %T, not an http.Handler or func(http.ResponseWriter, *http.Request)", route.Handler, service)
+}
+
+// handle registers handler, turning the panic ServeMux raises for invalid
+// or conflicting patterns into an error
+func handle(mux *http.ServeMux, pattern string, handler http.Handler) (err error) {
+    defer func() {
+        if r := recover(); r != nil {
+            err = fmt.Errorf("%v", r)
+        }
+    }()
+    mux.Handle(pattern, handler)
+    return nil
+}
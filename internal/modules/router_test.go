@@ -0,0 +1,95 @@
+package modules
+
+import (
+    "context"
+    "net/http"
+    "net/http/httptest"
+    "os"
+    "path/filepath"
+    "testing"
+
+    "di-example/pkg/container"
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+// writeRoutes writes a routes file to a temporary directory
+func writeRoutes(t *testing.T, content string) string {
+    path := filepath.Join(t.TempDir(), "routes")
+    require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+    return path
+}
+
+// textHandler replies with a fixed body
+type textHandler string
+
+func (h textHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+    _, _ = w.Write([]byte(h))
+}
+
+func TestRouterServesRoutesFromFile(t *testing.T) {
+    routes := writeRoutes(t, `
+# users
+GET  /users/{id}  users.show
+/healthz          health
+`)
+
+    c := container.NewContainer()
+    require.NoError(t, c.Register("users.show", textHandler("user")))
+    require.NoError(t, c.Register("health", func(w http.ResponseWriter, r *http.Request) {
+        _, _ = w.Write([]byte("ok"))
+    }))
+    require.NoError(t, c.Install(Router(routes)))
+    require.NoError(t, c.Start(context.Background()))
+    defer c.Stop(context.Background())
+
+    router, err := container.ResolveAs[*http.ServeMux](c, RouterQualifier)
+    require.NoError(t, err)
+
+    for path, body := range map[string]string{"/users/7": "user", "/healthz": "ok"} {
+        recorder := httptest.NewRecorder()
+        router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+        assert.Equal(t, body, recorder.Body.String(), path)
+    }
+
+    recorder := httptest.NewRecorder()
+    router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/users/7", nil))
+    assert.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+}
+
+func TestRouterChecksHandlersOnBuild(t *testing.T) {
+    routes := writeRoutes(t, "GET /users users.list\nGET /orders orders.list\n")
+
+    c := container.NewContainer()
+    require.NoError(t, c.Register("users.list", "not a handler"))
+    require.NoError(t, c.Install(Router(routes)))
+
+    err := c.Build()
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "route GET /users (line 1): handler users.list is a string, not an http.Handler")
+    assert.Contains(t, err.Error(), "route GET /orders (line 2): no service found for qualifier: orders.list")
+}
+
+func TestReadRoutesErrors(t *testing.T) {
+    tests := []struct {
+        name    string
+        content string
+        want    string
+    }{
+        {name: "too many fields", content: "GET /users users extra\n", want: ":1: want [METHOD] PATH HANDLER"},
+        {name: "missing handler", content: "/users\n", want: ":1: want [METHOD] PATH HANDLER"},
+        {name: "relative path", content: "GET users users.list\n", want: ":1: path must start with /"},
+        {name: "duplicate", content: "GET /users a\n\nGET /users b\n", want: ":3: route GET /users already defined on line 1"},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            _, err := ReadRoutes(writeRoutes(t, tt.content))
+            require.Error(t, err)
+            assert.Contains(t, err.Error(), tt.want)
+        })
+    }
+
+    _, err := ReadRoutes(filepath.Join(t.TempDir(), "missing"))
+    assert.ErrorContains(t, err, "failed to open routes file")
+}
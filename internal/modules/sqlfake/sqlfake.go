@@ -0,0 +1,166 @@
+// Package sqlfake is an in-memory database/sql driver for tests. It accepts
+// every statement, returns empty result sets and records what was executed,
+// so modules can be wired against a real *sql.DB without a database server.
+package sqlfake
+
+import (
+    "context"
+    "database/sql"
+    "database/sql/driver"
+    "fmt"
+    "io"
+    "sync"
+    "sync/atomic"
+)
+
+// DriverName is the name the driver is registered under with database/sql
+const DriverName = "sqlfake"
+
+// Recorder collects the activity of one fake database
+type Recorder struct {
+    mu         sync.Mutex
+    statements []string
+    commits    int
+    rollbacks  int
+}
+
+// Statements returns the executed statements in order
+func (r *Recorder) Statements() []string {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+    return append([]string(nil), r.statements...)
+}
+
+// Commits returns the number of committed transactions
+func (r *Recorder) Commits() int {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+    return r.commits
+}
+
+// Rollbacks returns the number of rolled back transactions
+func (r *Recorder) Rollbacks() int {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+    return r.rollbacks
+}
+
+func (r *Recorder) record(query string) {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+    r.statements = append(r.statements, query)
+}
+
+var (
+    recordersMu sync.Mutex
+    recorders   = make(map[string]*Recorder)
+    nextID      int64
+)
+
+func init() {
+    sql.Register(DriverName, fakeDriver{})
+}
+
+// Open returns a new fake database and the recorder observing it
+func Open() (*sql.DB, *Recorder, error) {
+    dsn := fmt.Sprintf("sqlfake-%d", atomic.AddInt64(&nextID, 1))
+    recorder := &Recorder{}
+
+    recordersMu.Lock()
+    recorders[dsn] = recorder
+    recordersMu.Unlock()
+
+    db, err := sql.Open(DriverName, dsn)
+    if err != nil {
+        return nil, nil, err
+    }
+    return db, recorder, nil
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(dsn string) (driver.Conn, error) {
+    recordersMu.Lock()
+    defer recordersMu.Unlock()
+
+    recorder, ok := recorders[dsn]
+    if !ok {
+        return nil, fmt.Errorf("sqlfake: unknown dsn %q, use sqlfake.Open", dsn)
+    }
+    return &fakeConn{recorder: recorder}, nil
+}
+
+type fakeConn struct {
+    recorder *Recorder
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+    return &fakeStmt{conn: c, query: query}, nil
+}
+
+func (c *fakeConn) Close() error {
+    return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+    c.recorder.record("BEGIN")
+    return &fakeTx{recorder: c.recorder}, nil
+}
+
+func (c *fakeConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+    c.recorder.record(query)
+    return driver.RowsAffected(1), nil
+}
+
+func (c *fakeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+    c.recorder.record(query)
+    return emptyRows{}, nil
+}
+
+func (c *fakeConn) Ping(ctx context.Context) error {
+    return nil
+}
+
+type fakeStmt struct {
+    conn  *fakeConn
+    query string
+}
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+    s.conn.recorder.record(s.query)
+    return driver.RowsAffected(1), nil
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+    s.conn.recorder.record(s.query)
+    return emptyRows{}, nil
+}
+
+type fakeTx struct {
+    recorder *Recorder
+}
+
+func (t *fakeTx) Commit() error {
+    t.recorder.mu.Lock()
+    defer t.recorder.mu.Unlock()
+    t.recorder.commits++
+    t.recorder.statements = append(t.recorder.statements, "COMMIT")
+    return nil
+}
+
+func (t *fakeTx) Rollback() error {
+    t.recorder.mu.Lock()
+    defer t.recorder.mu.Unlock()
+    t.recorder.rollbacks++
+    t.recorder.statements = append(t.recorder.statements, "ROLLBACK")
+    return nil
+}
+
+type emptyRows struct{}
+
+func (emptyRows) Columns() []string              { return nil }
+func (emptyRows) Close() error                   { return nil }
+func (emptyRows) Next(dest []driver.Value) error { return io.EOF }
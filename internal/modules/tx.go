@@ -0,0 +1,74 @@
+package modules
+
+import (
+    "context"
+    "database/sql"
+    "fmt"
+
+    "di-example/pkg/container"
+)
+
+// Tx registers a scoped *sql.Tx under TxQualifier. The first resolution in
+// a scope begins a transaction on the database module's *sql.DB; closing
+// the scope commits it, or rolls it back when the scope closes with an
+// error. Scoped repositories receive the transaction through a di tag:
+//
+//	type OrderRepository struct {
+//	    Tx *sql.Tx `di:"database.tx"`
+//	}
+//
+// Tx works with both Database and DatabaseTest.
+func Tx() container.Module {
+    return container.Module{
+        Name: "tx",
+        Setup: func(c *container.Container) error {
+            return c.RegisterScoped(TxQualifier, beginTx)
+        },
+    }
+}
+
+// beginTx begins the transaction of a scope and ends it when the scope closes
+func beginTx(s *container.Scope) (interface{}, error) {
+    service, err := s.Resolve(DatabaseQualifier)
+    if err != nil {
+        return nil, err
+    }
+    db, ok := service.(*sql.DB)
+    if !ok {
+        return nil, fmt.Errorf("%s is a %T, not a *sql.DB", DatabaseQualifier, service)
+    }
+
+    tx, err := db.BeginTx(s.Context(), nil)
+    if err != nil {
+        return nil, fmt.Errorf("failed to begin transaction: %w", err)
+    }
+    s.OnClose(func(outcome error) error {
+        if outcome != nil {
+            return tx.Rollback()
+        }
+        return tx.Commit()
+    })
+    return tx, nil
+}
+
+// UnitOfWork runs work in a new scope and closes it with work's error, so
+// a transaction begun in the scope commits only if work succeeds. It
+// returns work's error, or else the error of closing the scope. If work
+// panics, the scope is closed as failed, rolling the transaction back, and
+// the panic continues.
+func UnitOfWork(ctx context.Context, c *container.Container, work func(s *container.Scope) error) (err error) {
+    scope, err := c.OpenScope(ctx)
+    if err != nil {
+        return err
+    }
+    defer func() {
+        if recovered := recover(); recovered != nil {
+            scope.Close(fmt.Errorf("unit of work panicked: %v", recovered))
+            panic(recovered)
+        }
+        if closeErr := scope.Close(err); err == nil {
+            err = closeErr
+        }
+    }()
+    return work(scope)
+}
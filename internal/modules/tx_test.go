@@ -0,0 +1,109 @@
+package modules
+
+import (
+    "context"
+    "database/sql"
+    "errors"
+    "testing"
+
+    "di-example/internal/modules/sqlfake"
+    "di-example/pkg/container"
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+// orderRepository is a scoped repository sharing the scope's transaction
+type orderRepository struct {
+    Tx *sql.Tx `di:"database.tx"`
+}
+
+func (r *orderRepository) Insert(name string) error {
+    _, err := r.Tx.Exec("INSERT INTO orders(name) VALUES (?)", name)
+    return err
+}
+
+// newTxContainer wires the test database, the tx module and the repository
+func newTxContainer(t *testing.T) (*container.Container, *sqlfake.Recorder) {
+    c := container.NewContainer()
+    c.SetProfile(TestProfile)
+    require.NoError(t, c.Install(All("postgres", "postgres://unused")...))
+    require.NoError(t, c.RegisterScoped("orders", func(s *container.Scope) (interface{}, error) {
+        repo := &orderRepository{}
+        return repo, s.InjectStruct(repo)
+    }))
+
+    recorder, err := c.Resolve(RecorderQualifier)
+    require.NoError(t, err)
+    return c, recorder.(*sqlfake.Recorder)
+}
+
+func TestUnitOfWork_Commits(t *testing.T) {
+    c, recorder := newTxContainer(t)
+
+    err := UnitOfWork(context.Background(), c, func(s *container.Scope) error {
+        orders, err := s.Resolve("orders")
+        if err != nil {
+            return err
+        }
+        tx, err := s.Resolve(TxQualifier)
+        if err != nil {
+            return err
+        }
+        assert.Same(t, tx, orders.(*orderRepository).Tx) // One transaction per scope
+        return orders.(*orderRepository).Insert("book")
+    })
+    require.NoError(t, err)
+
+    assert.Equal(t, 1, recorder.Commits())
+    assert.Equal(t, 0, recorder.Rollbacks())
+    assert.Equal(t, []string{"BEGIN", "INSERT INTO orders(name) VALUES (?)", "COMMIT"}, recorder.Statements())
+}
+
+func TestUnitOfWork_RollsBackOnError(t *testing.T) {
+    c, recorder := newTxContainer(t)
+    failure := errors.New("payment declined")
+
+    err := UnitOfWork(context.Background(), c, func(s *container.Scope) error {
+        orders, err := s.Resolve("orders")
+        if err != nil {
+            return err
+        }
+        if err := orders.(*orderRepository).Insert("book"); err != nil {
+            return err
+        }
+        return failure
+    })
+    assert.Same(t, failure, err)
+    assert.Equal(t, 0, recorder.Commits())
+    assert.Equal(t, 1, recorder.Rollbacks())
+}
+
+func TestUnitOfWork_RollsBackOnPanic(t *testing.T) {
+    c, recorder := newTxContainer(t)
+
+    assert.PanicsWithValue(t, "out of stock", func() {
+        _ = UnitOfWork(context.Background(), c, func(s *container.Scope) error {
+            orders, err := s.Resolve("orders")
+            if err != nil {
+                return err
+            }
+            if err := orders.(*orderRepository).Insert("book"); err != nil {
+                return err
+            }
+            panic("out of stock")
+        })
+    })
+    assert.Equal(t, 0, recorder.Commits())
+    assert.Equal(t, 1, recorder.Rollbacks())
+}
+
+func TestTx_ScopedOnly(t *testing.T) {
+    c, recorder := newTxContainer(t)
+
+    _, err := c.Resolve(TxQualifier)
+    assert.ErrorContains(t, err, "is scoped")
+
+    // Scopes that never touch the database do not begin transactions
+    require.NoError(t, UnitOfWork(context.Background(), c, func(s *container.Scope) error { return nil }))
+    assert.Empty(t, recorder.Statements())
+}
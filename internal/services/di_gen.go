@@ -0,0 +1,20 @@
+// Code generated by digen. DO NOT EDIT.
+
+package services
+
+import "di-example/pkg/container"
+
+// RegisterProviders registers every constructor annotated with //di:provide
+// and every interface annotated with //di:compose
+func RegisterProviders(c *container.Container) error {
+	if err := c.Register("configService", NewConfigService()); err != nil {
+		return err
+	}
+	if err := c.Register("emailService", NewEmailService()); err != nil {
+		return err
+	}
+	if err := c.Register("userService", NewUserService()); err != nil {
+		return err
+	}
+	return nil
+}
@@ -1,8 +1,11 @@
 package services
 
+//go:generate go run di-example/cmd/digen -dir .
+
 import (
     "fmt"
     "di-example/pkg/logger"
+    "go.uber.org/zap"
 )
 
 // Interfaces remain the same
@@ -21,18 +24,24 @@ type ConfigService interface {
 // UserService implementation
 type userService struct {
     prefix string
+    log    *zap.SugaredLogger
 }
 
+//di:provide qualifier=userService singleton
 func NewUserService() UserService {
-    log := logger.Get()
+    return NewUserServiceWithLogger(logger.Get())
+}
+
+// NewUserServiceWithLogger returns a UserService logging to log, such as a
+// logger scoped to the trace of a request
+func NewUserServiceWithLogger(log *zap.SugaredLogger) UserService {
     log.Infow("Creating new UserService", "prefix", "USER-")
-    return &userService{prefix: "USER-"}
+    return &userService{prefix: "USER-", log: log}
 }
 
 func (s *userService) GetUser(id int) string {
-    log := logger.Get()
     result := fmt.Sprintf("%s%d", s.prefix, id)
-    log.Infow("Getting user",
+    s.log.Infow("Getting user",
         "id", id,
         "prefix", s.prefix,
         "result", result)
@@ -42,24 +51,28 @@ func (s *userService) GetUser(id int) string {
 // EmailService implementation
 type emailService struct {
     server string
+    log    *zap.SugaredLogger
 }
 
+//di:provide qualifier=emailService singleton
 func NewEmailService() EmailService {
-    log := logger.Get()
+    return NewEmailServiceWithLogger(logger.Get())
+}
+
+// NewEmailServiceWithLogger returns an EmailService logging to log, such as
+// a logger scoped to the trace of a request
+func NewEmailServiceWithLogger(log *zap.SugaredLogger) EmailService {
     log.Infow("Creating new EmailService", "server", "smtp.example.com")
-    return &emailService{server: "smtp.example.com"}
+    return &emailService{server: "smtp.example.com", log: log}
 }
 
 func (s *emailService) SendEmail(to, message string) error {
-    log := logger.Get()
-    log.Infow("Sending email",
+    s.log.Infow("Sending email",
         "to", to,
         "server", s.server,
-        "messageLength", len(message))
-
-    fmt.Printf("Sending email to %s via %s: %s\n", to, s.server, message)
+        "message", message)
 
-    log.Infow("Email sent successfully",
+    s.log.Infow("Email sent successfully",
         "to", to,
         "server", s.server)
     return nil
@@ -70,6 +83,7 @@ type configService struct {
     env string
 }
 
+//di:provide qualifier=configService singleton
 func NewConfigService() ConfigService {
     log := logger.Get()
     log.Infow("Creating new ConfigService", "environment", "development")
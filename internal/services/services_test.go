@@ -2,6 +2,7 @@ package services
 
 import (
     "testing"
+    "di-example/pkg/container"
     "github.com/stretchr/testify/assert"
     "github.com/stretchr/testify/require"
     "strings"
@@ -126,4 +127,16 @@ func TestConfigService_GetConfig(t *testing.T) {
     // Test result format and content
     assert.True(t, strings.HasPrefix(result, "Environment:"))
     assert.Contains(t, result, "development")
-}
\ No newline at end of file
+}
+
+func TestRegisterProviders(t *testing.T) {
+    c := container.NewContainer()
+    require.NoError(t, RegisterProviders(c))
+
+    assert.Equal(t, []string{"configService", "emailService", "userService"}, c.Qualifiers())
+
+    service, err := c.Resolve("userService")
+    require.NoError(t, err)
+    _, ok := service.(UserService)
+    assert.True(t, ok)
+}
@@ -0,0 +1,187 @@
+// Package structgen builds random struct types and values from fuzz input,
+// so reflection code can be fuzzed over struct shapes instead of a fixed
+// set of test structs. Every byte sequence yields a valid type; running out
+// of input reads zeros.
+package structgen
+
+import (
+    "fmt"
+    "math"
+    "reflect"
+)
+
+// Node is a recursive type, for self-referencing values
+type Node struct {
+    Name string
+    Next *Node
+}
+
+// Config describes what Generate may put in a struct
+type Config struct {
+    Types     []reflect.Type // Field types to choose from, besides the built-in ones
+    Embedded  []reflect.Type // Named types that may be embedded, e.g. marker types
+    TagKeys   []string       // Struct tag keys to choose from, e.g. "di"
+    TagValues []string       // Tag values to choose from besides random bytes
+    MaxFields int            // Fields per struct, 8 when zero
+    MaxDepth  int            // Nesting of struct fields, 32 when zero
+}
+
+// builtinTypes are field types every generated struct may use
+var builtinTypes = []reflect.Type{
+    reflect.TypeOf(0),
+    reflect.TypeOf(""),
+    reflect.TypeOf(0.0),
+    reflect.TypeOf(false),
+    reflect.TypeOf((*interface{})(nil)).Elem(),
+    reflect.TypeOf(map[string]interface{}{}),
+    reflect.TypeOf([]interface{}{}),
+    reflect.TypeOf(&Node{}),
+    reflect.TypeOf(Node{}),
+    reflect.TypeOf((**int)(nil)),
+    reflect.TypeOf(map[int]string{}),
+    reflect.TypeOf(make(chan int)),
+    reflect.TypeOf(func() {}),
+}
+
+// reader hands out the fuzz input one byte at a time
+type reader struct {
+    data []byte
+}
+
+func (r *reader) next() byte {
+    if len(r.data) == 0 {
+        return 0
+    }
+    b := r.data[0]
+    r.data = r.data[1:]
+    return b
+}
+
+// text reads a length byte, then up to that many bytes
+func (r *reader) text(max int) string {
+    n := int(r.next()) % (max + 1)
+    if n > len(r.data) {
+        n = len(r.data)
+    }
+    s := string(r.data[:n])
+    r.data = r.data[n:]
+    return s
+}
+
+// Generate returns a pointer to a new value of a struct type described by
+// data. Fields are filled with awkward values: NaN, cyclic maps, slices and
+// pointers, and deeply nested structs.
+func Generate(data []byte, config Config) interface{} {
+    if config.MaxFields == 0 {
+        config.MaxFields = 8
+    }
+    if config.MaxDepth == 0 {
+        config.MaxDepth = 32
+    }
+    r := &reader{data: data}
+    structType := generateType(r, config, 0)
+    value := reflect.New(structType)
+    fill(r, value.Elem(), 0)
+    return value.Interface()
+}
+
+// generateType builds a struct type at the given nesting depth
+func generateType(r *reader, config Config, depth int) reflect.Type {
+    types := append(append([]reflect.Type(nil), builtinTypes...), config.Types...)
+    count := int(r.next()) % (config.MaxFields + 1)
+
+    var fields []reflect.StructField
+    embedded := make(map[string]bool)
+    for i := 0; i < count; i++ {
+        field := reflect.StructField{Name: fmt.Sprintf("F%d", i)}
+        switch choice := r.next() % 8; {
+        case choice == 0 && len(config.Embedded) > 0:
+            // Each named type can be embedded once
+            field.Type = config.Embedded[int(r.next())%len(config.Embedded)]
+            if embedded[field.Type.Name()] {
+                continue
+            }
+            embedded[field.Type.Name()] = true
+            field.Name = field.Type.Name()
+            field.Anonymous = true
+        case choice == 1 && depth < config.MaxDepth:
+            field.Type = generateType(r, config, depth+1)
+        case choice == 2:
+            field.Type = reflect.PointerTo(types[int(r.next())%len(types)])
+        default:
+            field.Type = types[int(r.next())%len(types)]
+        }
+        field.Tag = generateTag(r, config)
+        fields = append(fields, field)
+    }
+    return reflect.StructOf(fields)
+}
+
+// generateTag returns a well-formed tag, a tag with a random value or
+// random bytes
+func generateTag(r *reader, config Config) reflect.StructTag {
+    key := "di"
+    if len(config.TagKeys) > 0 {
+        key = config.TagKeys[int(r.next())%len(config.TagKeys)]
+    }
+
+    switch r.next() % 4 {
+    case 0:
+        return ""
+    case 1:
+        if len(config.TagValues) > 0 {
+            return reflect.StructTag(fmt.Sprintf("%s:%q", key, config.TagValues[int(r.next())%len(config.TagValues)]))
+        }
+        fallthrough
+    case 2:
+        return reflect.StructTag(fmt.Sprintf("%s:%q", key, r.text(24)))
+    default:
+        return reflect.StructTag(r.text(32))
+    }
+}
+
+// fill sets the settable fields of a struct value
+func fill(r *reader, value reflect.Value, depth int) {
+    for i := 0; i < value.NumField(); i++ {
+        field := value.Field(i)
+        if !field.CanSet() || r.next()%2 == 0 {
+            continue
+        }
+
+        switch field.Kind() {
+        case reflect.Float64:
+            field.SetFloat([]float64{math.NaN(), math.Inf(1), math.Inf(-1), -0.0}[r.next()%4])
+        case reflect.String:
+            field.SetString(r.text(16))
+        case reflect.Int:
+            field.SetInt(int64(int8(r.next())))
+        case reflect.Map:
+            if field.Type() == reflect.TypeOf(map[string]interface{}{}) {
+                cyclic := map[string]interface{}{}
+                cyclic["self"] = cyclic
+                field.Set(reflect.ValueOf(cyclic))
+            }
+        case reflect.Slice:
+            if field.Type() == reflect.TypeOf([]interface{}{}) {
+                cyclic := make([]interface{}, 1)
+                cyclic[0] = cyclic
+                field.Set(reflect.ValueOf(cyclic))
+            }
+        case reflect.Ptr:
+            if field.Type() == reflect.TypeOf(&Node{}) {
+                node := &Node{Name: "loop"}
+                node.Next = node
+                field.Set(reflect.ValueOf(node))
+            }
+        case reflect.Interface:
+            if field.NumMethod() > 0 {
+                continue
+            }
+            field.Set(reflect.ValueOf(math.NaN()))
+        case reflect.Struct:
+            if depth < 64 {
+                fill(r, field, depth+1)
+            }
+        }
+    }
+}
@@ -0,0 +1,179 @@
+// Package tracing propagates request trace IDs through context and provides
+// loggers scoped to the current trace, so log lines emitted by different
+// injected services can be correlated. Its middleware opens a container
+// scope per request; the scoped services of Module log through the scope's
+// trace logger.
+package tracing
+
+import (
+    "context"
+    "crypto/rand"
+    "encoding/hex"
+    "fmt"
+    "net/http"
+
+    "di-example/internal/services"
+    "di-example/pkg/container"
+    "di-example/pkg/logger"
+    "go.uber.org/zap"
+)
+
+// HeaderName is the HTTP header carrying the trace ID
+const HeaderName = "X-Trace-ID"
+
+// Qualifiers of the services registered by Module
+const (
+    MiddlewareQualifier = "tracing.middleware"
+    LoggerQualifier     = "tracing.logger"  // Scoped logger of the request's trace
+    WelcomeQualifier    = "tracing.welcome" // Scoped WelcomeService
+)
+
+type traceIDKey struct{}
+
+type scopeKey struct{}
+
+// WithTraceID returns a context carrying the given trace ID
+func WithTraceID(ctx context.Context, traceID string) context.Context {
+    return context.WithValue(ctx, traceIDKey{}, traceID)
+}
+
+// TraceID returns the trace ID stored in ctx, or an empty string
+func TraceID(ctx context.Context) string {
+    traceID, _ := ctx.Value(traceIDKey{}).(string)
+    return traceID
+}
+
+// NewTraceID generates a random 16 byte trace ID
+func NewTraceID() string {
+    var buf [16]byte
+    if _, err := rand.Read(buf[:]); err != nil {
+        panic(fmt.Sprintf("tracing: cannot read random bytes: %v", err))
+    }
+    return hex.EncodeToString(buf[:])
+}
+
+// Logger returns the application logger scoped to the trace in ctx
+func Logger(ctx context.Context) *zap.SugaredLogger {
+    log := logger.Get()
+    if traceID := TraceID(ctx); traceID != "" {
+        return log.With("traceID", traceID)
+    }
+    return log
+}
+
+// Module registers the tracing middleware and, per request scope, a logger
+// scoped to the request's trace along with a UserService, an EmailService
+// and a WelcomeService logging through it
+func Module() container.Module {
+    return container.Module{
+        Name: "tracing",
+        Setup: func(c *container.Container) error {
+            if err := c.Register(MiddlewareQualifier, NewMiddleware(c)); err != nil {
+                return err
+            }
+            if err := c.RegisterScoped(LoggerQualifier, func(s *container.Scope) (interface{}, error) {
+                return Logger(s.Context()), nil
+            }); err != nil {
+                return err
+            }
+            if err := c.RegisterScoped("userService", func(s *container.Scope) (interface{}, error) {
+                log, err := traceLogger(s)
+                if err != nil {
+                    return nil, err
+                }
+                return services.NewUserServiceWithLogger(log), nil
+            }); err != nil {
+                return err
+            }
+            if err := c.RegisterScoped("emailService", func(s *container.Scope) (interface{}, error) {
+                log, err := traceLogger(s)
+                if err != nil {
+                    return nil, err
+                }
+                return services.NewEmailServiceWithLogger(log), nil
+            }); err != nil {
+                return err
+            }
+            return c.RegisterScoped(WelcomeQualifier, func(s *container.Scope) (interface{}, error) {
+                welcome := &WelcomeService{}
+                return welcome, s.InjectStruct(welcome)
+            })
+        },
+    }
+}
+
+// traceLogger resolves the trace logger of scope s
+func traceLogger(s *container.Scope) (*zap.SugaredLogger, error) {
+    log, err := s.Resolve(LoggerQualifier)
+    if err != nil {
+        return nil, err
+    }
+    return log.(*zap.SugaredLogger), nil
+}
+
+// Scope returns the request scope the middleware opened for ctx, or nil
+func Scope(ctx context.Context) *container.Scope {
+    scope, _ := ctx.Value(scopeKey{}).(*container.Scope)
+    return scope
+}
+
+// Middleware wraps an HTTP handler so every request carries a trace ID,
+// reusing the incoming header when present, and runs in a container scope
+// whose context carries the trace
+type Middleware func(next http.Handler) http.Handler
+
+// NewMiddleware returns the tracing middleware, opening the request scopes
+// from c. A request whose scope fails to open is rejected.
+func NewMiddleware(c *container.Container) Middleware {
+    return func(next http.Handler) http.Handler {
+        return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+            traceID := r.Header.Get(HeaderName)
+            if traceID == "" {
+                traceID = NewTraceID()
+            }
+
+            w.Header().Set(HeaderName, traceID)
+            ctx := WithTraceID(r.Context(), traceID)
+            log := Logger(ctx)
+            log.Infow("Request received",
+                "method", r.Method,
+                "path", r.URL.Path)
+
+            scope, err := c.OpenScope(ctx)
+            if err != nil {
+                log.Errorw("Failed to open request scope", "error", err)
+                http.Error(w, "service unavailable", http.StatusServiceUnavailable)
+                return
+            }
+            defer func() {
+                if err := scope.Close(nil); err != nil {
+                    log.Errorw("Failed to close request scope", "error", err)
+                }
+            }()
+            next.ServeHTTP(w, r.WithContext(context.WithValue(ctx, scopeKey{}, scope)))
+        })
+    }
+}
+
+// WelcomeService greets a user by email. It is scoped to a request: its
+// dependencies and its logger carry the request's trace.
+type WelcomeService struct {
+    Users services.UserService  `di:"userService"`
+    Email services.EmailService `di:"emailService"`
+    Log   *zap.SugaredLogger    `di:"tracing.logger"`
+}
+
+// Welcome looks up the user and sends a welcome email
+func (s *WelcomeService) Welcome(userID int) error {
+    user := s.Users.GetUser(userID)
+    s.Log.Infow("Resolved user for welcome email", "user", user)
+
+    address := fmt.Sprintf("%s@example.com", user)
+    if err := s.Email.SendEmail(address, "Welcome aboard!"); err != nil {
+        s.Log.Errorw("Welcome email failed", "error", err)
+        return err
+    }
+
+    s.Log.Infow("Welcome email sent", "to", address)
+    return nil
+}
@@ -0,0 +1,119 @@
+package tracing
+
+import (
+    "context"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+
+    "di-example/pkg/container"
+    "di-example/pkg/logger"
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+    "go.uber.org/zap"
+    "go.uber.org/zap/zapcore"
+    "go.uber.org/zap/zaptest/observer"
+)
+
+type recordingEmail struct {
+    to []string
+}
+
+func (r *recordingEmail) SendEmail(to, message string) error {
+    r.to = append(r.to, to)
+    return nil
+}
+
+type fixedUsers struct{}
+
+func (fixedUsers) GetUser(id int) string {
+    return "USER-7"
+}
+
+func TestTraceIDContext(t *testing.T) {
+    ctx := context.Background()
+    assert.Equal(t, "", TraceID(ctx))
+    assert.Equal(t, "abc", TraceID(WithTraceID(ctx, "abc")))
+
+    id := NewTraceID()
+    assert.Len(t, id, 32)
+    assert.NotEqual(t, id, NewTraceID())
+}
+
+func TestMiddleware(t *testing.T) {
+    var seen string
+    var scope *container.Scope
+    handler := NewMiddleware(container.NewContainer())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        seen = TraceID(r.Context())
+        scope = Scope(r.Context())
+    }))
+
+    t.Run("propagates incoming header", func(t *testing.T) {
+        request := httptest.NewRequest(http.MethodGet, "/welcome", nil)
+        request.Header.Set(HeaderName, "incoming")
+        recorder := httptest.NewRecorder()
+
+        handler.ServeHTTP(recorder, request)
+        assert.Equal(t, "incoming", seen)
+        assert.Equal(t, "incoming", recorder.Header().Get(HeaderName))
+        require.NotNil(t, scope)
+        assert.Equal(t, "incoming", TraceID(scope.Context()))
+    })
+
+    t.Run("generates missing id", func(t *testing.T) {
+        recorder := httptest.NewRecorder()
+        handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/welcome", nil))
+        assert.NotEmpty(t, seen)
+        assert.Equal(t, seen, recorder.Header().Get(HeaderName))
+    })
+}
+
+func TestWelcomeService(t *testing.T) {
+    email := &recordingEmail{}
+    service := &WelcomeService{Users: fixedUsers{}, Email: email, Log: zap.NewNop().Sugar()}
+
+    require.NoError(t, service.Welcome(7))
+    assert.Equal(t, []string{"USER-7@example.com"}, email.to)
+}
+
+func TestModule_ServicesLogUnderTheRequestTrace(t *testing.T) {
+    core, logs := observer.New(zapcore.InfoLevel)
+    logger.Replace(zap.New(core))
+    t.Cleanup(func() { logger.Replace(nil) })
+
+    c := container.NewContainer()
+    require.NoError(t, c.Install(Module()))
+    middleware, err := container.ResolveAs[Middleware](c, MiddlewareQualifier)
+    require.NoError(t, err)
+    handler := middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        welcome, err := Scope(r.Context()).Resolve(WelcomeQualifier)
+        require.NoError(t, err)
+        require.NoError(t, welcome.(*WelcomeService).Welcome(42))
+    }))
+
+    for _, traceID := range []string{"first", "second"} {
+        request := httptest.NewRequest(http.MethodPost, "/welcome", nil)
+        request.Header.Set(HeaderName, traceID)
+        handler.ServeHTTP(httptest.NewRecorder(), request)
+    }
+
+    // Every line of a request, from the middleware down to EmailService,
+    // carries its trace ID
+    messages := []string{
+        "Request received",
+        "Creating new UserService",
+        "Creating new EmailService",
+        "Getting user",
+        "Resolved user for welcome email",
+        "Sending email",
+        "Email sent successfully",
+        "Welcome email sent",
+    }
+    for _, message := range messages {
+        var traces []interface{}
+        for _, entry := range logs.FilterMessage(message).All() {
+            traces = append(traces, entry.ContextMap()["traceID"])
+        }
+        assert.Equal(t, []interface{}{"first", "second"}, traces, message)
+    }
+}
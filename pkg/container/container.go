@@ -1,160 +1,571 @@
-// Package container provides dependency injection functionality
+// Package container provides dependency injection functionality.
+//
+// This is version 1 of the API, kept as a thin compatibility wrapper over
+// version 2, di-example/pkg/container/v2, which holds the engine. Every type
+// here is an alias of the v2 type of the same name and every function calls
+// its v2 counterpart, so a *Container created through either package can be
+// handed to code written against the other, and code can migrate one
+// package at a time. New APIs, such as the Builder, are added to v2 only.
+//
+// Exported APIs are stable, and kept compatible until the next major
+// version, unless their documentation says Experimental. The recorded API
+// lives in api/container.txt at the module root; cmd/apicheck and its test
+// reject incompatible changes to stable APIs.
 package container
 
-import (
-    "fmt"
-    "reflect"
-    "sync"
-    "di-example/pkg/logger"
-    "go.uber.org/zap"
-)
+import v2 "di-example/pkg/container/v2"
+
+// ModuleUsage is what one module costs the container. Module is empty for
+// registrations, workers and hooks made outside any module.
+type ModuleUsage = v2.ModuleUsage
+
+// AuditKind tells how a sensitive service was accessed
+type AuditKind = v2.AuditKind
+
+// AuditEvent records one access to a sensitive service
+type AuditEvent = v2.AuditEvent
+
+// AuditSink receives audit events. Audit is called synchronously on the
+// resolving goroutine, so sinks should hand events off quickly.
+type AuditSink = v2.AuditSink
+
+// AuditSinkFunc adapts a function to AuditSink
+type AuditSinkFunc = v2.AuditSinkFunc
+
+// Budget limits the dependencies of a service. Zero values mean unlimited.
+// Fan-in counts the distinct services and injected struct types depending
+// on a service; fan-out counts the services it depends on.
+type Budget = v2.Budget
+
+// Budgets declares dependency budgets, checked by Validate. A service uses
+// its own entry in Services, else the entry of its module, else Default.
+// Budgets flag "god services" that too much of the graph depends on.
+type Budgets = v2.Budgets
+
+// DiskCache persists provider outputs as JSON files keyed by a hash of the
+// provider's inputs and the JSON shape of its output type, so expensive
+// derived values survive process restarts but not changes to their type
+type DiskCache = v2.DiskCache
+
+// CloseTiming is how long closing one service took
+type CloseTiming = v2.CloseTiming
+
+// CloseReport describes the services the most recent Close, or Stop,
+// closed, in the order they were closed
+type CloseReport = v2.CloseReport
+
+// Option configures a container created by NewContainer
+type Option = v2.Option
+
+// Clock tells the container the time: timestamps of events, audit records,
+// mutations, workers and traces, and the durations it measures. Tests use
+// a fake clock to get stable values. A clock that also has an
+// After(d time.Duration) <-chan time.Time method, like time.After, times
+// the backoff delays of retries and WaitFor probes too.
+type Clock = v2.Clock
+
+// DuplicatePolicy decides what happens when a qualifier is registered twice
+type DuplicatePolicy = v2.DuplicatePolicy
 
 // Container represents a dependency injection container that manages services
-type Container struct {
-    mu       sync.RWMutex                // Mutex for thread-safe operations
-    services map[string]interface{}      // Map to store services with their qualifiers
-    log      *zap.SugaredLogger         // Logger instance
-}
-
-// NewContainer creates and initializes a new DI container
-func NewContainer() *Container {
-    return &Container{
-        services: make(map[string]interface{}), // Initialize empty service map
-        log:      logger.Get(),                 // Get logger instance
-    }
-}
-
-// Register adds a new service to the container with the specified qualifier
-func (c *Container) Register(qualifier string, service interface{}) error {
-    c.mu.Lock()                    // Lock for thread safety
-    defer c.mu.Unlock()            // Ensure unlock when function returns
-
-    // Log registration attempt
-    c.log.Infow("Registering service",
-        "qualifier", qualifier,
-        "type", reflect.TypeOf(service))
-
-    // Validate service is not nil
-    if service == nil {
-        c.log.Errorw("Cannot register nil service",
-            "qualifier", qualifier)
-        return fmt.Errorf("cannot register nil service for qualifier: %s", qualifier)
-    }
-
-    // Check if service already exists
-    if _, exists := c.services[qualifier]; exists {
-        c.log.Errorw("Service already registered",
-            "qualifier", qualifier)
-        return fmt.Errorf("service already registered for qualifier: %s", qualifier)
-    }
-
-    // Store service in container
-    c.services[qualifier] = service
-    c.log.Infow("Service registered successfully",
-        "qualifier", qualifier,
-        "type", reflect.TypeOf(service))
-    return nil
-}
-
-// Resolve retrieves a service from the container by its qualifier
-func (c *Container) Resolve(qualifier string) (interface{}, error) {
-    c.mu.RLock()                   // Read lock for thread safety
-    defer c.mu.RUnlock()           // Ensure unlock when function returns
-
-    c.log.Debugw("Resolving service", "qualifier", qualifier)
-
-    // Look up service in container
-    service, exists := c.services[qualifier]
-    if !exists {
-        c.log.Errorw("Service not found", "qualifier", qualifier)
-        return nil, fmt.Errorf("no service found for qualifier: %s", qualifier)
-    }
-
-    c.log.Debugw("Service resolved successfully",
-        "qualifier", qualifier,
-        "type", reflect.TypeOf(service))
-    return service, nil
-}
-
-// InjectStruct injects dependencies into struct fields marked with "di" tags
-func (c *Container) InjectStruct(target interface{}) error {
-    c.log.Info("Starting struct injection")
-
-    // Get reflect.Value of target and ensure it's a pointer
-    targetValue := reflect.ValueOf(target)
-    if targetValue.Kind() != reflect.Ptr {
-        c.log.Errorw("Target must be a pointer",
-            "actualKind", targetValue.Kind())
-        return fmt.Errorf("target must be a pointer to struct, got: %v", targetValue.Kind())
-    }
-
-    // Dereference pointer to get struct value
-    targetValue = targetValue.Elem()
-    targetType := targetValue.Type()
-
-    // Verify target is a struct
-    if targetValue.Kind() != reflect.Struct {
-        c.log.Errorw("Target must be a pointer to struct",
-            "actualKind", targetValue.Kind())
-        return fmt.Errorf("target must be a pointer to struct, got pointer to: %v", targetValue.Kind())
-    }
-
-    c.log.Infow("Analyzing struct for injection",
-        "structType", targetType.Name(),
-        "numFields", targetType.NumField())
-
-    // Iterate through all fields in the struct
-    for i := 0; i < targetType.NumField(); i++ {
-        field := targetType.Field(i)
-
-        // Look for 'di' tag on field
-        qualifier, ok := field.Tag.Lookup("di")
-        if !ok {
-            c.log.Debugw("Skipping field without di tag",
-                "field", field.Name)
-            continue
-        }
-
-        c.log.Infow("Injecting field",
-            "field", field.Name,
-            "qualifier", qualifier)
-
-        // Get field value and check if it can be set
-        fieldValue := targetValue.Field(i)
-        if !fieldValue.CanSet() {
-            c.log.Debugw("Cannot set field (unexported), skipping",
-                "field", field.Name)
-            continue
-        }
-
-        // Resolve service for this field
-        service, err := c.Resolve(qualifier)
-        if err != nil {
-            // If the service is not found, just log it and continue
-            c.log.Debugw("Optional service not found, skipping field",
-                "field", field.Name,
-                "qualifier", qualifier)
-            continue
-        }
-
-        // Verify type compatibility
-        serviceValue := reflect.ValueOf(service)
-        if !serviceValue.Type().AssignableTo(fieldValue.Type()) {
-            c.log.Errorw("Type mismatch during injection",
-                "field", field.Name,
-                "expectedType", fieldValue.Type(),
-                "actualType", serviceValue.Type())
-            return fmt.Errorf("service type %v is not assignable to field type %v",
-                serviceValue.Type(), fieldValue.Type())
-        }
-
-        // Set the field value to the service
-        fieldValue.Set(serviceValue)
-        c.log.Infow("Successfully injected field",
-            "field", field.Name,
-            "qualifier", qualifier)
-    }
-
-    c.log.Info("Completed struct injection")
-    return nil
-}
\ No newline at end of file
+type Container = v2.Container
+
+// Degradable is an optional subsystem with a fallback used while the
+// primary implementation is unhealthy, e.g. an in-memory queue standing in
+// for an unreachable broker. Both must be assignable to the type consumers
+// expect; Hot fields follow the switch.
+type Degradable = v2.Degradable
+
+// ServiceDescriptor is a snapshot of a registration's metadata
+type ServiceDescriptor = v2.ServiceDescriptor
+
+// ManifestEntry describes one registration in a RegistrationManifest
+type ManifestEntry = v2.ManifestEntry
+
+// RegistrationManifest is a serializable view of a container's wiring.
+// Committing it as JSON lets CI review wiring changes with DiffManifests
+// like a schema migration.
+type RegistrationManifest = v2.RegistrationManifest
+
+// ManifestChange is a registration present in both manifests whose type,
+// lifetime, module or profile differs
+type ManifestChange = v2.ManifestChange
+
+// ManifestDiff lists how the wiring changed between two manifests. Every
+// list is sorted by qualifier.
+type ManifestDiff = v2.ManifestDiff
+
+// NilPointerError is returned by InjectStruct when the target is a nil
+// pointer of a concrete type, e.g. (*Handler)(nil). It matches ErrNilTarget
+// with errors.Is; use errors.As to tell the two cases apart.
+type NilPointerError = v2.NilPointerError
+
+// ServiceNotFoundError is returned when no service is registered for a
+// qualifier, or, when resolving by type, none is assignable to Type. It
+// matches ErrServiceNotFound.
+type ServiceNotFoundError = v2.ServiceNotFoundError
+
+// DuplicateRegistrationError is returned when registering a qualifier that
+// is already taken in the container or the scope. It matches
+// ErrDuplicateRegistration.
+type DuplicateRegistrationError = v2.DuplicateRegistrationError
+
+// NilServiceError is returned when a service is nil, a typed nil such as
+// (*Store)(nil) rejected by the NilPolicy, or when the factory or provider
+// registered to build it is nil. It matches ErrNilService.
+type NilServiceError = v2.NilServiceError
+
+// ScopeRequiredError is returned when a scoped service is resolved from the
+// container instead of a Scope. It matches ErrScopeRequired.
+type ScopeRequiredError = v2.ScopeRequiredError
+
+// TypeMismatchError is returned when a service cannot be assigned to the
+// type it is injected or resolved as. It matches ErrTypeMismatch.
+type TypeMismatchError = v2.TypeMismatchError
+
+// EventKind names a container event. Mutations use their MutationKind.
+type EventKind = v2.EventKind
+
+// Event is a notable change or failure in the container
+type Event = v2.Event
+
+// Executor runs a batch of independent tasks. *errgroup.Group from
+// golang.org/x/sync satisfies this interface, as does any worker pool with
+// the same two methods.
+type Executor = v2.Executor
+
+// ExecutorFactory creates a fresh Executor for each batch of tasks
+type ExecutorFactory = v2.ExecutorFactory
+
+// Experiment routes a share of scopes, e.g. 5% of requests, to a variant
+// implementation of a service
+//
+// Experimental: experiments build on scopes and share their stability.
+type Experiment = v2.Experiment
+
+// Explanation describes how resolving a qualifier would proceed, see
+// Explain
+type Explanation = v2.Explanation
+
+// Factory builds a service lazily. It receives the container so it can
+// resolve its own dependencies.
+type Factory = v2.Factory
+
+// Future is the eventual result of ResolveAsync
+type Future = v2.Future
+
+// Implementation pairs a service with the qualifier it is registered under
+type Implementation[T any] = v2.Implementation[T]
+
+// Graph is the dependency graph of a container, see Container.Graph
+type Graph = v2.Graph
+
+// GraphNode is a service of the Graph
+type GraphNode = v2.GraphNode
+
+// GraphEdge is a dependency between two services of the Graph
+type GraphEdge = v2.GraphEdge
+
+// Decorator wraps a group member, e.g. to add auth or metrics around a
+// handler. It receives the member's qualifier and current instance and
+// returns the instance to use instead.
+//
+// Experimental: decorators may become typed once generic groups land.
+type Decorator = v2.Decorator
+
+// MutationKind names a change made to the container
+type MutationKind = v2.MutationKind
+
+// Mutation is one recorded change to the container
+type Mutation = v2.Mutation
+
+// Hot holds a hot-swappable dependency. InjectStruct fills Hot[T] and
+// *Hot[T] fields, and Swap later replaces the value atomically, so
+// consumers read the current instance without locks
+type Hot[T any] = v2.Hot[T]
+
+// FieldStatus is the outcome of injecting one field
+type FieldStatus = v2.FieldStatus
+
+// FieldInjection describes how a single field was injected
+type FieldInjection = v2.FieldInjection
+
+// InjectionResult reports what InjectStructWithResult did for every field
+// with a di tag
+type InjectionResult = v2.InjectionResult
+
+// InjectionLogging controls how InjectStruct logs its work
+type InjectionLogging = v2.InjectionLogging
+
+// Hook is a pair of callbacks invoked when the container starts and stops
+type Hook = v2.Hook
+
+// Limits protects a shared downstream behind a service by capping the calls
+// made through it. Calls wait up to MaxWait for a free slot and a rate
+// token, then fail with ErrBulkheadFull or ErrRateLimited.
+type Limits = v2.Limits
+
+// LimitError is returned for a call rejected by a Guard. It unwraps to
+// ErrBulkheadFull or ErrRateLimited.
+type LimitError = v2.LimitError
+
+// Guard enforces Limits on the calls made through the proxies of one
+// service. A guard outlives the instances it protects, so the limits hold
+// across Swap and weak rebuilds.
+type Guard = v2.Guard
+
+// GuardProxy wraps real in a proxy implementing T whose methods run through
+// guard.Do
+type GuardProxy[T any] = v2.GuardProxy[T]
+
+// Reference is a di tag qualifier found in a compiled package. Code generated
+// by digen declares the references of a package from its init function, so
+// the manifest covers every package linked into the binary.
+type Reference = v2.Reference
+
+// MetricsSink receives measurements emitted by the container
+type MetricsSink = v2.MetricsSink
+
+// Module groups related registrations under a name. Registrations made by
+// Setup are attributed to the module for quotas and diagnostics.
+type Module = v2.Module
+
+// NilPolicy decides what happens when a typed nil, such as a nil *T stored
+// in an interface, is registered or returned by a provider. Such values pass
+// the untyped nil check but panic once a method dereferences them.
+type NilPolicy = v2.NilPolicy
+
+// Optional wraps a dependency that may be absent. InjectStruct fills an
+// Optional[T] field with the resolved service when its qualifier is
+// registered and marks it empty otherwise, so consumers check presence
+// explicitly instead of relying on nil interfaces
+type Optional[T any] = v2.Optional[T]
+
+// InjectionCost sums up the cost of a set of injections
+type InjectionCost = v2.InjectionCost
+
+// PerfReport compares the cost of the container's successful injections by
+// how they analysed their struct types. Reflective injections analysed at
+// least one struct type; planned injections found every plan cached,
+// which WithInjectionPlans and Freeze enable.
+type PerfReport = v2.PerfReport
+
+// PostConstructor is implemented by structs that finish their setup once
+// InjectStruct has wired their fields, e.g. to validate them or derive
+// state from them. Nested structs, see NestedTag, are set up before the
+// struct holding them. ReinjectStruct does not call it again.
+type PostConstructor = v2.PostConstructor
+
+// PreDestroyer is implemented by services that clean up when the container
+// stops. Stop calls PreDestroy once per built singleton and cached weak
+// instance, in reverse registration order, after the stop hooks and workers.
+type PreDestroyer = v2.PreDestroyer
+
+// Quota limits how many services can be registered. Zero values mean
+// unlimited. Quotas catch runaway dynamic registration, e.g. a bug that
+// registers a new service per request.
+type Quota = v2.Quota
+
+// Lifetime describes how the container keeps instances of a service
+type Lifetime = v2.Lifetime
+
+// RegisterOption customizes a registration
+type RegisterOption = v2.RegisterOption
+
+// Endpoint is where service discovery says a remote service runs
+type Endpoint = v2.Endpoint
+
+// Discovery looks up remote services by name (Consul, DNS SRV, a config
+// file, ...)
+type Discovery = v2.Discovery
+
+// StaticDiscovery is a Discovery backed by a fixed table, e.g. loaded from
+// configuration
+type StaticDiscovery = v2.StaticDiscovery
+
+// RemoteService maps a local qualifier to a service running in another
+// process. Stub builds the client for the discovered endpoint; it usually
+// returns a type implementing the same interface as a local implementation,
+// so injected fields cannot tell the difference.
+type RemoteService = v2.RemoteService
+
+// ConfigSource is the subset of a remote configuration service (etcd,
+// Consul, ...) needed to drive bindings
+type ConfigSource = v2.ConfigSource
+
+// RemoteBinding selects the implementation registered under Qualifier from
+// Choices, using the value of Key in a ConfigSource
+type RemoteBinding = v2.RemoteBinding
+
+// CachingSource wraps a ConfigSource, serving Get from a cache for ttl and
+// refreshing cached values from watch notifications
+type CachingSource = v2.CachingSource
+
+// RetryPolicy controls how a failed construction is retried
+type RetryPolicy = v2.RetryPolicy
+
+// ConstructionRetry describes a construction that was retried
+type ConstructionRetry = v2.ConstructionRetry
+
+// Schema is the subset of JSON schema needed to describe config structs
+type Schema = v2.Schema
+
+// ScopedProvider builds the instance of a scoped service for one scope. It
+// may resolve other services, scoped or not, through the scope and
+// register cleanup with Scope.OnClose.
+//
+// Experimental: the signature may gain the unit of work's outcome.
+type ScopedProvider = v2.ScopedProvider
+
+// Scope is a child container for a unit of work, such as a request or a
+// transaction, holding the instances of scoped services and the services
+// registered in it. Anything else resolves from the parent scope, if any,
+// then from the container. A scope serves one unit of work and must not be
+// used from several goroutines at once.
+//
+// Experimental: scopes and their methods may change in any release while
+// their interaction with lifetimes and experiments settles.
+type Scope = v2.Scope
+
+// ScopeMiddleware prepares every new scope, root or child, before it is
+// used: it may seed services with Scope.Register, add cleanup with
+// Scope.OnClose or set a budget with Scope.SetBudget. An error rejects the
+// scope, see OpenScope.
+//
+// Experimental: see Scope.
+type ScopeMiddleware = v2.ScopeMiddleware
+
+// ScopeBudget limits the work a single scope may do. Zero values mean
+// unlimited.
+//
+// Experimental: see Scope.
+type ScopeBudget = v2.ScopeBudget
+
+// SelfTester is implemented by services that can verify their own health,
+// e.g. by round-tripping a request to a dependency
+type SelfTester = v2.SelfTester
+
+// SelfTestResult is the outcome of one service's self-test
+type SelfTestResult = v2.SelfTestResult
+
+// SelfTestReport aggregates the results of SelfTest in registration order
+type SelfTestReport = v2.SelfTestReport
+
+// DiagnosticStater is implemented by services that can describe their
+// internal state for support bundles. The state must marshal to JSON and
+// must not contain secrets.
+type DiagnosticStater = v2.DiagnosticStater
+
+// ServiceSnapshot is the diagnostic state of one service
+type ServiceSnapshot = v2.ServiceSnapshot
+
+// Snapshot is a one-shot diagnostic bundle of the container
+type Snapshot = v2.Snapshot
+
+// Starter is implemented by services that start work when the container
+// starts, such as a server listening or a consumer polling
+type Starter = v2.Starter
+
+// Stopper is implemented by services that release resources when the
+// container stops
+type Stopper = v2.Stopper
+
+// Warmer is implemented by services that want to prepare caches or
+// connections during the warmup phase of Start
+type Warmer = v2.Warmer
+
+// PhaseTiming is the measured duration of a single startup phase
+type PhaseTiming = v2.PhaseTiming
+
+// StartupReport describes how long each phase of Start took and which
+// constructions were retried, see RetryConstruction
+type StartupReport = v2.StartupReport
+
+// Inject is a marker that can be embedded in a struct to set injection
+// defaults for all of its fields through its own di tag
+type Inject = v2.Inject
+
+// TraceNode is one resolution in a traced call tree
+type TraceNode = v2.TraceNode
+
+// TraceReport is the call tree recorded for one traced resolution
+type TraceReport = v2.TraceReport
+
+// ReadOnlyView gives untrusted code, such as third-party plugins, access to
+// an allow-list of services. It can only resolve those qualifiers: it cannot
+// register or override services, and it cannot enumerate or probe the rest
+// of the graph, since qualifiers outside the list fail the same way whether
+// or not they are registered.
+type ReadOnlyView = v2.ReadOnlyView
+
+// Probe checks that an external resource a service needs is reachable
+type Probe = v2.Probe
+
+// WaitPolicy controls how long and how often probes are retried
+type WaitPolicy = v2.WaitPolicy
+
+// WeakProvider builds a weak service instance
+type WeakProvider = v2.WeakProvider
+
+// WorkerInfo describes a running worker
+type WorkerInfo = v2.WorkerInfo
+
+const (
+    AuditResolve AuditKind = v2.AuditResolve // Resolve and functions built on it
+    AuditInject  AuditKind = v2.AuditInject  // InjectStruct filling a field
+)
+
+const (
+    // DuplicateReject fails the second registration. It is the default.
+    DuplicateReject    DuplicatePolicy = v2.DuplicateReject
+    // DuplicateKeepFirst ignores the second registration with a warning
+    DuplicateKeepFirst DuplicatePolicy = v2.DuplicateKeepFirst
+    // DuplicateReplace swaps the instance of a registered singleton for the
+    // new one, like Swap. Other registrations are still rejected.
+    DuplicateReplace   DuplicatePolicy = v2.DuplicateReplace
+)
+
+const (
+    EventRegister      EventKind = v2.EventRegister
+    EventSwap          EventKind = v2.EventSwap
+    EventUnregister    EventKind = v2.EventUnregister
+    EventRename        EventKind = v2.EventRename
+    EventDecorate      EventKind = v2.EventDecorate
+    EventFreeze        EventKind = v2.EventFreeze
+    EventScopeOpen     EventKind = v2.EventScopeOpen
+    EventScopeClose    EventKind = v2.EventScopeClose
+    EventResolveFailed EventKind = v2.EventResolveFailed
+    EventStarting      EventKind = v2.EventStarting
+    EventStarted       EventKind = v2.EventStarted
+    EventStartFailed   EventKind = v2.EventStartFailed
+    EventStopped       EventKind = v2.EventStopped
+    EventStopFailed    EventKind = v2.EventStopFailed
+    EventDegraded      EventKind = v2.EventDegraded
+    EventRecovered     EventKind = v2.EventRecovered
+    EventExposure      EventKind = v2.EventExposure
+)
+
+// Kinds of GraphEdge
+const (
+    EdgeDeclared = v2.EdgeDeclared // Declared with DependsOn
+    EdgeProvider = v2.EdgeProvider // A parameter of the provider
+    EdgeBuilt    = v2.EdgeBuilt    // Resolved while the service was built
+)
+
+// MapTagPrefix starts the di tag of a map field that receives a whole group
+// keyed by qualifier, for plugin registries and strategy lookups
+const MapTagPrefix = v2.MapTagPrefix
+
+// DefaultHistorySize is the number of mutations kept by History
+const DefaultHistorySize = v2.DefaultHistorySize
+
+const (
+    MutationRegister   MutationKind = v2.MutationRegister   // Register, RegisterWeak and helpers built on them
+    MutationSwap       MutationKind = v2.MutationSwap       // An instance replaced with Swap
+    MutationUnregister MutationKind = v2.MutationUnregister // A service removed with Unregister
+    MutationRename     MutationKind = v2.MutationRename     // A deprecated qualifier redirected
+    MutationDecorate   MutationKind = v2.MutationDecorate   // A decorator added to a group
+    MutationFreeze     MutationKind = v2.MutationFreeze     // The container closed for registration
+    MutationScopeOpen  MutationKind = v2.MutationScopeOpen  // A scope or child scope started
+    MutationScopeClose MutationKind = v2.MutationScopeClose // A scope closed, with its open children
+)
+
+const (
+    FieldInjected   FieldStatus = v2.FieldInjected   // The service was set on the field
+    FieldMissing    FieldStatus = v2.FieldMissing    // No service; the field is optional
+    FieldUnexported FieldStatus = v2.FieldUnexported // The field cannot be set
+    FieldGuarded    FieldStatus = v2.FieldGuarded    // The ifPresent guard is not registered
+    FieldUnchanged  FieldStatus = v2.FieldUnchanged  // ReinjectStruct kept the current value
+)
+
+const (
+    // InjectionLogSummary logs one Info entry per struct with counts, and
+    // the per-field details in one Debug entry. It is the default.
+    InjectionLogSummary InjectionLogging = v2.InjectionLogSummary
+    // InjectionLogVerbose also logs every field at Info level
+    InjectionLogVerbose InjectionLogging = v2.InjectionLogVerbose
+    // InjectionLogQuiet logs the summary at Debug level only
+    InjectionLogQuiet   InjectionLogging = v2.InjectionLogQuiet
+)
+
+// Profiler label keys set while the container runs service code
+const (
+    LabelService = v2.LabelService // Qualifier or hook name
+    LabelPhase   = v2.LabelPhase   // What the container is doing: build, warmup, start, stop, selftest
+)
+
+// InjectMethodPrefix starts the names of the methods InjectStruct calls
+// once the fields of a target are set, passing services resolved like the
+// parameters of Invoke: a *Container receives the container, a struct with
+// di tagged fields is filled by qualifier and anything else is resolved by
+// type. It suits types that keep their dependencies unexported
+const InjectMethodPrefix = v2.InjectMethodPrefix
+
+// DefaultProfile is the active profile of a new container
+const DefaultProfile = v2.DefaultProfile
+
+// NestedTag marks a struct field whose own di tagged fields are injected,
+// recursively
+const NestedTag = v2.NestedTag
+
+const (
+    // NilReject fails the registration or resolution. It is the default.
+    NilReject NilPolicy = v2.NilReject
+    // NilWarn logs a warning and accepts the value
+    NilWarn   NilPolicy = v2.NilWarn
+)
+
+// OptionsTag is the di tag injecting the option struct of the field's type
+const OptionsTag = v2.OptionsTag
+
+// Init stages commonly used with InStage. Lower stages start first.
+const (
+    StageInfrastructure = v2.StageInfrastructure // Databases, queues, caches
+    StageDomain         = v2.StageDomain         // Business services
+    StageTransport      = v2.StageTransport      // HTTP servers, consumers
+)
+
+const (
+    // Singleton services have one instance for the life of the container
+    Singleton Lifetime = v2.Singleton
+    // Weak services are cached in a bounded LRU and rebuilt after eviction
+    Weak      Lifetime = v2.Weak
+    // Scoped services have one instance per Scope
+    Scoped    Lifetime = v2.Scoped
+    // Transient services are built anew by every resolve
+    Transient Lifetime = v2.Transient
+)
+
+// SchemaDraft is the JSON schema dialect produced by SchemaOf
+const SchemaDraft = v2.SchemaDraft
+
+// DefaultWeakCapacity is the number of weak instances kept before eviction
+const DefaultWeakCapacity = v2.DefaultWeakCapacity
+
+// ErrNoDefault is returned by the package-level wrappers when no default
+// container was installed with InitDefault or SetDefault
+var ErrNoDefault = v2.ErrNoDefault
+
+// ErrNilTarget is returned by InjectStruct when the target is nil
+var ErrNilTarget = v2.ErrNilTarget
+
+// Sentinel errors of container operations. The errors returned carry the
+// qualifier and types involved; match them with errors.Is, or extract the
+// details with errors.As
+var (
+    ErrServiceNotFound       = v2.ErrServiceNotFound
+    ErrDuplicateRegistration = v2.ErrDuplicateRegistration
+    ErrNilService            = v2.ErrNilService
+    ErrTypeMismatch          = v2.ErrTypeMismatch
+    ErrScopeRequired         = v2.ErrScopeRequired
+)
+
+// Errors of calls rejected by a Guard, wrapped in a *LimitError
+var (
+    ErrBulkheadFull = v2.ErrBulkheadFull
+    ErrRateLimited  = v2.ErrRateLimited
+)
+
+// DefaultWaitPolicy retries for up to a minute with backoff from 100ms to 5s.
+// It is a copy: new containers use v2.DefaultWaitPolicy.
+var DefaultWaitPolicy = v2.DefaultWaitPolicy
@@ -0,0 +1,147 @@
+// Package containertest provides helpers for integration tests that run
+// against a real container.
+package containertest
+
+import (
+    "fmt"
+    "reflect"
+    "sync"
+
+    "di-example/pkg/container"
+)
+
+// Call is one recorded method call
+type Call struct {
+    Method  string
+    Args    []interface{}
+    Results []interface{}
+}
+
+// Recorder collects the calls made through a recording proxy
+type Recorder struct {
+    mu    sync.Mutex
+    calls []Call
+}
+
+// Record stores a call. Proxy factories call it after invoking the real
+// implementation.
+func (r *Recorder) Record(method string, args []interface{}, results []interface{}) {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+    r.calls = append(r.calls, Call{Method: method, Args: args, Results: results})
+}
+
+// Calls returns every recorded call in order
+func (r *Recorder) Calls() []Call {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+    return append([]Call(nil), r.calls...)
+}
+
+// CallsTo returns the recorded calls of one method in order
+func (r *Recorder) CallsTo(method string) []Call {
+    var calls []Call
+    for _, call := range r.Calls() {
+        if call.Method == method {
+            calls = append(calls, call)
+        }
+    }
+    return calls
+}
+
+// Reset forgets all recorded calls
+func (r *Recorder) Reset() {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+    r.calls = nil
+}
+
+// ProxyFactory wraps real in a proxy implementing the same interface that
+// forwards every call to real and records it
+type ProxyFactory[T any] func(real T, recorder *Recorder) T
+
+var (
+    factoriesMu sync.Mutex
+    factories   = make(map[reflect.Type]interface{}) // Interface type -> ProxyFactory
+)
+
+// RegisterProxy installs the proxy factory used by RecordCalls for the
+// interface T. Go cannot implement interfaces at runtime, so interface
+// proxies are written once per interface, typically in a test helper file:
+//
+//	containertest.RegisterProxy(func(real UserService, rec *containertest.Recorder) UserService {
+//	    return &userServiceProxy{real: real, rec: rec}
+//	})
+func RegisterProxy[T any](factory ProxyFactory[T]) {
+    factoriesMu.Lock()
+    defer factoriesMu.Unlock()
+    factories[reflect.TypeOf((*T)(nil)).Elem()] = factory
+}
+
+// RecordCalls swaps the singleton registered under qualifier for a recording
+// proxy around it and returns the recorder. Function services (T a func
+// type) are proxied automatically and recorded under the method name
+// "call"; interfaces need a factory installed with RegisterProxy.
+func RecordCalls[T any](c *container.Container, qualifier string) (*Recorder, error) {
+    service, err := c.Resolve(qualifier)
+    if err != nil {
+        return nil, err
+    }
+    real, ok := service.(T)
+    if !ok {
+        return nil, fmt.Errorf("service %s of type %T does not implement %v",
+            qualifier, service, reflect.TypeOf((*T)(nil)).Elem())
+    }
+
+    recorder := &Recorder{}
+    proxy, err := newProxy(real, recorder)
+    if err != nil {
+        return nil, err
+    }
+    if _, err := c.Swap(qualifier, proxy); err != nil {
+        return nil, err
+    }
+    return recorder, nil
+}
+
+// newProxy builds the recording proxy for real
+func newProxy[T any](real T, recorder *Recorder) (T, error) {
+    proxyType := reflect.TypeOf((*T)(nil)).Elem()
+    if proxyType.Kind() == reflect.Func {
+        return recordFunc(real, recorder), nil
+    }
+
+    factoriesMu.Lock()
+    factory, ok := factories[proxyType]
+    factoriesMu.Unlock()
+    if !ok {
+        var zero T
+        return zero, fmt.Errorf("no proxy registered for %v, call RegisterProxy first", proxyType)
+    }
+    return factory.(ProxyFactory[T])(real, recorder), nil
+}
+
+// recordFunc wraps a function value with reflect.MakeFunc
+func recordFunc[T any](real T, recorder *Recorder) T {
+    realValue := reflect.ValueOf(real)
+    proxy := reflect.MakeFunc(realValue.Type(), func(args []reflect.Value) []reflect.Value {
+        var results []reflect.Value
+        if realValue.Type().IsVariadic() {
+            results = realValue.CallSlice(args)
+        } else {
+            results = realValue.Call(args)
+        }
+        recorder.Record("call", interfaces(args), interfaces(results))
+        return results
+    })
+    return proxy.Interface().(T)
+}
+
+// interfaces converts reflect values to their interface values
+func interfaces(values []reflect.Value) []interface{} {
+    converted := make([]interface{}, len(values))
+    for i, value := range values {
+        converted[i] = value.Interface()
+    }
+    return converted
+}
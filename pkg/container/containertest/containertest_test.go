@@ -0,0 +1,91 @@
+package containertest
+
+import (
+    "testing"
+
+    "di-example/internal/services"
+    "di-example/pkg/container"
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+// userServiceProxy is a hand-written recording proxy for services.UserService
+type userServiceProxy struct {
+    real services.UserService
+    rec  *Recorder
+}
+
+func (p *userServiceProxy) GetUser(id int) string {
+    result := p.real.GetUser(id)
+    p.rec.Record("GetUser", []interface{}{id}, []interface{}{result})
+    return result
+}
+
+func init() {
+    RegisterProxy(func(real services.UserService, rec *Recorder) services.UserService {
+        return &userServiceProxy{real: real, rec: rec}
+    })
+}
+
+type handler struct {
+    Users services.UserService `di:"userService"`
+}
+
+func TestRecordCalls_Interface(t *testing.T) {
+    c := container.NewContainer()
+    require.NoError(t, c.Register("userService", services.NewUserService()))
+
+    recorder, err := RecordCalls[services.UserService](c, "userService")
+    require.NoError(t, err)
+
+    // Consumers injected afterwards talk to the real service through the proxy
+    target := &handler{}
+    require.NoError(t, c.InjectStruct(target))
+    result := target.Users.GetUser(42)
+    assert.Contains(t, result, "42")
+
+    calls := recorder.CallsTo("GetUser")
+    require.Len(t, calls, 1)
+    assert.Equal(t, []interface{}{42}, calls[0].Args)
+    assert.Equal(t, []interface{}{result}, calls[0].Results)
+
+    recorder.Reset()
+    assert.Empty(t, recorder.Calls())
+}
+
+func TestRecordCalls_Func(t *testing.T) {
+    c := container.NewContainer()
+    require.NoError(t, c.Register("sum", func(values ...int) int {
+        total := 0
+        for _, value := range values {
+            total += value
+        }
+        return total
+    }))
+
+    recorder, err := RecordCalls[func(...int) int](c, "sum")
+    require.NoError(t, err)
+
+    service, err := c.Resolve("sum")
+    require.NoError(t, err)
+    assert.Equal(t, 6, service.(func(...int) int)(1, 2, 3))
+
+    calls := recorder.Calls()
+    require.Len(t, calls, 1)
+    assert.Equal(t, []interface{}{[]int{1, 2, 3}}, calls[0].Args)
+    assert.Equal(t, []interface{}{6}, calls[0].Results)
+}
+
+func TestRecordCalls_Errors(t *testing.T) {
+    c := container.NewContainer()
+    require.NoError(t, c.Register("emailService", services.NewEmailService()))
+
+    _, err := RecordCalls[services.UserService](c, "emailService")
+    assert.ErrorContains(t, err, "does not implement")
+
+    _, err = RecordCalls[services.EmailService](c, "emailService")
+    assert.ErrorContains(t, err, "no proxy registered")
+
+    _, err = RecordCalls[services.UserService](c, "missing")
+    assert.Error(t, err)
+}
@@ -0,0 +1,105 @@
+// Package dobridge bridges registrations between the container and a
+// samber/do injector, so applications mixing both libraries can share
+// services while their wiring is consolidated.
+//
+// The bridge talks to the injector through the Injector interface instead
+// of importing samber/do. Adapt a *do.Injector with a few lines:
+//
+//	type doInjector struct{ *do.Injector }
+//
+//	func (i doInjector) InvokeNamed(name string) (any, error) {
+//	    return do.InvokeNamed[any](i.Injector, name)
+//	}
+//
+//	func (i doInjector) ProvideNamedValue(name string, value any) {
+//	    do.ProvideNamedValue[any](i.Injector, name, value)
+//	}
+package dobridge
+
+import (
+    "errors"
+    "fmt"
+
+    "di-example/pkg/container"
+    "di-example/pkg/logger"
+)
+
+// Injector is the part of a samber/do injector used by the bridge
+type Injector interface {
+    ListProvidedServices() []string
+    InvokeNamed(name string) (interface{}, error)
+    ProvideNamedValue(name string, value interface{})
+}
+
+// Import registers every service provided by injector in c under the same
+// name. Services stay lazy: they are invoked on the injector the first time
+// they are resolved, and samber/do keeps the instance. Names already
+// registered in c are skipped, so the container's own wiring wins.
+// It returns the imported names.
+func Import(c *container.Container, injector Injector, opts ...container.RegisterOption) ([]string, error) {
+    log := logger.Get()
+
+    registered := make(map[string]bool)
+    for _, qualifier := range c.Qualifiers() {
+        registered[qualifier] = true
+    }
+
+    var imported []string
+    var errs []error
+    for _, name := range injector.ListProvidedServices() {
+        if registered[name] {
+            log.Debugw("Skipping samber/do service already in container", "name", name)
+            continue
+        }
+
+        name := name
+        // The injector caches the instance, so rebuilding after an
+        // eviction returns the same service
+        err := c.RegisterWeak(name, func() (interface{}, error) {
+            return injector.InvokeNamed(name)
+        }, opts...)
+        if err != nil {
+            errs = append(errs, fmt.Errorf("failed to import %s: %w", name, err))
+            continue
+        }
+        imported = append(imported, name)
+    }
+
+    log.Infow("Imported samber/do services", "count", len(imported))
+    return imported, errors.Join(errs...)
+}
+
+// Export provides the given qualifiers of c to injector, or every qualifier
+// when none are given. Services are resolved eagerly; names the injector
+// already provides are skipped. It returns the exported names.
+func Export(c *container.Container, injector Injector, qualifiers ...string) ([]string, error) {
+    log := logger.Get()
+
+    if len(qualifiers) == 0 {
+        qualifiers = c.Qualifiers()
+    }
+    provided := make(map[string]bool)
+    for _, name := range injector.ListProvidedServices() {
+        provided[name] = true
+    }
+
+    var exported []string
+    var errs []error
+    for _, qualifier := range qualifiers {
+        if provided[qualifier] {
+            log.Debugw("Skipping service already provided by samber/do", "qualifier", qualifier)
+            continue
+        }
+
+        service, err := c.Resolve(qualifier)
+        if err != nil {
+            errs = append(errs, fmt.Errorf("failed to export %s: %w", qualifier, err))
+            continue
+        }
+        injector.ProvideNamedValue(qualifier, service)
+        exported = append(exported, qualifier)
+    }
+
+    log.Infow("Exported services to samber/do", "count", len(exported))
+    return exported, errors.Join(errs...)
+}
@@ -0,0 +1,87 @@
+package dobridge
+
+import (
+    "errors"
+    "sort"
+    "testing"
+
+    "di-example/pkg/container"
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+// fakeInjector mimics a samber/do injector holding named values
+type fakeInjector struct {
+    values  map[string]interface{}
+    invoked map[string]int
+}
+
+func newFakeInjector(values map[string]interface{}) *fakeInjector {
+    return &fakeInjector{values: values, invoked: make(map[string]int)}
+}
+
+func (f *fakeInjector) ListProvidedServices() []string {
+    var names []string
+    for name := range f.values {
+        names = append(names, name)
+    }
+    sort.Strings(names)
+    return names
+}
+
+func (f *fakeInjector) InvokeNamed(name string) (interface{}, error) {
+    f.invoked[name]++
+    value, ok := f.values[name]
+    if !ok {
+        return nil, errors.New("could not find service " + name)
+    }
+    return value, nil
+}
+
+func (f *fakeInjector) ProvideNamedValue(name string, value interface{}) {
+    f.values[name] = value
+}
+
+func TestImport(t *testing.T) {
+    c := container.NewContainer()
+    require.NoError(t, c.Register("config", "container-config"))
+
+    injector := newFakeInjector(map[string]interface{}{
+        "config":  "do-config",
+        "mailer":  "do-mailer",
+        "metrics": "do-metrics",
+    })
+
+    imported, err := Import(c, injector)
+    require.NoError(t, err)
+    assert.Equal(t, []string{"mailer", "metrics"}, imported)
+
+    // Lazy until resolved
+    assert.Zero(t, injector.invoked["mailer"])
+    mailer, err := c.Resolve("mailer")
+    require.NoError(t, err)
+    assert.Equal(t, "do-mailer", mailer)
+    assert.Equal(t, 1, injector.invoked["mailer"])
+
+    // The container's own registration wins
+    config, err := c.Resolve("config")
+    require.NoError(t, err)
+    assert.Equal(t, "container-config", config)
+}
+
+func TestExport(t *testing.T) {
+    c := container.NewContainer()
+    require.NoError(t, c.Register("users", "container-users"))
+    require.NoError(t, c.Register("orders", "container-orders"))
+
+    injector := newFakeInjector(map[string]interface{}{"orders": "do-orders"})
+
+    exported, err := Export(c, injector)
+    require.NoError(t, err)
+    assert.Equal(t, []string{"users"}, exported)
+    assert.Equal(t, "container-users", injector.values["users"])
+    assert.Equal(t, "do-orders", injector.values["orders"])
+
+    _, err = Export(c, injector, "missing")
+    assert.ErrorContains(t, err, "missing")
+}
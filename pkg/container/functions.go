@@ -0,0 +1,378 @@
+package container
+
+import (
+    "context"
+    "reflect"
+    "time"
+
+    v2 "di-example/pkg/container/v2"
+    "go.uber.org/zap"
+)
+
+// Sensitive marks a registration as sensitive, e.g. a service holding
+// credentials. Every resolution and injection of it emits an AuditEvent.
+func Sensitive() RegisterOption {
+    return v2.Sensitive()
+}
+
+// BindInterface is the generic form of Bind
+func BindInterface[I any](c *Container, qualifier string) error {
+    return v2.BindInterface[I](c, qualifier)
+}
+
+// ResolveInterface resolves the implementation of interface I, see
+// ResolveByType
+func ResolveInterface[I any](c *Container) (I, error) {
+    return v2.ResolveInterface[I](c)
+}
+
+// DependsOn declares the services a registration depends on. The edges are
+// used for dependency budgets; InjectStruct records its edges itself.
+func DependsOn(qualifiers ...string) RegisterOption {
+    return v2.DependsOn(qualifiers...)
+}
+
+// NewDiskCache creates a cache storing its entries in dir
+func NewDiskCache(dir string) (*DiskCache, error) {
+    return v2.NewDiskCache(dir)
+}
+
+// RegisterCached registers the output of build under qualifier, reusing the
+// value stored in cache when build previously ran with the same inputs.
+// T must round-trip through encoding/json. Failures to write the cache are
+// logged but do not fail the registration.
+func RegisterCached[T any](c *Container, cache *DiskCache, qualifier string, inputs interface{}, build func() (T, error), opts ...RegisterOption) error {
+    return v2.RegisterCached[T](c, cache, qualifier, inputs, build, opts...)
+}
+
+// RegisterChan creates a channel of T with the given buffer size, registers
+// it under qualifier and returns it. Producer and consumer structs can then
+// receive it through di tags, typed as chan T, chan<- T or <-chan T. The
+// channel is closed when the container stops, after the hooks of later
+// init stages have stopped, so consumers ranging over it terminate cleanly.
+func RegisterChan[T any](c *Container, qualifier string, buffer int, opts ...RegisterOption) (chan T, error) {
+    return v2.RegisterChan[T](c, qualifier, buffer, opts...)
+}
+
+// WithLogger makes the container log to log instead of the shared logger.
+// A nil logger keeps the default.
+func WithLogger(log *zap.SugaredLogger) Option {
+    return v2.WithLogger(log)
+}
+
+// WithMetrics installs the sink that receives container measurements, see
+// SetMetricsSink
+func WithMetrics(sink MetricsSink) Option {
+    return v2.WithMetrics(sink)
+}
+
+// WithClock makes the container read the time from clock. A nil clock keeps
+// the wall clock.
+func WithClock(clock Clock) Option {
+    return v2.WithClock(clock)
+}
+
+// WithStrictMode makes InjectStruct reject di tags it otherwise tolerates:
+// unknown field tag options, which are usually typos such as "optinal", and
+// tags on unexported fields, which the container cannot set unless
+// WithUnexportedInjection is given.
+func WithStrictMode() Option {
+    return v2.WithStrictMode()
+}
+
+// WithNestedInjection makes InjectStruct descend into every exported
+// struct field, and non-nil pointer to struct, without needing a
+// di:"inject" tag, as it always does for embedded structs, see NestedTag
+func WithNestedInjection() Option {
+    return v2.WithNestedInjection()
+}
+
+// WithInjectionPlans makes InjectStruct cache the analysis of every struct
+// type it injects, its tags and nested structs, instead of repeating it for
+// every injection. A frozen container caches plans anyway. See PerfReport
+// for what it saves.
+func WithInjectionPlans() Option {
+    return v2.WithInjectionPlans()
+}
+
+// WithDuplicatePolicy sets how registering a taken qualifier is handled
+func WithDuplicatePolicy(policy DuplicatePolicy) Option {
+    return v2.WithDuplicatePolicy(policy)
+}
+
+// NewContainer creates and initializes a new DI container configured by
+// opts, see Option
+func NewContainer(opts ...Option) *Container {
+    return v2.NewContainer(opts...)
+}
+
+// InitDefault creates the default container used by Register, Resolve and
+// InjectStruct. It is meant for small tools that do not want to pass a
+// container around; applications should create and pass their own with
+// NewContainer. It fails if a default container is already installed, so
+// two pieces of code cannot silently share one.
+func InitDefault() (*Container, error) {
+    return v2.InitDefault()
+}
+
+// SetDefault installs c as the default container. It fails if a default
+// container is already installed; call ResetDefault first to replace it.
+func SetDefault(c *Container) error {
+    return v2.SetDefault(c)
+}
+
+// ResetDefault removes the default container, returning it or nil. Tests
+// use it to isolate themselves.
+func ResetDefault() *Container {
+    return v2.ResetDefault()
+}
+
+// Default returns the default container. It panics with ErrNoDefault if
+// none was installed; there is no implicit global container.
+func Default() *Container {
+    return v2.Default()
+}
+
+// Register registers a service with the default container, see
+// Container.Register
+func Register(qualifier string, service interface{}, opts ...RegisterOption) error {
+    return v2.Register(qualifier, service, opts...)
+}
+
+// Resolve resolves a service from the default container, see
+// Container.Resolve
+func Resolve(qualifier string) (interface{}, error) {
+    return v2.Resolve(qualifier)
+}
+
+// InjectStruct injects target from the default container, see
+// Container.InjectStruct
+func InjectStruct(target interface{}) error {
+    return v2.InjectStruct(target)
+}
+
+// DiffManifests compares the wiring of manifest a, the old one, with b
+func DiffManifests(a, b RegistrationManifest) ManifestDiff {
+    return v2.DiffManifests(a, b)
+}
+
+// Sequential runs tasks one after another in the calling goroutine and
+// reports the first error. It is the default executor.
+func Sequential() Executor {
+    return v2.Sequential()
+}
+
+// Parallel returns a factory for executors that run at most limit tasks at
+// once (unbounded when limit <= 0) and join the errors of all tasks
+func Parallel(limit int) ExecutorFactory {
+    return v2.Parallel(limit)
+}
+
+// ResolveImplementing returns every registered service whose concrete type
+// implements the interface T, in registration order. It supports discovery
+// style code ("find all MigrationProviders") without grouping services in
+// advance. Services are matched by the type known without building them:
+// instances, providers and the results declared by constructors. Factories
+// not built yet and scoped services are skipped; transient matches are
+// built for the result.
+func ResolveImplementing[T any](c *Container) ([]Implementation[T], error) {
+    return v2.ResolveImplementing[T](c)
+}
+
+// ResolveAs resolves qualifier and asserts the service to T, so callers do
+// not repeat the type assertion. A service of another type is reported
+// with both types.
+func ResolveAs[T any](c *Container, qualifier string) (T, error) {
+    return v2.ResolveAs[T](c, qualifier)
+}
+
+// MustResolve is ResolveAs panicking on error, for wiring code where a
+// missing or mistyped service is a programming error
+func MustResolve[T any](c *Container, qualifier string) T {
+    return v2.MustResolve[T](c, qualifier)
+}
+
+// InGroup adds the registration to a named group. A service can belong to
+// several groups; members are resolved together with ResolveGroup.
+func InGroup(group string) RegisterOption {
+    return v2.InGroup(group)
+}
+
+// Before orders the registration ahead of the given members in every group
+// it belongs to, so a middleware chain assembled from several modules comes
+// out in a deterministic order
+func Before(qualifiers ...string) RegisterOption {
+    return v2.Before(qualifiers...)
+}
+
+// After orders the registration behind the given members in every group it
+// belongs to, see Before
+func After(qualifiers ...string) RegisterOption {
+    return v2.After(qualifiers...)
+}
+
+// WithLifetime selects how Register keeps a service. Singleton, the
+// default, registers the given instance. The other lifetimes take a
+// constructor instead of an instance, called whenever a new instance is
+// needed
+func WithLifetime(lifetime Lifetime) RegisterOption {
+    return v2.WithLifetime(lifetime)
+}
+
+// NewGuard returns a guard enforcing limits on the calls to qualifier
+func NewGuard(qualifier string, limits Limits) *Guard {
+    return v2.NewGuard(qualifier, limits)
+}
+
+// RegisterGuardProxy installs the proxy that guards services implementing
+// the interface T. Go cannot implement interfaces at runtime, so guarded
+// interfaces need a proxy written once; function services are guarded
+// without one.
+func RegisterGuardProxy[T any](proxy GuardProxy[T]) {
+    v2.RegisterGuardProxy[T](proxy)
+}
+
+// Limit returns a decorator guarding every member of a group with limits,
+// one Guard per member
+//
+// Experimental: see Decorator.
+func Limit(limits Limits) Decorator {
+    return v2.Limit(limits)
+}
+
+// WithLimits guards the registered service with limits. Every instance
+// behind the qualifier, including swapped and rebuilt ones, shares one
+// Guard, which counts rejections in the di_limit_rejections metric.
+func WithLimits(limits Limits) RegisterOption {
+    return v2.WithLimits(limits)
+}
+
+// DeclareReferences adds references to the binary manifest checked by Build
+func DeclareReferences(refs ...Reference) {
+    v2.DeclareReferences(refs...)
+}
+
+// Manifest returns every reference declared in the binary, sorted by site
+func Manifest() []Reference {
+    return v2.Manifest()
+}
+
+// WithInjectMethods makes InjectStruct also call the methods whose names
+// match, such as setters
+func WithInjectMethods(match func(name string) bool) Option {
+    return v2.WithInjectMethods(match)
+}
+
+// RegisterMethods registers every exported method of facade as a separate
+// function service whose qualifier is the method name. It suits
+// handler-per-method routing, where each route resolves one function
+func RegisterMethods[T any](c *Container, facade T, opts ...RegisterOption) ([]string, error) {
+    return v2.RegisterMethods[T](c, facade, opts...)
+}
+
+// Some returns an Optional holding value
+func Some[T any](value T) Optional[T] {
+    return v2.Some[T](value)
+}
+
+// RegisterOptions registers the option struct T. Fields left at their zero
+// value keep their default:"value" tag, and repeated calls merge: non-zero
+// fields of later calls override earlier ones. Use a pointer field to
+// override a default with a zero value.
+func RegisterOptions[T any](c *Container, opts T, regOpts ...RegisterOption) error {
+    return v2.RegisterOptions[T](c, opts, regOpts...)
+}
+
+// Options returns the option struct T: the registered value, or the
+// defaults of T when RegisterOptions was never called
+func Options[T any](c *Container) (T, error) {
+    return v2.Options[T](c)
+}
+
+// InStage assigns the registration to an init stage
+func InStage(stage int) RegisterOption {
+    return v2.InStage(stage)
+}
+
+// InModule attributes the registration to a module, overriding the module
+// being installed
+func InModule(module string) RegisterOption {
+    return v2.InModule(module)
+}
+
+// NewCachingSource returns a caching wrapper around source
+func NewCachingSource(source ConfigSource, ttl time.Duration) *CachingSource {
+    return v2.NewCachingSource(source, ttl)
+}
+
+// RetryConstruction makes the container retry the factory or provider of
+// the registration when it fails, so a transient failure during Start,
+// such as a DNS hiccup or a dependency still warming up, does not fail the
+// whole boot
+func RetryConstruction(policy RetryPolicy) RegisterOption {
+    return v2.RetryConstruction(policy)
+}
+
+// AsConfig marks a registration as a config struct. ConfigSchemas, the
+// debug handler and the application's -config-schema flag publish a JSON
+// schema for every config registration.
+func AsConfig() RegisterOption {
+    return v2.AsConfig()
+}
+
+// SchemaOf generates the JSON schema of a config type, following the json
+// tags of its fields. A default:"value" tag sets the default. Fields are
+// required unless they are pointers, have a default or are tagged omitempty.
+// A struct type that contains itself is described once under $defs and
+// referenced with $ref.
+func SchemaOf(configType reflect.Type) (*Schema, error) {
+    return v2.SchemaOf(configType)
+}
+
+// MaxScopedInstances returns middleware giving every scope a budget of max
+// scoped instances
+//
+// Experimental: see Scope.
+func MaxScopedInstances(max int) ScopeMiddleware {
+    return v2.MaxScopedInstances(max)
+}
+
+// SeedScope returns middleware registering the value built by seed in
+// every scope, such as a request ID or tenant read from the scope's
+// context
+//
+// Experimental: see Scope.
+func SeedScope(qualifier string, seed func(s *Scope) (interface{}, error)) ScopeMiddleware {
+    return v2.SeedScope(qualifier, seed)
+}
+
+// WithUnexportedInjection makes InjectStruct set unexported fields tagged
+// with di instead of skipping them, for types that keep their dependencies
+// private
+func WithUnexportedInjection() Option {
+    return v2.WithUnexportedInjection()
+}
+
+// TCPProbe succeeds once a TCP connection to address can be opened
+func TCPProbe(address string) Probe {
+    return v2.TCPProbe(address)
+}
+
+// HTTPProbe succeeds once a GET of url returns a 2xx or 3xx status
+func HTTPProbe(url string) Probe {
+    return v2.HTTPProbe(url)
+}
+
+// ProbeFunc wraps a custom readiness check
+func ProbeFunc(name string, check func(ctx context.Context) error) Probe {
+    return v2.ProbeFunc(name, check)
+}
+
+// WaitFor makes the container wait until every probe succeeds before the
+// service is built: before a weak or cached provider runs, and in the
+// construct phase of Start for other services. Probes are retried with
+// exponential backoff according to the wait policy, so a container started
+// alongside its database waits instead of crash-looping.
+func WaitFor(probes ...Probe) RegisterOption {
+    return v2.WaitFor(probes...)
+}
@@ -0,0 +1,177 @@
+//go:build dilite || tinygo
+
+// Package lite provides a minimal dependency injection container that avoids
+// the reflection used by package container. Services are registered through
+// explicit providers and resolved with generics, which keeps the package
+// usable on TinyGo and wasm targets with limited reflection support.
+//
+// The package is gated behind the dilite build tag, and is built by default
+// for TinyGo:
+//
+//	go build -tags dilite ./...
+package lite
+
+import (
+    "errors"
+    "fmt"
+    "sync"
+)
+
+// Provider builds a service, resolving its own dependencies from the container
+type Provider func(c *Container) (interface{}, error)
+
+// Container is a qualifier-keyed registry of lazily built singletons.
+// Providers receive a view of the container tied to their resolution, so a
+// cycle is told apart from concurrent resolutions of the same service.
+type Container struct {
+    shared *registry
+    chain  *chain // Resolution the view belongs to, nil for the container itself
+}
+
+// registry is the state shared by a container and its views
+type registry struct {
+    mu        sync.Mutex             // Guards all maps below and chain.waiting
+    providers map[string]Provider    // Registered providers by qualifier
+    instances map[string]interface{} // Constructed singletons by qualifier
+    building  map[string]*build      // Builds in progress by qualifier
+}
+
+// chain is one resolution: a call to Resolve and the resolutions its
+// providers make through their view
+type chain struct {
+    waiting *build // Build of another chain this one waits for, if any
+}
+
+// build is a provider run in progress; concurrent resolutions of the same
+// qualifier wait for it instead of running the provider again
+type build struct {
+    owner    *chain
+    done     chan struct{} // Closed once instance and err are set
+    instance interface{}
+    err      error
+}
+
+// ErrNotFound is returned when no provider is registered for a qualifier
+var ErrNotFound = errors.New("lite: service not found")
+
+// New creates an empty container
+func New() *Container {
+    return &Container{shared: &registry{
+        providers: make(map[string]Provider),
+        instances: make(map[string]interface{}),
+        building:  make(map[string]*build),
+    }}
+}
+
+// Provide registers a provider that builds the service on first resolution
+func Provide[T any](c *Container, qualifier string, provider func(c *Container) (T, error)) error {
+    if provider == nil {
+        return fmt.Errorf("lite: nil provider for qualifier %s", qualifier)
+    }
+    return c.add(qualifier, func(c *Container) (interface{}, error) {
+        return provider(c)
+    })
+}
+
+// Register registers an already constructed service
+func Register[T any](c *Container, qualifier string, service T) error {
+    return c.add(qualifier, func(*Container) (interface{}, error) {
+        return service, nil
+    })
+}
+
+// Resolve returns the service registered under qualifier as a T
+func Resolve[T any](c *Container, qualifier string) (T, error) {
+    var zero T
+
+    service, err := c.resolve(qualifier)
+    if err != nil {
+        return zero, err
+    }
+
+    typed, ok := service.(T)
+    if !ok {
+        return zero, fmt.Errorf("lite: service %s has type %T, not the requested type", qualifier, service)
+    }
+    return typed, nil
+}
+
+// add stores a provider, rejecting duplicates
+func (c *Container) add(qualifier string, provider Provider) error {
+    c.shared.mu.Lock()
+    defer c.shared.mu.Unlock()
+
+    if _, exists := c.shared.providers[qualifier]; exists {
+        return fmt.Errorf("lite: service already registered for qualifier %s", qualifier)
+    }
+    c.shared.providers[qualifier] = provider
+    return nil
+}
+
+// resolve returns the cached instance or runs the provider without holding
+// the lock, so providers may resolve their own dependencies. A resolution
+// of a qualifier being built by another chain waits for that build, unless
+// the chains wait for each other, which is a cycle.
+func (c *Container) resolve(qualifier string) (interface{}, error) {
+    c.shared.mu.Lock()
+    if instance, ok := c.shared.instances[qualifier]; ok {
+        c.shared.mu.Unlock()
+        return instance, nil
+    }
+    provider, ok := c.shared.providers[qualifier]
+    if !ok {
+        c.shared.mu.Unlock()
+        return nil, fmt.Errorf("%w: %s", ErrNotFound, qualifier)
+    }
+    self := c.chain
+    if self == nil {
+        self = &chain{}
+    }
+    if b, ok := c.shared.building[qualifier]; ok {
+        for w := b; w != nil; w = w.owner.waiting {
+            if w.owner == self {
+                c.shared.mu.Unlock()
+                return nil, fmt.Errorf("lite: dependency cycle while building %s", qualifier)
+            }
+        }
+        self.waiting = b
+        c.shared.mu.Unlock()
+
+        <-b.done
+        c.shared.mu.Lock()
+        self.waiting = nil
+        c.shared.mu.Unlock()
+        return b.instance, b.err
+    }
+    b := &build{owner: self, done: make(chan struct{})}
+    c.shared.building[qualifier] = b
+    c.shared.mu.Unlock()
+
+    finished := false
+    defer func() {
+        if !finished { // The provider panicked
+            c.finish(qualifier, b, nil, fmt.Errorf("lite: building %s: provider panicked", qualifier))
+        }
+    }()
+    instance, err := provider(&Container{shared: c.shared, chain: self})
+    if err != nil {
+        err = fmt.Errorf("lite: building %s: %w", qualifier, err)
+    }
+    finished = true
+    c.finish(qualifier, b, instance, err)
+    return b.instance, b.err
+}
+
+// finish records the outcome of b and releases the resolutions waiting for it
+func (c *Container) finish(qualifier string, b *build, instance interface{}, err error) {
+    c.shared.mu.Lock()
+    defer c.shared.mu.Unlock()
+
+    delete(c.shared.building, qualifier)
+    if err == nil {
+        c.shared.instances[qualifier] = instance
+        b.instance = instance
+    }
+    b.err = err
+    close(b.done)
+}
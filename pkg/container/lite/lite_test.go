@@ -0,0 +1,141 @@
+//go:build dilite || tinygo
+
+package lite
+
+import (
+    "errors"
+    "sync"
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+type greeter interface {
+    Greet() string
+}
+
+type englishGreeter struct {
+    name string
+}
+
+func (g *englishGreeter) Greet() string {
+    return "hello " + g.name
+}
+
+func TestProvideAndResolve(t *testing.T) {
+    c := New()
+    calls := 0
+
+    require.NoError(t, Register(c, "name", "gopher"))
+    require.NoError(t, Provide(c, "greeter", func(c *Container) (greeter, error) {
+        calls++
+        name, err := Resolve[string](c, "name")
+        if err != nil {
+            return nil, err
+        }
+        return &englishGreeter{name: name}, nil
+    }))
+
+    g, err := Resolve[greeter](c, "greeter")
+    require.NoError(t, err)
+    assert.Equal(t, "hello gopher", g.Greet())
+
+    // Providers run once; later resolutions reuse the instance
+    _, err = Resolve[greeter](c, "greeter")
+    require.NoError(t, err)
+    assert.Equal(t, 1, calls)
+}
+
+func TestResolveErrors(t *testing.T) {
+    c := New()
+    require.NoError(t, Register(c, "name", "gopher"))
+
+    _, err := Resolve[string](c, "missing")
+    assert.True(t, errors.Is(err, ErrNotFound))
+
+    _, err = Resolve[int](c, "name")
+    assert.Error(t, err)
+
+    assert.Error(t, Register(c, "name", "again"))
+    assert.Error(t, Provide[string](c, "nilProvider", nil))
+}
+
+func TestResolveCycle(t *testing.T) {
+    c := New()
+    require.NoError(t, Provide(c, "a", func(c *Container) (string, error) {
+        return Resolve[string](c, "b")
+    }))
+    require.NoError(t, Provide(c, "b", func(c *Container) (string, error) {
+        return Resolve[string](c, "a")
+    }))
+
+    _, err := Resolve[string](c, "a")
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "cycle")
+}
+
+func TestResolveConcurrently(t *testing.T) {
+    c := New()
+    release := make(chan struct{})
+    calls := 0
+    require.NoError(t, Provide(c, "slow", func(c *Container) (string, error) {
+        calls++
+        <-release
+        return "ready", nil
+    }))
+
+    // Resolutions of a service being built wait for it, not report a cycle
+    var wg sync.WaitGroup
+    results := make([]string, 4)
+    errs := make([]error, 4)
+    for i := range results {
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+            results[i], errs[i] = Resolve[string](c, "slow")
+        }()
+    }
+    close(release)
+    wg.Wait()
+
+    for i := range results {
+        require.NoError(t, errs[i])
+        assert.Equal(t, "ready", results[i])
+    }
+    assert.Equal(t, 1, calls)
+}
+
+func TestResolveCycleAcrossGoroutines(t *testing.T) {
+    c := New()
+    var started sync.WaitGroup
+    started.Add(2)
+    var once [2]sync.Once
+    require.NoError(t, Provide(c, "a", func(c *Container) (string, error) {
+        once[0].Do(started.Done)
+        started.Wait()
+        return Resolve[string](c, "b")
+    }))
+    require.NoError(t, Provide(c, "b", func(c *Container) (string, error) {
+        once[1].Do(started.Done)
+        started.Wait()
+        return Resolve[string](c, "a")
+    }))
+
+    // Each goroutine builds one service and waits for the other's
+    var wg sync.WaitGroup
+    errs := make([]error, 2)
+    for i, qualifier := range []string{"a", "b"} {
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+            _, errs[i] = Resolve[string](c, qualifier)
+        }()
+    }
+    wg.Wait()
+
+    for _, err := range errs {
+        require.Error(t, err)
+        assert.Contains(t, err.Error(), "dependency cycle")
+    }
+}
@@ -0,0 +1,139 @@
+// Package otelbridge exports container events as OpenTelemetry log records
+// and metrics, so DI health can be monitored with existing OTel pipelines.
+//
+// The bridge depends on the small LogEmitter and Meter interfaces rather
+// than the OTel SDK. Adapt an OTel log.Logger and metric.Meter like this:
+//
+//	type otelLogs struct{ logger log.Logger }
+//
+//	func (o otelLogs) Emit(ctx context.Context, r otelbridge.LogRecord) {
+//	    var record log.Record
+//	    record.SetTimestamp(r.Timestamp)
+//	    record.SetSeverity(log.Severity(r.Severity))
+//	    record.SetSeverityText(r.SeverityText)
+//	    record.SetBody(log.StringValue(r.Body))
+//	    for key, value := range r.Attributes {
+//	        record.AddAttributes(log.String(key, value))
+//	    }
+//	    o.logger.Emit(ctx, record)
+//	}
+//
+// and a Meter whose Int64Counter wraps metric.Meter.Int64Counter.
+package otelbridge
+
+import (
+    "context"
+    "fmt"
+    "time"
+
+    "di-example/pkg/container"
+)
+
+// Severity numbers as defined by the OpenTelemetry log data model
+const (
+    SeverityInfo  = 9
+    SeverityError = 17
+)
+
+// LogRecord is the part of an OTel log record the bridge fills in
+type LogRecord struct {
+    Timestamp    time.Time
+    Severity     int
+    SeverityText string
+    Body         string
+    Attributes   map[string]string
+}
+
+// LogEmitter receives log records, e.g. an adapted OTel log.Logger
+type LogEmitter interface {
+    Emit(ctx context.Context, record LogRecord)
+}
+
+// Counter is a monotonic int64 counter, e.g. an OTel metric.Int64Counter
+type Counter interface {
+    Add(ctx context.Context, increment int64, attributes map[string]string)
+}
+
+// Meter creates counters, e.g. an adapted OTel metric.Meter
+type Meter interface {
+    Int64Counter(name, description string) (Counter, error)
+}
+
+// Metric names recorded by the bridge
+const (
+    MetricEvents          = "di.events"
+    MetricResolveFailures = "di.resolve.failures"
+)
+
+// Exporter converts container events to log records and counters
+type Exporter struct {
+    logs            LogEmitter
+    events          Counter
+    resolveFailures Counter
+}
+
+// New creates an exporter. Either logs or meter may be nil to export only
+// metrics or only logs.
+func New(logs LogEmitter, meter Meter) (*Exporter, error) {
+    exporter := &Exporter{logs: logs}
+    if meter == nil {
+        return exporter, nil
+    }
+
+    var err error
+    if exporter.events, err = meter.Int64Counter(MetricEvents, "Container events by kind"); err != nil {
+        return nil, fmt.Errorf("failed to create %s counter: %w", MetricEvents, err)
+    }
+    if exporter.resolveFailures, err = meter.Int64Counter(MetricResolveFailures, "Failed resolutions by qualifier"); err != nil {
+        return nil, fmt.Errorf("failed to create %s counter: %w", MetricResolveFailures, err)
+    }
+    return exporter, nil
+}
+
+// Attach subscribes the exporter to every event of c
+func (e *Exporter) Attach(c *container.Container) {
+    c.OnEvent(e.Export)
+}
+
+// Export converts one event
+func (e *Exporter) Export(event container.Event) {
+    ctx := context.Background()
+    attributes := map[string]string{"di.event": string(event.Kind)}
+    if event.Qualifier != "" {
+        attributes["di.qualifier"] = event.Qualifier
+    }
+
+    if e.events != nil {
+        e.events.Add(ctx, 1, map[string]string{"di.event": string(event.Kind)})
+    }
+    if e.resolveFailures != nil && event.Kind == container.EventResolveFailed {
+        e.resolveFailures.Add(ctx, 1, map[string]string{"di.qualifier": event.Qualifier})
+    }
+
+    if e.logs == nil {
+        return
+    }
+    record := LogRecord{
+        Timestamp:    event.Time,
+        Severity:     SeverityInfo,
+        SeverityText: "INFO",
+        Body:         describe(event),
+        Attributes:   attributes,
+    }
+    if event.Detail != "" {
+        attributes["di.detail"] = event.Detail
+    }
+    if event.Failed() {
+        record.Severity, record.SeverityText = SeverityError, "ERROR"
+        attributes["exception.message"] = event.Err.Error()
+    }
+    e.logs.Emit(ctx, record)
+}
+
+// describe renders the log body of an event
+func describe(event container.Event) string {
+    if event.Qualifier == "" {
+        return "container " + string(event.Kind)
+    }
+    return fmt.Sprintf("container %s %s", event.Kind, event.Qualifier)
+}
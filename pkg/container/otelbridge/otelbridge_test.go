@@ -0,0 +1,91 @@
+package otelbridge
+
+import (
+    "context"
+    "errors"
+    "testing"
+
+    "di-example/pkg/container"
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+type recordingLogs struct {
+    records []LogRecord
+}
+
+func (r *recordingLogs) Emit(ctx context.Context, record LogRecord) {
+    r.records = append(r.records, record)
+}
+
+type recordingCounter struct {
+    totals map[string]int64 // Attribute rendering -> total
+}
+
+func (r *recordingCounter) Add(ctx context.Context, increment int64, attributes map[string]string) {
+    for key, value := range attributes {
+        r.totals[key+"="+value] += increment
+    }
+}
+
+type recordingMeter struct {
+    counters map[string]*recordingCounter
+    fail     bool
+}
+
+func (m *recordingMeter) Int64Counter(name, description string) (Counter, error) {
+    if m.fail {
+        return nil, errors.New("meter closed")
+    }
+    counter := &recordingCounter{totals: make(map[string]int64)}
+    m.counters[name] = counter
+    return counter, nil
+}
+
+func TestExporter(t *testing.T) {
+    logs := &recordingLogs{}
+    meter := &recordingMeter{counters: make(map[string]*recordingCounter)}
+    exporter, err := New(logs, meter)
+    require.NoError(t, err)
+
+    c := container.NewContainer()
+    exporter.Attach(c)
+
+    require.NoError(t, c.Register("users", "users"))
+    _, err = c.Resolve("missing")
+    require.Error(t, err)
+    require.NoError(t, c.Start(context.Background()))
+    require.NoError(t, c.Stop(context.Background()))
+
+    var bodies []string
+    for _, record := range logs.records {
+        bodies = append(bodies, record.Body)
+    }
+    assert.Equal(t, []string{
+        "container register users",
+        "container resolve_failed missing",
+        "container starting",
+        "container started",
+        "container stopped",
+    }, bodies)
+
+    failure := logs.records[1]
+    assert.Equal(t, SeverityError, failure.Severity)
+    assert.Equal(t, "missing", failure.Attributes["di.qualifier"])
+    assert.Contains(t, failure.Attributes["exception.message"], "no service found")
+    assert.Equal(t, "singleton", logs.records[0].Attributes["di.detail"])
+
+    events := meter.counters[MetricEvents].totals
+    assert.Equal(t, int64(1), events["di.event=register"])
+    assert.Equal(t, int64(1), events["di.event=started"])
+    assert.Equal(t, int64(1), meter.counters[MetricResolveFailures].totals["di.qualifier=missing"])
+}
+
+func TestNew_MeterError(t *testing.T) {
+    _, err := New(nil, &recordingMeter{fail: true})
+    assert.ErrorContains(t, err, "meter closed")
+
+    exporter, err := New(nil, nil)
+    require.NoError(t, err)
+    exporter.Export(container.Event{Kind: container.EventStarted}) // No sinks, no panic
+}
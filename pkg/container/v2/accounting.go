@@ -0,0 +1,70 @@
+package container
+
+import (
+    "sort"
+)
+
+// ModuleUsage is what one module costs the container. Module is empty for
+// registrations, workers and hooks made outside any module.
+type ModuleUsage struct {
+    Module        string `json:"module"`
+    Registrations int    `json:"registrations"` // Registered qualifiers
+    Instances     int    `json:"instances"`     // Built singletons and cached weak instances
+    Workers       int    `json:"workers"`       // Running goroutines started with Go
+    Lifecycles    int    `json:"lifecycles"`    // Started hooks that have not stopped
+}
+
+// ModuleUsage returns the resources attributed to each module, sorted by
+// module name with top-level usage first
+func (c *Container) ModuleUsage() []ModuleUsage {
+    usage := make(map[string]*ModuleUsage)
+    entry := func(module string) *ModuleUsage {
+        if u, ok := usage[module]; ok {
+            return u
+        }
+        u := &ModuleUsage{Module: module}
+        usage[module] = u
+        return u
+    }
+
+    instances := c.builtInstances()
+    c.mu.RLock()
+    for qualifier, reg := range c.regs {
+        u := entry(reg.module)
+        u.Registrations++
+        if _, built := instances[qualifier]; built {
+            u.Instances++
+        }
+    }
+    c.mu.RUnlock()
+
+    c.accountingMu.Lock()
+    for _, w := range c.workers {
+        entry(w.module).Workers++
+    }
+    for module, open := range c.openLifecycles {
+        entry(module).Lifecycles += open
+    }
+    c.accountingMu.Unlock()
+
+    result := make([]ModuleUsage, 0, len(usage))
+    for _, u := range usage {
+        result = append(result, *u)
+    }
+    sort.Slice(result, func(i, j int) bool {
+        return result[i].Module < result[j].Module
+    })
+    return result
+}
+
+// trackLifecycle counts a hook of module as started (delta 1) or stopped
+// (delta -1)
+func (c *Container) trackLifecycle(module string, delta int) {
+    c.accountingMu.Lock()
+    defer c.accountingMu.Unlock()
+
+    c.openLifecycles[module] += delta
+    if c.openLifecycles[module] <= 0 {
+        delete(c.openLifecycles, module)
+    }
+}
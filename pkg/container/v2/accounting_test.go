@@ -0,0 +1,49 @@
+package container
+
+import (
+    "context"
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+type startedService struct{}
+
+func (s *startedService) OnStart(ctx context.Context) error { return nil }
+
+func TestModuleUsage_AttributesResourcesToModules(t *testing.T) {
+    c := NewContainer()
+    require.NoError(t, c.Register("clock", &testServiceImpl{}))
+    require.NoError(t, c.Install(Module{Name: "billing", Setup: func(c *Container) error {
+        if err := c.Register("invoices", &startedService{}); err != nil {
+            return err
+        }
+        if err := c.RegisterFactory("reports", func(c *Container) (interface{}, error) {
+            return &testServiceImpl{}, nil
+        }); err != nil {
+            return err
+        }
+        c.Go("reminders", func(ctx context.Context) error {
+            <-ctx.Done()
+            return nil
+        })
+        c.Append(Hook{Name: "billing"})
+        return nil
+    }}))
+    require.NoError(t, c.Start(context.Background()))
+
+    assert.Equal(t, []ModuleUsage{
+        {Module: "", Registrations: 1, Instances: 1},
+        {Module: "billing", Registrations: 2, Instances: 1, Workers: 1, Lifecycles: 2},
+    }, c.ModuleUsage())
+
+    // Resolving the factory builds an instance; Stop ends the rest
+    _, err := c.Resolve("reports")
+    require.NoError(t, err)
+    require.NoError(t, c.Stop(context.Background()))
+    assert.Equal(t, []ModuleUsage{
+        {Module: "", Registrations: 1, Instances: 1},
+        {Module: "billing", Registrations: 2, Instances: 2},
+    }, c.ModuleUsage())
+}
@@ -0,0 +1,117 @@
+package container
+
+import (
+    "reflect"
+    "sync/atomic"
+    "time"
+)
+
+// AuditKind tells how a sensitive service was accessed
+type AuditKind string
+
+const (
+    AuditResolve AuditKind = "resolve" // Resolve and functions built on it
+    AuditInject  AuditKind = "inject"  // InjectStruct filling a field
+)
+
+// AuditEvent records one access to a sensitive service
+type AuditEvent struct {
+    Time      time.Time
+    Kind      AuditKind
+    Qualifier string
+    Target    string // Injected field as "pkg.Type.Field", empty for Resolve
+    Caller    string // file:line of the first caller outside the container
+    Goroutine uint64
+    Err       error  // Set when the resolution failed
+}
+
+// AuditSink receives audit events. Audit is called synchronously on the
+// resolving goroutine, so sinks should hand events off quickly.
+type AuditSink interface {
+    Audit(event AuditEvent)
+}
+
+// AuditSinkFunc adapts a function to AuditSink
+type AuditSinkFunc func(event AuditEvent)
+
+// Audit calls f(event)
+func (f AuditSinkFunc) Audit(event AuditEvent) {
+    f(event)
+}
+
+// Sensitive marks a registration as sensitive, e.g. a service holding
+// credentials. Every resolution and injection of it emits an AuditEvent.
+func Sensitive() RegisterOption {
+    return func(r *registration) {
+        r.sensitive = true
+    }
+}
+
+// SetAuditSink installs the sink for audit events. Without a sink, accesses
+// to sensitive services are logged at Info level.
+func (c *Container) SetAuditSink(sink AuditSink) {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    c.auditSink = sink
+}
+
+// audit emits an event if qualifier is sensitive, attributed to the caller
+func (c *Container) audit(kind AuditKind, qualifier, target string, err error) {
+    c.auditAs(nil, kind, qualifier, target, err)
+}
+
+// auditCaller captures the caller for a later auditAs, or nil when no
+// registration is sensitive
+func (c *Container) auditCaller() *access {
+    if atomic.LoadInt32(&c.sensitiveCount) == 0 {
+        return nil
+    }
+    caller := currentAccess()
+    return &caller
+}
+
+// auditAs emits an event if qualifier is sensitive, attributed to caller or,
+// when nil, to the current caller
+func (c *Container) auditAs(caller *access, kind AuditKind, qualifier, target string, err error) {
+    // Fast path: nothing is sensitive
+    if atomic.LoadInt32(&c.sensitiveCount) == 0 {
+        return
+    }
+
+    c.mu.RLock()
+    reg, ok := c.regs[qualifier]
+    sink := c.auditSink
+    c.mu.RUnlock()
+    if !ok || !reg.sensitive {
+        return
+    }
+
+    if caller == nil {
+        current := currentAccess()
+        caller = &current
+    }
+    event := AuditEvent{
+        Time:      c.clock.Now(),
+        Kind:      kind,
+        Qualifier: qualifier,
+        Target:    target,
+        Caller:    caller.site,
+        Goroutine: caller.goroutine,
+        Err:       err,
+    }
+    if sink == nil {
+        c.log.Infow("Sensitive service accessed",
+            "kind", kind,
+            "qualifier", qualifier,
+            "target", target,
+            "caller", caller.site,
+            "error", err)
+        return
+    }
+    sink.Audit(event)
+}
+
+// auditTarget names an injected field as "pkg.Type.Field"
+func auditTarget(structType reflect.Type, field reflect.StructField) string {
+    return structType.String() + "." + field.Name
+}
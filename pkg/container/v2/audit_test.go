@@ -0,0 +1,75 @@
+package container
+
+import (
+    "context"
+    "sync"
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+// auditRecorder collects audit events
+type auditRecorder struct {
+    mu     sync.Mutex
+    events []AuditEvent
+}
+
+func (r *auditRecorder) Audit(event AuditEvent) {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+    r.events = append(r.events, event)
+}
+
+type credentialConsumer struct {
+    Vault  TestService           `di:"vault"`
+    Users  TestService           `di:"users"`
+    Backup Optional[TestService] `di:"vault"`
+}
+
+func TestContainer_AuditSensitive(t *testing.T) {
+    container := NewContainer()
+    recorder := &auditRecorder{}
+    container.SetAuditSink(recorder)
+
+    require.NoError(t, container.Register("vault", &testServiceImpl{name: "vault"}, Sensitive()))
+    require.NoError(t, container.Register("users", &testServiceImpl{name: "users"}))
+
+    _, err := container.Resolve("vault")
+    require.NoError(t, err)
+    _, err = container.Resolve("users")
+    require.NoError(t, err)
+    require.NoError(t, container.InjectStruct(&credentialConsumer{}))
+    _, err = container.ResolveAsync("vault").Get(context.Background())
+    require.NoError(t, err)
+
+    require.Len(t, recorder.events, 4)
+
+    resolved := recorder.events[0]
+    assert.Equal(t, AuditResolve, resolved.Kind)
+    assert.Equal(t, "vault", resolved.Qualifier)
+    assert.Empty(t, resolved.Target)
+    assert.Contains(t, resolved.Caller, "audit_test.go")
+    assert.False(t, resolved.Time.IsZero())
+
+    injected := recorder.events[1]
+    assert.Equal(t, AuditInject, injected.Kind)
+    assert.Equal(t, "container.credentialConsumer.Vault", injected.Target)
+    assert.Contains(t, injected.Caller, "audit_test.go")
+    assert.Equal(t, "container.credentialConsumer.Backup", recorder.events[2].Target)
+
+    async := recorder.events[3]
+    assert.Equal(t, AuditResolve, async.Kind)
+    assert.Contains(t, async.Caller, "audit_test.go")
+}
+
+func TestContainer_AuditWithoutSensitive(t *testing.T) {
+    container := NewContainer()
+    var events int
+    container.SetAuditSink(AuditSinkFunc(func(AuditEvent) { events++ }))
+    require.NoError(t, container.Register("users", &testServiceImpl{}))
+
+    _, err := container.Resolve("users")
+    require.NoError(t, err)
+    assert.Zero(t, events)
+}
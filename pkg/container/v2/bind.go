@@ -0,0 +1,71 @@
+package container
+
+import (
+    "fmt"
+    "reflect"
+)
+
+// Bind maps an interface type, given as a nil pointer like
+// (*UserService)(nil), to the service registered under qualifier.
+// ResolveByType and di:"" fields of that interface then use it even when
+// other services implement the interface too. The service may be
+// registered before or after the binding; its type is checked on
+// resolution.
+func (c *Container) Bind(ifacePtr interface{}, qualifier string) error {
+    ptrType := reflect.TypeOf(ifacePtr)
+    if ptrType == nil || ptrType.Kind() != reflect.Ptr || ptrType.Elem().Kind() != reflect.Interface {
+        return fmt.Errorf("bind requires a pointer to an interface, got %v", ptrType)
+    }
+    return c.bind(ptrType.Elem(), qualifier)
+}
+
+// BindInterface is the generic form of Bind
+func BindInterface[I any](c *Container, qualifier string) error {
+    return c.Bind((*I)(nil), qualifier)
+}
+
+// ResolveInterface resolves the implementation of interface I, see
+// ResolveByType
+func ResolveInterface[I any](c *Container) (I, error) {
+    var zero I
+    service, err := c.ResolveByType(reflect.TypeOf((*I)(nil)).Elem())
+    if err != nil {
+        return zero, err
+    }
+    return service.(I), nil
+}
+
+// bind records the binding of iface to qualifier
+func (c *Container) bind(iface reflect.Type, qualifier string) error {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+
+    if bound, ok := c.bindings[iface]; ok {
+        c.log.Errorw("Interface already bound",
+            "interface", iface,
+            "qualifier", bound)
+        return fmt.Errorf("interface %v is already bound to %s", iface, bound)
+    }
+    if service, ok := c.services[qualifier]; ok && !reflect.TypeOf(service).Implements(iface) {
+        return &TypeMismatchError{
+            Qualifier: qualifier,
+            Type:      reflect.TypeOf(service),
+            Want:      iface,
+            Detail:    mismatchDetail(reflect.TypeOf(service), iface),
+        }
+    }
+
+    c.log.Infow("Binding interface",
+        "interface", iface,
+        "qualifier", qualifier)
+    c.bindings[iface] = qualifier
+    return nil
+}
+
+// boundQualifier returns the qualifier bound to t, if any
+func (c *Container) boundQualifier(t reflect.Type) (string, bool) {
+    c.mu.RLock()
+    defer c.mu.RUnlock()
+    qualifier, ok := c.bindings[t]
+    return qualifier, ok
+}
@@ -0,0 +1,62 @@
+package container
+
+import (
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+type ordersMigrations struct{}
+
+func (ordersMigrations) Migrations() []string { return []string{"create orders"} }
+
+func TestBind_SelectsImplementation(t *testing.T) {
+    c := NewContainer()
+    require.NoError(t, c.Register("users", usersMigrations{}))
+    require.NoError(t, c.Register("orders", ordersMigrations{}))
+
+    // Two implementations are ambiguous until one is bound
+    _, err := ResolveInterface[migrationProvider](c)
+    assert.ErrorContains(t, err, "candidates users, orders")
+
+    require.NoError(t, c.Bind((*migrationProvider)(nil), "orders"))
+    provider, err := ResolveInterface[migrationProvider](c)
+    require.NoError(t, err)
+    assert.Equal(t, []string{"create orders"}, provider.Migrations())
+
+    target := &struct {
+        Migrations migrationProvider `di:""`
+    }{}
+    require.NoError(t, c.InjectStruct(target))
+    assert.Equal(t, ordersMigrations{}, target.Migrations)
+
+    assert.EqualError(t, BindInterface[migrationProvider](c, "users"),
+        "interface container.migrationProvider is already bound to orders")
+}
+
+func TestBind_BeforeRegistration(t *testing.T) {
+    c := NewContainer()
+    require.NoError(t, BindInterface[migrationProvider](c, "users"))
+    require.NoError(t, BindInterface[TestService](c, "port"))
+    require.NoError(t, c.Register("users", usersMigrations{}))
+    require.NoError(t, c.Register("port", 8080))
+
+    provider, err := ResolveInterface[migrationProvider](c)
+    require.NoError(t, err)
+    assert.Equal(t, usersMigrations{}, provider)
+
+    // A wrong binding is reported once the service can be checked
+    _, err = ResolveInterface[TestService](c)
+    assert.ErrorContains(t, err, "service port has type int, which is not assignable to container.TestService")
+}
+
+func TestBind_Errors(t *testing.T) {
+    c := NewContainer()
+    require.NoError(t, c.Register("port", 8080))
+
+    assert.ErrorContains(t, c.Bind(migrationProvider(nil), "port"), "requires a pointer to an interface")
+    assert.EqualError(t, c.Bind(new(int), "port"), "bind requires a pointer to an interface, got *int")
+    assert.EqualError(t, c.Bind((*migrationProvider)(nil), "port"),
+        "service port has type int, which is not assignable to container.migrationProvider: missing methods Migrations")
+}
@@ -0,0 +1,154 @@
+package container
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "reflect"
+    "sort"
+)
+
+// Budget limits the dependencies of a service. Zero values mean unlimited.
+// Fan-in counts the distinct services and injected struct types depending
+// on a service; fan-out counts the services it depends on.
+type Budget struct {
+    MaxFanIn  int
+    MaxFanOut int
+}
+
+// Budgets declares dependency budgets, checked by Validate. A service uses
+// its own entry in Services, else the entry of its module, else Default.
+// Budgets flag "god services" that too much of the graph depends on.
+type Budgets struct {
+    Default  Budget
+    Modules  map[string]Budget
+    Services map[string]Budget
+}
+
+// DependsOn declares the services a registration depends on. The edges are
+// used for dependency budgets; InjectStruct records its edges itself.
+func DependsOn(qualifiers ...string) RegisterOption {
+    return func(r *registration) {
+        r.dependsOn = append(r.dependsOn, qualifiers...)
+    }
+}
+
+// SetBudgets installs dependency budgets
+func (c *Container) SetBudgets(budgets Budgets) {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+
+    c.log.Infow("Setting dependency budgets",
+        "services", len(budgets.Services),
+        "modules", len(budgets.Modules))
+    c.budgets = budgets
+}
+
+// Validate checks the wiring of the container without building anything,
+// so CI can fail before the application starts: the parameters of every
+// provider and the di tags of targets, struct values, pointers to structs
+// or reflect.Types, with their nested structs and injection methods. Every
+// missing service, ambiguous match by type, type mismatch and cycle of
+// group ordering constraints are reported together. Types are known for
+// instances, built services and providers; a factory that was not built
+// yet only satisfies lookups by qualifier.
+//
+// Once the wiring is sound, Validate runs the registered validators and
+// checks the dependency budgets, reporting every violation together. Start
+// runs those checks in its validate phase.
+func (c *Container) Validate(targets ...interface{}) error {
+    if err := c.checkWiring(targets); err != nil {
+        return err
+    }
+    return c.validatePhase(context.Background())
+}
+
+// budgetFor returns the budget that applies to reg
+func (b Budgets) budgetFor(reg *registration) Budget {
+    if budget, ok := b.Services[reg.qualifier]; ok {
+        return budget
+    }
+    if budget, ok := b.Modules[reg.module]; ok && reg.module != "" {
+        return budget
+    }
+    return b.Default
+}
+
+// recordConsumer records that qualifier was injected into field of structType
+func (c *Container) recordConsumer(structType reflect.Type, field reflect.StructField, qualifier string) {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+
+    if c.consumers[qualifier] == nil {
+        c.consumers[qualifier] = make(map[string]bool)
+        c.consumerFields[qualifier] = make(map[string]bool)
+    }
+    c.consumers[qualifier][structType.String()] = true
+    c.consumerFields[qualifier][auditTarget(structType, field)] = true
+}
+
+// checkBudgets lists every service over its fan-in or fan-out budget
+func (c *Container) checkBudgets() error {
+    c.mu.RLock()
+    defer c.mu.RUnlock()
+
+    // Dependents per qualifier: declared edges plus injected struct types
+    dependents := make(map[string]map[string]bool)
+    for qualifier, components := range c.consumers {
+        dependents[qualifier] = make(map[string]bool)
+        for component := range components {
+            dependents[qualifier][component] = true
+        }
+    }
+    for _, reg := range c.regs {
+        for _, dependency := range reg.dependsOn {
+            if dependents[dependency] == nil {
+                dependents[dependency] = make(map[string]bool)
+            }
+            dependents[dependency][reg.qualifier] = true
+        }
+    }
+
+    var errs []error
+    for _, qualifier := range c.order {
+        reg := c.regs[qualifier]
+        budget := c.budgets.budgetFor(reg)
+
+        if fanIn := len(dependents[qualifier]); budget.MaxFanIn > 0 && fanIn > budget.MaxFanIn {
+            errs = append(errs, fmt.Errorf("%s has fan-in %d over budget %d (depended on by %v)",
+                qualifier, fanIn, budget.MaxFanIn, sortedKeys(dependents[qualifier])))
+        }
+        if fanOut := len(uniqueStrings(reg.dependsOn)); budget.MaxFanOut > 0 && fanOut > budget.MaxFanOut {
+            errs = append(errs, fmt.Errorf("%s has fan-out %d over budget %d",
+                qualifier, fanOut, budget.MaxFanOut))
+        }
+    }
+
+    if len(errs) > 0 {
+        c.log.Errorw("Dependency budgets exceeded", "violations", len(errs))
+    }
+    return errors.Join(errs...)
+}
+
+// sortedKeys returns the keys of set in ascending order
+func sortedKeys(set map[string]bool) []string {
+    keys := make([]string, 0, len(set))
+    for key := range set {
+        keys = append(keys, key)
+    }
+    sort.Strings(keys)
+    return keys
+}
+
+// uniqueStrings returns values without duplicates, keeping the first of each
+func uniqueStrings(values []string) []string {
+    seen := make(map[string]bool, len(values))
+    var unique []string
+    for _, value := range values {
+        if !seen[value] {
+            seen[value] = true
+            unique = append(unique, value)
+        }
+    }
+    return unique
+}
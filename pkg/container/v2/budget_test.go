@@ -0,0 +1,60 @@
+package container
+
+import (
+    "context"
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+type billingHandler struct {
+    Users TestService `di:"users"`
+}
+
+type adminHandler struct {
+    Users TestService `di:"users"`
+    Audit TestService `di:"audit"`
+}
+
+func TestContainer_ValidateBudgets(t *testing.T) {
+    container := NewContainer()
+    require.NoError(t, container.Register("users", &testServiceImpl{name: "users"}))
+    require.NoError(t, container.Register("audit", &testServiceImpl{name: "audit"}, DependsOn("users")))
+    require.NoError(t, container.Register("reports", &testServiceImpl{name: "reports"},
+        DependsOn("users", "audit", "users")))
+
+    require.NoError(t, container.InjectStruct(&billingHandler{}))
+    require.NoError(t, container.InjectStruct(&adminHandler{}))
+    require.NoError(t, container.InjectStruct(&adminHandler{})) // Same component counts once
+
+    // Without budgets everything passes
+    assert.NoError(t, container.Validate())
+
+    container.SetBudgets(Budgets{
+        Default:  Budget{MaxFanIn: 3},
+        Services: map[string]Budget{"reports": {MaxFanOut: 1}},
+    })
+    err := container.Validate()
+    require.Error(t, err)
+    assert.Contains(t, err.Error(),
+        "users has fan-in 4 over budget 3 (depended on by [audit container.adminHandler container.billingHandler reports])")
+    assert.Contains(t, err.Error(), "reports has fan-out 2 over budget 1")
+    assert.NotContains(t, err.Error(), "audit has")
+
+    // Start fails in its validate phase
+    err = container.Start(context.Background())
+    assert.ErrorContains(t, err, "startup phase validate failed")
+}
+
+func TestBudgets_BudgetFor(t *testing.T) {
+    budgets := Budgets{
+        Default:  Budget{MaxFanIn: 10},
+        Modules:  map[string]Budget{"billing": {MaxFanIn: 5}},
+        Services: map[string]Budget{"invoices": {MaxFanIn: 1}},
+    }
+
+    assert.Equal(t, 1, budgets.budgetFor(&registration{qualifier: "invoices", module: "billing"}).MaxFanIn)
+    assert.Equal(t, 5, budgets.budgetFor(&registration{qualifier: "payments", module: "billing"}).MaxFanIn)
+    assert.Equal(t, 10, budgets.budgetFor(&registration{qualifier: "users"}).MaxFanIn)
+}
@@ -0,0 +1,117 @@
+package container
+
+import (
+    "errors"
+    "fmt"
+)
+
+// binding is a registration collected by the Builder
+type binding struct {
+    qualifier   string
+    lifetime    Lifetime
+    instance    interface{}
+    provider    WeakProvider
+    scoped      ScopedProvider
+    constructor interface{}
+    opts        []RegisterOption
+}
+
+// Builder collects bindings and validates them all at once in Build:
+//
+//	b := container.NewBuilder()
+//	b.Provide("userService", services.NewUserService())
+//	c, err := b.Build()
+//
+// Where NewContainer returns a live container mutated by Register calls,
+// the container of a Builder has its registrations fixed once Build
+// succeeds.
+type Builder struct {
+    options  []Option
+    bindings []binding
+}
+
+// NewBuilder creates an empty builder. Options such as WithLogger or
+// WithStrictMode configure the container it builds.
+func NewBuilder(opts ...Option) *Builder {
+    return &Builder{options: opts}
+}
+
+// Provide binds an already constructed singleton instance
+func (b *Builder) Provide(qualifier string, instance interface{}, opts ...RegisterOption) *Builder {
+    b.bindings = append(b.bindings, binding{
+        qualifier: qualifier,
+        lifetime:  Singleton,
+        instance:  instance,
+        opts:      opts,
+    })
+    return b
+}
+
+// ProvideWeak binds a service with the Weak lifetime built by provider
+func (b *Builder) ProvideWeak(qualifier string, provider WeakProvider, opts ...RegisterOption) *Builder {
+    b.bindings = append(b.bindings, binding{
+        qualifier: qualifier,
+        lifetime:  Weak,
+        provider:  provider,
+        opts:      opts,
+    })
+    return b
+}
+
+// ProvideScoped binds a service with the Scoped lifetime: every Scope
+// builds its own instance with provider.
+//
+// Experimental: see Scope.
+func (b *Builder) ProvideScoped(qualifier string, provider ScopedProvider, opts ...RegisterOption) *Builder {
+    b.bindings = append(b.bindings, binding{
+        qualifier: qualifier,
+        lifetime:  Scoped,
+        scoped:    provider,
+        opts:      opts,
+    })
+    return b
+}
+
+// ProvideTransient binds a service with the Transient lifetime: every
+// resolve calls constructor, a func() T, func() (T, error) or
+// func(*Container) (T, error)
+func (b *Builder) ProvideTransient(qualifier string, constructor interface{}, opts ...RegisterOption) *Builder {
+    b.bindings = append(b.bindings, binding{
+        qualifier:   qualifier,
+        lifetime:    Transient,
+        constructor: constructor,
+        opts:        opts,
+    })
+    return b
+}
+
+// Build registers every binding and returns the container. All binding
+// errors are reported together rather than stopping at the first. The
+// container is frozen, so its registrations do not change, see Freeze.
+func (b *Builder) Build() (*Container, error) {
+    c := NewContainer(b.options...)
+
+    var errs []error
+    for _, bind := range b.bindings {
+        var err error
+        switch bind.lifetime {
+        case Weak:
+            err = c.RegisterWeak(bind.qualifier, bind.provider, bind.opts...)
+        case Scoped:
+            err = c.RegisterScoped(bind.qualifier, bind.scoped, bind.opts...)
+        case Transient:
+            opts := append([]RegisterOption{WithLifetime(Transient)}, bind.opts...)
+            err = c.Register(bind.qualifier, bind.constructor, opts...)
+        default:
+            err = c.Register(bind.qualifier, bind.instance, bind.opts...)
+        }
+        if err != nil {
+            errs = append(errs, fmt.Errorf("binding %q: %w", bind.qualifier, err))
+        }
+    }
+    if err := errors.Join(errs...); err != nil {
+        return nil, err
+    }
+    c.Freeze()
+    return c, nil
+}
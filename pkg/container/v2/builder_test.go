@@ -0,0 +1,89 @@
+package container
+
+import (
+    "context"
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+type builtGreeter struct {
+    name string
+}
+
+func TestBuilder_Build(t *testing.T) {
+    builds := 0
+    c, err := NewBuilder().
+        Provide("greeter", &builtGreeter{name: "eager"}, InStage(StageDomain)).
+        ProvideWeak("lazyGreeter", func() (interface{}, error) {
+            builds++
+            return &builtGreeter{name: "lazy"}, nil
+        }).
+        Build()
+    require.NoError(t, err)
+    assert.Equal(t, 0, builds)
+
+    got, err := c.Resolve("lazyGreeter")
+    require.NoError(t, err)
+    assert.Equal(t, "lazy", got.(*builtGreeter).name)
+
+    target := &struct {
+        Greeter *builtGreeter `di:"greeter"`
+    }{}
+    require.NoError(t, c.InjectStruct(target))
+    assert.Equal(t, "eager", target.Greeter.name)
+
+    require.NoError(t, c.Start(context.Background()))
+    require.NoError(t, c.Stop(context.Background()))
+}
+
+func TestBuilder_BuildReportsAllErrors(t *testing.T) {
+    _, err := NewBuilder().
+        Provide("nil", nil).
+        Provide("dup", &builtGreeter{}).
+        Provide("dup", &builtGreeter{}).
+        ProvideWeak("noProvider", nil).
+        Build()
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), `binding "nil"`)
+    assert.Contains(t, err.Error(), `binding "dup"`)
+    assert.Contains(t, err.Error(), `binding "noProvider"`)
+}
+
+func TestBuilder_ScopedAndTransient(t *testing.T) {
+    built := 0
+    c, err := NewBuilder(WithStrictMode()).
+        ProvideScoped("request", func(*Scope) (interface{}, error) {
+            return &builtGreeter{name: "request"}, nil
+        }).
+        ProvideTransient("visitor", func() *builtGreeter {
+            built++
+            return &builtGreeter{name: "visitor"}
+        }).
+        Build()
+    require.NoError(t, err)
+
+    first, err := c.Resolve("visitor")
+    require.NoError(t, err)
+    second, err := c.Resolve("visitor")
+    require.NoError(t, err)
+    assert.NotSame(t, first, second)
+    assert.Equal(t, 2, built)
+
+    scope := c.NewScope(context.Background())
+    request, err := scope.Resolve("request")
+    require.NoError(t, err)
+    again, err := scope.Resolve("request")
+    require.NoError(t, err)
+    assert.Same(t, request, again)
+    require.NoError(t, scope.Close(nil))
+}
+
+func TestBuilder_BuildFreezes(t *testing.T) {
+    c, err := NewBuilder().Provide("greeter", &builtGreeter{}).Build()
+    require.NoError(t, err)
+
+    assert.True(t, c.Frozen())
+    assert.Error(t, c.Register("other", &builtGreeter{}))
+}
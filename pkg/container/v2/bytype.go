@@ -0,0 +1,82 @@
+package container
+
+import (
+    "fmt"
+    "reflect"
+    "strings"
+)
+
+// ResolveByType returns the service bound to t with Bind or else the only
+// registered service assignable to t, for wiring without qualifiers. It
+// fails when no service matches or when several do, listing the
+// candidates. Nothing is built to learn its type: instances, providers and
+// the results declared by constructors are matched, factories not built
+// yet only through Bind, and scoped services never.
+func (c *Container) ResolveByType(t reflect.Type) (interface{}, error) {
+    qualifier, err := c.qualifierForType(t)
+    if err != nil {
+        return nil, err
+    }
+    if qualifier == "" {
+        return nil, &ServiceNotFoundError{Type: t}
+    }
+    service, err := c.Resolve(qualifier)
+    if err != nil {
+        return nil, err
+    }
+    // Bindings are not checked until the service is built
+    if !reflect.TypeOf(service).AssignableTo(t) {
+        return nil, &TypeMismatchError{
+            Qualifier: qualifier,
+            Type:      reflect.TypeOf(service),
+            Want:      t,
+            Detail:    mismatchDetail(reflect.TypeOf(service), t),
+        }
+    }
+    return service, nil
+}
+
+// qualifierForType returns the qualifier bound to t with Bind, or else of
+// the only service whose known type is assignable to t, or "" when none is
+func (c *Container) qualifierForType(t reflect.Type) (string, error) {
+    if qualifier, ok := c.boundQualifier(t); ok {
+        return qualifier, nil
+    }
+
+    var candidates []string
+    for _, qualifier := range c.snapshotOrder() {
+        // Services being built by this goroutine cannot be injected into themselves
+        if c.lifetimeOf(qualifier) == Scoped || c.isBuilding(qualifier) {
+            continue
+        }
+
+        serviceType, _ := c.staticType(qualifier)
+        if serviceType != nil && serviceType.AssignableTo(t) {
+            candidates = append(candidates, qualifier)
+        }
+    }
+
+    c.log.Debugw("Matched services by type",
+        "type", t,
+        "candidates", candidates)
+    if len(candidates) > 1 {
+        return "", fmt.Errorf("ambiguous type %v: candidates %s; use a qualifier", t, strings.Join(candidates, ", "))
+    }
+    if len(candidates) == 0 {
+        return "", nil
+    }
+    return candidates[0], nil
+}
+
+// wiredType is the type a di:"" field is matched against: the value type of
+// Optional and Hot fields, the field type otherwise
+func wiredType(fieldType reflect.Type) reflect.Type {
+    field := reflect.New(fieldType).Elem()
+    if opt, ok := field.Addr().Interface().(optionalField); ok {
+        return opt.valueType()
+    }
+    if valueType, _, ok := asHotField(field); ok {
+        return valueType
+    }
+    return fieldType
+}
@@ -0,0 +1,98 @@
+package container
+
+import (
+    "errors"
+    "reflect"
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+type byTypeConsumer struct {
+    Migrations migrationProvider `di:""`
+    Port       Optional[int]     `di:""`
+    Name       Hot[string]       `di:""`
+}
+
+func TestResolveByType(t *testing.T) {
+    c := NewContainer()
+    require.NoError(t, c.Register("users", usersMigrations{}))
+    require.NoError(t, c.RegisterFactory("name", func(*Container) (interface{}, error) {
+        return "api", nil
+    }))
+    require.NoError(t, c.RegisterFactory("broken", func(*Container) (interface{}, error) {
+        return nil, errors.New("db down")
+    }))
+
+    // Unrelated factories are not built, so their failures do not matter
+    service, err := c.ResolveByType(reflect.TypeOf((*migrationProvider)(nil)).Elem())
+    require.NoError(t, err)
+    assert.Equal(t, usersMigrations{}, service)
+
+    // Factories are matched once built, never built to learn their type
+    _, err = c.ResolveByType(reflect.TypeOf(""))
+    assert.EqualError(t, err, "no service assignable to string")
+    _, err = c.Resolve("name")
+    require.NoError(t, err)
+    name, err := c.ResolveByType(reflect.TypeOf(""))
+    require.NoError(t, err)
+    assert.Equal(t, "api", name)
+
+    _, err = c.ResolveByType(reflect.TypeOf(0))
+    assert.EqualError(t, err, "no service assignable to int")
+
+    require.NoError(t, c.Register("legacy", usersMigrations{}))
+    _, err = c.ResolveByType(reflect.TypeOf((*migrationProvider)(nil)).Elem())
+    assert.EqualError(t, err, "ambiguous type container.migrationProvider: candidates users, legacy; use a qualifier")
+}
+
+func TestInjectStruct_WiresEmptyQualifierByType(t *testing.T) {
+    c := NewContainer()
+    require.NoError(t, c.Register("users", usersMigrations{}))
+    require.NoError(t, c.Register("name", "api"))
+
+    var consumer byTypeConsumer
+    result, err := c.InjectStructWithResult(&consumer)
+    require.NoError(t, err)
+    assert.Equal(t, usersMigrations{}, consumer.Migrations)
+    assert.Equal(t, "api", consumer.Name.Load())
+    assert.False(t, consumer.Port.Present())
+    assert.Equal(t, "users", result.Fields[0].Qualifier)
+    assert.Equal(t, FieldMissing, result.Fields[1].Status)
+
+    // Hot fields wired by type follow swaps of the matched qualifier
+    _, err = c.Swap("name", "web")
+    require.NoError(t, err)
+    assert.Equal(t, "web", consumer.Name.Load())
+
+    require.NoError(t, c.Register("legacy", usersMigrations{}))
+    err = c.InjectStruct(&byTypeConsumer{})
+    assert.ErrorContains(t, err, "failed to wire field Migrations by type: ambiguous type")
+    assert.ErrorContains(t, err, "candidates users, legacy")
+}
+
+func TestInjectStruct_RequiredByTypeMissing(t *testing.T) {
+    c := NewContainer()
+    target := &struct {
+        Inject     `di:"required"`
+        Migrations migrationProvider `di:""`
+    }{}
+    err := c.InjectStruct(target)
+    assert.ErrorContains(t, err, "failed to inject 1 fields of")
+    assert.ErrorContains(t, err, ": no service assignable to container.migrationProvider for field Migrations")
+}
+
+func TestResolveByType_SkipsServiceBeingBuilt(t *testing.T) {
+    c := NewContainer()
+    require.NoError(t, c.Register("users", usersMigrations{}))
+    require.NoError(t, c.RegisterFactory("runner", func(c *Container) (interface{}, error) {
+        runner := &struct {
+            Migrations migrationProvider `di:""`
+        }{}
+        return runner, c.InjectStruct(runner)
+    }))
+
+    _, err := c.Resolve("runner")
+    require.NoError(t, err)
+}
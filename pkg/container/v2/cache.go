@@ -0,0 +1,135 @@
+package container
+
+import (
+    "context"
+    "crypto/sha256"
+    "encoding/hex"
+    "encoding/json"
+    "fmt"
+    "os"
+    "path/filepath"
+    "reflect"
+
+    "di-example/pkg/logger"
+    "go.uber.org/zap"
+)
+
+// DiskCache persists provider outputs as JSON files keyed by a hash of the
+// provider's inputs and the JSON shape of its output type, so expensive
+// derived values survive process restarts but not changes to their type
+type DiskCache struct {
+    dir string
+    log *zap.SugaredLogger
+}
+
+// NewDiskCache creates a cache storing its entries in dir
+func NewDiskCache(dir string) (*DiskCache, error) {
+    if err := os.MkdirAll(dir, 0o755); err != nil {
+        return nil, fmt.Errorf("failed to create cache directory %s: %w", dir, err)
+    }
+    return &DiskCache{dir: dir, log: logger.Get()}, nil
+}
+
+// path returns the cache file for qualifier, inputs and values of
+// valueType
+func (d *DiskCache) path(qualifier string, inputs interface{}, valueType reflect.Type) (string, error) {
+    encoded, err := json.Marshal(inputs)
+    if err != nil {
+        return "", fmt.Errorf("cache inputs for %s are not serializable: %w", qualifier, err)
+    }
+
+    hash := sha256.New()
+    hash.Write([]byte(qualifier))
+    hash.Write([]byte{0})
+    hash.Write(encoded)
+    hash.Write([]byte{0})
+    hash.Write(typeShape(valueType))
+    return filepath.Join(d.dir, hex.EncodeToString(hash.Sum(nil))+".json"), nil
+}
+
+// typeShape describes t by its name and, when it has one, its JSON schema,
+// which changes with the fields of a struct even when its name does not
+func typeShape(t reflect.Type) []byte {
+    if schema, err := SchemaOf(t); err == nil {
+        if shape, err := json.Marshal(schema); err == nil {
+            return append([]byte(t.String()+"\x00"), shape...)
+        }
+    }
+    return []byte(t.String())
+}
+
+// load decodes a cached entry into out, reporting whether one was found
+func (d *DiskCache) load(path string, out interface{}) bool {
+    data, err := os.ReadFile(path)
+    if err != nil {
+        return false
+    }
+    if err := json.Unmarshal(data, out); err != nil {
+        d.log.Warnw("Discarding unreadable cache entry",
+            "path", path,
+            "error", err)
+        return false
+    }
+    return true
+}
+
+// store writes an entry atomically so readers never see partial files
+func (d *DiskCache) store(path string, value interface{}) error {
+    data, err := json.Marshal(value)
+    if err != nil {
+        return err
+    }
+
+    tmp, err := os.CreateTemp(d.dir, "entry-*.tmp")
+    if err != nil {
+        return err
+    }
+    defer os.Remove(tmp.Name())
+
+    if _, err := tmp.Write(data); err != nil {
+        tmp.Close()
+        return err
+    }
+    if err := tmp.Close(); err != nil {
+        return err
+    }
+    return os.Rename(tmp.Name(), path)
+}
+
+// RegisterCached registers the output of build under qualifier, reusing the
+// value stored in cache when build previously ran with the same inputs.
+// T must round-trip through encoding/json. Failures to write the cache are
+// logged but do not fail the registration.
+func RegisterCached[T any](c *Container, cache *DiskCache, qualifier string, inputs interface{}, build func() (T, error), opts ...RegisterOption) error {
+    path, err := cache.path(qualifier, inputs, reflect.TypeOf((*T)(nil)).Elem())
+    if err != nil {
+        return err
+    }
+
+    var value T
+    if cache.load(path, &value) {
+        c.log.Infow("Loaded service from disk cache",
+            "qualifier", qualifier,
+            "path", path)
+        return c.Register(qualifier, value, opts...)
+    }
+
+    if err := c.waitForProbes(c.currentContext(), qualifier, probesOf(opts)); err != nil {
+        return err
+    }
+    err = c.withServiceLabels(c.currentContext(), "build", qualifier, func(context.Context) error {
+        var buildErr error
+        value, buildErr = build()
+        return buildErr
+    })
+    if err != nil {
+        return fmt.Errorf("failed to build cached service %s: %w", qualifier, err)
+    }
+    if err := cache.store(path, value); err != nil {
+        c.log.Warnw("Failed to write disk cache entry",
+            "qualifier", qualifier,
+            "path", path,
+            "error", err)
+    }
+    return c.Register(qualifier, value, opts...)
+}
@@ -0,0 +1,119 @@
+package container
+
+import (
+    "errors"
+    "os"
+    "reflect"
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+type compiledSchema struct {
+    Version int
+    Fields  []string
+}
+
+func TestRegisterCached(t *testing.T) {
+    cache, err := NewDiskCache(t.TempDir())
+    require.NoError(t, err)
+
+    builds := 0
+    build := func() (compiledSchema, error) {
+        builds++
+        return compiledSchema{Version: 2, Fields: []string{"id", "name"}}, nil
+    }
+    inputs := map[string]string{"schema": "users.json"}
+
+    // First process builds and stores the value
+    first := NewContainer()
+    require.NoError(t, RegisterCached(first, cache, "schema", inputs, build))
+
+    // A later process with the same inputs loads it from disk
+    second := NewContainer()
+    require.NoError(t, RegisterCached(second, cache, "schema", inputs, build))
+    assert.Equal(t, 1, builds)
+
+    got, err := second.Resolve("schema")
+    require.NoError(t, err)
+    assert.Equal(t, compiledSchema{Version: 2, Fields: []string{"id", "name"}}, got)
+
+    // Different inputs produce a different cache entry
+    third := NewContainer()
+    require.NoError(t, RegisterCached(third, cache, "schema", map[string]string{"schema": "orders.json"}, build))
+    assert.Equal(t, 2, builds)
+}
+
+func TestRegisterCachedCorruptEntry(t *testing.T) {
+    cache, err := NewDiskCache(t.TempDir())
+    require.NoError(t, err)
+
+    path, err := cache.path("schema", 1, reflect.TypeOf(compiledSchema{}))
+    require.NoError(t, err)
+    require.NoError(t, os.WriteFile(path, []byte("{not json"), 0o644))
+
+    container := NewContainer()
+    require.NoError(t, RegisterCached(container, cache, "schema", 1, func() (compiledSchema, error) {
+        return compiledSchema{Version: 3}, nil
+    }))
+    got, err := container.Resolve("schema")
+    require.NoError(t, err)
+    assert.Equal(t, 3, got.(compiledSchema).Version)
+}
+
+func TestRegisterCachedBuildError(t *testing.T) {
+    cache, err := NewDiskCache(t.TempDir())
+    require.NoError(t, err)
+
+    err = RegisterCached(NewContainer(), cache, "schema", 1, func() (compiledSchema, error) {
+        return compiledSchema{}, errors.New("parse failed")
+    })
+    assert.ErrorContains(t, err, "parse failed")
+
+    err = RegisterCached(NewContainer(), cache, "schema", func() {}, func() (compiledSchema, error) {
+        return compiledSchema{}, nil
+    })
+    assert.Error(t, err)
+}
+
+// cacheEntryV1 and cacheEntryV2 register the two shapes of a type named
+// entry, as two releases of a program would
+func cacheEntryV1(c *Container, cache *DiskCache, builds *int) error {
+    type entry struct {
+        Name string
+    }
+    return RegisterCached(c, cache, "entry", "inputs", func() (entry, error) {
+        *builds++
+        return entry{Name: "v1"}, nil
+    })
+}
+
+func cacheEntryV2(c *Container, cache *DiskCache, builds *int) error {
+    type entry struct {
+        Name  string
+        Count int
+    }
+    return RegisterCached(c, cache, "entry", "inputs", func() (entry, error) {
+        *builds++
+        return entry{Name: "v2", Count: 2}, nil
+    })
+}
+
+func TestRegisterCachedTypeChange(t *testing.T) {
+    cache, err := NewDiskCache(t.TempDir())
+    require.NoError(t, err)
+    builds := 0
+
+    require.NoError(t, cacheEntryV1(NewContainer(), cache, &builds))
+    require.NoError(t, cacheEntryV1(NewContainer(), cache, &builds))
+    assert.Equal(t, 1, builds)
+
+    // Same qualifier, inputs and type name, but a new shape misses
+    c := NewContainer()
+    require.NoError(t, cacheEntryV2(c, cache, &builds))
+    assert.Equal(t, 2, builds)
+    got, err := c.Resolve("entry")
+    require.NoError(t, err)
+    assert.Equal(t, 2, reflect.ValueOf(got).FieldByName("Count").Interface())
+}
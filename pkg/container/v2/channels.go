@@ -0,0 +1,32 @@
+package container
+
+import (
+    "context"
+    "sync"
+)
+
+// RegisterChan creates a channel of T with the given buffer size, registers
+// it under qualifier and returns it. Producer and consumer structs can then
+// receive it through di tags, typed as chan T, chan<- T or <-chan T. The
+// channel is closed when the container stops, after the hooks of later
+// init stages have stopped, so consumers ranging over it terminate cleanly.
+func RegisterChan[T any](c *Container, qualifier string, buffer int, opts ...RegisterOption) (chan T, error) {
+    ch := make(chan T, buffer)
+    if err := c.Register(qualifier, ch, opts...); err != nil {
+        return nil, err
+    }
+
+    var once sync.Once
+    c.Append(Hook{
+        Name:  "close channel " + qualifier,
+        Stage: c.stageOf(qualifier),
+        OnStop: func(ctx context.Context) error {
+            once.Do(func() {
+                c.log.Debugw("Closing registered channel", "qualifier", qualifier)
+                close(ch)
+            })
+            return nil
+        },
+    })
+    return ch, nil
+}
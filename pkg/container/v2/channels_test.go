@@ -0,0 +1,59 @@
+package container
+
+import (
+    "context"
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+type domainEvent struct {
+    Name string
+}
+
+type eventProducer struct {
+    Events chan<- domainEvent `di:"events"`
+}
+
+type eventConsumer struct {
+    Events <-chan domainEvent `di:"events"`
+}
+
+func TestRegisterChan(t *testing.T) {
+    container := NewContainer()
+    _, err := RegisterChan[domainEvent](container, "events", 4, InStage(StageInfrastructure))
+    require.NoError(t, err)
+
+    producer := &eventProducer{}
+    consumer := &eventConsumer{}
+    require.NoError(t, container.InjectStruct(producer))
+    require.NoError(t, container.InjectStruct(consumer))
+    require.NoError(t, container.Start(context.Background()))
+
+    producer.Events <- domainEvent{Name: "user.created"}
+    producer.Events <- domainEvent{Name: "user.deleted"}
+
+    // Stopping the container closes the channel so the consumer's range ends
+    require.NoError(t, container.Stop(context.Background()))
+    var received []string
+    for event := range consumer.Events {
+        received = append(received, event.Name)
+    }
+    assert.Equal(t, []string{"user.created", "user.deleted"}, received)
+}
+
+func TestRegisterChanDuplicate(t *testing.T) {
+    container := NewContainer()
+    _, err := RegisterChan[int](container, "numbers", 0)
+    require.NoError(t, err)
+
+    _, err = RegisterChan[int](container, "numbers", 0)
+    assert.Error(t, err)
+
+    // A channel of the wrong element type is rejected at injection
+    target := &struct {
+        Numbers chan string `di:"numbers"`
+    }{}
+    assert.Error(t, container.InjectStruct(target))
+}
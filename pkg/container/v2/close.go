@@ -0,0 +1,124 @@
+package container
+
+import (
+    "errors"
+    "fmt"
+    "io"
+    "reflect"
+    "time"
+)
+
+// CloseTiming is how long closing one service took
+type CloseTiming struct {
+    Qualifier string
+    Duration  time.Duration
+    Err       error // Error returned by Close, nil on success
+}
+
+// CloseReport describes the services the most recent Close, or Stop,
+// closed, in the order they were closed
+type CloseReport struct {
+    Closed []CloseTiming
+    Total  time.Duration
+}
+
+// Close calls Close on every built singleton and cached weak instance that
+// implements io.Closer, dependents before their dependencies: a service
+// closes before the services it declared with DependsOn, that its provider
+// takes or that it resolved while being built, such as an HTTP server
+// before its database pool. Services without dependencies between them
+// close in reverse registration order. An instance registered under
+// several qualifiers is closed once, and instances closed by an earlier
+// Close are skipped. All closers are attempted; their errors are joined.
+// Stop calls Close once hooks and workers have stopped; see CloseReport
+// for the durations.
+func (c *Container) Close() error {
+    c.lifecycleMu.Lock()
+    defer c.lifecycleMu.Unlock()
+
+    return c.closeLocked()
+}
+
+// closeLocked closes the services and records the close report. Callers
+// must hold lifecycleMu.
+func (c *Container) closeLocked() error {
+    c.log.Info("Closing container services")
+    metrics := c.metricsSink()
+    report := &CloseReport{}
+    begin := c.clock.Now()
+
+    var errs []error
+    closedInstances := make(map[interface{}]bool)
+    order := c.dependencyOrder()
+    for i := len(order) - 1; i >= 0; i-- {
+        qualifier := order[i]
+        closer, ok := c.closerOf(qualifier)
+        if !ok {
+            continue
+        }
+        if reflect.TypeOf(closer).Comparable() {
+            if closedInstances[closer] {
+                continue
+            }
+            closedInstances[closer] = true
+        }
+
+        closeStart := c.clock.Now()
+        err := closer.Close()
+        elapsed := c.since(closeStart)
+        report.Closed = append(report.Closed, CloseTiming{Qualifier: qualifier, Duration: elapsed, Err: err})
+        metrics.ObserveDuration("di_close_seconds", elapsed, map[string]string{"qualifier": qualifier})
+        if err != nil {
+            c.log.Errorw("Failed to close service",
+                "qualifier", qualifier,
+                "duration", elapsed,
+                "error", err)
+            errs = append(errs, fmt.Errorf("failed to close %s: %w", qualifier, err))
+            continue
+        }
+        c.log.Debugw("Closed service",
+            "qualifier", qualifier,
+            "duration", elapsed)
+    }
+    report.Total = c.since(begin)
+    c.closeReport = report
+
+    err := errors.Join(errs...)
+    if err == nil {
+        c.log.Infow("Container services closed",
+            "closed", len(report.Closed),
+            "duration", report.Total)
+    }
+    return err
+}
+
+// CloseReport returns the services closed by the most recent Close or Stop
+// with their durations, or nil if the container was never closed
+func (c *Container) CloseReport() *CloseReport {
+    c.lifecycleMu.Lock()
+    defer c.lifecycleMu.Unlock()
+    return c.closeReport
+}
+
+// closerOf returns the built instance of qualifier if it implements
+// io.Closer and was not closed yet, marking it closed
+func (c *Container) closerOf(qualifier string) (io.Closer, bool) {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+
+    if c.closed[qualifier] {
+        return nil, false
+    }
+    service, ok := c.services[qualifier]
+    if !ok {
+        if _, weak := c.weak[qualifier]; weak {
+            service, ok = c.weakLRU.peek(qualifier)
+        }
+    }
+    closer, isCloser := service.(io.Closer)
+    if !ok || !isCloser {
+        return nil, false
+    }
+    c.closed[qualifier] = true
+    return closer, true
+}
@@ -0,0 +1,107 @@
+package container
+
+import (
+    "context"
+    "errors"
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+// closeRecorder records its Close in a shared log
+type closeRecorder struct {
+    name string
+    log  *[]string
+    err  error
+}
+
+func (r *closeRecorder) Close() error {
+    *r.log = append(*r.log, r.name)
+    return r.err
+}
+
+func TestClose_ReverseRegistrationOrder(t *testing.T) {
+    c := NewContainer()
+    var log []string
+    database := &closeRecorder{name: "database", log: &log}
+    require.NoError(t, c.Register("database", database))
+    require.NoError(t, c.Register("plain", "not a closer"))
+    require.NoError(t, c.Register("cache", &closeRecorder{name: "cache", log: &log}))
+    require.NoError(t, c.Register("db", database)) // Same instance, closed once
+    require.NoError(t, c.RegisterWeak("templates", func() (interface{}, error) {
+        return &closeRecorder{name: "templates", log: &log}, nil
+    }))
+    require.NoError(t, c.RegisterFactory("unused", func(*Container) (interface{}, error) {
+        return &closeRecorder{name: "unused", log: &log}, nil
+    }))
+    _, err := c.Resolve("templates")
+    require.NoError(t, err)
+
+    require.NoError(t, c.Close())
+    assert.Equal(t, []string{"templates", "database", "cache"}, log)
+
+    // Closed instances are not closed again
+    log = nil
+    require.NoError(t, c.Close())
+    assert.Empty(t, log)
+}
+
+func TestClose_JoinsErrors(t *testing.T) {
+    c := NewContainer()
+    var log []string
+    require.NoError(t, c.Register("first", &closeRecorder{name: "first", log: &log, err: errors.New("busy")}))
+    require.NoError(t, c.Register("second", &closeRecorder{name: "second", log: &log, err: errors.New("timeout")}))
+
+    err := c.Close()
+    assert.ErrorContains(t, err, "failed to close first: busy")
+    assert.ErrorContains(t, err, "failed to close second: timeout")
+    assert.Equal(t, []string{"second", "first"}, log)
+}
+
+func TestClose_DependentsFirst(t *testing.T) {
+    c := NewContainer()
+    var log []string
+    // Registered before the pool it uses, so registration order alone would
+    // close the pool first
+    require.NoError(t, c.RegisterFactory("server", func(c *Container) (interface{}, error) {
+        if _, err := c.Resolve("pool"); err != nil {
+            return nil, err
+        }
+        return &closeRecorder{name: "server", log: &log}, nil
+    }))
+    require.NoError(t, c.Register("pool", &closeRecorder{name: "pool", log: &log}))
+    require.NoError(t, c.Register("audit", &closeRecorder{name: "audit", log: &log}, DependsOn("cache")))
+    require.NoError(t, c.Register("cache", &closeRecorder{name: "cache", log: &log}))
+    _, err := c.Resolve("server")
+    require.NoError(t, err)
+
+    require.NoError(t, c.Close())
+    assert.Equal(t, []string{"audit", "cache", "server", "pool"}, log)
+}
+
+func TestStop_ClosesServices(t *testing.T) {
+    metrics := newRecordingMetrics()
+    c := NewContainer(WithMetrics(metrics))
+    var log []string
+    require.NoError(t, c.Register("pool", &closeRecorder{name: "pool", log: &log}))
+    require.NoError(t, c.Register("broker", &closeRecorder{name: "broker", log: &log, err: errors.New("busy")}))
+    assert.Nil(t, c.CloseReport())
+
+    err := c.Stop(context.Background())
+    assert.EqualError(t, err, "failed to close broker: busy")
+    assert.Equal(t, []string{"broker", "pool"}, log)
+
+    report := c.CloseReport()
+    require.NotNil(t, report)
+    require.Len(t, report.Closed, 2)
+    assert.Equal(t, "broker", report.Closed[0].Qualifier)
+    assert.EqualError(t, report.Closed[0].Err, "busy")
+    assert.Equal(t, "pool", report.Closed[1].Qualifier)
+    assert.NoError(t, report.Closed[1].Err)
+    assert.Len(t, metrics.durations["di_close_seconds"], 2)
+
+    // A later Close has nothing left to close
+    require.NoError(t, c.Close())
+    assert.Empty(t, c.CloseReport().Closed)
+}
@@ -0,0 +1,170 @@
+package container
+
+import (
+    "fmt"
+    "time"
+
+    "di-example/pkg/logger"
+    "go.uber.org/zap"
+)
+
+// Option configures a container created by NewContainer:
+//
+//	c := container.NewContainer(
+//	    container.WithLogger(log),
+//	    container.WithStrictMode(),
+//	    container.WithDuplicatePolicy(container.DuplicateKeepFirst),
+//	)
+//
+// Options only set what a container starts with; setters such as
+// SetMetricsSink still change it later.
+type Option func(*containerOptions)
+
+// containerOptions holds the settings NewContainer builds a container with
+type containerOptions struct {
+    log        *zap.SugaredLogger
+    metrics    MetricsSink
+    clock      Clock
+    strict     bool
+    nested     bool
+    plans      bool
+    unexported bool
+    methodConventions []func(name string) bool
+    duplicates DuplicatePolicy
+}
+
+// defaultContainerOptions returns the settings of a container created without options
+func defaultContainerOptions() containerOptions {
+    return containerOptions{
+        log:        logger.Get(),
+        metrics:    nopMetrics{}, // Metrics are disabled until a sink is set
+        clock:      systemClock{},
+        duplicates: DuplicateReject,
+    }
+}
+
+// Clock tells the container the time: timestamps of events, audit records,
+// mutations, workers and traces, and the durations it measures. Tests use
+// a fake clock to get stable values. A clock that also has an
+// After(d time.Duration) <-chan time.Time method, like time.After, times
+// the backoff delays of retries and WaitFor probes too.
+type Clock interface {
+    Now() time.Time
+}
+
+// timerClock is a Clock that also times delays
+type timerClock interface {
+    After(d time.Duration) <-chan time.Time
+}
+
+// systemClock is the wall clock
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+// DuplicatePolicy decides what happens when a qualifier is registered twice
+type DuplicatePolicy int
+
+const (
+    // DuplicateReject fails the second registration. It is the default.
+    DuplicateReject DuplicatePolicy = iota
+    // DuplicateKeepFirst ignores the second registration with a warning
+    DuplicateKeepFirst
+    // DuplicateReplace swaps the instance of a registered singleton for the
+    // new one, like Swap. Other registrations are still rejected.
+    DuplicateReplace
+)
+
+// String returns the lowercase name of the policy
+func (p DuplicatePolicy) String() string {
+    switch p {
+    case DuplicateReject:
+        return "reject"
+    case DuplicateKeepFirst:
+        return "keep-first"
+    case DuplicateReplace:
+        return "replace"
+    default:
+        return fmt.Sprintf("duplicatepolicy(%d)", int(p))
+    }
+}
+
+// WithLogger makes the container log to log instead of the shared logger.
+// A nil logger keeps the default.
+func WithLogger(log *zap.SugaredLogger) Option {
+    return func(o *containerOptions) {
+        if log != nil {
+            o.log = log
+        }
+    }
+}
+
+// WithMetrics installs the sink that receives container measurements, see
+// SetMetricsSink
+func WithMetrics(sink MetricsSink) Option {
+    return func(o *containerOptions) {
+        if sink == nil {
+            sink = nopMetrics{}
+        }
+        o.metrics = sink
+    }
+}
+
+// WithClock makes the container read the time from clock. A nil clock keeps
+// the wall clock.
+func WithClock(clock Clock) Option {
+    return func(o *containerOptions) {
+        if clock != nil {
+            o.clock = clock
+        }
+    }
+}
+
+// WithStrictMode makes InjectStruct reject di tags it otherwise tolerates:
+// unknown field tag options, which are usually typos such as "optinal", and
+// tags on unexported fields, which the container cannot set unless
+// WithUnexportedInjection is given.
+func WithStrictMode() Option {
+    return func(o *containerOptions) {
+        o.strict = true
+    }
+}
+
+// WithNestedInjection makes InjectStruct descend into every exported
+// struct field, and non-nil pointer to struct, without needing a
+// di:"inject" tag, as it always does for embedded structs, see NestedTag
+func WithNestedInjection() Option {
+    return func(o *containerOptions) {
+        o.nested = true
+    }
+}
+
+// WithInjectionPlans makes InjectStruct cache the analysis of every struct
+// type it injects, its tags and nested structs, instead of repeating it for
+// every injection. A frozen container caches plans anyway. See PerfReport
+// for what it saves.
+func WithInjectionPlans() Option {
+    return func(o *containerOptions) {
+        o.plans = true
+    }
+}
+
+// WithDuplicatePolicy sets how registering a taken qualifier is handled
+func WithDuplicatePolicy(policy DuplicatePolicy) Option {
+    return func(o *containerOptions) {
+        o.duplicates = policy
+    }
+}
+
+// after returns a channel receiving once d elapsed on the container's clock
+func (c *Container) after(d time.Duration) <-chan time.Time {
+    if timer, ok := c.clock.(timerClock); ok {
+        return timer.After(d)
+    }
+    return time.After(d)
+}
+
+// since returns the time elapsed since begin on the container's clock
+func (c *Container) since(begin time.Time) time.Duration {
+    return c.clock.Now().Sub(begin)
+}
@@ -0,0 +1,132 @@
+package container
+
+import (
+    "context"
+    "testing"
+    "time"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+    "go.uber.org/zap"
+    "go.uber.org/zap/zapcore"
+    "go.uber.org/zap/zaptest/observer"
+)
+
+// fixedClock is a Clock stopped at a given time
+type fixedClock struct{ now time.Time }
+
+func (c fixedClock) Now() time.Time { return c.now }
+
+func TestNewContainer_Defaults(t *testing.T) {
+    container := NewContainer()
+
+    assert.Equal(t, systemClock{}, container.clock)
+    assert.Equal(t, nopMetrics{}, container.metrics)
+    assert.False(t, container.strict)
+    assert.Equal(t, DuplicateReject, container.duplicates)
+}
+
+func TestNewContainer_WithLogger(t *testing.T) {
+    core, logs := observer.New(zapcore.InfoLevel)
+    container := NewContainer(WithLogger(zap.New(core).Sugar()))
+
+    require.NoError(t, container.Register("user", "alice"))
+    assert.NotZero(t, logs.FilterMessage("Service registered successfully").Len())
+}
+
+func TestNewContainer_WithMetrics(t *testing.T) {
+    metrics := newRecordingMetrics()
+    container := NewContainer(WithMetrics(metrics))
+
+    require.NoError(t, container.Register("user", "alice"))
+    assert.Equal(t, 1.0, metrics.gauges["di_registrations"])
+}
+
+func TestNewContainer_WithClock(t *testing.T) {
+    now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+    container := NewContainer(WithClock(fixedClock{now: now}))
+
+    var events []Event
+    container.OnEvent(func(e Event) { events = append(events, e) })
+    require.NoError(t, container.Register("user", "alice"))
+    require.NoError(t, container.Start(context.Background()))
+
+    require.NotEmpty(t, events)
+    for _, event := range events {
+        assert.Equal(t, now, event.Time)
+    }
+    assert.Zero(t, container.StartupReport().Total, "a stopped clock measures no time")
+}
+
+func TestNewContainer_WithStrictMode(t *testing.T) {
+    type typo struct {
+        User string `di:"user,optinal"`
+    }
+    type unexported struct {
+        user string `di:"user"`
+    }
+
+    lenient := NewContainer()
+    require.NoError(t, lenient.Register("user", "alice"))
+    assert.NoError(t, lenient.InjectStruct(&typo{}))
+    assert.NoError(t, lenient.InjectStruct(&unexported{}))
+
+    strict := NewContainer(WithStrictMode())
+    require.NoError(t, strict.Register("user", "alice"))
+    assert.ErrorContains(t, strict.InjectStruct(&typo{}), `field User: unknown option "optinal" in di tag`)
+    assert.ErrorContains(t, strict.InjectStruct(&unexported{}), "field user is unexported and cannot be injected")
+
+    var valid struct {
+        User  string `di:"user,required"`
+        Guard string `di:"user,ifPresent=feature,optional"`
+    }
+    assert.NoError(t, strict.InjectStruct(&valid))
+    assert.Equal(t, "alice", valid.User)
+}
+
+func TestNewContainer_WithDuplicatePolicy(t *testing.T) {
+    t.Run("reject", func(t *testing.T) {
+        container := NewContainer()
+        require.NoError(t, container.Register("user", "alice"))
+        assert.ErrorIs(t, container.Register("user", "bob"), ErrDuplicateRegistration)
+    })
+
+    t.Run("keep first", func(t *testing.T) {
+        container := NewContainer(WithDuplicatePolicy(DuplicateKeepFirst))
+        require.NoError(t, container.Register("user", "alice"))
+        require.NoError(t, container.Register("user", "bob"))
+        require.NoError(t, container.Provide("user", func() string { return "carol" }))
+
+        user, err := ResolveAs[string](container, "user")
+        require.NoError(t, err)
+        assert.Equal(t, "alice", user)
+
+        require.NoError(t, container.Provide("store", func() *providedStore { return &providedStore{dsn: "first"} }))
+        require.NoError(t, container.Provide("store", func() *providedStore { return &providedStore{dsn: "second"} }))
+        require.NoError(t, container.Build())
+        store, err := ResolveAs[*providedStore](container, "store")
+        require.NoError(t, err)
+        assert.Equal(t, "first", store.dsn)
+    })
+
+    t.Run("replace", func(t *testing.T) {
+        container := NewContainer(WithDuplicatePolicy(DuplicateReplace))
+        require.NoError(t, container.Register("user", "alice"))
+        require.NoError(t, container.Register("user", "bob"))
+
+        user, err := ResolveAs[string](container, "user")
+        require.NoError(t, err)
+        assert.Equal(t, "bob", user)
+
+        require.NoError(t, container.RegisterFactory("lazy", func(*Container) (interface{}, error) { return "x", nil }))
+        assert.ErrorIs(t, container.RegisterFactory("lazy", func(*Container) (interface{}, error) { return "y", nil }),
+            ErrDuplicateRegistration, "only singleton instances are replaced")
+    })
+}
+
+func TestDuplicatePolicy_String(t *testing.T) {
+    assert.Equal(t, "reject", DuplicateReject.String())
+    assert.Equal(t, "keep-first", DuplicateKeepFirst.String())
+    assert.Equal(t, "replace", DuplicateReplace.String())
+    assert.Equal(t, "duplicatepolicy(7)", DuplicatePolicy(7).String())
+}
@@ -0,0 +1,667 @@
+// Package container provides dependency injection functionality. It is
+// version 2 of the container and the engine behind version 1, the package
+// di-example/pkg/container, which is kept as a thin compatibility wrapper:
+// its types are aliases of the types here, so a *Container created through
+// either package can be handed to code written against the other, and code
+// can migrate one package at a time.
+//
+// Next to NewContainer and the Register methods of a live container, v2
+// adds a Builder, which collects all bindings and validates them at once
+// in Build, returning a frozen container.
+//
+// Exported APIs are stable, and kept compatible until the next major
+// version, unless their documentation says Experimental. The recorded API
+// lives in api/container-v2.txt at the module root; cmd/apicheck and its
+// test reject incompatible changes to stable APIs.
+package container
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "reflect"
+    "sync"
+    "sync/atomic"
+    "time"
+    "weak"
+    "go.uber.org/zap"
+)
+
+// Container represents a dependency injection container that manages services
+type Container struct {
+    mu       sync.RWMutex                // Mutex for thread-safe operations
+    writeMu  sync.Mutex                  // Serializes instance writes whose decorators run unlocked
+    services map[string]interface{}      // Map to store services with their qualifiers
+    regs     map[string]*registration     // Registration metadata by qualifier
+    renames  map[string]string            // Deprecated qualifier -> replacement
+    weak     map[string]WeakProvider      // Providers of weak services
+    weakLRU  *lruCache                    // Cached weak instances, evicted least recently used first
+    lazy     map[string]*lazyService      // Factory registrations not built yet
+    providers map[string]*provider        // Constructors registered with Provide
+    scoped   map[string]ScopedProvider    // Providers of scoped services
+    hot      map[string][]hotBinding      // Hot fields updated by Swap, by qualifier
+    transient map[string]func() (interface{}, error) // Constructors of transient services
+    remotes  map[string]Probe             // Health checks of remote services
+    degradables map[string]*degradation   // Primaries with fallbacks, by qualifier
+    bindings map[reflect.Type]string      // Interface type -> qualifier of its implementation
+    decorators map[string][]Decorator     // Group -> decorators applied to its members
+    guards     map[string]*Guard          // Qualifier -> guard of services registered WithLimits
+    guardsMu   sync.Mutex                 // Guards guards, which are created under the read lock
+    tracer   *tracer                      // Records armed resolution traces
+    quota    Quota                        // Registration limits, zero means unlimited
+    budgets  Budgets                      // Dependency fan-in/fan-out limits checked by Validate
+    consumers map[string]map[string]bool  // Qualifier -> struct types it was injected into
+    consumerFields map[string]map[string]bool // Qualifier -> struct fields it was injected into
+    installing map[uint64]string          // Goroutine ID -> module being installed
+    profile  string                       // Active profile selecting module variants
+    order    []string                    // Qualifiers in registration order
+    log      *zap.SugaredLogger         // Logger instance
+    metrics  MetricsSink                 // Receives timings and other measurements
+    clock    Clock                       // Source of timestamps and durations
+    strict   bool                        // Rejects di tags that are otherwise tolerated, see WithStrictMode
+    nested   bool                        // Injects untagged nested structs, see WithNestedInjection
+    plansEnabled bool                    // Caches injection plans, see WithInjectionPlans
+    unexported bool                      // Sets unexported tagged fields, see WithUnexportedInjection
+    methodConventions []func(name string) bool // Names of further injection methods, see WithInjectMethods
+    plans    planCache                   // Injection plans by struct type
+    perf     perfStats                   // Injection costs, see PerfReport
+    duplicates DuplicatePolicy           // How registering a taken qualifier is handled
+    executor ExecutorFactory             // Runs independent startup work
+    random   *randomSource               // Source of scope IDs, seeded by SetSeed
+    frozen   bool                        // Set by Freeze, rejects further registrations
+    nilPolicy NilPolicy                  // How typed nil services are handled
+    debug    *debugState                 // Ownership annotations of didebug builds
+    history  *mutationLog                // Recent mutations, see History
+    auditSink AuditSink                  // Receives accesses to sensitive services
+    waitPolicy WaitPolicy                // Retry policy of WaitFor probes
+    retries    []ConstructionRetry       // Construction retries not yet reported by Start
+    injectionLogging InjectionLogging    // How InjectStruct logs its work
+    sensitiveCount int32                 // Number of sensitive registrations, read atomically
+    versionSeq uint64                    // Last registration version handed out, see ReinjectStruct
+
+    eventsMu   sync.Mutex                // Guards listeners
+    listeners  []func(Event)             // Receive container events, see OnEvent
+
+    scopeHooksMu    sync.Mutex            // Guards the scope middleware and hooks
+    scopeMiddleware []ScopeMiddleware     // Prepare new scopes, see UseScope
+    scopeOpenHooks  []func(*Scope)        // See OnScopeOpen
+    scopeCloseHooks []func(*Scope, error) // See OnScopeClose
+
+    asyncMu    sync.Mutex                // Guards pending
+    pending    map[string]*Future        // In-flight ResolveAsync results by qualifier
+    asyncLimit asyncLimiter              // Bounds concurrent async resolutions
+
+    resolvingMu sync.Mutex               // Guards resolving, observed, builders, waiting and contexts
+    resolving   map[uint64][]string      // Goroutine ID -> services it is building
+    observed    map[string][]string      // Qualifier -> services resolved while building it
+    builders    map[string]uint64        // Qualifier -> goroutine holding its build lock
+    waiting     map[uint64]string        // Goroutine ID -> qualifier whose build lock it waits for
+    contexts    map[uint64][]context.Context // Goroutine ID -> contexts of the work it does for the container
+    builds      atomic.Int32             // Builds in progress, see recordDependency
+
+    inflightMu sync.Mutex                // Guards inflight and reinjected
+    inflight   map[uintptr]struct{}      // Addresses of structs currently being injected
+    reinjected map[weak.Pointer[byte]]map[string]injectedVersion // Field versions of structs injected by ReinjectStruct
+
+    accountingMu sync.Mutex              // Guards workers, hookModules and openLifecycles
+    workers     map[uint64]*worker       // Running goroutines started with Go, by ID
+    workerSeq   uint64                   // Last worker ID handed out
+    workerCtx   context.Context          // Context of running workers, cancelled by Stop
+    cancelWorkers context.CancelFunc     // Cancels workerCtx
+    workerWG    sync.WaitGroup           // Counts running workers
+    hookModules map[uint64]string        // Goroutine ID -> module whose hook it runs
+    openLifecycles map[string]int        // Module -> started hooks not stopped yet
+
+    lifecycleMu sync.Mutex               // Guards lifecycle hooks, separate so hooks may Resolve
+    hooks       []Hook                   // Lifecycle hooks in start order
+    started     []int                    // Indexes of started hooks in start order
+    serviceHooks map[string]bool         // Qualifiers whose Starter/Stopper hooks were added
+    closed   map[string]bool              // Qualifiers whose instance Close has closed
+    destroyed map[string]bool             // Qualifiers whose instance PreDestroy has run
+    warmed      map[string]bool          // Qualifiers whose Warmup already ran
+    validators  []func() error           // Checks run in the validate startup phase
+    deferredModules []Module             // Modules with an enable key, installed by Build
+    disabledModules []string             // Modules skipped because their enable key is false
+    report      *StartupReport           // Timings of the last Start
+    closeReport *CloseReport             // Timings of the last Close
+}
+
+// NewContainer creates and initializes a new DI container configured by
+// opts, see Option
+func NewContainer(opts ...Option) *Container {
+    o := defaultContainerOptions()
+    for _, opt := range opts {
+        opt(&o)
+    }
+
+    c := &Container{
+        services: make(map[string]interface{}), // Initialize empty service map
+        regs:     make(map[string]*registration),
+        renames:  make(map[string]string),
+        weak:     make(map[string]WeakProvider),
+        weakLRU:  newLRUCache(DefaultWeakCapacity),
+        lazy:     make(map[string]*lazyService),
+        providers: make(map[string]*provider),
+        scoped:   make(map[string]ScopedProvider),
+        hot:      make(map[string][]hotBinding),
+        transient: make(map[string]func() (interface{}, error)),
+        remotes:  make(map[string]Probe),
+        degradables: make(map[string]*degradation),
+        bindings: make(map[reflect.Type]string),
+        serviceHooks: make(map[string]bool),
+        closed:   make(map[string]bool),
+        destroyed: make(map[string]bool),
+        decorators: make(map[string][]Decorator),
+        guards:     make(map[string]*Guard),
+        consumers: make(map[string]map[string]bool),
+        consumerFields: make(map[string]map[string]bool),
+        tracer:   newTracer(),
+        installing: make(map[uint64]string),
+        profile:  DefaultProfile,
+        log:      o.log,
+        metrics:  o.metrics,
+        clock:    o.clock,
+        strict:   o.strict,
+        nested:   o.nested,
+        plansEnabled: o.plans,
+        unexported: o.unexported,
+        methodConventions: o.methodConventions,
+        duplicates: o.duplicates,
+        executor: Sequential,                   // Startup work runs sequentially by default
+        random:   newRandomSource(),            // Unseeded until SetSeed
+        inflight: make(map[uintptr]struct{}),   // No injections in progress
+        reinjected: make(map[weak.Pointer[byte]]map[string]injectedVersion),
+        workers:  make(map[uint64]*worker),
+        hookModules: make(map[uint64]string),
+        openLifecycles: make(map[string]int),
+        resolving: make(map[uint64][]string),
+        observed:  make(map[string][]string),
+        builders:  make(map[string]uint64),
+        waiting:   make(map[uint64]string),
+        contexts:  make(map[uint64][]context.Context),
+        pending:  make(map[string]*Future),
+        warmed:   make(map[string]bool),
+        debug:    newDebugState(),
+        history:  newMutationLog(DefaultHistorySize),
+        waitPolicy: DefaultWaitPolicy,
+    }
+    watchMemoryPressure(weak.Make(c)) // Sheds weak instances under memory pressure
+    return c
+}
+
+// Register adds a new service to the container with the specified qualifier.
+// Options such as InStage attach registration metadata. WithLifetime
+// registers a constructor instead of an instance.
+func (c *Container) Register(qualifier string, service interface{}, opts ...RegisterOption) error {
+    // Lifetimes other than Singleton build instances with a constructor
+    if lifetime := requestedLifetime(opts); lifetime != Singleton && service != nil {
+        return c.registerConstructor(qualifier, service, lifetime, opts)
+    }
+
+    // Under DuplicateReplace, registering a singleton again swaps its instance
+    if c.duplicates == DuplicateReplace && service != nil {
+        c.mu.RLock()
+        _, exists := c.services[qualifier]
+        c.mu.RUnlock()
+        if exists {
+            c.log.Infow("Replacing registered singleton", "qualifier", qualifier)
+            _, err := c.Swap(qualifier, service)
+            return err
+        }
+    }
+
+    c.writeMu.Lock()               // Serialize with other instance writes
+    defer c.writeMu.Unlock()
+    c.mu.Lock()                    // Lock for thread safety
+
+    // Log registration attempt
+    c.log.Infow("Registering service",
+        "qualifier", qualifier,
+        "type", reflect.TypeOf(service))
+
+    // Validate service is not nil
+    if service == nil {
+        c.mu.Unlock()
+        c.log.Errorw("Cannot register nil service",
+            "qualifier", qualifier)
+        return fmt.Errorf("cannot register %w", &NilServiceError{Qualifier: qualifier})
+    }
+    if err := c.checkTypedNilLocked(qualifier, service, "registered service"); err != nil {
+        c.mu.Unlock()
+        return err
+    }
+
+    // Check for duplicates and quota limits
+    reg := c.newRegistrationLocked(qualifier, opts)
+    if err := c.admitLocked(reg); err != nil {
+        c.mu.Unlock()
+        return admitted(err)
+    }
+    decorators := c.decoratorsLocked(reg)
+    c.mu.Unlock()
+
+    // Wrap the service with the decorators of its groups. They run unlocked
+    // so they may resolve other services.
+    service, err := c.decorate(qualifier, service, decorators)
+    if err != nil {
+        return err
+    }
+
+    c.mu.Lock()
+    defer c.mu.Unlock()
+
+    // A weak registration may have taken the qualifier meanwhile
+    if err := c.admitLocked(reg); err != nil {
+        return admitted(err)
+    }
+
+    // Store service in container
+    c.services[qualifier] = service
+    c.recordLocked(reg)
+    c.log.Infow("Service registered successfully",
+        "qualifier", qualifier,
+        "type", reflect.TypeOf(service))
+    return nil
+}
+
+// Resolve retrieves a service from the container by its qualifier.
+// Deprecated qualifiers installed with Rename are redirected.
+func (c *Container) Resolve(qualifier string) (interface{}, error) {
+    // Frames above callerLocation: site closure, renamed, Resolve, caller
+    qualifier = c.renamed(qualifier, func() string { return callerLocation(3) })
+
+    service, err := c.resolveTraced(qualifier)
+    c.audit(AuditResolve, qualifier, "", err)
+    if err != nil {
+        c.emit(Event{Kind: EventResolveFailed, Qualifier: qualifier, Err: err})
+    }
+    return service, err
+}
+
+// ResolveContext is Resolve building the service, and the services it
+// resolves, under ctx: the profiler labels of the builds are added to those
+// of ctx, and retry backoffs end when ctx is done.
+func (c *Container) ResolveContext(ctx context.Context, qualifier string) (interface{}, error) {
+    // Frames above callerLocation: site closure, renamed, ResolveContext, caller
+    qualifier = c.renamed(qualifier, func() string { return callerLocation(3) })
+
+    leave := c.enterContext(ctx)
+    defer leave()
+    service, err := c.resolveTraced(qualifier)
+    c.audit(AuditResolve, qualifier, "", err)
+    if err != nil {
+        c.emit(Event{Kind: EventResolveFailed, Qualifier: qualifier, Err: err})
+    }
+    return service, err
+}
+
+// resolveTraced resolves a final qualifier, recording the resolution when a
+// trace is armed or in progress
+func (c *Container) resolveTraced(qualifier string) (interface{}, error) {
+    finish := c.traceEnter(qualifier)
+    c.checkHappensBefore(qualifier)
+    service, err := c.resolve(qualifier)
+    finish(err)
+    return service, err
+}
+
+// resolve looks up a service by its final qualifier
+func (c *Container) resolve(qualifier string) (interface{}, error) {
+    c.recordDependency(qualifier)
+    c.mu.RLock()                   // Read lock for thread safety
+    c.log.Debugw("Resolving service", "qualifier", qualifier)
+
+    // Look up service in container
+    service, exists := c.services[qualifier]
+    build, weak := c.weak[qualifier]
+    lazy, isLazy := c.lazy[qualifier]
+    _, scoped := c.scoped[qualifier]
+    buildTransient, transient := c.transient[qualifier]
+    c.mu.RUnlock()                 // Providers run without holding the lock
+
+    // Weak services are served from the LRU or rebuilt on demand
+    if !exists && weak {
+        return c.resolveWeak(qualifier, build)
+    }
+
+    // Factory registrations are built on their first resolve
+    if !exists && isLazy {
+        return c.resolveLazy(qualifier, lazy)
+    }
+
+    // Transient services are built anew every time
+    if !exists && transient {
+        return c.resolveTransient(qualifier, buildTransient)
+    }
+
+    // Scoped services only exist within a Scope
+    if !exists && scoped {
+        c.log.Errorw("Scoped service resolved outside a scope", "qualifier", qualifier)
+        return nil, &ScopeRequiredError{Qualifier: qualifier}
+    }
+
+    if !exists {
+        c.log.Errorw("Service not found", "qualifier", qualifier)
+        return nil, &ServiceNotFoundError{Qualifier: qualifier, Suggestions: c.suggest(qualifier)}
+    }
+
+    c.log.Debugw("Service resolved successfully",
+        "qualifier", qualifier,
+        "type", reflect.TypeOf(service))
+    return service, nil
+}
+
+// InjectStruct injects dependencies into struct fields marked with "di" tags.
+// An embedded Inject marker can set defaults for all fields of the struct.
+// Embedded structs and fields tagged di:"inject" hold structs injected the
+// same way, see NestedTag.
+// Fields tagged di:"" are wired by type, see ResolveByType. Fields are
+// required unless tagged optional. Every field is attempted: missing
+// required services, type mismatches and other field errors are returned
+// together, joined with errors.Join, and fields without errors are set.
+// Once every field is set, the injection methods of the target are called,
+// see InjectMethodPrefix, and a target implementing PostConstructor is set
+// up.
+func (c *Container) InjectStruct(target interface{}) error {
+    _, err := c.InjectStructWithResult(target)
+    return err
+}
+
+// InjectStructWithResult is InjectStruct returning an InjectionResult that
+// details, per field, what was injected, from which registration and how
+// long resolution took. Field outcomes are buffered and logged as a single
+// summary entry, see SetInjectionLogging.
+func (c *Container) InjectStructWithResult(target interface{}) (*InjectionResult, error) {
+    return c.injectStruct(target, c.resolveTraced, nil)
+}
+
+// injectStruct implements InjectStructWithResult, looking services up by
+// their final qualifier with resolve. When keep is set, fields it reports as
+// current are left untouched, see ReinjectStruct.
+func (c *Container) injectStruct(target interface{}, resolve func(qualifier string) (interface{}, error), keep func(field, qualifier string) bool) (*InjectionResult, error) {
+    c.log.Debug("Starting struct injection")
+    begin := c.clock.Now()
+
+    // Reject nil targets before reflecting on them
+    if target == nil {
+        c.log.Errorw("Target is nil")
+        return nil, ErrNilTarget
+    }
+
+    // Get reflect.Value of target and ensure it's a pointer
+    targetValue := reflect.ValueOf(target)
+    if targetValue.Kind() == reflect.Ptr && targetValue.IsNil() {
+        c.log.Errorw("Target is a nil pointer",
+            "targetType", targetValue.Type())
+        return nil, &NilPointerError{Type: targetValue.Type()}
+    }
+    if targetValue.Kind() != reflect.Ptr {
+        c.log.Errorw("Target must be a pointer",
+            "actualKind", targetValue.Kind())
+        return nil, fmt.Errorf("target must be a pointer to struct, got: %v", targetValue.Kind())
+    }
+
+    // Dereference pointer to get struct value
+    targetValue = targetValue.Elem()
+    targetType := targetValue.Type()
+
+    // Verify target is a struct
+    if targetValue.Kind() != reflect.Struct {
+        c.log.Errorw("Target must be a pointer to struct",
+            "actualKind", targetValue.Kind())
+        return nil, fmt.Errorf("target must be a pointer to struct, got pointer to: %v", targetValue.Kind())
+    }
+
+    // Refuse to inject the same struct from two goroutines at once
+    release, err := c.beginInjection(targetValue.Addr().Pointer(), targetType)
+    if err != nil {
+        return nil, err
+    }
+    defer release()
+
+    return c.injectGuarded(targetValue, resolve, keep, begin)
+}
+
+// injectGuarded injects the struct targetValue, started at begin, once the
+// caller holds its in-flight mark, see beginInjection
+func (c *Container) injectGuarded(targetValue reflect.Value, resolve func(qualifier string) (interface{}, error), keep func(field, qualifier string) bool, begin time.Time) (*InjectionResult, error) {
+    targetType := targetValue.Type()
+    c.log.Debugw("Analyzing struct for injection",
+        "structType", targetType.Name(),
+        "numFields", targetType.NumField())
+
+    frozen := c.Frozen()
+    in := &injection{
+        resolve:    resolve,
+        keep:       keep,
+        result:     &InjectionResult{Type: targetType},
+        visited:    map[uintptr]bool{targetValue.Addr().Pointer(): true},
+        cachePlans: c.plansEnabled || frozen,
+        frozen:     frozen,
+    }
+
+    // Struct-level defaults come from an embedded Inject marker
+    plan := c.plan(in, targetType)
+    if plan.markerErr != nil {
+        c.log.Errorw("Invalid Inject marker", "error", plan.markerErr)
+        return nil, plan.markerErr
+    }
+    c.injectFields(in, targetValue, plan, "", 0)
+    result, errs := in.result, in.errs
+
+    // Every wiring problem of the struct is reported at once
+    if len(errs) > 0 {
+        c.log.Errorw("Struct injection failed",
+            "structType", targetType,
+            "errors", len(errs))
+        return nil, fmt.Errorf("failed to inject %d fields of %v: %w", len(errs), targetType, errors.Join(errs...))
+    }
+    if keep == nil {
+        if err := c.callInjectMethods(in, targetValue, plan); err != nil {
+            return nil, err
+        }
+        if err := c.runPostConstruct(in, targetValue); err != nil {
+            return nil, err
+        }
+    }
+
+    result.Duration = c.since(begin)
+    c.recordInjection(in, result.Duration)
+    c.logInjection(result)
+    return result, nil
+}
+
+// injectFields injects the tagged fields of structValue, whose fields are
+// named path plus their own name in results and errors, and descends into
+// nested structs, see NestedTag
+func (c *Container) injectFields(in *injection, structValue reflect.Value, plan *injectionPlan, path string, depth int) {
+    structType := structValue.Type()
+    defaults := plan.defaults
+
+    // Iterate through the tagged and nested fields of the struct
+    for _, fp := range plan.fields {
+        field := fp.field
+        name := path + field.Name
+
+        // Nested structs are injected field by field
+        if fp.nested {
+            c.injectNested(in, structValue, field, name, fp.tagged, depth)
+            continue
+        }
+        spec := fp.spec
+        if c.strict && fp.optionErr != nil {
+            in.errs = append(in.errs, fmt.Errorf("field %s: %w", name, fp.optionErr))
+            continue
+        }
+        requested, group, isMap := fp.requested, fp.group, fp.isMap
+
+        // di:"" fields are wired to the only service matching their type
+        if spec.qualifier == "" {
+            valueType := wiredType(field.Type)
+            matched, err := c.qualifierForType(valueType)
+            if err != nil {
+                in.errs = append(in.errs, fmt.Errorf("failed to wire field %s by type: %w", name, err))
+                continue
+            }
+            if matched == "" {
+                _, isOptional := reflect.New(field.Type).Interface().(optionalField)
+                if !spec.isOptional(defaults) && !isOptional {
+                    in.errs = append(in.errs, fmt.Errorf("%w for field %s", &ServiceNotFoundError{Type: valueType}, name))
+                }
+                in.result.Fields = append(in.result.Fields, FieldInjection{Field: name, Status: FieldMissing})
+                continue
+            }
+            requested = matched
+        }
+        qualifier := c.renamed(requested, func() string {
+            return fmt.Sprintf("field %s of %v", name, structType)
+        })
+        entry := FieldInjection{Field: name, Requested: requested, Qualifier: qualifier}
+
+        // ifPresent=guard only injects the field when the guard is registered
+        if guard, ok := spec.options["ifPresent"]; ok && !c.guardPresent(guard, field, structType) {
+            entry.Status = FieldGuarded
+            in.result.Fields = append(in.result.Fields, entry)
+            continue
+        }
+
+        // Get field value and check if it can be set
+        fieldValue, settable := c.settableField(structValue.Field(field.Index[0]))
+        if !settable {
+            if c.strict {
+                in.errs = append(in.errs, fmt.Errorf("field %s is unexported and cannot be injected", name))
+            }
+            entry.Status = FieldUnexported
+            in.result.Fields = append(in.result.Fields, entry)
+            continue
+        }
+
+        // Fields still backed by the registration they were injected from
+        // keep their value
+        if in.keep != nil && in.keep(name, qualifier) {
+            entry.Status = FieldUnchanged
+            in.result.Fields = append(in.result.Fields, entry)
+            continue
+        }
+
+        fieldStart := c.clock.Now()
+
+        // di:"map:group" fields receive every member of the group by qualifier
+        if isMap {
+            members, err := c.injectGroupMap(fieldValue, group, in.resolve)
+            for i, member := range members {
+                // Only the last member resolved can have failed
+                var memberErr error
+                if i == len(members)-1 {
+                    memberErr = err
+                }
+                c.audit(AuditInject, member, auditTarget(structType, field), memberErr)
+                c.recordConsumer(structType, field, member)
+            }
+            if err != nil {
+                in.errs = append(in.errs, fmt.Errorf("failed to inject group %s into field %s: %w", group, name, err))
+                continue
+            }
+            entry.Status = FieldInjected
+            entry.Type = fieldValue.Type()
+            entry.Duration = c.since(fieldStart)
+            in.result.Fields = append(in.result.Fields, entry)
+            continue
+        }
+
+        // di:"options" fields receive their option struct, defaults included
+        if spec.qualifier == OptionsTag {
+            if err := c.injectOptions(fieldValue); err != nil {
+                in.errs = append(in.errs, fmt.Errorf("failed to inject options into field %s: %w", name, err))
+                continue
+            }
+            entry.Status = FieldInjected
+            entry.Type = fieldValue.Type()
+            entry.Lifetime, entry.Module = c.registrationSource(qualifier)
+            entry.Duration = c.since(fieldStart)
+            in.result.Fields = append(in.result.Fields, entry)
+            continue
+        }
+
+        // Optional[T] fields record presence instead of being skipped or failing
+        if opt, ok := fieldValue.Addr().Interface().(optionalField); ok {
+            present, err := c.injectOptional(opt, qualifier, field, in.resolve)
+            c.audit(AuditInject, qualifier, auditTarget(structType, field), err)
+            if err != nil {
+                in.errs = append(in.errs, fmt.Errorf("field %s: %w", name, err))
+                continue
+            }
+            entry.Status = FieldMissing
+            if present {
+                entry.Status = FieldInjected
+                entry.Lifetime, entry.Module = c.registrationSource(qualifier)
+                c.recordConsumer(structType, field, qualifier)
+            }
+            entry.Duration = c.since(fieldStart)
+            in.result.Fields = append(in.result.Fields, entry)
+            continue
+        }
+
+        // Resolve service for this field
+        service, err := in.resolve(qualifier)
+        entry.Duration = c.since(fieldStart)
+        c.audit(AuditInject, qualifier, auditTarget(structType, field), err)
+        if err != nil {
+            if !spec.isOptional(defaults) {
+                c.log.Errorw("Required service not found",
+                    "field", name,
+                    "qualifier", qualifier)
+                in.errs = append(in.errs, fmt.Errorf("required service %q for field %s not found: %w", qualifier, name, err))
+            }
+            entry.Status = FieldMissing
+            in.result.Fields = append(in.result.Fields, entry)
+            continue
+        }
+
+        // Hot fields are bound to the qualifier so Swap updates them
+        if valueType, store, ok := asHotField(fieldValue); ok {
+            if err := c.injectHot(qualifier, service, auditTarget(structType, field), valueType, store); err != nil {
+                in.errs = append(in.errs, err)
+                continue
+            }
+            entry.Status = FieldInjected
+            entry.Type = reflect.TypeOf(service)
+            entry.Lifetime, entry.Module = c.registrationSource(qualifier)
+            in.result.Fields = append(in.result.Fields, entry)
+            c.recordConsumer(structType, field, qualifier)
+            continue
+        }
+
+        // Verify type compatibility
+        serviceValue := reflect.ValueOf(service)
+        if !serviceValue.Type().AssignableTo(fieldValue.Type()) {
+            c.log.Errorw("Type mismatch during injection",
+                "field", name,
+                "expectedType", fieldValue.Type(),
+                "actualType", serviceValue.Type())
+            in.errs = append(in.errs, fmt.Errorf("field %s: %w", name, &TypeMismatchError{
+                Qualifier: qualifier,
+                Type:      serviceValue.Type(),
+                Want:      fieldValue.Type(),
+                Target:    "field type",
+                Detail:    mismatchDetail(serviceValue.Type(), fieldValue.Type()),
+            }))
+            continue
+        }
+
+        // Set the field value to the service
+        fieldValue.Set(serviceValue)
+
+        entry.Status = FieldInjected
+        entry.Type = serviceValue.Type()
+        entry.Lifetime, entry.Module = c.registrationSource(qualifier)
+        in.result.Fields = append(in.result.Fields, entry)
+        c.recordConsumer(structType, field, qualifier)
+    }
+
+}
+
+// Qualifiers returns all registered qualifiers in registration order
+func (c *Container) Qualifiers() []string {
+    return c.snapshotOrder()
+}
@@ -0,0 +1,102 @@
+package container
+
+import (
+    "fmt"
+    "path/filepath"
+    "runtime"
+    "strings"
+)
+
+// access identifies the goroutine and call site of a container operation
+type access struct {
+    goroutine uint64
+    site      string
+}
+
+func (a access) String() string {
+    return fmt.Sprintf("goroutine %d at %s", a.goroutine, a.site)
+}
+
+// debugState holds the happens-before annotations kept in didebug builds
+type debugState struct {
+    freeze     *access           // Where Freeze was called
+    registered map[string]access // Qualifier -> where it was registered
+}
+
+func newDebugState() *debugState {
+    return &debugState{registered: make(map[string]access)}
+}
+
+// packageDir is the directory of this package's sources, used to find the
+// first caller outside the container
+var packageDir = func() string {
+    _, file, _, _ := runtime.Caller(0)
+    return filepath.Dir(file)
+}()
+
+// legacyDir is the directory of the v1 package, whose wrappers are not
+// callers either
+var legacyDir = filepath.Dir(packageDir)
+
+// inContainer reports whether file is a source of the container: this
+// package or the v1 wrappers, but not their tests
+func inContainer(file string) bool {
+    dir := filepath.Dir(file)
+    return (dir == packageDir || dir == legacyDir) && !strings.HasSuffix(file, "_test.go")
+}
+
+// currentAccess returns the calling goroutine and the first call site outside
+// the container package
+func currentAccess() access {
+    return access{goroutine: goroutineID(), site: callSite()}
+}
+
+// callSite returns "file:line" of the first call site outside the container
+// and its v1 wrappers. Test files count as outside.
+func callSite() string {
+    pcs := make([]uintptr, 32)
+    frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+    for {
+        frame, more := frames.Next()
+        if !inContainer(frame.File) {
+            return fmt.Sprintf("%s:%d", frame.File, frame.Line)
+        }
+        if !more {
+            return "unknown"
+        }
+    }
+}
+
+// annotateRegisterLocked records where qualifier was registered. It does
+// nothing outside didebug builds. Callers must hold c.mu.
+func (c *Container) annotateRegisterLocked(qualifier string) {
+    if debugEnabled {
+        c.debug.registered[qualifier] = currentAccess()
+    }
+}
+
+// checkHappensBefore warns when a service is resolved on a goroutine other
+// than the one that registered it while the container is not frozen. Without
+// Freeze or other synchronization the resolver may not observe the
+// registration, which -race only reports once the timing goes wrong. It does
+// nothing outside didebug builds.
+func (c *Container) checkHappensBefore(qualifier string) {
+    if !debugEnabled {
+        return
+    }
+
+    c.mu.RLock()
+    registered, ok := c.debug.registered[qualifier]
+    frozen := c.frozen
+    c.mu.RUnlock()
+
+    resolver := currentAccess()
+    if !ok || frozen || registered.goroutine == resolver.goroutine {
+        return
+    }
+    c.log.Warnw("Resolve without happens-before edge to registration",
+        "qualifier", qualifier,
+        "registeredBy", registered.String(),
+        "resolvedBy", resolver.String(),
+        "hint", "call Freeze after registering, before sharing the container")
+}
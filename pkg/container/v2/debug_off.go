@@ -0,0 +1,6 @@
+//go:build !didebug
+
+package container
+
+// debugEnabled is false in regular builds; see debug_on.go
+const debugEnabled = false
@@ -0,0 +1,7 @@
+//go:build didebug
+
+package container
+
+// debugEnabled turns on ownership and happens-before diagnostics. Build with
+// -tags didebug to enable them.
+const debugEnabled = true
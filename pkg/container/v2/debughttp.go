@@ -0,0 +1,68 @@
+package container
+
+import (
+    "encoding/json"
+    "io"
+    "net/http"
+)
+
+// DebugHandler returns an http.Handler exposing container internals for
+// operators. Mount it on an internal-only listener:
+//
+//	GET /config/schema    JSON schemas of config registrations by qualifier
+//	GET /graph.dot        Dependency graph in the Graphviz DOT language, see Graph
+//	GET /history          Recent container mutations, oldest first
+//	GET /modules          Registrations, instances, workers and lifecycles by module
+//	GET /snapshot         Diagnostic state of services, see Snapshot
+func (c *Container) DebugHandler() http.Handler {
+    mux := http.NewServeMux()
+    mux.HandleFunc("/config/schema", c.serveConfigSchema)
+    mux.HandleFunc("/graph.dot", c.serveGraph)
+    mux.HandleFunc("/history", c.serveHistory)
+    mux.HandleFunc("/modules", c.serveModules)
+    mux.HandleFunc("/snapshot", c.serveSnapshot)
+    return mux
+}
+
+// serveSnapshot writes the diagnostic snapshot
+func (c *Container) serveSnapshot(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+    if err := c.Snapshot(w); err != nil {
+        c.log.Errorw("Failed to serve diagnostic snapshot", "error", err)
+    }
+}
+
+// serveGraph writes the dependency graph as DOT
+func (c *Container) serveGraph(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "text/vnd.graphviz")
+    _, _ = io.WriteString(w, c.Graph().DOT())
+}
+
+// serveHistory writes the mutation history as JSON
+func (c *Container) serveHistory(w http.ResponseWriter, r *http.Request) {
+    writeJSON(w, c.History())
+}
+
+// serveModules writes the resource usage of every module as JSON
+func (c *Container) serveModules(w http.ResponseWriter, r *http.Request) {
+    writeJSON(w, c.ModuleUsage())
+}
+
+// serveConfigSchema writes the config schemas as JSON
+func (c *Container) serveConfigSchema(w http.ResponseWriter, r *http.Request) {
+    schemas, err := c.ConfigSchemas()
+    if err != nil {
+        c.log.Errorw("Failed to generate config schemas", "error", err)
+        http.Error(w, err.Error(), http.StatusInternalServerError)
+        return
+    }
+    writeJSON(w, schemas)
+}
+
+// writeJSON writes value as indented JSON
+func writeJSON(w http.ResponseWriter, value interface{}) {
+    w.Header().Set("Content-Type", "application/json")
+    encoder := json.NewEncoder(w)
+    encoder.SetIndent("", "  ")
+    _ = encoder.Encode(value)
+}
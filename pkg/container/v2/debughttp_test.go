@@ -0,0 +1,56 @@
+package container
+
+import (
+    "encoding/json"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+func TestContainer_DebugHandlerConfigSchema(t *testing.T) {
+    container := NewContainer()
+    require.NoError(t, container.Register("database", &databaseConfig{}, AsConfig()))
+
+    recorder := httptest.NewRecorder()
+    container.DebugHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/config/schema", nil))
+
+    assert.Equal(t, http.StatusOK, recorder.Code)
+    assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+
+    var schemas map[string]Schema
+    require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &schemas))
+    assert.Equal(t, []string{"host"}, schemas["database"].Required)
+}
+
+func TestContainer_DebugHandlerHistory(t *testing.T) {
+    container := NewContainer()
+    require.NoError(t, container.Register("users", &testServiceImpl{}))
+
+    recorder := httptest.NewRecorder()
+    container.DebugHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/history", nil))
+    assert.Equal(t, http.StatusOK, recorder.Code)
+
+    var history []Mutation
+    require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &history))
+    require.Len(t, history, 1)
+    assert.Equal(t, MutationRegister, history[0].Kind)
+    assert.Equal(t, "users", history[0].Qualifier)
+}
+
+func TestContainer_DebugHandlerModules(t *testing.T) {
+    container := NewContainer()
+    require.NoError(t, container.Install(Module{Name: "billing", Setup: func(c *Container) error {
+        return c.Register("invoices", &testServiceImpl{})
+    }}))
+
+    recorder := httptest.NewRecorder()
+    container.DebugHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/modules", nil))
+    assert.Equal(t, http.StatusOK, recorder.Code)
+
+    var usage []ModuleUsage
+    require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &usage))
+    assert.Equal(t, []ModuleUsage{{Module: "billing", Registrations: 1, Instances: 1}}, usage)
+}
@@ -0,0 +1,85 @@
+package container
+
+import (
+    "errors"
+    "sync/atomic"
+)
+
+// ErrNoDefault is returned by the package-level wrappers when no default
+// container was installed with InitDefault or SetDefault
+var ErrNoDefault = errors.New("no default container; call container.InitDefault or container.SetDefault first")
+
+// defaultContainer is the container behind Default, nil until opted in
+var defaultContainer atomic.Pointer[Container]
+
+// InitDefault creates the default container used by Register, Resolve and
+// InjectStruct. It is meant for small tools that do not want to pass a
+// container around; applications should create and pass their own with
+// NewContainer. It fails if a default container is already installed, so
+// two pieces of code cannot silently share one.
+func InitDefault() (*Container, error) {
+    c := NewContainer()
+    if err := SetDefault(c); err != nil {
+        return nil, err
+    }
+    return c, nil
+}
+
+// SetDefault installs c as the default container. It fails if a default
+// container is already installed; call ResetDefault first to replace it.
+func SetDefault(c *Container) error {
+    if c == nil {
+        return errors.New("default container cannot be nil")
+    }
+    if !defaultContainer.CompareAndSwap(nil, c) {
+        return errors.New("default container already initialized")
+    }
+    c.log.Info("Installed default container")
+    return nil
+}
+
+// ResetDefault removes the default container, returning it or nil. Tests
+// use it to isolate themselves.
+func ResetDefault() *Container {
+    return defaultContainer.Swap(nil)
+}
+
+// Default returns the default container. It panics with ErrNoDefault if
+// none was installed; there is no implicit global container.
+func Default() *Container {
+    c := defaultContainer.Load()
+    if c == nil {
+        panic(ErrNoDefault)
+    }
+    return c
+}
+
+// Register registers a service with the default container, see
+// Container.Register
+func Register(qualifier string, service interface{}, opts ...RegisterOption) error {
+    c := defaultContainer.Load()
+    if c == nil {
+        return ErrNoDefault
+    }
+    return c.Register(qualifier, service, opts...)
+}
+
+// Resolve resolves a service from the default container, see
+// Container.Resolve
+func Resolve(qualifier string) (interface{}, error) {
+    c := defaultContainer.Load()
+    if c == nil {
+        return nil, ErrNoDefault
+    }
+    return c.Resolve(qualifier)
+}
+
+// InjectStruct injects target from the default container, see
+// Container.InjectStruct
+func InjectStruct(target interface{}) error {
+    c := defaultContainer.Load()
+    if c == nil {
+        return ErrNoDefault
+    }
+    return c.InjectStruct(target)
+}
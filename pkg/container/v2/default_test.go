@@ -0,0 +1,56 @@
+package container
+
+import (
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+func TestDefault_RequiresOptIn(t *testing.T) {
+    ResetDefault()
+
+    assert.PanicsWithValue(t, ErrNoDefault, func() { Default() })
+    assert.ErrorIs(t, Register("users", &testServiceImpl{}), ErrNoDefault)
+    _, err := Resolve("users")
+    assert.ErrorIs(t, err, ErrNoDefault)
+    assert.ErrorIs(t, InjectStruct(&struct{}{}), ErrNoDefault)
+}
+
+func TestDefault_WrappersUseTheDefaultContainer(t *testing.T) {
+    c, err := InitDefault()
+    require.NoError(t, err)
+    t.Cleanup(func() { ResetDefault() })
+    assert.Same(t, c, Default())
+
+    require.NoError(t, Register("users", &testServiceImpl{}))
+    service, err := Resolve("users")
+    require.NoError(t, err)
+    assert.IsType(t, &testServiceImpl{}, service)
+
+    var target struct {
+        Users TestService `di:"users"`
+    }
+    require.NoError(t, InjectStruct(&target))
+    assert.NotNil(t, target.Users)
+
+    // The instance API sees the same registrations
+    _, err = c.Resolve("users")
+    assert.NoError(t, err)
+}
+
+func TestDefault_CannotBeInitializedTwice(t *testing.T) {
+    ResetDefault()
+    first := NewContainer()
+    require.NoError(t, SetDefault(first))
+    t.Cleanup(func() { ResetDefault() })
+
+    _, err := InitDefault()
+    assert.EqualError(t, err, "default container already initialized")
+    assert.EqualError(t, SetDefault(NewContainer()), "default container already initialized")
+    assert.Same(t, first, Default())
+
+    assert.Same(t, first, ResetDefault())
+    assert.NoError(t, SetDefault(NewContainer()))
+    assert.Error(t, SetDefault(nil))
+}
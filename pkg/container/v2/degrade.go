@@ -0,0 +1,183 @@
+package container
+
+import (
+    "context"
+    "fmt"
+    "sync"
+    "time"
+)
+
+// Degradable is an optional subsystem with a fallback used while the
+// primary implementation is unhealthy, e.g. an in-memory queue standing in
+// for an unreachable broker. Both must be assignable to the type consumers
+// expect; Hot fields follow the switch.
+type Degradable struct {
+    Qualifier string
+    Primary   interface{}
+    Fallback  interface{}
+    Health    Probe // Checks the primary; defaults to its SelfTest or Warmup
+}
+
+// degradation tracks which implementation of a Degradable is active
+type degradation struct {
+    mu       sync.Mutex // Serializes switches
+    primary  interface{}
+    fallback interface{}
+    health   func(ctx context.Context) error
+    degraded bool
+}
+
+// RegisterDegradable registers the primary implementation and switches to
+// the fallback when the primary fails to warm up or fails its health check
+// at start or in CheckDegradation, and back once it is healthy again.
+// Every switch emits EventDegraded or EventRecovered.
+func (c *Container) RegisterDegradable(d Degradable, opts ...RegisterOption) error {
+    if d.Primary == nil || d.Fallback == nil {
+        return fmt.Errorf("degradable service %s needs a primary and a fallback", d.Qualifier)
+    }
+
+    state := &degradation{
+        primary:  d.Primary,
+        fallback: d.Fallback,
+        health:   d.Health.Check,
+    }
+    if state.health == nil {
+        state.health = defaultHealth(d.Primary)
+    }
+    if err := c.Register(d.Qualifier, d.Primary, opts...); err != nil {
+        return err
+    }
+
+    c.mu.Lock()
+    c.degradables[d.Qualifier] = state
+    c.mu.Unlock()
+
+    // Check the primary once warmup is done
+    qualifier := d.Qualifier
+    c.Append(Hook{
+        Name:  "degradation check " + qualifier,
+        Stage: c.stageOf(qualifier),
+        OnStart: func(ctx context.Context) error {
+            c.checkDegradable(ctx, qualifier, state)
+            return nil
+        },
+    })
+    return nil
+}
+
+// defaultHealth checks a primary through SelfTest, or Warmup, when it
+// implements them, and considers it healthy otherwise
+func defaultHealth(primary interface{}) func(ctx context.Context) error {
+    if tester, ok := primary.(SelfTester); ok {
+        return tester.SelfTest
+    }
+    if warmer, ok := primary.(Warmer); ok {
+        return warmer.Warmup
+    }
+    return func(context.Context) error { return nil }
+}
+
+// CheckDegradation runs the health check of every degradable primary and
+// switches implementations as needed. It returns the qualifiers running on
+// their fallback.
+func (c *Container) CheckDegradation(ctx context.Context) []string {
+    var degraded []string
+    for _, qualifier := range c.snapshotOrder() {
+        state, ok := c.degradableState(qualifier)
+        if !ok {
+            continue
+        }
+        if c.checkDegradable(ctx, qualifier, state) {
+            degraded = append(degraded, qualifier)
+        }
+    }
+    return degraded
+}
+
+// RunDegradationChecks calls CheckDegradation every interval until ctx is
+// done
+func (c *Container) RunDegradationChecks(ctx context.Context, interval time.Duration) {
+    ticker := time.NewTicker(interval)
+    defer ticker.Stop()
+
+    for {
+        c.CheckDegradation(ctx)
+        select {
+        case <-ctx.Done():
+            return
+        case <-ticker.C:
+        }
+    }
+}
+
+// Degraded reports whether qualifier is running on its fallback
+func (c *Container) Degraded(qualifier string) bool {
+    state, ok := c.degradableState(qualifier)
+    if !ok {
+        return false
+    }
+    state.mu.Lock()
+    defer state.mu.Unlock()
+    return state.degraded
+}
+
+// degradableState returns the degradation state of qualifier, if any
+func (c *Container) degradableState(qualifier string) (*degradation, bool) {
+    c.mu.RLock()
+    defer c.mu.RUnlock()
+    state, ok := c.degradables[qualifier]
+    return state, ok
+}
+
+// checkDegradable runs one health check and reports whether the service
+// is degraded afterwards
+func (c *Container) checkDegradable(ctx context.Context, qualifier string, state *degradation) bool {
+    err := c.withServiceLabels(ctx, "health", qualifier, state.health)
+    return c.switchDegradable(qualifier, state, err)
+}
+
+// switchDegradable activates the fallback when cause is not nil and the
+// primary otherwise, reporting whether the service is degraded afterwards
+func (c *Container) switchDegradable(qualifier string, state *degradation, cause error) bool {
+    state.mu.Lock()
+    defer state.mu.Unlock()
+
+    degrade := cause != nil
+    if state.degraded == degrade {
+        return state.degraded
+    }
+
+    active := state.primary
+    if degrade {
+        active = state.fallback
+    }
+    if _, err := c.Swap(qualifier, active); err != nil {
+        c.log.Errorw("Failed to switch degradable service",
+            "qualifier", qualifier,
+            "degrade", degrade,
+            "error", err)
+        return state.degraded
+    }
+    state.degraded = degrade
+
+    if degrade {
+        c.log.Warnw("Service degraded to fallback",
+            "qualifier", qualifier,
+            "cause", cause)
+        c.emit(Event{Kind: EventDegraded, Qualifier: qualifier, Err: cause})
+    } else {
+        c.log.Infow("Service recovered", "qualifier", qualifier)
+        c.emit(Event{Kind: EventRecovered, Qualifier: qualifier})
+    }
+    return state.degraded
+}
+
+// degradeOnWarmupFailure switches a degradable service whose primary failed
+// to warm up to its fallback, reporting whether it did
+func (c *Container) degradeOnWarmupFailure(qualifier string, cause error) bool {
+    state, ok := c.degradableState(qualifier)
+    if !ok {
+        return false
+    }
+    return c.switchDegradable(qualifier, state, cause)
+}
@@ -0,0 +1,118 @@
+package container
+
+import (
+    "context"
+    "errors"
+    "sync/atomic"
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+type queue interface {
+    Name() string
+}
+
+type brokerQueue struct {
+    reachable *atomic.Bool
+}
+
+func (q *brokerQueue) Name() string { return "broker" }
+
+func (q *brokerQueue) SelfTest(ctx context.Context) error {
+    if !q.reachable.Load() {
+        return errors.New("broker unreachable")
+    }
+    return nil
+}
+
+type memoryQueue struct{}
+
+func (memoryQueue) Name() string { return "memory" }
+
+type queueConsumer struct {
+    Queue Hot[queue] `di:"queue"`
+}
+
+func TestRegisterDegradable_SwitchesOnHealth(t *testing.T) {
+    c := NewContainer()
+    var events []Event
+    c.OnEvent(func(event Event) {
+        if event.Kind == EventDegraded || event.Kind == EventRecovered {
+            events = append(events, event)
+        }
+    })
+
+    var reachable atomic.Bool
+    require.NoError(t, c.RegisterDegradable(Degradable{
+        Qualifier: "queue",
+        Primary:   queue(&brokerQueue{reachable: &reachable}),
+        Fallback:  queue(memoryQueue{}),
+    }))
+    consumer := &queueConsumer{}
+    require.NoError(t, c.InjectStruct(consumer))
+
+    // The broker is down at start: the fallback takes over
+    require.NoError(t, c.Start(context.Background()))
+    assert.True(t, c.Degraded("queue"))
+    assert.Equal(t, "memory", consumer.Queue.Load().Name())
+    require.Len(t, events, 1)
+    assert.Equal(t, EventDegraded, events[0].Kind)
+    assert.EqualError(t, events[0].Err, "broker unreachable")
+
+    // Unchanged health does not switch again
+    assert.Equal(t, []string{"queue"}, c.CheckDegradation(context.Background()))
+    assert.Len(t, events, 1)
+
+    reachable.Store(true)
+    assert.Empty(t, c.CheckDegradation(context.Background()))
+    assert.False(t, c.Degraded("queue"))
+    assert.Equal(t, "broker", consumer.Queue.Load().Name())
+    require.Len(t, events, 2)
+    assert.Equal(t, EventRecovered, events[1].Kind)
+}
+
+type failingWarmupQueue struct{}
+
+func (failingWarmupQueue) Name() string { return "broker" }
+
+func (failingWarmupQueue) Warmup(ctx context.Context) error {
+    return errors.New("connection refused")
+}
+
+func TestRegisterDegradable_FallsBackWhenStartFails(t *testing.T) {
+    c := NewContainer()
+    require.NoError(t, c.RegisterDegradable(Degradable{
+        Qualifier: "queue",
+        Primary:   queue(failingWarmupQueue{}),
+        Fallback:  queue(memoryQueue{}),
+    }))
+
+    require.NoError(t, c.Start(context.Background()))
+    assert.True(t, c.Degraded("queue"))
+    service, err := c.Resolve("queue")
+    require.NoError(t, err)
+    assert.Equal(t, memoryQueue{}, service)
+
+    // Without a fallback the same failure fails startup
+    other := NewContainer()
+    require.NoError(t, other.Register("queue", queue(failingWarmupQueue{})))
+    assert.ErrorContains(t, other.Start(context.Background()), "connection refused")
+}
+
+func TestRegisterDegradable_CustomHealthProbe(t *testing.T) {
+    c := NewContainer()
+    require.NoError(t, c.RegisterDegradable(Degradable{
+        Qualifier: "queue",
+        Primary:   queue(memoryQueue{}),
+        Fallback:  queue(memoryQueue{}),
+        Health: ProbeFunc("always down", func(ctx context.Context) error {
+            return errors.New("down")
+        }),
+    }))
+    assert.Equal(t, []string{"queue"}, c.CheckDegradation(context.Background()))
+
+    assert.Error(t, c.RegisterDegradable(Degradable{Qualifier: "other", Primary: 1}))
+    assert.False(t, c.Degraded("unknown"))
+}
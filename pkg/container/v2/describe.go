@@ -0,0 +1,113 @@
+package container
+
+import (
+    "fmt"
+    "log/slog"
+    "reflect"
+)
+
+// ServiceDescriptor is a snapshot of a registration's metadata
+type ServiceDescriptor struct {
+    Qualifier string
+    Type      reflect.Type // Concrete type, nil for weak services not built yet
+    Lifetime  Lifetime
+    Stage     int
+    Module    string
+    Profile   string // Profile active at registration
+    Groups    []string
+}
+
+// String returns a one-line summary such as "users *app.userService (singleton)"
+func (d ServiceDescriptor) String() string {
+    typeName := "<unbuilt>"
+    if d.Type != nil {
+        typeName = d.Type.String()
+    }
+    summary := fmt.Sprintf("%s %s (%s", d.Qualifier, typeName, d.Lifetime)
+    if d.Module != "" {
+        summary += ", module " + d.Module
+    }
+    return summary + ")"
+}
+
+// LogValue implements slog.LogValuer
+func (d ServiceDescriptor) LogValue() slog.Value {
+    attrs := []slog.Attr{
+        slog.String("qualifier", d.Qualifier),
+        slog.String("lifetime", d.Lifetime.String()),
+        slog.Int("stage", d.Stage),
+    }
+    if d.Type != nil {
+        attrs = append(attrs, slog.String("type", d.Type.String()))
+    }
+    if d.Module != "" {
+        attrs = append(attrs, slog.String("module", d.Module))
+    }
+    return slog.GroupValue(attrs...)
+}
+
+// Describe returns the descriptor of a registered qualifier
+func (c *Container) Describe(qualifier string) (ServiceDescriptor, bool) {
+    c.mu.RLock()
+    defer c.mu.RUnlock()
+
+    reg, ok := c.regs[qualifier]
+    if !ok {
+        return ServiceDescriptor{}, false
+    }
+    return c.describeLocked(reg), true
+}
+
+// Descriptors returns the descriptors of all registrations in registration order
+func (c *Container) Descriptors() []ServiceDescriptor {
+    c.mu.RLock()
+    defer c.mu.RUnlock()
+
+    descriptors := make([]ServiceDescriptor, 0, len(c.order))
+    for _, qualifier := range c.order {
+        descriptors = append(descriptors, c.describeLocked(c.regs[qualifier]))
+    }
+    return descriptors
+}
+
+// describeLocked builds the descriptor of reg. Weak services report the type
+// of their cached instance, if any. Callers must hold c.mu.
+func (c *Container) describeLocked(reg *registration) ServiceDescriptor {
+    descriptor := ServiceDescriptor{
+        Qualifier: reg.qualifier,
+        Lifetime:  reg.lifetime,
+        Stage:     reg.stage,
+        Module:    reg.module,
+        Profile:   reg.profile,
+        Groups:    append([]string(nil), reg.groups...),
+    }
+    if service, ok := c.services[reg.qualifier]; ok {
+        descriptor.Type = reflect.TypeOf(service)
+    } else if service, ok := c.weakLRU.peek(reg.qualifier); ok {
+        descriptor.Type = reflect.TypeOf(service)
+    }
+    return descriptor
+}
+
+// String summarizes the container without dumping its services
+func (c *Container) String() string {
+    c.mu.RLock()
+    defer c.mu.RUnlock()
+
+    return fmt.Sprintf("Container{registrations: %d, singletons: %d, weak: %d, profile: %s, frozen: %t}",
+        len(c.regs), len(c.services), len(c.weak), c.profile, c.frozen)
+}
+
+// LogValue implements slog.LogValuer with the same counts as String
+func (c *Container) LogValue() slog.Value {
+    c.mu.RLock()
+    defer c.mu.RUnlock()
+
+    return slog.GroupValue(
+        slog.Int("registrations", len(c.regs)),
+        slog.Int("singletons", len(c.services)),
+        slog.Int("weak", len(c.weak)),
+        slog.String("profile", c.profile),
+        slog.Bool("frozen", c.frozen),
+    )
+}
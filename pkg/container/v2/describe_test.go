@@ -0,0 +1,61 @@
+package container
+
+import (
+    "bytes"
+    "log/slog"
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+func TestContainer_Describe(t *testing.T) {
+    container := NewContainer()
+    require.NoError(t, container.Register("users", &testServiceImpl{}, InModule("core"), InStage(StageDomain), InGroup("handlers")))
+    require.NoError(t, container.RegisterWeak("report", func() (interface{}, error) { return "report", nil }))
+
+    users, ok := container.Describe("users")
+    require.True(t, ok)
+    assert.Equal(t, "users *container.testServiceImpl (singleton, module core)", users.String())
+    assert.Equal(t, StageDomain, users.Stage)
+    assert.Equal(t, []string{"handlers"}, users.Groups)
+
+    report, ok := container.Describe("report")
+    require.True(t, ok)
+    assert.Equal(t, "report <unbuilt> (weak)", report.String())
+    _, err := container.Resolve("report")
+    require.NoError(t, err)
+    report, _ = container.Describe("report")
+    assert.Equal(t, "string", report.Type.String())
+
+    _, ok = container.Describe("missing")
+    assert.False(t, ok)
+
+    descriptors := container.Descriptors()
+    require.Len(t, descriptors, 2)
+    assert.Equal(t, "users", descriptors[0].Qualifier)
+}
+
+func TestContainer_StringAndLogValue(t *testing.T) {
+    container := NewContainer()
+    require.NoError(t, container.Register("users", &testServiceImpl{}))
+    require.NoError(t, container.RegisterWeak("report", func() (interface{}, error) { return "report", nil }))
+
+    assert.Equal(t, "Container{registrations: 2, singletons: 1, weak: 1, profile: default, frozen: false}", container.String())
+
+    var buf bytes.Buffer
+    logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+        ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
+            if attr.Key == slog.TimeKey {
+                return slog.Attr{}
+            }
+            return attr
+        },
+    }))
+    logger.Info("wired", "container", container)
+    descriptor, _ := container.Describe("users")
+    logger.Info("service", "descriptor", descriptor)
+
+    assert.Contains(t, buf.String(), "container.registrations=2 container.singletons=1 container.weak=1 container.profile=default container.frozen=false")
+    assert.Contains(t, buf.String(), "descriptor.qualifier=users descriptor.lifetime=singleton descriptor.stage=0 descriptor.type=*container.testServiceImpl")
+}
@@ -0,0 +1,155 @@
+package container
+
+import (
+    "fmt"
+    "sort"
+    "strings"
+)
+
+// ManifestEntry describes one registration in a RegistrationManifest
+type ManifestEntry struct {
+    Qualifier string `json:"qualifier"`
+    Type      string `json:"type,omitempty"` // Empty for weak services not built yet
+    Lifetime  string `json:"lifetime"`
+    Module    string `json:"module,omitempty"`
+    Profile   string `json:"profile"`
+}
+
+// String returns a one-line summary such as "users *app.userService (singleton, profile default)"
+func (e ManifestEntry) String() string {
+    typeName := e.Type
+    if typeName == "" {
+        typeName = "<unbuilt>"
+    }
+    summary := fmt.Sprintf("%s %s (%s, profile %s", e.Qualifier, typeName, e.Lifetime, e.Profile)
+    if e.Module != "" {
+        summary += ", module " + e.Module
+    }
+    return summary + ")"
+}
+
+// RegistrationManifest is a serializable view of a container's wiring.
+// Committing it as JSON lets CI review wiring changes with DiffManifests
+// like a schema migration.
+type RegistrationManifest struct {
+    Registrations []ManifestEntry `json:"registrations"` // Sorted by qualifier
+}
+
+// RegistrationManifest returns the manifest of all current registrations
+func (c *Container) RegistrationManifest() RegistrationManifest {
+    descriptors := c.Descriptors()
+
+    manifest := RegistrationManifest{Registrations: make([]ManifestEntry, 0, len(descriptors))}
+    for _, descriptor := range descriptors {
+        entry := ManifestEntry{
+            Qualifier: descriptor.Qualifier,
+            Lifetime:  descriptor.Lifetime.String(),
+            Module:    descriptor.Module,
+            Profile:   descriptor.Profile,
+        }
+        if descriptor.Type != nil {
+            entry.Type = descriptor.Type.String()
+        }
+        manifest.Registrations = append(manifest.Registrations, entry)
+    }
+    sort.Slice(manifest.Registrations, func(a, b int) bool {
+        return manifest.Registrations[a].Qualifier < manifest.Registrations[b].Qualifier
+    })
+    return manifest
+}
+
+// ManifestChange is a registration present in both manifests whose type,
+// lifetime, module or profile differs
+type ManifestChange struct {
+    Before ManifestEntry
+    After  ManifestEntry
+}
+
+// Changes lists the differing attributes, e.g. "lifetime singleton -> weak"
+func (m ManifestChange) Changes() []string {
+    var changes []string
+    compare := func(name, before, after string) {
+        if before != after {
+            changes = append(changes, fmt.Sprintf("%s %s -> %s", name, orNone(before), orNone(after)))
+        }
+    }
+    // An unbuilt weak service has no type to compare
+    if m.Before.Type != "" && m.After.Type != "" {
+        compare("type", m.Before.Type, m.After.Type)
+    }
+    compare("lifetime", m.Before.Lifetime, m.After.Lifetime)
+    compare("module", m.Before.Module, m.After.Module)
+    compare("profile", m.Before.Profile, m.After.Profile)
+    return changes
+}
+
+// orNone renders empty attributes readably
+func orNone(value string) string {
+    if value == "" {
+        return "<none>"
+    }
+    return value
+}
+
+// ManifestDiff lists how the wiring changed between two manifests. Every
+// list is sorted by qualifier.
+type ManifestDiff struct {
+    Added   []ManifestEntry
+    Removed []ManifestEntry
+    Changed []ManifestChange
+}
+
+// Empty reports whether the manifests have the same wiring
+func (d ManifestDiff) Empty() bool {
+    return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// String renders the diff one registration per line, prefixed with +, -
+// or ~ for added, removed and changed registrations
+func (d ManifestDiff) String() string {
+    var b strings.Builder
+    for _, entry := range d.Added {
+        fmt.Fprintf(&b, "+ %s\n", entry)
+    }
+    for _, entry := range d.Removed {
+        fmt.Fprintf(&b, "- %s\n", entry)
+    }
+    for _, change := range d.Changed {
+        fmt.Fprintf(&b, "~ %s: %s\n", change.After.Qualifier, strings.Join(change.Changes(), ", "))
+    }
+    return b.String()
+}
+
+// DiffManifests compares the wiring of manifest a, the old one, with b
+func DiffManifests(a, b RegistrationManifest) ManifestDiff {
+    before := make(map[string]ManifestEntry, len(a.Registrations))
+    for _, entry := range a.Registrations {
+        before[entry.Qualifier] = entry
+    }
+    after := make(map[string]ManifestEntry, len(b.Registrations))
+    for _, entry := range b.Registrations {
+        after[entry.Qualifier] = entry
+    }
+
+    var diff ManifestDiff
+    for qualifier, entry := range after {
+        old, ok := before[qualifier]
+        if !ok {
+            diff.Added = append(diff.Added, entry)
+            continue
+        }
+        if change := (ManifestChange{Before: old, After: entry}); len(change.Changes()) > 0 {
+            diff.Changed = append(diff.Changed, change)
+        }
+    }
+    for qualifier, entry := range before {
+        if _, ok := after[qualifier]; !ok {
+            diff.Removed = append(diff.Removed, entry)
+        }
+    }
+
+    sort.Slice(diff.Added, func(i, j int) bool { return diff.Added[i].Qualifier < diff.Added[j].Qualifier })
+    sort.Slice(diff.Removed, func(i, j int) bool { return diff.Removed[i].Qualifier < diff.Removed[j].Qualifier })
+    sort.Slice(diff.Changed, func(i, j int) bool { return diff.Changed[i].After.Qualifier < diff.Changed[j].After.Qualifier })
+    return diff
+}
@@ -0,0 +1,53 @@
+package container
+
+import (
+    "encoding/json"
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+func TestRegistrationManifest(t *testing.T) {
+    c := NewContainer()
+    require.NoError(t, c.Register("users", &adminHandler{}))
+    c.SetProfile("test")
+    require.NoError(t, c.Install(Module{Name: "mail", Setup: func(c *Container) error {
+        return c.Register("mailer", "smtp")
+    }}))
+
+    manifest := c.RegistrationManifest()
+    assert.Equal(t, []ManifestEntry{
+        {Qualifier: "mailer", Type: "string", Lifetime: "singleton", Module: "mail", Profile: "test"},
+        {Qualifier: "users", Type: "*container.adminHandler", Lifetime: "singleton", Profile: "default"},
+    }, manifest.Registrations)
+
+    // Manifests round-trip through JSON so CI can commit them
+    data, err := json.Marshal(manifest)
+    require.NoError(t, err)
+    var decoded RegistrationManifest
+    require.NoError(t, json.Unmarshal(data, &decoded))
+    assert.True(t, DiffManifests(manifest, decoded).Empty())
+}
+
+func TestDiffManifests(t *testing.T) {
+    a := RegistrationManifest{Registrations: []ManifestEntry{
+        {Qualifier: "cache", Type: "*redis.Client", Lifetime: "singleton", Profile: "default"},
+        {Qualifier: "mailer", Type: "*smtp.Mailer", Lifetime: "singleton", Profile: "default"},
+        {Qualifier: "reports", Lifetime: "weak", Profile: "default"},
+        {Qualifier: "users", Type: "*app.Users", Lifetime: "singleton", Profile: "default"},
+    }}
+    b := RegistrationManifest{Registrations: []ManifestEntry{
+        {Qualifier: "cache", Type: "*memcache.Client", Lifetime: "weak", Profile: "default"},
+        {Qualifier: "queue", Type: "*nats.Conn", Lifetime: "singleton", Profile: "default"},
+        {Qualifier: "reports", Type: "*app.Reports", Lifetime: "weak", Profile: "default"},
+        {Qualifier: "users", Type: "*app.Users", Lifetime: "singleton", Module: "accounts", Profile: "test"},
+    }}
+
+    diff := DiffManifests(a, b)
+    require.False(t, diff.Empty())
+    assert.Equal(t, "+ queue *nats.Conn (singleton, profile default)\n"+
+        "- mailer *smtp.Mailer (singleton, profile default)\n"+
+        "~ cache: type *redis.Client -> *memcache.Client, lifetime singleton -> weak\n"+
+        "~ users: module <none> -> accounts, profile default -> test\n", diff.String())
+}
@@ -0,0 +1,125 @@
+package container
+
+import (
+    "fmt"
+    "reflect"
+    "strings"
+)
+
+// installDeferredModules installs the modules deferred by their enable key
+// if the key allows it. Modules installed by a deferred module's Setup are
+// handled in the same pass.
+func (c *Container) installDeferredModules() error {
+    for {
+        c.mu.Lock()
+        modules := c.deferredModules
+        c.deferredModules = nil
+        c.mu.Unlock()
+        if len(modules) == 0 {
+            return nil
+        }
+
+        for _, module := range modules {
+            enabled, err := c.ModuleEnabled(module.EnableKey)
+            if err != nil {
+                return fmt.Errorf("failed to read enable key of module %s: %w", module.Name, err)
+            }
+            if !enabled {
+                c.log.Infow("Skipping disabled module",
+                    "module", module.Name,
+                    "enableKey", module.EnableKey)
+                c.mu.Lock()
+                c.disabledModules = append(c.disabledModules, module.Name)
+                c.mu.Unlock()
+                continue
+            }
+            if err := c.installNow(module); err != nil {
+                return err
+            }
+        }
+    }
+}
+
+// DisabledModules returns the modules skipped because their enable key is
+// false, in installation order
+func (c *Container) DisabledModules() []string {
+    c.mu.RLock()
+    defer c.mu.RUnlock()
+    return append([]string(nil), c.disabledModules...)
+}
+
+// ModuleEnabled reads a boolean enable key. A bool registered under the key
+// itself wins; otherwise the dotted path is looked up in every config
+// registration (see AsConfig) in registration order, matching struct
+// fields by json name or case-insensitive field name, and map keys. Keys
+// found nowhere are enabled.
+func (c *Container) ModuleEnabled(key string) (bool, error) {
+    c.mu.RLock()
+    var configs []interface{}
+    direct, hasDirect := c.services[key]
+    for _, qualifier := range c.order {
+        if service, ok := c.services[qualifier]; ok && c.regs[qualifier].config {
+            configs = append(configs, service)
+        }
+    }
+    c.mu.RUnlock()
+
+    if hasDirect {
+        enabled, ok := direct.(bool)
+        if !ok {
+            return false, fmt.Errorf("service %s has type %T, not bool", key, direct)
+        }
+        return enabled, nil
+    }
+
+    path := strings.Split(key, ".")
+    for _, config := range configs {
+        value, found := lookupConfigPath(reflect.ValueOf(config), path)
+        if !found {
+            continue
+        }
+        if value.Kind() != reflect.Bool {
+            return false, fmt.Errorf("config key %s has type %v, not bool", key, value.Type())
+        }
+        return value.Bool(), nil
+    }
+
+    c.log.Debugw("Enable key not configured, enabling", "key", key)
+    return true, nil
+}
+
+// lookupConfigPath follows path through structs and string-keyed maps
+func lookupConfigPath(value reflect.Value, path []string) (reflect.Value, bool) {
+    for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+        if value.IsNil() {
+            return reflect.Value{}, false
+        }
+        value = value.Elem()
+    }
+    if len(path) == 0 {
+        return value, true
+    }
+
+    switch value.Kind() {
+    case reflect.Struct:
+        for i := 0; i < value.NumField(); i++ {
+            field := value.Type().Field(i)
+            if !field.IsExported() {
+                continue
+            }
+            name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+            if name == path[0] || (name == "" && strings.EqualFold(field.Name, path[0])) {
+                return lookupConfigPath(value.Field(i), path[1:])
+            }
+        }
+    case reflect.Map:
+        if value.Type().Key().Kind() != reflect.String {
+            return reflect.Value{}, false
+        }
+        entry := value.MapIndex(reflect.ValueOf(path[0]).Convert(value.Type().Key()))
+        if entry.IsValid() {
+            return lookupConfigPath(entry, path[1:])
+        }
+    }
+    return reflect.Value{}, false
+}
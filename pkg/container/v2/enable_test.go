@@ -0,0 +1,92 @@
+package container
+
+import (
+    "context"
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+type moduleFlags struct {
+    Enabled bool `json:"enabled"`
+}
+
+type featureConfig struct {
+    Modules map[string]moduleFlags `json:"modules"`
+}
+
+// featureModule registers a service, a group member and a hook
+func featureModule(name string, started *[]string) Module {
+    return Module{
+        Name:      name,
+        EnableKey: "modules." + name + ".enabled",
+        Setup: func(c *Container) error {
+            c.Append(Hook{Name: name, OnStart: func(ctx context.Context) error {
+                *started = append(*started, name)
+                return nil
+            }})
+            return c.Register(name, name, InGroup("features"))
+        },
+    }
+}
+
+func isRegistered(c *Container, qualifier string) bool {
+    _, ok := c.Describe(qualifier)
+    return ok
+}
+
+func TestModule_EnableKey(t *testing.T) {
+    c := NewContainer()
+    var started []string
+    require.NoError(t, c.Install(
+        featureModule("billing", &started),
+        featureModule("search", &started),
+        featureModule("reports", &started),
+    ))
+    require.NoError(t, c.Register("config", &featureConfig{Modules: map[string]moduleFlags{
+        "billing": {Enabled: true},
+        "search":  {Enabled: false},
+    }}, AsConfig()))
+
+    // Nothing is installed before Build reads the config
+    assert.False(t, isRegistered(c, "billing"))
+
+    require.NoError(t, c.Build())
+    assert.True(t, isRegistered(c, "billing"))
+    assert.False(t, isRegistered(c, "search"))
+    assert.True(t, isRegistered(c, "reports")) // Unconfigured keys are enabled
+    assert.Equal(t, []string{"search"}, c.DisabledModules())
+
+    members, err := c.ResolveGroup("features")
+    require.NoError(t, err)
+    assert.Equal(t, []interface{}{"billing", "reports"}, members)
+
+    require.NoError(t, c.Start(context.Background()))
+    assert.Equal(t, []string{"billing", "reports"}, started)
+}
+
+func TestModule_EnableKeyInstalledByStart(t *testing.T) {
+    c := NewContainer()
+    var started []string
+    require.NoError(t, c.Register("modules.billing.enabled", false))
+    require.NoError(t, c.Install(featureModule("billing", &started)))
+
+    require.NoError(t, c.Start(context.Background()))
+    assert.False(t, isRegistered(c, "billing"))
+    assert.Empty(t, started)
+}
+
+func TestModuleEnabled_Errors(t *testing.T) {
+    c := NewContainer()
+    require.NoError(t, c.Register("modules.billing.enabled", "yes"))
+    require.NoError(t, c.Register("config", &featureConfig{}, AsConfig()))
+
+    _, err := c.ModuleEnabled("modules.billing.enabled")
+    assert.EqualError(t, err, "service modules.billing.enabled has type string, not bool")
+    _, err = c.ModuleEnabled("modules")
+    assert.ErrorContains(t, err, "not bool")
+
+    require.NoError(t, c.Install(Module{Name: "billing", EnableKey: "modules.billing.enabled"}))
+    assert.ErrorContains(t, c.Build(), "failed to read enable key of module billing")
+}
@@ -0,0 +1,152 @@
+package container
+
+import (
+    "errors"
+    "fmt"
+    "reflect"
+    "strings"
+)
+
+// ErrNilTarget is returned by InjectStruct when the target is nil
+var ErrNilTarget = errors.New("injection target is nil")
+
+// NilPointerError is returned by InjectStruct when the target is a nil
+// pointer of a concrete type, e.g. (*Handler)(nil). It matches ErrNilTarget
+// with errors.Is; use errors.As to tell the two cases apart.
+type NilPointerError struct {
+    Type reflect.Type // Pointer type of the target
+}
+
+func (e *NilPointerError) Error() string {
+    return fmt.Sprintf("injection target is a nil %v; pass a pointer to an allocated struct", e.Type)
+}
+
+// Is makes errors.Is(err, ErrNilTarget) true
+func (e *NilPointerError) Is(target error) bool {
+    return target == ErrNilTarget
+}
+
+// Sentinel errors of container operations. The errors returned carry the
+// qualifier and types involved; match them with errors.Is, or extract the
+// details with errors.As:
+//
+//	var notFound *container.ServiceNotFoundError
+//	if errors.As(err, &notFound) {
+//	    log.Printf("missing %s", notFound.Qualifier)
+//	}
+var (
+    ErrServiceNotFound       = errors.New("service not found")
+    ErrDuplicateRegistration = errors.New("service already registered")
+    ErrNilService            = errors.New("nil service")
+    ErrTypeMismatch          = errors.New("service type mismatch")
+    ErrScopeRequired         = errors.New("scoped service resolved outside a scope")
+)
+
+// ServiceNotFoundError is returned when no service is registered for a
+// qualifier, or, when resolving by type, none is assignable to Type. It
+// matches ErrServiceNotFound.
+type ServiceNotFoundError struct {
+    Qualifier   string       // Empty when resolving by type
+    Type        reflect.Type // Requested type, nil when resolving by qualifier
+    Suggestions []string     // Registered qualifiers with a similar name
+}
+
+func (e *ServiceNotFoundError) Error() string {
+    if e.Qualifier == "" && e.Type != nil {
+        return fmt.Sprintf("no service assignable to %v", e.Type)
+    }
+    if len(e.Suggestions) > 0 {
+        return fmt.Sprintf("no service found for qualifier: %s; did you mean %s?", e.Qualifier, strings.Join(e.Suggestions, " or "))
+    }
+    return fmt.Sprintf("no service found for qualifier: %s", e.Qualifier)
+}
+
+// Is makes errors.Is(err, ErrServiceNotFound) true
+func (e *ServiceNotFoundError) Is(target error) bool {
+    return target == ErrServiceNotFound
+}
+
+// DuplicateRegistrationError is returned when registering a qualifier that
+// is already taken in the container or the scope. It matches
+// ErrDuplicateRegistration.
+type DuplicateRegistrationError struct {
+    Qualifier string
+    InScope   bool // Whether the qualifier is taken in a Scope
+}
+
+func (e *DuplicateRegistrationError) Error() string {
+    if e.InScope {
+        return fmt.Sprintf("service already registered in scope for qualifier: %s", e.Qualifier)
+    }
+    return fmt.Sprintf("service already registered for qualifier: %s", e.Qualifier)
+}
+
+// Is makes errors.Is(err, ErrDuplicateRegistration) true
+func (e *DuplicateRegistrationError) Is(target error) bool {
+    return target == ErrDuplicateRegistration
+}
+
+// NilServiceError is returned when a service is nil, a typed nil such as
+// (*Store)(nil) rejected by the NilPolicy, or when the factory or provider
+// registered to build it is nil. It matches ErrNilService.
+type NilServiceError struct {
+    Qualifier string
+    Type      reflect.Type // Type of a typed nil, nil for an untyped nil
+    Source    string       // Where a typed nil came from, e.g. "factory result", or the nil function registered, e.g. "factory"
+    InScope   bool         // Whether the service was registered in a Scope
+}
+
+func (e *NilServiceError) Error() string {
+    if e.Type != nil {
+        return fmt.Sprintf("%s for qualifier %s is a nil %v", e.Source, e.Qualifier, e.Type)
+    }
+    if e.Source != "" {
+        return fmt.Sprintf("nil %s for qualifier: %s", e.Source, e.Qualifier)
+    }
+    if e.InScope {
+        return fmt.Sprintf("nil service in scope for qualifier: %s", e.Qualifier)
+    }
+    return fmt.Sprintf("nil service for qualifier: %s", e.Qualifier)
+}
+
+// Is makes errors.Is(err, ErrNilService) true
+func (e *NilServiceError) Is(target error) bool {
+    return target == ErrNilService
+}
+
+// ScopeRequiredError is returned when a scoped service is resolved from the
+// container instead of a Scope. It matches ErrScopeRequired.
+type ScopeRequiredError struct {
+    Qualifier string
+}
+
+func (e *ScopeRequiredError) Error() string {
+    return fmt.Sprintf("service %s is scoped; resolve it through a Scope", e.Qualifier)
+}
+
+// Is makes errors.Is(err, ErrScopeRequired) true
+func (e *ScopeRequiredError) Is(target error) bool {
+    return target == ErrScopeRequired
+}
+
+// TypeMismatchError is returned when a service cannot be assigned to the
+// type it is injected or resolved as. It matches ErrTypeMismatch.
+type TypeMismatchError struct {
+    Qualifier string       // Empty when the qualifier is not known at the failing site
+    Type      reflect.Type // Type of the service
+    Want      reflect.Type // Type it must be assignable to
+    Target    string       // What has type Want, e.g. "field type"; empty for a plain type
+    Detail    string       // Explanation of the mismatch, e.g. missing methods
+}
+
+func (e *TypeMismatchError) Error() string {
+    if e.Target != "" {
+        return fmt.Sprintf("service type %v is not assignable to %s %v%s", e.Type, e.Target, e.Want, e.Detail)
+    }
+    return fmt.Sprintf("service %s has type %v, which is not assignable to %v%s", e.Qualifier, e.Type, e.Want, e.Detail)
+}
+
+// Is makes errors.Is(err, ErrTypeMismatch) true
+func (e *TypeMismatchError) Is(target error) bool {
+    return target == ErrTypeMismatch
+}
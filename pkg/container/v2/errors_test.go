@@ -0,0 +1,103 @@
+package container
+
+import (
+    "context"
+    "reflect"
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+func TestErrors_ServiceNotFound(t *testing.T) {
+    c := NewContainer()
+
+    _, err := c.Resolve("missing")
+    assert.ErrorIs(t, err, ErrServiceNotFound)
+    var notFound *ServiceNotFoundError
+    require.ErrorAs(t, err, &notFound)
+    assert.Equal(t, "missing", notFound.Qualifier)
+
+    serviceType := reflect.TypeOf((*TestService)(nil)).Elem()
+    _, err = c.ResolveByType(serviceType)
+    require.ErrorAs(t, err, &notFound)
+    assert.Equal(t, serviceType, notFound.Type)
+    assert.EqualError(t, err, "no service assignable to container.TestService")
+}
+
+func TestErrors_DuplicateRegistration(t *testing.T) {
+    c := NewContainer()
+    require.NoError(t, c.Register("user", "alice"))
+
+    err := c.Register("user", "bob")
+    assert.ErrorIs(t, err, ErrDuplicateRegistration)
+    var duplicate *DuplicateRegistrationError
+    require.ErrorAs(t, err, &duplicate)
+    assert.Equal(t, "user", duplicate.Qualifier)
+    assert.False(t, duplicate.InScope)
+
+    scope := c.NewScope(context.Background())
+    defer scope.Close(nil)
+    require.NoError(t, scope.Register("request", "r1"))
+    err = scope.Register("request", "r2")
+    require.ErrorAs(t, err, &duplicate)
+    assert.True(t, duplicate.InScope)
+}
+
+func TestErrors_NilService(t *testing.T) {
+    c := NewContainer()
+
+    err := c.Register("store", nil)
+    assert.ErrorIs(t, err, ErrNilService)
+    assert.EqualError(t, err, "cannot register nil service for qualifier: store")
+
+    err = c.Register("store", (*testServiceImpl)(nil))
+    var nilService *NilServiceError
+    require.ErrorAs(t, err, &nilService)
+    assert.Equal(t, "store", nilService.Qualifier)
+    assert.Equal(t, reflect.TypeOf((*testServiceImpl)(nil)), nilService.Type)
+
+    // Nil factories and providers are nil services too
+    err = c.RegisterFactory("factory", nil)
+    assert.ErrorIs(t, err, ErrNilService)
+    assert.EqualError(t, err, "cannot register nil factory for qualifier: factory")
+    err = c.RegisterWeak("weak", nil)
+    require.ErrorAs(t, err, &nilService)
+    assert.Equal(t, "weak", nilService.Qualifier)
+    assert.EqualError(t, err, "cannot register nil provider for qualifier: weak")
+    assert.ErrorIs(t, c.RegisterScoped("scoped", nil), ErrNilService)
+}
+
+func TestErrors_ScopeRequired(t *testing.T) {
+    c := NewContainer()
+    require.NoError(t, c.RegisterScoped("request", func(*Scope) (interface{}, error) {
+        return "r1", nil
+    }))
+
+    _, err := c.Resolve("request")
+    assert.ErrorIs(t, err, ErrScopeRequired)
+    var scopeRequired *ScopeRequiredError
+    require.ErrorAs(t, err, &scopeRequired)
+    assert.Equal(t, "request", scopeRequired.Qualifier)
+    assert.EqualError(t, err, "service request is scoped; resolve it through a Scope")
+}
+
+func TestErrors_TypeMismatch(t *testing.T) {
+    c := NewContainer()
+    require.NoError(t, c.Register("count", 42))
+
+    var target struct {
+        Count string `di:"count"`
+    }
+    err := c.InjectStruct(&target)
+    assert.ErrorIs(t, err, ErrTypeMismatch)
+    var mismatch *TypeMismatchError
+    require.ErrorAs(t, err, &mismatch)
+    assert.Equal(t, "count", mismatch.Qualifier)
+    assert.Equal(t, reflect.TypeOf(0), mismatch.Type)
+    assert.Equal(t, reflect.TypeOf(""), mismatch.Want)
+
+    _, err = ResolveAs[TestService](c, "count")
+    assert.ErrorIs(t, err, ErrTypeMismatch)
+    assert.ErrorIs(t, c.Bind((*TestService)(nil), "count"), ErrTypeMismatch)
+}
@@ -0,0 +1,68 @@
+package container
+
+import (
+    "time"
+)
+
+// EventKind names a container event. Mutations use their MutationKind.
+type EventKind string
+
+const (
+    EventRegister      EventKind = EventKind(MutationRegister)
+    EventSwap          EventKind = EventKind(MutationSwap)
+    EventUnregister    EventKind = EventKind(MutationUnregister)
+    EventRename        EventKind = EventKind(MutationRename)
+    EventDecorate      EventKind = EventKind(MutationDecorate)
+    EventFreeze        EventKind = EventKind(MutationFreeze)
+    EventScopeOpen     EventKind = EventKind(MutationScopeOpen)
+    EventScopeClose    EventKind = EventKind(MutationScopeClose)
+    EventResolveFailed EventKind = "resolve_failed"
+    EventStarting      EventKind = "starting"
+    EventStarted       EventKind = "started"
+    EventStartFailed   EventKind = "start_failed"
+    EventStopped       EventKind = "stopped"
+    EventStopFailed    EventKind = "stop_failed"
+    EventDegraded      EventKind = "degraded"
+    EventRecovered     EventKind = "recovered"
+    EventExposure      EventKind = "exposure"
+)
+
+// Event is a notable change or failure in the container
+type Event struct {
+    Time      time.Time
+    Kind      EventKind
+    Qualifier string // Affected qualifier, empty for lifecycle events
+    Detail    string
+    Err       error
+}
+
+// Failed reports whether the event describes a failure
+func (e Event) Failed() bool {
+    return e.Err != nil
+}
+
+// OnEvent adds a listener called for every container event. Listeners run
+// synchronously, possibly while the container is locked, so they must be
+// fast and must not call back into the container.
+func (c *Container) OnEvent(listener func(Event)) {
+    c.eventsMu.Lock()
+    defer c.eventsMu.Unlock()
+    c.listeners = append(c.listeners, listener)
+}
+
+// emit delivers an event to every listener
+func (c *Container) emit(event Event) {
+    c.eventsMu.Lock()
+    listeners := c.listeners
+    c.eventsMu.Unlock()
+
+    if len(listeners) == 0 {
+        return
+    }
+    if event.Time.IsZero() {
+        event.Time = c.clock.Now()
+    }
+    for _, listener := range listeners {
+        listener(event)
+    }
+}
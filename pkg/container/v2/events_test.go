@@ -0,0 +1,79 @@
+package container
+
+import (
+    "context"
+    "errors"
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+// collectEvents records every event of c
+func collectEvents(c *Container) *[]Event {
+    events := &[]Event{}
+    c.OnEvent(func(event Event) {
+        *events = append(*events, event)
+    })
+    return events
+}
+
+func TestOnEvent_Mutations(t *testing.T) {
+    c := NewContainer()
+    events := collectEvents(c)
+
+    require.NoError(t, c.Register("users", "users"))
+    _, err := c.Swap("users", "replacement")
+    require.NoError(t, err)
+
+    require.Len(t, *events, 2)
+    assert.Equal(t, EventRegister, (*events)[0].Kind)
+    assert.Equal(t, "users", (*events)[0].Qualifier)
+    assert.Equal(t, EventSwap, (*events)[1].Kind)
+    assert.False(t, (*events)[1].Time.IsZero())
+}
+
+func TestOnEvent_ResolveFailed(t *testing.T) {
+    c := NewContainer()
+    events := collectEvents(c)
+
+    _, err := c.Resolve("missing")
+    require.Error(t, err)
+
+    require.Len(t, *events, 1)
+    assert.Equal(t, EventResolveFailed, (*events)[0].Kind)
+    assert.Equal(t, "missing", (*events)[0].Qualifier)
+    assert.True(t, (*events)[0].Failed())
+}
+
+func TestOnEvent_Lifecycle(t *testing.T) {
+    c := NewContainer()
+    c.Append(Hook{
+        Name:   "db",
+        OnStop: func(ctx context.Context) error { return errors.New("close failed") },
+    })
+    events := collectEvents(c)
+
+    require.NoError(t, c.Start(context.Background()))
+    require.Error(t, c.Stop(context.Background()))
+
+    var kinds []EventKind
+    for _, event := range *events {
+        kinds = append(kinds, event.Kind)
+    }
+    assert.Equal(t, []EventKind{EventStarting, EventStarted, EventStopFailed}, kinds)
+}
+
+func TestOnEvent_StartFailed(t *testing.T) {
+    c := NewContainer()
+    c.Append(Hook{
+        Name:    "db",
+        OnStart: func(ctx context.Context) error { return errors.New("unreachable") },
+    })
+    events := collectEvents(c)
+
+    require.Error(t, c.Start(context.Background()))
+    last := (*events)[len(*events)-1]
+    assert.Equal(t, EventStartFailed, last.Kind)
+    assert.ErrorContains(t, last.Err, "unreachable")
+}
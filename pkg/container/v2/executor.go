@@ -0,0 +1,112 @@
+package container
+
+import (
+    "errors"
+    "sync"
+)
+
+// Executor runs a batch of independent tasks. *errgroup.Group from
+// golang.org/x/sync satisfies this interface, as does any worker pool with
+// the same two methods.
+type Executor interface {
+    Go(task func() error)
+    Wait() error
+}
+
+// ExecutorFactory creates a fresh Executor for each batch of tasks
+type ExecutorFactory func() Executor
+
+// SetExecutor installs the factory used to run independent startup work:
+// constructing one dependency level of providers and warming the services
+// of one init stage. A nil factory restores the default sequential
+// execution.
+//
+// To use errgroup with a concurrency limit:
+//
+//	c.SetExecutor(func() container.Executor {
+//	    g := new(errgroup.Group)
+//	    g.SetLimit(4)
+//	    return g
+//	})
+func (c *Container) SetExecutor(factory ExecutorFactory) {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+
+    if factory == nil {
+        factory = Sequential
+    }
+    c.executor = factory
+}
+
+// newExecutor returns a new Executor from the configured factory, or a
+// sequential one in deterministic mode so startup runs in a fixed order
+func (c *Container) newExecutor() Executor {
+    if c.random.isSeeded() {
+        return Sequential()
+    }
+    c.mu.RLock()
+    defer c.mu.RUnlock()
+    return c.executor()
+}
+
+// Sequential runs tasks one after another in the calling goroutine and
+// reports the first error. It is the default executor.
+func Sequential() Executor {
+    return &sequentialExecutor{}
+}
+
+type sequentialExecutor struct {
+    err error
+}
+
+func (e *sequentialExecutor) Go(task func() error) {
+    if e.err == nil {
+        e.err = task()
+    }
+}
+
+func (e *sequentialExecutor) Wait() error {
+    return e.err
+}
+
+// Parallel returns a factory for executors that run at most limit tasks at
+// once (unbounded when limit <= 0) and join the errors of all tasks
+func Parallel(limit int) ExecutorFactory {
+    return func() Executor {
+        executor := &parallelExecutor{}
+        if limit > 0 {
+            executor.slots = make(chan struct{}, limit)
+        }
+        return executor
+    }
+}
+
+type parallelExecutor struct {
+    wg    sync.WaitGroup
+    slots chan struct{} // Semaphore bounding concurrency, nil when unbounded
+    mu    sync.Mutex
+    errs  []error
+}
+
+func (e *parallelExecutor) Go(task func() error) {
+    if e.slots != nil {
+        e.slots <- struct{}{}
+    }
+    e.wg.Add(1)
+    go func() {
+        defer e.wg.Done()
+        if e.slots != nil {
+            defer func() { <-e.slots }()
+        }
+        if err := task(); err != nil {
+            e.mu.Lock()
+            e.errs = append(e.errs, err)
+            e.mu.Unlock()
+        }
+    }()
+}
+
+func (e *parallelExecutor) Wait() error {
+    e.wg.Wait()
+    return errors.Join(e.errs...)
+}
@@ -0,0 +1,94 @@
+package container
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "sync/atomic"
+    "testing"
+    "time"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+func TestSequentialExecutor(t *testing.T) {
+    executor := Sequential()
+    var ran []int
+
+    executor.Go(func() error { ran = append(ran, 1); return nil })
+    executor.Go(func() error { ran = append(ran, 2); return errors.New("stop") })
+    executor.Go(func() error { ran = append(ran, 3); return nil })
+
+    assert.EqualError(t, executor.Wait(), "stop")
+    assert.Equal(t, []int{1, 2}, ran)
+}
+
+func TestParallelExecutorLimit(t *testing.T) {
+    executor := Parallel(2)()
+    var running, peak int32
+
+    for i := 0; i < 6; i++ {
+        executor.Go(func() error {
+            current := atomic.AddInt32(&running, 1)
+            for {
+                old := atomic.LoadInt32(&peak)
+                if current <= old || atomic.CompareAndSwapInt32(&peak, old, current) {
+                    break
+                }
+            }
+            time.Sleep(5 * time.Millisecond)
+            atomic.AddInt32(&running, -1)
+            return nil
+        })
+    }
+
+    require.NoError(t, executor.Wait())
+    assert.LessOrEqual(t, peak, int32(2))
+}
+
+// slowWarmer records how many warmups overlap
+type slowWarmer struct {
+    running *int32
+    peak    *int32
+}
+
+func (w *slowWarmer) Warmup(ctx context.Context) error {
+    current := atomic.AddInt32(w.running, 1)
+    defer atomic.AddInt32(w.running, -1)
+    if current > atomic.LoadInt32(w.peak) {
+        atomic.StoreInt32(w.peak, current)
+    }
+    time.Sleep(20 * time.Millisecond)
+    return nil
+}
+
+func TestContainer_ParallelWarmup(t *testing.T) {
+    container := NewContainer()
+    container.SetExecutor(Parallel(0))
+
+    var running, peak int32
+    require.NoError(t, container.Register("a", &slowWarmer{running: &running, peak: &peak}))
+    require.NoError(t, container.Register("b", &slowWarmer{running: &running, peak: &peak}))
+    require.NoError(t, container.Register("c", &warmingService{err: errors.New("cold")}))
+
+    err := container.Start(context.Background())
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "cold")
+    assert.Equal(t, int32(2), peak)
+
+    // Successfully warmed services are remembered
+    assert.True(t, container.warmed["a"])
+    assert.True(t, container.warmed["b"])
+}
+
+func TestContainer_ParallelWarmupManyServices(t *testing.T) {
+    container := NewContainer()
+    container.SetExecutor(Parallel(0))
+    for i := 0; i < 200; i++ {
+        require.NoError(t, container.Register(fmt.Sprintf("warmer%d", i), &warmingService{}))
+    }
+
+    require.NoError(t, container.Start(context.Background()))
+    assert.Len(t, container.warmed, 200)
+}
@@ -0,0 +1,128 @@
+package container
+
+import (
+    "fmt"
+)
+
+// Experiment routes a share of scopes, e.g. 5% of requests, to a variant
+// implementation of a service:
+//
+//	ranker := container.Experiment{Name: "ranker-v2", Qualifier: "ranker", Variant: "rankerV2", Percent: 5}
+//	scope := c.NewScope(r.Context())
+//	scope.Assign(ranker)
+//
+// Both implementations are registered in the container as usual.
+//
+// Experimental: experiments build on scopes and share their stability.
+type Experiment struct {
+    Name      string
+    Qualifier string  // Qualifier consumers resolve
+    Variant   string  // Qualifier of the implementation under test
+    Percent   float64 // Share of scopes assigned to the variant, from 0 to 100
+}
+
+// override is the arm of an experiment a scope takes part in
+type override struct {
+    experiment string
+    variant    string // Empty for the control arm
+}
+
+// Assign draws whether the scope takes the variant arm of e, overriding
+// e.Qualifier with e.Variant if so, and returns the outcome. Scopes in the
+// control arm keep the original service but still log their exposure. The
+// draw is reproducible when the container is seeded with SetSeed.
+func (s *Scope) Assign(e Experiment) (bool, error) {
+    if e.Percent < 0 || e.Percent > 100 {
+        return false, fmt.Errorf("experiment %s: percent %v is not between 0 and 100", e.Name, e.Percent)
+    }
+    if s.c.random.float64()*100 < e.Percent {
+        return true, s.Override(e.Name, e.Qualifier, e.Variant)
+    }
+    return false, s.setOverride(e.Qualifier, override{experiment: e.Name})
+}
+
+// Override makes the scope and its children resolve variant wherever
+// qualifier is requested, through Resolve, InjectStruct and the providers
+// of scoped services, on behalf of experiment. The first resolution of
+// qualifier in the scope logs an exposure and emits an EventExposure, so
+// analysis only counts units of work that used the service. Resolutions
+// through the container itself are unaffected.
+func (s *Scope) Override(experiment, qualifier, variant string) error {
+    if qualifier == variant {
+        return fmt.Errorf("experiment %s: %s cannot override itself", experiment, qualifier)
+    }
+    if _, ok, err := s.local(variant); err != nil || !ok {
+        s.c.mu.RLock()
+        _, registered := s.c.regs[variant]
+        s.c.mu.RUnlock()
+        if err == nil && !registered {
+            err = &ServiceNotFoundError{Qualifier: variant}
+        }
+        if err != nil {
+            return fmt.Errorf("experiment %s: cannot override %s: %w", experiment, qualifier, err)
+        }
+    }
+    return s.setOverride(qualifier, override{experiment: experiment, variant: variant})
+}
+
+// setOverride records the arm a scope takes for qualifier
+func (s *Scope) setOverride(qualifier string, arm override) error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    if s.closed {
+        return fmt.Errorf("cannot override %s: scope is closed", qualifier)
+    }
+    if existing, ok := s.overrides[qualifier]; ok {
+        return fmt.Errorf("cannot override %s for experiment %s: already assigned by experiment %s", qualifier, arm.experiment, existing.experiment)
+    }
+    s.overrides[qualifier] = arm
+    return nil
+}
+
+// experimentVariant returns the qualifier to resolve in place of qualifier:
+// the variant of the nearest scope overriding it, else qualifier itself.
+// The first use in this scope logs the exposure.
+func (s *Scope) experimentVariant(qualifier string) string {
+    for scope := s; scope != nil; scope = scope.parent {
+        scope.mu.Lock()
+        arm, ok := scope.overrides[qualifier]
+        scope.mu.Unlock()
+        if !ok {
+            continue
+        }
+        s.expose(qualifier, arm)
+        if arm.variant == "" {
+            return qualifier
+        }
+        return arm.variant
+    }
+    return qualifier
+}
+
+// expose logs the first exposure of the scope to an experiment arm
+func (s *Scope) expose(qualifier string, arm override) {
+    s.mu.Lock()
+    first := !s.exposed[qualifier] && !s.closed
+    if first {
+        s.exposed[qualifier] = true
+    }
+    s.mu.Unlock()
+    if !first {
+        return
+    }
+
+    variant := arm.variant
+    if variant == "" {
+        variant = "control"
+    }
+    s.c.log.Infow("Experiment exposure",
+        "experiment", arm.experiment,
+        "scope", s.id,
+        "qualifier", qualifier,
+        "variant", variant)
+    s.c.emit(Event{
+        Kind:      EventExposure,
+        Qualifier: qualifier,
+        Detail:    fmt.Sprintf("experiment %s, variant %s, scope %s", arm.experiment, variant, s.id),
+    })
+}
@@ -0,0 +1,112 @@
+package container
+
+import (
+    "context"
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+type ranker struct{ version string }
+
+type searchHandler struct {
+    Ranker *ranker `di:"ranker"`
+}
+
+// exposures returns the exposure events among events
+func exposures(events []Event) []Event {
+    var matched []Event
+    for _, event := range events {
+        if event.Kind == EventExposure {
+            matched = append(matched, event)
+        }
+    }
+    return matched
+}
+
+func newRankerContainer(t *testing.T) *Container {
+    c := NewContainer()
+    require.NoError(t, c.Register("ranker", &ranker{version: "v1"}))
+    require.NoError(t, c.Register("rankerV2", &ranker{version: "v2"}))
+    return c
+}
+
+func TestScope_OverrideRoutesInjectionToVariant(t *testing.T) {
+    c := newRankerContainer(t)
+    events := collectEvents(c)
+
+    scope := c.NewScope(context.Background())
+    require.NoError(t, scope.Override("ranker-v2", "ranker", "rankerV2"))
+
+    var handler searchHandler
+    require.NoError(t, scope.InjectStruct(&handler))
+    assert.Equal(t, "v2", handler.Ranker.version)
+
+    // Children inherit the override; the container and other scopes do not
+    child, err := scope.NewScope(context.Background())
+    require.NoError(t, err)
+    service, err := child.Resolve("ranker")
+    require.NoError(t, err)
+    assert.Equal(t, "v2", service.(*ranker).version)
+
+    service, err = c.Resolve("ranker")
+    require.NoError(t, err)
+    assert.Equal(t, "v1", service.(*ranker).version)
+    service, err = c.NewScope(context.Background()).Resolve("ranker")
+    require.NoError(t, err)
+    assert.Equal(t, "v1", service.(*ranker).version)
+
+    // One exposure per scope that used the service
+    _, err = scope.Resolve("ranker")
+    require.NoError(t, err)
+    logged := exposures(*events)
+    require.Len(t, logged, 2)
+    assert.Equal(t, "ranker", logged[0].Qualifier)
+    assert.Equal(t, "experiment ranker-v2, variant rankerV2, scope "+scope.ID(), logged[0].Detail)
+    assert.Equal(t, "experiment ranker-v2, variant rankerV2, scope "+child.ID(), logged[1].Detail)
+}
+
+func TestScope_AssignSplitsTraffic(t *testing.T) {
+    c := newRankerContainer(t)
+    c.SetSeed(42)
+    events := collectEvents(c)
+    experiment := Experiment{Name: "ranker-v2", Qualifier: "ranker", Variant: "rankerV2", Percent: 5}
+
+    variants := 0
+    for i := 0; i < 1000; i++ {
+        scope := c.NewScope(context.Background())
+        assigned, err := scope.Assign(experiment)
+        require.NoError(t, err)
+
+        service, err := scope.Resolve("ranker")
+        require.NoError(t, err)
+        assert.Equal(t, assigned, service.(*ranker).version == "v2")
+        if assigned {
+            variants++
+        }
+    }
+    assert.InDelta(t, 50, variants, 25)
+
+    // Control scopes log their exposure too
+    assert.Len(t, exposures(*events), 1000)
+}
+
+func TestScope_OverrideErrors(t *testing.T) {
+    c := newRankerContainer(t)
+    scope := c.NewScope(context.Background())
+
+    assert.EqualError(t, scope.Override("x", "ranker", "missing"),
+        "experiment x: cannot override ranker: no service found for qualifier: missing")
+    assert.EqualError(t, scope.Override("x", "ranker", "ranker"), "experiment x: ranker cannot override itself")
+
+    require.NoError(t, scope.Override("x", "ranker", "rankerV2"))
+    assert.EqualError(t, scope.Override("y", "ranker", "rankerV2"),
+        "cannot override ranker for experiment y: already assigned by experiment x")
+
+    _, err := scope.Assign(Experiment{Name: "z", Percent: 120})
+    assert.EqualError(t, err, "experiment z: percent 120 is not between 0 and 100")
+
+    require.NoError(t, scope.Close(nil))
+    assert.Error(t, scope.Override("x", "other", "rankerV2"))
+}
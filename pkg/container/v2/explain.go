@@ -0,0 +1,167 @@
+package container
+
+import (
+    "fmt"
+    "reflect"
+    "sort"
+    "strings"
+)
+
+// Explanation describes how resolving a qualifier would proceed, see
+// Explain
+type Explanation struct {
+    Requested    string         // Qualifier asked for
+    Qualifier    string         // Qualifier after renames
+    Found        bool           // Whether the qualifier is registered
+    Source       string         // "instance", "factory", "provider", or the lifetime of other constructors
+    Built        bool           // Whether an instance exists, so resolving builds nothing
+    Type         reflect.Type   // Known type, nil for services not built yet
+    Lifetime     Lifetime
+    Module       string
+    Profile      string
+    RegisteredAt string         // file:line of the registration
+    BoundTypes   []reflect.Type // Types bound to the qualifier, see Bind
+    Dependencies []GraphEdge    // What the service depends on, see Graph
+    Suggestions  []string       // Registered qualifiers close to Requested when not found
+}
+
+// String renders the explanation over several lines
+func (e *Explanation) String() string {
+    var b strings.Builder
+    if !e.Found {
+        fmt.Fprintf(&b, "%s: not registered", e.Qualifier)
+        if len(e.Suggestions) > 0 {
+            fmt.Fprintf(&b, "; did you mean %s?", strings.Join(e.Suggestions, " or "))
+        }
+        return b.String()
+    }
+
+    fmt.Fprintf(&b, "%s", e.Qualifier)
+    if e.Requested != e.Qualifier {
+        fmt.Fprintf(&b, " (renamed from %s)", e.Requested)
+    }
+    typeName := "<unbuilt>"
+    if e.Type != nil {
+        typeName = e.Type.String()
+    }
+    fmt.Fprintf(&b, ": %s %s, %s", e.Source, typeName, e.Lifetime)
+    if e.Module != "" {
+        fmt.Fprintf(&b, ", module %s", e.Module)
+    }
+    if e.Built {
+        b.WriteString(", built")
+    }
+    fmt.Fprintf(&b, "\n  registered at %s", e.RegisteredAt)
+    for _, boundType := range e.BoundTypes {
+        fmt.Fprintf(&b, "\n  bound to %v", boundType)
+    }
+    for _, edge := range e.Dependencies {
+        fmt.Fprintf(&b, "\n  depends on %s (%s)", edge.To, edge.Kind)
+    }
+    return b.String()
+}
+
+// Explain describes how resolving qualifier would proceed, without
+// building anything: the renames followed, how the registration provides
+// the service, its type and lifetime, where it was registered, the types
+// bound to it and what it depends on. For an unknown qualifier it suggests
+// registered qualifiers with a similar name.
+func (c *Container) Explain(qualifier string) *Explanation {
+    explanation := &Explanation{
+        Requested: qualifier,
+        Qualifier: c.renamed(qualifier, func() string { return callerLocation(3) }),
+    }
+    qualifier = explanation.Qualifier
+
+    c.mu.RLock()
+    reg, ok := c.regs[qualifier]
+    if !ok {
+        c.mu.RUnlock()
+        explanation.Suggestions = c.suggest(qualifier)
+        return explanation
+    }
+    descriptor := c.describeLocked(reg)
+    _, explanation.Built = c.services[qualifier]
+    p := c.providers[qualifier]
+    for boundType, bound := range c.bindings {
+        if bound == qualifier {
+            explanation.BoundTypes = append(explanation.BoundTypes, boundType)
+        }
+    }
+    c.mu.RUnlock()
+
+    explanation.Found = true
+    explanation.Type = descriptor.Type
+    explanation.Lifetime = reg.lifetime
+    explanation.Module = reg.module
+    explanation.Profile = reg.profile
+    explanation.RegisteredAt = reg.location
+    switch {
+    case p != nil:
+        explanation.Source = "provider"
+        if explanation.Type == nil {
+            explanation.Type = p.out
+        }
+    case reg.factory:
+        explanation.Source = "factory"
+    case reg.lifetime != Singleton:
+        explanation.Source = reg.lifetime.String()
+    default:
+        explanation.Source = "instance"
+    }
+    sort.Slice(explanation.BoundTypes, func(i, j int) bool {
+        return explanation.BoundTypes[i].String() < explanation.BoundTypes[j].String()
+    })
+    explanation.Dependencies = c.edgesOf(qualifier, c.observedDependencies()[qualifier])
+    return explanation
+}
+
+// maxSuggestions bounds the qualifiers suggested for an unknown one
+const maxSuggestions = 3
+
+// suggest returns the registered qualifiers closest to qualifier: those
+// within a few edits of it, or containing it, nearest first
+func (c *Container) suggest(qualifier string) []string {
+    type candidate struct {
+        qualifier string
+        distance  int
+    }
+    limit := len(qualifier)/3 + 1
+    var candidates []candidate
+    for _, registered := range c.snapshotOrder() {
+        distance := editDistance(strings.ToLower(qualifier), strings.ToLower(registered))
+        if distance <= limit || (len(qualifier) >= 3 && strings.Contains(registered, qualifier)) {
+            candidates = append(candidates, candidate{registered, distance})
+        }
+    }
+    sort.SliceStable(candidates, func(i, j int) bool {
+        return candidates[i].distance < candidates[j].distance
+    })
+
+    var suggestions []string
+    for i := 0; i < len(candidates) && i < maxSuggestions; i++ {
+        suggestions = append(suggestions, candidates[i].qualifier)
+    }
+    return suggestions
+}
+
+// editDistance returns the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+    previous := make([]int, len(b)+1)
+    current := make([]int, len(b)+1)
+    for j := range previous {
+        previous[j] = j
+    }
+    for i := 1; i <= len(a); i++ {
+        current[0] = i
+        for j := 1; j <= len(b); j++ {
+            cost := 1
+            if a[i-1] == b[j-1] {
+                cost = 0
+            }
+            current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+        }
+        previous, current = current, previous
+    }
+    return previous[len(b)]
+}
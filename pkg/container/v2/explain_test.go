@@ -0,0 +1,69 @@
+package container
+
+import (
+    "fmt"
+    "reflect"
+    "runtime"
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+func TestExplain(t *testing.T) {
+    c := NewContainer()
+    _, file, line, _ := runtime.Caller(0)
+    require.NoError(t, c.Register("audit", &testServiceImpl{}))
+    require.NoError(t, c.Provide("repo", func(store *providedStore) *providedRepo {
+        return &providedRepo{store: store}
+    }, DependsOn("audit")))
+    require.NoError(t, c.Provide("store", func() *providedStore { return &providedStore{} }))
+    require.NoError(t, c.Bind((*TestService)(nil), "audit"))
+    c.Rename("auditor", "audit")
+
+    explanation := c.Explain("auditor")
+    assert.True(t, explanation.Found)
+    assert.Equal(t, "audit", explanation.Qualifier)
+    assert.Equal(t, "instance", explanation.Source)
+    assert.True(t, explanation.Built)
+    assert.Equal(t, Singleton, explanation.Lifetime)
+    assert.Equal(t, fmt.Sprintf("%s:%d", file, line+1), explanation.RegisteredAt)
+    assert.Equal(t, []reflect.Type{reflect.TypeOf((*TestService)(nil)).Elem()}, explanation.BoundTypes)
+
+    explanation = c.Explain("repo")
+    assert.Equal(t, "provider", explanation.Source)
+    assert.False(t, explanation.Built)
+    assert.Equal(t, "*container.providedRepo", explanation.Type.String())
+    assert.Equal(t, []GraphEdge{
+        {From: "repo", To: "audit", Kind: EdgeDeclared},
+        {From: "repo", To: "store", Kind: EdgeProvider},
+    }, explanation.Dependencies)
+    assert.Contains(t, explanation.String(), "repo: provider *container.providedRepo, singleton\n  registered at "+file)
+    assert.Contains(t, explanation.String(), "depends on store (provider)")
+}
+
+func TestExplain_SuggestsQualifiers(t *testing.T) {
+    c := NewContainer()
+    for _, qualifier := range []string{"user.repository", "order.repository", "mailer", "cache"} {
+        require.NoError(t, c.Register(qualifier, &testServiceImpl{}))
+    }
+
+    explanation := c.Explain("user.repositry")
+    assert.False(t, explanation.Found)
+    assert.Equal(t, []string{"user.repository", "order.repository"}, explanation.Suggestions)
+    assert.Equal(t, "user.repositry: not registered; did you mean user.repository or order.repository?", explanation.String())
+
+    assert.Equal(t, []string{"mailer"}, c.Explain("Mailer").Suggestions)
+    assert.Empty(t, c.Explain("payments").Suggestions)
+
+    // Failed resolutions suggest the same qualifiers
+    _, err := c.Resolve("mailr")
+    assert.EqualError(t, err, "no service found for qualifier: mailr; did you mean mailer?")
+}
+
+func TestEditDistance(t *testing.T) {
+    assert.Equal(t, 0, editDistance("cache", "cache"))
+    assert.Equal(t, 1, editDistance("mailr", "mailer"))
+    assert.Equal(t, 3, editDistance("kitten", "sitting"))
+    assert.Equal(t, 5, editDistance("", "cache"))
+}
@@ -0,0 +1,123 @@
+package container
+
+import (
+    "context"
+    "fmt"
+    "sync"
+)
+
+// Factory builds a service lazily. It receives the container so it can
+// resolve its own dependencies.
+type Factory func(c *Container) (interface{}, error)
+
+// lazyService is a factory registration that has not been built yet
+type lazyService struct {
+    mu      sync.Mutex // Held while building so the factory runs once
+    factory Factory
+}
+
+// RegisterFactory registers a singleton built by factory on its first
+// Resolve instead of at registration or startup. The instance is cached
+// and returned by later resolves. If factory fails, the error is returned
+// and the next Resolve tries again.
+//
+// The factory may resolve other services. Resolving the service it builds,
+// directly or through other factories, fails with a dependency cycle error,
+// also when those factories are being built by other goroutines.
+func (c *Container) RegisterFactory(qualifier string, factory Factory, opts ...RegisterOption) error {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    return admitted(c.registerFactoryLocked(qualifier, factory, opts))
+}
+
+// registerFactoryLocked registers factory, returning errKeptFirst when a
+// duplicate is ignored so callers can skip their own bookkeeping. Callers
+// must hold c.mu.
+func (c *Container) registerFactoryLocked(qualifier string, factory Factory, opts []RegisterOption) error {
+    c.log.Infow("Registering factory", "qualifier", qualifier)
+
+    if factory == nil {
+        c.log.Errorw("Cannot register nil factory", "qualifier", qualifier)
+        return fmt.Errorf("cannot register %w", &NilServiceError{Qualifier: qualifier, Source: "factory"})
+    }
+    reg := c.newRegistrationLocked(qualifier, opts)
+    reg.factory = true
+    if err := c.admitLocked(reg); err != nil {
+        return err
+    }
+
+    c.lazy[qualifier] = &lazyService{factory: factory}
+    c.recordLocked(reg)
+    return nil
+}
+
+// resolveLazy builds a factory registration once and stores the instance
+// with the other singletons
+func (c *Container) resolveLazy(qualifier string, lazy *lazyService) (interface{}, error) {
+    // Checked before taking lazy.mu, which this goroutine may already hold
+    leave, err := c.enterResolution(qualifier)
+    if err != nil {
+        return nil, err
+    }
+    defer leave()
+
+    release, err := c.acquireBuild(qualifier, &lazy.mu)
+    if err != nil {
+        return nil, err
+    }
+    defer release()
+
+    // Another goroutine may have built it while we waited
+    c.mu.RLock()
+    service, built := c.services[qualifier]
+    c.mu.RUnlock()
+    if built {
+        return service, nil
+    }
+
+    if err := c.waitForQualifier(c.currentContext(), qualifier); err != nil {
+        return nil, err
+    }
+
+    c.log.Debugw("Building lazy service", "qualifier", qualifier)
+    service, err = c.construct(c.currentContext(), qualifier, func() (interface{}, error) {
+        var built interface{}
+        err := c.withServiceLabels(c.currentContext(), "build", qualifier, func(context.Context) error {
+            var buildErr error
+            built, buildErr = lazy.factory(c)
+            return buildErr
+        })
+        return built, err
+    })
+    if err != nil {
+        c.log.Errorw("Factory failed",
+            "qualifier", qualifier,
+            "error", err)
+        return nil, fmt.Errorf("failed to build lazy service %s: %w", qualifier, err)
+    }
+    if service == nil {
+        return nil, fmt.Errorf("factory for %s returned nil", qualifier)
+    }
+    if err := c.checkTypedNil(qualifier, service, "factory result"); err != nil {
+        return nil, err
+    }
+    if service, err = c.decorateWeak(qualifier, service); err != nil {
+        return nil, err
+    }
+
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    c.services[qualifier] = service
+    delete(c.lazy, qualifier)
+    c.log.Infow("Lazy service built", "qualifier", qualifier)
+    return service, nil
+}
+
+// isUnbuiltLazy reports whether qualifier is a factory registration that
+// has not been built yet
+func (c *Container) isUnbuiltLazy(qualifier string) bool {
+    c.mu.RLock()
+    defer c.mu.RUnlock()
+    _, ok := c.lazy[qualifier]
+    return ok
+}
@@ -0,0 +1,115 @@
+package container
+
+import (
+    "context"
+    "errors"
+    "sync"
+    "sync/atomic"
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+type warmedRepo struct {
+    warmups int
+}
+
+func (w *warmedRepo) Warmup(ctx context.Context) error {
+    w.warmups++
+    return nil
+}
+
+func TestRegisterFactory_LazyAndCached(t *testing.T) {
+    c := NewContainer()
+    require.NoError(t, c.Register("dsn", "postgres://db"))
+
+    var builds int32
+    require.NoError(t, c.RegisterFactory("repo", func(c *Container) (interface{}, error) {
+        atomic.AddInt32(&builds, 1)
+        if _, err := c.Resolve("dsn"); err != nil {
+            return nil, err
+        }
+        return &warmedRepo{}, nil
+    }))
+
+    // Start does not build or warm factory registrations
+    require.NoError(t, c.Start(context.Background()))
+    assert.Equal(t, int32(0), atomic.LoadInt32(&builds))
+
+    var wg sync.WaitGroup
+    results := make([]interface{}, 8)
+    for i := range results {
+        wg.Add(1)
+        go func(i int) {
+            defer wg.Done()
+            service, err := c.Resolve("repo")
+            assert.NoError(t, err)
+            results[i] = service
+        }(i)
+    }
+    wg.Wait()
+
+    assert.Equal(t, int32(1), atomic.LoadInt32(&builds))
+    for _, service := range results {
+        assert.Same(t, results[0], service)
+    }
+    assert.Equal(t, 0, results[0].(*warmedRepo).warmups)
+}
+
+func TestRegisterFactory_RetriesAfterFailure(t *testing.T) {
+    c := NewContainer()
+    attempts := 0
+    require.NoError(t, c.RegisterFactory("flaky", func(c *Container) (interface{}, error) {
+        attempts++
+        if attempts == 1 {
+            return nil, errors.New("connection refused")
+        }
+        return "ready", nil
+    }))
+
+    _, err := c.Resolve("flaky")
+    assert.ErrorContains(t, err, "failed to build lazy service flaky: connection refused")
+
+    service, err := c.Resolve("flaky")
+    require.NoError(t, err)
+    assert.Equal(t, "ready", service)
+}
+
+func TestRegisterFactory_Cycle(t *testing.T) {
+    c := NewContainer()
+    require.NoError(t, c.RegisterFactory("a", func(c *Container) (interface{}, error) { return c.Resolve("b") }))
+    require.NoError(t, c.RegisterFactory("b", func(c *Container) (interface{}, error) { return c.Resolve("a") }))
+
+    withinDeadline(t, func() {
+        _, err := c.Resolve("a")
+        assert.ErrorContains(t, err, "a -> b -> a")
+    })
+}
+
+func TestRegisterFactory_Validation(t *testing.T) {
+    c := NewContainer()
+    assert.ErrorContains(t, c.RegisterFactory("nil", nil), "nil factory")
+
+    require.NoError(t, c.Register("taken", "value"))
+    err := c.RegisterFactory("taken", func(c *Container) (interface{}, error) { return "other", nil })
+    assert.ErrorContains(t, err, "already registered")
+
+    require.NoError(t, c.RegisterFactory("empty", func(c *Container) (interface{}, error) { return nil, nil }))
+    _, err = c.Resolve("empty")
+    assert.ErrorContains(t, err, "returned nil")
+}
+
+func TestRegisterFactory_Decorated(t *testing.T) {
+    c := NewContainer()
+    require.NoError(t, c.DecorateGroup("handlers", func(qualifier string, service interface{}) (interface{}, error) {
+        return "decorated " + service.(string), nil
+    }))
+    require.NoError(t, c.RegisterFactory("users", func(c *Container) (interface{}, error) {
+        return "users", nil
+    }, InGroup("handlers")))
+
+    service, err := c.Resolve("users")
+    require.NoError(t, err)
+    assert.Equal(t, "decorated users", service)
+}
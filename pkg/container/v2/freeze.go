@@ -0,0 +1,48 @@
+package container
+
+import (
+    "fmt"
+)
+
+// Freeze closes the container for registration. Register and the other
+// registration functions fail afterwards, while Resolve, Swap and the
+// lifecycle keep working. Freezing publishes the registrations: goroutines
+// that observe the frozen container see every service registered before it.
+func (c *Container) Freeze() {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+
+    if c.frozen {
+        return
+    }
+    c.frozen = true
+    if debugEnabled {
+        freeze := currentAccess()
+        c.debug.freeze = &freeze
+    }
+    c.recordMutation(MutationFreeze, "", fmt.Sprintf("%d services", len(c.regs)))
+    c.log.Infow("Container frozen", "services", len(c.regs))
+}
+
+// Frozen reports whether Freeze has been called
+func (c *Container) Frozen() bool {
+    c.mu.RLock()
+    defer c.mu.RUnlock()
+    return c.frozen
+}
+
+// checkFrozenLocked rejects registrations after Freeze. didebug builds name
+// the offending goroutine and call site as well as those of Freeze.
+// Callers must hold c.mu.
+func (c *Container) checkFrozenLocked(reg *registration) error {
+    if !c.frozen {
+        return nil
+    }
+
+    c.log.Errorw("Registration after Freeze", "qualifier", reg.qualifier)
+    if debugEnabled && c.debug.freeze != nil {
+        return fmt.Errorf("cannot register %q from %v: container frozen by %v",
+            reg.qualifier, currentAccess(), *c.debug.freeze)
+    }
+    return fmt.Errorf("cannot register %q: container is frozen", reg.qualifier)
+}
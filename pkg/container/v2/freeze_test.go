@@ -0,0 +1,49 @@
+package container
+
+import (
+    "context"
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+func TestContainer_Freeze(t *testing.T) {
+    container := NewContainer()
+    require.NoError(t, container.Register("early", &testServiceImpl{name: "early"}))
+    assert.False(t, container.Frozen())
+
+    container.Freeze()
+    container.Freeze() // Idempotent
+    assert.True(t, container.Frozen())
+
+    // Registration fails from any goroutine, resolution keeps working
+    errs := make(chan error, 1)
+    go func() {
+        errs <- container.Register("late", &testServiceImpl{name: "late"})
+    }()
+    err := <-errs
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), `"late"`)
+    if debugEnabled {
+        assert.Contains(t, err.Error(), "frozen by goroutine")
+        assert.Contains(t, err.Error(), "freeze_test.go")
+    }
+
+    err = container.RegisterWeak("lateWeak", func() (interface{}, error) { return 1, nil })
+    assert.Error(t, err)
+    _, err = RegisterChan[int](container, "events", 1)
+    assert.Error(t, err)
+
+    _, err = container.Resolve("early")
+    assert.NoError(t, err)
+    _, err = container.Swap("early", &testServiceImpl{name: "swapped"})
+    assert.NoError(t, err)
+    assert.NoError(t, container.Start(context.Background()))
+}
+
+func TestCurrentAccess(t *testing.T) {
+    current := currentAccess()
+    assert.Equal(t, goroutineID(), current.goroutine)
+    assert.Contains(t, current.site, "freeze_test.go")
+}
@@ -0,0 +1,185 @@
+package container
+
+import (
+    "context"
+    "fmt"
+    "sync"
+    "time"
+)
+
+// Future is the eventual result of ResolveAsync
+type Future struct {
+    qualifier string
+    done      chan struct{} // Closed once service and err are set
+    service   interface{}
+    err       error
+}
+
+// Done returns a channel that is closed when the result is available
+func (f *Future) Done() <-chan struct{} {
+    return f.done
+}
+
+// Get waits for the result. If ctx is cancelled first, Get returns the
+// context error; the construction keeps running for other waiters. To
+// cancel the construction itself, start it with ResolveAsyncContext.
+func (f *Future) Get(ctx context.Context) (interface{}, error) {
+    select {
+    case <-f.done:
+        return f.service, f.err
+    case <-ctx.Done():
+        return nil, fmt.Errorf("waiting for %s: %w", f.qualifier, ctx.Err())
+    }
+}
+
+// GetTimeout waits at most timeout for the result
+func (f *Future) GetTimeout(timeout time.Duration) (interface{}, error) {
+    ctx, cancel := context.WithTimeout(context.Background(), timeout)
+    defer cancel()
+    return f.Get(ctx)
+}
+
+// complete publishes the result and wakes every waiter
+func (f *Future) complete(service interface{}, err error) {
+    f.service, f.err = service, err
+    close(f.done)
+}
+
+// ResolveAsync resolves qualifier in the background, for services whose
+// provider is slow. Callers resolving the same qualifier while a resolution
+// is in flight share its Future, so a provider is never run twice at once.
+// The number of concurrent background resolutions is bounded by
+// SetAsyncLimit; further ones queue until a slot frees up.
+func (c *Container) ResolveAsync(qualifier string) *Future {
+    // Frames above callerLocation: site closure, renamed, ResolveAsync, caller
+    qualifier = c.renamed(qualifier, func() string { return callerLocation(3) })
+    return c.resolveAsync(context.Background(), qualifier)
+}
+
+// ResolveAsyncContext is ResolveAsync building under ctx, like
+// ResolveContext: cancelling ctx stops waiting for a slot and ends retry
+// backoffs and WaitFor probes of the construction. Callers joining an
+// in-flight resolution share the context of the one that started it.
+func (c *Container) ResolveAsyncContext(ctx context.Context, qualifier string) *Future {
+    // Frames above callerLocation: site closure, renamed, ResolveAsyncContext, caller
+    qualifier = c.renamed(qualifier, func() string { return callerLocation(3) })
+    return c.resolveAsync(ctx, qualifier)
+}
+
+// resolveAsync starts or joins the background resolution of a final
+// qualifier
+func (c *Container) resolveAsync(ctx context.Context, qualifier string) *Future {
+    c.asyncMu.Lock()
+    if future, ok := c.pending[qualifier]; ok {
+        c.asyncMu.Unlock()
+        c.log.Debugw("Joining in-flight async resolution", "qualifier", qualifier)
+        return future
+    }
+    future := &Future{qualifier: qualifier, done: make(chan struct{})}
+    c.pending[qualifier] = future
+    c.asyncMu.Unlock()
+
+    c.log.Debugw("Starting async resolution", "qualifier", qualifier)
+    caller := c.auditCaller()
+    go func() {
+        leave := c.enterContext(ctx)
+        defer leave()
+
+        var service interface{}
+        err := c.asyncLimit.acquire(ctx)
+        if err == nil {
+            finish := c.traceEnter(qualifier)
+            service, err = c.resolve(qualifier)
+            finish(err)
+            c.asyncLimit.release()
+        } else {
+            err = fmt.Errorf("waiting for an async slot for %s: %w", qualifier, err)
+        }
+        c.auditAs(caller, AuditResolve, qualifier, "", err)
+
+        // Later calls start a fresh resolution, e.g. after a weak eviction
+        c.asyncMu.Lock()
+        delete(c.pending, qualifier)
+        c.asyncMu.Unlock()
+        future.complete(service, err)
+    }()
+    return future
+}
+
+// SetAsyncLimit bounds how many ResolveAsync resolutions run at once.
+// A limit of zero or less removes the bound. Resolutions already running
+// keep their slot; queued ones start as the new limit allows.
+func (c *Container) SetAsyncLimit(limit int) {
+    c.log.Infow("Setting async resolution limit", "limit", limit)
+    c.asyncLimit.setLimit(limit)
+}
+
+// asyncLimiter bounds the number of running async resolutions. Its limit
+// can change while resolutions hold or wait for slots.
+type asyncLimiter struct {
+    mu      sync.Mutex
+    limit   int             // Zero or less for no bound
+    running int             // Slots held
+    waiters []chan struct{} // Closed when handed a slot, first come first served
+}
+
+// acquire takes a slot, waiting until one is free or ctx is done
+func (l *asyncLimiter) acquire(ctx context.Context) error {
+    l.mu.Lock()
+    if len(l.waiters) == 0 && l.free() {
+        l.running++
+        l.mu.Unlock()
+        return nil
+    }
+    granted := make(chan struct{})
+    l.waiters = append(l.waiters, granted)
+    l.mu.Unlock()
+
+    select {
+    case <-granted:
+        return nil
+    case <-ctx.Done():
+        l.mu.Lock()
+        defer l.mu.Unlock()
+        for i, waiter := range l.waiters {
+            if waiter == granted {
+                l.waiters = append(l.waiters[:i], l.waiters[i+1:]...)
+                return ctx.Err()
+            }
+        }
+        // Handed a slot while giving up; pass it on
+        l.running--
+        l.grantLocked()
+        return ctx.Err()
+    }
+}
+
+// release frees a slot taken by acquire
+func (l *asyncLimiter) release() {
+    l.mu.Lock()
+    defer l.mu.Unlock()
+    l.running--
+    l.grantLocked()
+}
+
+// setLimit changes the limit, starting waiters the new limit allows
+func (l *asyncLimiter) setLimit(limit int) {
+    l.mu.Lock()
+    defer l.mu.Unlock()
+    l.limit = limit
+    l.grantLocked()
+}
+
+// free reports whether a slot is free. Callers must hold l.mu.
+func (l *asyncLimiter) free() bool {
+    return l.limit <= 0 || l.running < l.limit
+}
+
+// grantLocked hands free slots to waiters in order. Callers must hold l.mu.
+func (l *asyncLimiter) grantLocked() {
+    for len(l.waiters) > 0 && l.free() {
+        l.running++
+        close(l.waiters[0])
+        l.waiters = l.waiters[1:]
+    }
+}
@@ -0,0 +1,158 @@
+package container
+
+import (
+    "context"
+    "errors"
+    "sync"
+    "sync/atomic"
+    "testing"
+    "time"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+func TestContainer_ResolveAsyncSharesConstruction(t *testing.T) {
+    container := NewContainer()
+    release := make(chan struct{})
+    var builds int32
+    require.NoError(t, container.RegisterWeak("report", func() (interface{}, error) {
+        atomic.AddInt32(&builds, 1)
+        <-release
+        return &testServiceImpl{name: "report"}, nil
+    }))
+
+    first := container.ResolveAsync("report")
+    second := container.ResolveAsync("report")
+    assert.Same(t, first, second)
+
+    close(release)
+    service, err := first.Get(context.Background())
+    require.NoError(t, err)
+    assert.Equal(t, &testServiceImpl{name: "report"}, service)
+    assert.Equal(t, int32(1), atomic.LoadInt32(&builds))
+}
+
+func TestContainer_ResolveAsyncTimeout(t *testing.T) {
+    container := NewContainer()
+    release := make(chan struct{})
+    defer close(release)
+    require.NoError(t, container.RegisterWeak("slow", func() (interface{}, error) {
+        <-release
+        return 1, nil
+    }))
+
+    _, err := container.ResolveAsync("slow").GetTimeout(10 * time.Millisecond)
+    assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+    ctx, cancel := context.WithCancel(context.Background())
+    cancel()
+    _, err = container.ResolveAsync("slow").Get(ctx)
+    assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestContainer_ResolveAsyncErrors(t *testing.T) {
+    container := NewContainer()
+    require.NoError(t, container.RegisterWeak("broken", func() (interface{}, error) {
+        return nil, errors.New("boom")
+    }))
+
+    _, err := container.ResolveAsync("broken").Get(context.Background())
+    assert.ErrorContains(t, err, "boom")
+    _, err = container.ResolveAsync("missing").Get(context.Background())
+    assert.Error(t, err)
+}
+
+func TestContainer_SetAsyncLimit(t *testing.T) {
+    container := NewContainer()
+    container.SetAsyncLimit(1)
+
+    var running, peak int32
+    var wg sync.WaitGroup
+    for _, name := range []string{"a", "b", "c"} {
+        require.NoError(t, container.RegisterWeak(name, func() (interface{}, error) {
+            now := atomic.AddInt32(&running, 1)
+            for {
+                old := atomic.LoadInt32(&peak)
+                if now <= old || atomic.CompareAndSwapInt32(&peak, old, now) {
+                    break
+                }
+            }
+            time.Sleep(5 * time.Millisecond)
+            atomic.AddInt32(&running, -1)
+            return 1, nil
+        }))
+    }
+
+    for _, name := range []string{"a", "b", "c"} {
+        future := container.ResolveAsync(name)
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+            _, err := future.Get(context.Background())
+            assert.NoError(t, err)
+        }()
+    }
+    wg.Wait()
+    assert.Equal(t, int32(1), atomic.LoadInt32(&peak))
+}
+
+func TestContainer_ResolveAsyncContextCancelsConstruction(t *testing.T) {
+    container := NewContainer()
+    ctor, calls := flakyStore(5)
+    require.NoError(t, container.Provide("store", ctor, RetryConstruction(RetryPolicy{
+        Attempts:       5,
+        InitialBackoff: time.Hour,
+    })))
+
+    ctx, cancel := context.WithCancel(context.Background())
+    future := container.ResolveAsyncContext(ctx, "store")
+    time.AfterFunc(10*time.Millisecond, cancel)
+
+    // The construction itself ends, not only the wait
+    _, err := future.GetTimeout(time.Second)
+    assert.ErrorIs(t, err, context.Canceled)
+    assert.ErrorIs(t, err, errColdStart)
+    assert.Equal(t, 1, *calls)
+}
+
+func TestContainer_SetAsyncLimitWhileQueued(t *testing.T) {
+    container := NewContainer()
+    container.SetAsyncLimit(1)
+
+    started, release := make(chan struct{}, 3), make(chan struct{})
+    for _, name := range []string{"a", "b", "c"} {
+        require.NoError(t, container.RegisterWeak(name, func() (interface{}, error) {
+            started <- struct{}{}
+            <-release
+            return 1, nil
+        }))
+    }
+    require.NoError(t, container.Register("quick", 1))
+
+    a := container.ResolveAsync("a")
+    <-started
+    b := container.ResolveAsync("b")
+    ctx, cancel := context.WithCancel(context.Background())
+    quick := container.ResolveAsyncContext(ctx, "quick")
+    time.Sleep(10 * time.Millisecond)
+
+    // A queued resolution gives up its place when its context ends
+    cancel()
+    _, err := quick.GetTimeout(time.Second)
+    assert.ErrorContains(t, err, "waiting for an async slot for quick")
+
+    // Raising the limit starts the queued resolutions
+    container.SetAsyncLimit(0)
+    c := container.ResolveAsync("c")
+    close(release)
+    for _, future := range []*Future{a, b, c} {
+        _, err := future.GetTimeout(time.Second)
+        assert.NoError(t, err)
+    }
+
+    container.asyncLimit.mu.Lock()
+    defer container.asyncLimit.mu.Unlock()
+    assert.Zero(t, container.asyncLimit.running)
+    assert.Empty(t, container.asyncLimit.waiters)
+}
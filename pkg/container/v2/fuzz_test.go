@@ -0,0 +1,65 @@
+package container
+
+import (
+    "reflect"
+    "sync/atomic"
+    "testing"
+
+    "di-example/internal/structgen"
+    "github.com/stretchr/testify/require"
+)
+
+// fuzzOptions is an options type fuzzed structs may receive
+type fuzzOptions struct {
+    Name    string `default:"fuzz"`
+    Level   **int  `default:"3"`
+    Ratio   float64 `default:"NaN"`
+    Nested  struct {
+        Enabled bool `default:"true"`
+    }
+}
+
+// FuzzInjectStruct injects randomly shaped structs with random di tags; the
+// seed corpus in testdata/fuzz runs with the regular tests. Injection may
+// fail, but must not panic.
+func FuzzInjectStruct(f *testing.F) {
+    f.Add([]byte{})
+    f.Add([]byte{2, 3, 0, 1, 1, 3, 0, 1, 2})
+    f.Add([]byte("\x04\x00\x00\x01\x01\x00\x03\x00\x01\x05\x03\x00\x01\x06\x07\x00\x01\x07"))
+    f.Add([]byte("\x03\x01\x03\x01\x01\x02\x01\x08\x01\x04\x02\x0dmap:handlers\x00\x0e\x01\x09"))
+    f.Add([]byte("\x02\x00\x00\x01\x02\x14prefix=x.,optional\x01\x01\x05\x02\x0aa,required"))
+
+    config := structgen.Config{
+        Types: []reflect.Type{
+            reflect.TypeOf(fuzzOptions{}),
+            reflect.TypeOf(&fuzzOptions{}),
+            reflect.TypeOf(&Hot[string]{}),
+            reflect.TypeOf(&atomic.Pointer[string]{}),
+            reflect.TypeOf(map[string]interface{}{}),
+            reflect.TypeOf(map[int]int{}),
+            reflect.TypeOf((*TestService)(nil)).Elem(),
+        },
+        Embedded: []reflect.Type{injectMarkerType, reflect.TypeOf(structgen.Node{})},
+        TagKeys:  []string{"di", "di", "default"},
+        TagValues: []string{
+            "a", "b", "node", "handlers", "missing", OptionsTag, MapTagPrefix + "handlers",
+            MapTagPrefix, "a,optional", "b,required,optional", "a,ifPresent=b", "prefix=,optional", "",
+        },
+    }
+
+    f.Fuzz(func(t *testing.T, data []byte) {
+        c := NewContainer()
+        require.NoError(t, c.Register("a", "service a"))
+        require.NoError(t, c.Register("b", 42))
+        require.NoError(t, c.Register("node", &structgen.Node{Name: "node"}))
+        require.NoError(t, c.Register("handler1", &testServiceImpl{}, InGroup("handlers")))
+        require.NoError(t, c.Register("handler2", "handler", InGroup("handlers")))
+
+        target := structgen.Generate(data, config)
+        if result, err := c.InjectStructWithResult(target); err == nil {
+            _ = result.String()
+        }
+        _, _ = c.ReinjectStruct(target)
+        c.ForgetStruct(target)
+    })
+}
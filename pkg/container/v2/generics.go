@@ -0,0 +1,97 @@
+package container
+
+import (
+    "fmt"
+    "reflect"
+)
+
+// Implementation pairs a service with the qualifier it is registered under
+type Implementation[T any] struct {
+    Qualifier string
+    Service   T
+}
+
+// ResolveImplementing returns every registered service whose concrete type
+// implements the interface T, in registration order. It supports discovery
+// style code ("find all MigrationProviders") without grouping services in
+// advance. Services are matched by the type known without building them:
+// instances, providers and the results declared by constructors. Factories
+// not built yet and scoped services are skipped; transient matches are
+// built for the result.
+func ResolveImplementing[T any](c *Container) ([]Implementation[T], error) {
+    ifaceType := reflect.TypeOf((*T)(nil)).Elem()
+    if ifaceType.Kind() != reflect.Interface {
+        return nil, fmt.Errorf("ResolveImplementing requires an interface type, got %v", ifaceType)
+    }
+
+    var found []Implementation[T]
+    for _, qualifier := range c.Qualifiers() {
+        if c.lifetimeOf(qualifier) == Scoped {
+            continue
+        }
+        serviceType, _ := c.staticType(qualifier)
+        if serviceType == nil || !serviceType.Implements(ifaceType) {
+            continue
+        }
+        service, err := c.Resolve(qualifier)
+        if err != nil {
+            return nil, fmt.Errorf("failed to resolve %s while searching for %v: %w", qualifier, ifaceType, err)
+        }
+        if typed, ok := service.(T); ok {
+            found = append(found, Implementation[T]{Qualifier: qualifier, Service: typed})
+        }
+    }
+
+    c.log.Debugw("Resolved implementations",
+        "interface", ifaceType,
+        "count", len(found))
+    return found, nil
+}
+
+// ResolveAs resolves qualifier and asserts the service to T, so callers do
+// not repeat the type assertion. A service of another type is reported
+// with both types.
+func ResolveAs[T any](c *Container, qualifier string) (T, error) {
+    return resolveAs[T](c, qualifier)
+}
+
+// MustResolve is ResolveAs panicking on error, for wiring code where a
+// missing or mistyped service is a programming error
+func MustResolve[T any](c *Container, qualifier string) T {
+    service, err := resolveAs[T](c, qualifier)
+    if err != nil {
+        panic(err)
+    }
+    return service
+}
+
+// resolveAs is shared by ResolveAs and MustResolve so rename warnings
+// report the caller of either
+func resolveAs[T any](c *Container, qualifier string) (T, error) {
+    var zero T
+    // Frames above callerLocation: site closure, renamed, resolveAs, ResolveAs or MustResolve, caller
+    qualifier = c.renamed(qualifier, func() string { return callerLocation(4) })
+
+    service, err := c.resolveTraced(qualifier)
+    c.audit(AuditResolve, qualifier, "", err)
+    if err != nil {
+        c.emit(Event{Kind: EventResolveFailed, Qualifier: qualifier, Err: err})
+        return zero, err
+    }
+
+    typed, ok := service.(T)
+    if !ok {
+        want := reflect.TypeOf((*T)(nil)).Elem()
+        c.log.Errorw("Resolved service has unexpected type",
+            "qualifier", qualifier,
+            "type", reflect.TypeOf(service),
+            "expected", want)
+        return zero, &TypeMismatchError{
+            Qualifier: qualifier,
+            Type:      reflect.TypeOf(service),
+            Want:      want,
+            Detail:    mismatchDetail(reflect.TypeOf(service), want),
+        }
+    }
+    return typed, nil
+}
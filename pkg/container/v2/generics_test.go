@@ -0,0 +1,84 @@
+package container
+
+import (
+    "errors"
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+type migrationProvider interface {
+    Migrations() []string
+}
+
+type usersMigrations struct{}
+
+func (usersMigrations) Migrations() []string { return []string{"create users"} }
+
+func TestResolveImplementing(t *testing.T) {
+    container := NewContainer()
+    require.NoError(t, container.Register("users", usersMigrations{}))
+    require.NoError(t, container.Register("plain", &testServiceImpl{name: "plain"}))
+    require.NoError(t, container.Register("orders", func() usersMigrations {
+        return usersMigrations{}
+    }, WithLifetime(Weak)))
+    require.NoError(t, container.RegisterScoped("request", func(*Scope) (interface{}, error) {
+        return usersMigrations{}, nil
+    }))
+    built := 0
+    require.NoError(t, container.Register("report", func() *testServiceImpl {
+        built++
+        return &testServiceImpl{}
+    }, WithLifetime(Transient)))
+    require.NoError(t, container.RegisterFactory("broken", func(*Container) (interface{}, error) {
+        return nil, errors.New("db down")
+    }))
+
+    found, err := ResolveImplementing[migrationProvider](container)
+    require.NoError(t, err)
+    require.Len(t, found, 2)
+    assert.Equal(t, "users", found[0].Qualifier)
+    assert.Equal(t, "orders", found[1].Qualifier)
+    assert.Equal(t, []string{"create users"}, found[0].Service.Migrations())
+    assert.Zero(t, built) // Matched by declared type, not built
+
+    none, err := ResolveImplementing[interface{ Unknown() }](container)
+    require.NoError(t, err)
+    assert.Empty(t, none)
+
+    _, err = ResolveImplementing[usersMigrations](container)
+    assert.ErrorContains(t, err, "requires an interface type")
+}
+
+func TestResolveAs(t *testing.T) {
+    c := NewContainer()
+    require.NoError(t, c.Register("users", usersMigrations{}))
+    require.NoError(t, c.Register("port", 8080))
+
+    provider, err := ResolveAs[migrationProvider](c, "users")
+    require.NoError(t, err)
+    assert.Equal(t, []string{"create users"}, provider.Migrations())
+
+    port, err := ResolveAs[int](c, "port")
+    require.NoError(t, err)
+    assert.Equal(t, 8080, port)
+
+    _, err = ResolveAs[string](c, "port")
+    assert.EqualError(t, err, "service port has type int, which is not assignable to string")
+    _, err = ResolveAs[migrationProvider](c, "port")
+    assert.ErrorContains(t, err, "type int, which is not assignable to container.migrationProvider")
+
+    _, err = ResolveAs[int](c, "missing")
+    assert.Error(t, err)
+}
+
+func TestMustResolve(t *testing.T) {
+    c := NewContainer()
+    require.NoError(t, c.Register("port", 8080))
+
+    assert.Equal(t, 8080, MustResolve[int](c, "port"))
+    assert.PanicsWithError(t, "service port has type int, which is not assignable to string", func() {
+        MustResolve[string](c, "port")
+    })
+}
@@ -0,0 +1,24 @@
+package container
+
+import (
+    "bytes"
+    "runtime"
+    "strconv"
+)
+
+// goroutineID returns the ID of the calling goroutine, parsed from the
+// header of its stack trace ("goroutine 42 [running]:"). It is not only
+// diagnostic: cycle detection, module attribution of hooks and the
+// attribution of workers rely on it. Parsing runtime.Stack is fragile, as
+// the header format is not a stable API, so its callers must keep working
+// when it returns 0.
+func goroutineID() uint64 {
+    var buf [64]byte
+    header := buf[:runtime.Stack(buf[:], false)]
+    header = bytes.TrimPrefix(header, []byte("goroutine "))
+    if end := bytes.IndexByte(header, ' '); end >= 0 {
+        header = header[:end]
+    }
+    id, _ := strconv.ParseUint(string(header), 10, 64)
+    return id
+}
@@ -0,0 +1,204 @@
+package container
+
+import (
+    "fmt"
+    "reflect"
+    "sort"
+    "strings"
+)
+
+// Kinds of GraphEdge
+const (
+    EdgeDeclared = "declared" // Declared with DependsOn
+    EdgeProvider = "provider" // A parameter of the provider
+    EdgeBuilt    = "built"    // Resolved while the service was built
+)
+
+// Graph is the dependency graph of a container, see Container.Graph
+type Graph struct {
+    Nodes []GraphNode // Services in registration order
+    Edges []GraphEdge // Dependencies, from dependent to dependency
+}
+
+// GraphNode is a service of the Graph
+type GraphNode struct {
+    Qualifier string
+    Type      reflect.Type // Known type, nil for services not built yet
+    Lifetime  Lifetime
+    Module    string
+    Provider  string   // Signature of the constructor registered with Provide
+    Consumers []string // Struct fields the service was injected into, such as "app.Server.DB"
+}
+
+// GraphEdge is a dependency between two services of the Graph
+type GraphEdge struct {
+    From string // Dependent qualifier
+    To   string // Dependency qualifier
+    Kind string // EdgeDeclared, EdgeProvider or EdgeBuilt
+}
+
+// Graph returns the dependency graph of the container without building
+// anything: every registration with its type, lifetime and provider, the
+// dependencies declared with DependsOn, taken by providers or resolved
+// while building a service, and the struct fields InjectStruct injected
+// every service into. Render it with DOT.
+func (c *Container) Graph() *Graph {
+    graph := &Graph{}
+    observed := c.observedDependencies()
+    for _, qualifier := range c.snapshotOrder() {
+        c.mu.RLock()
+        reg, ok := c.regs[qualifier]
+        if !ok {
+            c.mu.RUnlock()
+            continue
+        }
+        descriptor := c.describeLocked(reg)
+        p := c.providers[qualifier]
+        consumers := sortedKeys(c.consumerFields[qualifier])
+        c.mu.RUnlock()
+
+        node := GraphNode{
+            Qualifier: qualifier,
+            Type:      descriptor.Type,
+            Lifetime:  descriptor.Lifetime,
+            Module:    descriptor.Module,
+            Consumers: consumers,
+        }
+        if p != nil {
+            if node.Type == nil {
+                node.Type = p.out
+            }
+            node.Provider = p.ctor.Type().String()
+        }
+        graph.Nodes = append(graph.Nodes, node)
+        graph.Edges = append(graph.Edges, c.edgesOf(qualifier, observed[qualifier])...)
+    }
+    return graph
+}
+
+// edgesOf returns the dependencies of qualifier, given those observed while
+// building it, as edges without duplicates
+func (c *Container) edgesOf(qualifier string, observed []string) []GraphEdge {
+    c.mu.RLock()
+    reg, ok := c.regs[qualifier]
+    if !ok {
+        c.mu.RUnlock()
+        return nil
+    }
+    declared := append([]string(nil), reg.dependsOn...)
+    p := c.providers[qualifier]
+    if p != nil {
+        declared = p.declared // The rest of dependsOn is wired from the provider
+    }
+    c.mu.RUnlock()
+
+    var edges []GraphEdge
+    seen := make(map[string]bool)
+    add := func(dependency, kind string) {
+        if !seen[dependency] {
+            seen[dependency] = true
+            edges = append(edges, GraphEdge{From: qualifier, To: dependency, Kind: kind})
+        }
+    }
+    for _, dependency := range declared {
+        add(dependency, EdgeDeclared)
+    }
+    if p != nil {
+        for _, dependency := range c.providerEdges(p) {
+            add(dependency, EdgeProvider)
+        }
+    }
+    for _, dependency := range observed {
+        add(dependency, EdgeBuilt)
+    }
+    return edges
+}
+
+// providerEdges returns the qualifiers the parameters of a provider match,
+// matching by type only among the types known without building anything
+func (c *Container) providerEdges(p *provider) []string {
+    var dependencies []string
+    ctorType := p.ctor.Type()
+    for i := 0; i < ctorType.NumIn(); i++ {
+        paramType := ctorType.In(i)
+        switch {
+        case paramType == containerPtrType:
+        case isTaggedStruct(paramType):
+            dependencies = append(dependencies, c.taggedQualifiers(paramType)...)
+        default:
+            if qualifier, _, err := c.staticMatch(paramType); err == nil && qualifier != "" {
+                dependencies = append(dependencies, qualifier)
+            }
+        }
+    }
+    return dependencies
+}
+
+// DOT renders the graph in the Graphviz DOT language, for example with
+// "dot -Tsvg". Services are labelled with their qualifier, type and
+// lifetime, which are also node attributes; struct types are dashed boxes
+// pointing at the services their fields consume.
+func (g *Graph) DOT() string {
+    var b strings.Builder
+    b.WriteString("digraph container {\n")
+    b.WriteString("    rankdir=LR;\n")
+    b.WriteString("    node [shape=box, style=rounded];\n")
+
+    consumers := make(map[string][][2]string) // Struct type -> field and qualifier
+    for _, node := range g.Nodes {
+        typeName := "<unbuilt>"
+        if node.Type != nil {
+            typeName = node.Type.String()
+        }
+        label := dotEscape(node.Qualifier) + `\n` + dotEscape(typeName) + `\n` + node.Lifetime.String()
+        fmt.Fprintf(&b, "    %s [label=\"%s\", qualifier=%s, type=%s, lifetime=%s",
+            dotQuote(node.Qualifier), label, dotQuote(node.Qualifier), dotQuote(typeName), dotQuote(node.Lifetime.String()))
+        if node.Module != "" {
+            fmt.Fprintf(&b, ", module=%s", dotQuote(node.Module))
+        }
+        if node.Provider != "" {
+            fmt.Fprintf(&b, ", provider=%s", dotQuote(node.Provider))
+        }
+        b.WriteString("];\n")
+
+        for _, consumer := range node.Consumers {
+            dot := strings.LastIndexByte(consumer, '.')
+            structType := consumer[:dot]
+            consumers[structType] = append(consumers[structType], [2]string{consumer[dot+1:], node.Qualifier})
+        }
+    }
+
+    structTypes := make([]string, 0, len(consumers))
+    for structType := range consumers {
+        structTypes = append(structTypes, structType)
+    }
+    sort.Strings(structTypes)
+    for _, structType := range structTypes {
+        fmt.Fprintf(&b, "    %s [shape=box, style=dashed];\n", dotQuote(structType))
+    }
+
+    for _, edge := range g.Edges {
+        style := "solid"
+        if edge.Kind == EdgeBuilt {
+            style = "dashed"
+        }
+        fmt.Fprintf(&b, "    %s -> %s [kind=%s, style=%s];\n", dotQuote(edge.From), dotQuote(edge.To), dotQuote(edge.Kind), style)
+    }
+    for _, structType := range structTypes {
+        for _, consumer := range consumers[structType] {
+            fmt.Fprintf(&b, "    %s -> %s [label=%s, style=dotted];\n", dotQuote(structType), dotQuote(consumer[1]), dotQuote(consumer[0]))
+        }
+    }
+    b.WriteString("}\n")
+    return b.String()
+}
+
+// dotQuote quotes s as a DOT string
+func dotQuote(s string) string {
+    return `"` + dotEscape(s) + `"`
+}
+
+// dotEscape escapes s for a quoted DOT string
+func dotEscape(s string) string {
+    return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s)
+}
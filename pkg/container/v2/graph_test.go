@@ -0,0 +1,78 @@
+package container
+
+import (
+    "net/http"
+    "net/http/httptest"
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+type graphHandler struct {
+    Repo  *providedRepo `di:"repo"`
+    Cache TestService   `di:"cache"`
+}
+
+func newGraphContainer(t *testing.T) *Container {
+    c := NewContainer()
+    require.NoError(t, c.Provide("store", func() *providedStore { return &providedStore{} }))
+    require.NoError(t, c.Provide("repo", func(store *providedStore) *providedRepo {
+        return &providedRepo{store: store}
+    }, DependsOn("audit")))
+    require.NoError(t, c.Register("audit", &testServiceImpl{}))
+    require.NoError(t, c.RegisterFactory("cache", func(c *Container) (interface{}, error) {
+        if _, err := c.Resolve("audit"); err != nil {
+            return nil, err
+        }
+        return &testServiceImpl{}, nil
+    }, InModule("caching")))
+    return c
+}
+
+func TestGraph(t *testing.T) {
+    c := newGraphContainer(t)
+
+    // Before anything is built
+    graph := c.Graph()
+    require.Len(t, graph.Nodes, 4)
+    repo := graph.Nodes[1]
+    assert.Equal(t, "repo", repo.Qualifier)
+    assert.Equal(t, "*container.providedRepo", repo.Type.String())
+    assert.Equal(t, "func(*container.providedStore) *container.providedRepo", repo.Provider)
+    assert.Nil(t, graph.Nodes[3].Type)
+    assert.Equal(t, "caching", graph.Nodes[3].Module)
+    assert.Equal(t, []GraphEdge{
+        {From: "repo", To: "audit", Kind: EdgeDeclared},
+        {From: "repo", To: "store", Kind: EdgeProvider},
+    }, graph.Edges)
+
+    require.NoError(t, c.InjectStruct(&graphHandler{}))
+    graph = c.Graph()
+    assert.Equal(t, "*container.testServiceImpl", graph.Nodes[3].Type.String())
+    assert.Equal(t, []string{"container.graphHandler.Cache"}, graph.Nodes[3].Consumers)
+    assert.Contains(t, graph.Edges, GraphEdge{From: "cache", To: "audit", Kind: EdgeBuilt})
+}
+
+func TestGraph_DOT(t *testing.T) {
+    c := newGraphContainer(t)
+    require.NoError(t, c.InjectStruct(&graphHandler{}))
+
+    dot := c.Graph().DOT()
+    assert.Contains(t, dot, "digraph container {\n")
+    assert.Contains(t, dot, `"repo" [label="repo\n*container.providedRepo\nsingleton", qualifier="repo", type="*container.providedRepo", lifetime="singleton", provider="func(*container.providedStore) *container.providedRepo"];`)
+    assert.Contains(t, dot, `"cache" [label="cache\n*container.testServiceImpl\nsingleton", qualifier="cache", type="*container.testServiceImpl", lifetime="singleton", module="caching"];`)
+    assert.Contains(t, dot, `"repo" -> "store" [kind="provider", style=solid];`)
+    assert.Contains(t, dot, `"cache" -> "audit" [kind="built", style=dashed];`)
+    assert.Contains(t, dot, `"container.graphHandler" [shape=box, style=dashed];`)
+    assert.Contains(t, dot, `"container.graphHandler" -> "cache" [label="Cache", style=dotted];`)
+
+    recorder := httptest.NewRecorder()
+    c.DebugHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/graph.dot", nil))
+    assert.Equal(t, "text/vnd.graphviz", recorder.Header().Get("Content-Type"))
+    assert.Equal(t, dot, recorder.Body.String())
+}
+
+func TestDOTEscape(t *testing.T) {
+    assert.Equal(t, `"a \"b\" \\c"`, dotQuote(`a "b" \c`))
+}
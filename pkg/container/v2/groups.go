@@ -0,0 +1,299 @@
+package container
+
+import (
+    "fmt"
+    "reflect"
+    "strings"
+)
+
+// Decorator wraps a group member, e.g. to add auth or metrics around a
+// handler. It receives the member's qualifier and current instance and
+// returns the instance to use instead.
+//
+// Experimental: decorators may become typed once generic groups land.
+type Decorator func(qualifier string, service interface{}) (interface{}, error)
+
+// InGroup adds the registration to a named group. A service can belong to
+// several groups; members are resolved together with ResolveGroup.
+func InGroup(group string) RegisterOption {
+    return func(r *registration) {
+        r.groups = append(r.groups, group)
+    }
+}
+
+// Before orders the registration ahead of the given members in every group
+// it belongs to, so a middleware chain assembled from several modules comes
+// out in a deterministic order:
+//
+//	c.Register("recoveryMiddleware", recovery, container.InGroup("middleware"),
+//	    container.Before("authMiddleware"))
+//
+// Qualifiers that are not members of a group are ignored for that group.
+func Before(qualifiers ...string) RegisterOption {
+    return func(r *registration) {
+        r.before = append(r.before, qualifiers...)
+    }
+}
+
+// After orders the registration behind the given members in every group it
+// belongs to, see Before
+func After(qualifiers ...string) RegisterOption {
+    return func(r *registration) {
+        r.after = append(r.after, qualifiers...)
+    }
+}
+
+// DecorateGroup applies decorator to every current and future member of
+// group. Decorators run in the order they were added, so the first one
+// added is innermost. Singleton members are wrapped once, here or when they
+// are registered or swapped in; weak members are wrapped each time they are
+// rebuilt. Decorators may resolve services but must not register, swap or
+// decorate.
+//
+// Experimental: see Decorator.
+func (c *Container) DecorateGroup(group string, decorator Decorator) error {
+    c.writeMu.Lock()
+    defer c.writeMu.Unlock()
+
+    c.log.Infow("Decorating group", "group", group)
+    if decorator == nil {
+        return fmt.Errorf("cannot add nil decorator to group: %s", group)
+    }
+
+    // Collect the current singleton members; writeMu keeps them unchanged
+    c.mu.RLock()
+    var members []string
+    services := make(map[string]interface{})
+    for _, qualifier := range c.order {
+        service, isSingleton := c.services[qualifier]
+        if c.regs[qualifier].inGroup(group) && isSingleton {
+            members = append(members, qualifier)
+            services[qualifier] = service
+        }
+    }
+    c.mu.RUnlock()
+
+    // Wrap every member before committing any of them
+    wrapped := make(map[string]interface{}, len(members))
+    for _, qualifier := range members {
+        decorated, err := applyDecorators(qualifier, services[qualifier], []Decorator{decorator})
+        if err != nil {
+            c.log.Errorw("Group decorator failed",
+                "group", group,
+                "qualifier", qualifier,
+                "error", err)
+            return fmt.Errorf("failed to decorate %s in group %s: %w", qualifier, group, err)
+        }
+        wrapped[qualifier] = decorated
+    }
+
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    for qualifier, service := range wrapped {
+        c.services[qualifier] = service
+        c.bumpVersionLocked(qualifier)
+    }
+    c.decorators[group] = append(c.decorators[group], decorator)
+    c.recordMutation(MutationDecorate, group, fmt.Sprintf("%d members", len(wrapped)))
+    return nil
+}
+
+// ResolveGroup resolves every member of group in registration order,
+// adjusted to honor the Before and After constraints of the members. It
+// fails if the constraints form a cycle.
+func (c *Container) ResolveGroup(group string) ([]interface{}, error) {
+    qualifiers, err := c.orderedGroupMembers(group)
+    if err != nil {
+        return nil, err
+    }
+    var members []interface{}
+    for _, qualifier := range qualifiers {
+        service, err := c.Resolve(qualifier)
+        if err != nil {
+            return nil, fmt.Errorf("failed to resolve %s in group %s: %w", qualifier, group, err)
+        }
+        members = append(members, service)
+    }
+
+    c.log.Debugw("Resolved group",
+        "group", group,
+        "members", len(members))
+    return members, nil
+}
+
+// groupMembers returns the qualifiers of group in registration order
+func (c *Container) groupMembers(group string) []string {
+    c.mu.RLock()
+    defer c.mu.RUnlock()
+
+    var members []string
+    for _, qualifier := range c.order {
+        if c.regs[qualifier].inGroup(group) {
+            members = append(members, qualifier)
+        }
+    }
+    return members
+}
+
+// groupNames returns every group with members, in order of first member
+func (c *Container) groupNames() []string {
+    c.mu.RLock()
+    defer c.mu.RUnlock()
+
+    seen := make(map[string]bool)
+    var groups []string
+    for _, qualifier := range c.order {
+        for _, group := range c.regs[qualifier].groups {
+            if !seen[group] {
+                seen[group] = true
+                groups = append(groups, group)
+            }
+        }
+    }
+    return groups
+}
+
+// orderedGroupMembers returns the qualifiers of group sorted topologically
+// by their Before and After constraints. Among members free to go next, the
+// one registered first goes first.
+func (c *Container) orderedGroupMembers(group string) ([]string, error) {
+    members := c.groupMembers(group)
+    index := make(map[string]int, len(members))
+    for i, qualifier := range members {
+        index[qualifier] = i
+    }
+
+    // successors[i] must come after member i
+    successors := make([][]int, len(members))
+    predecessors := make([]int, len(members))
+    addEdge := func(first, second string) {
+        i, ok := index[first]
+        j, isMember := index[second]
+        if ok && isMember && i != j {
+            successors[i] = append(successors[i], j)
+            predecessors[j]++
+        }
+    }
+    c.mu.RLock()
+    for _, qualifier := range members {
+        reg := c.regs[qualifier]
+        for _, other := range reg.before {
+            addEdge(qualifier, other)
+        }
+        for _, other := range reg.after {
+            addEdge(other, qualifier)
+        }
+    }
+    c.mu.RUnlock()
+
+    ordered := make([]string, 0, len(members))
+    placed := make([]bool, len(members))
+    for len(ordered) < len(members) {
+        next := -1
+        for i := range members {
+            if !placed[i] && predecessors[i] == 0 {
+                next = i
+                break
+            }
+        }
+        if next < 0 {
+            var unordered []string
+            for i, qualifier := range members {
+                if !placed[i] {
+                    unordered = append(unordered, qualifier)
+                }
+            }
+            c.log.Errorw("Group ordering cycle",
+                "group", group,
+                "members", unordered)
+            return nil, fmt.Errorf("group %s: Before and After constraints form a cycle, cannot order %s", group, strings.Join(unordered, ", "))
+        }
+        placed[next] = true
+        ordered = append(ordered, members[next])
+        for _, j := range successors[next] {
+            predecessors[j]--
+        }
+    }
+    return ordered, nil
+}
+
+// MapTagPrefix starts the di tag of a map field that receives a whole group
+// keyed by qualifier, for plugin registries and strategy lookups:
+//
+//	Plugins map[string]Plugin `di:"map:plugins"`
+//
+// The field must be a map keyed by a string type whose values every member
+// is assignable to. An empty group yields an empty map.
+const MapTagPrefix = "map:"
+
+// injectGroupMap sets field to a new map of the members of group, resolved
+// with resolve, keyed by qualifier. It returns the members it resolved.
+func (c *Container) injectGroupMap(field reflect.Value, group string, resolve func(qualifier string) (interface{}, error)) ([]string, error) {
+    mapType := field.Type()
+    if mapType.Kind() != reflect.Map || mapType.Key().Kind() != reflect.String {
+        return nil, fmt.Errorf("a %s%s field must be a map keyed by string, got %v", MapTagPrefix, group, mapType)
+    }
+
+    members := c.groupMembers(group)
+    services := reflect.MakeMapWithSize(mapType, len(members))
+    for i, qualifier := range members {
+        service, err := resolve(qualifier)
+        if err != nil {
+            return members[:i+1], fmt.Errorf("failed to resolve %s in group %s: %w", qualifier, group, err)
+        }
+        serviceValue := reflect.ValueOf(service)
+        if !serviceValue.Type().AssignableTo(mapType.Elem()) {
+            return members[:i+1], &TypeMismatchError{
+                Qualifier: qualifier,
+                Type:      serviceValue.Type(),
+                Want:      mapType.Elem(),
+                Detail:    mismatchDetail(serviceValue.Type(), mapType.Elem()),
+            }
+        }
+        services.SetMapIndex(reflect.ValueOf(qualifier).Convert(mapType.Key()), serviceValue)
+    }
+
+    c.log.Debugw("Injected group map",
+        "group", group,
+        "members", len(members))
+    field.Set(services)
+    return members, nil
+}
+
+// decorateWeak decorates a freshly built weak instance
+func (c *Container) decorateWeak(qualifier string, service interface{}) (interface{}, error) {
+    c.mu.RLock()
+    reg, ok := c.regs[qualifier]
+    var decorators []groupDecorators
+    if ok {
+        decorators = c.decoratorsLocked(reg)
+    }
+    c.mu.RUnlock()
+
+    return c.decorate(qualifier, service, decorators)
+}
+
+// applyDecorators runs decorators in order, rejecting nil results
+func applyDecorators(qualifier string, service interface{}, decorators []Decorator) (interface{}, error) {
+    for _, decorate := range decorators {
+        decorated, err := decorate(qualifier, service)
+        if err != nil {
+            return nil, err
+        }
+        if decorated == nil {
+            return nil, fmt.Errorf("decorator returned nil for %v", reflect.TypeOf(service))
+        }
+        service = decorated
+    }
+    return service, nil
+}
+
+// inGroup reports whether the registration belongs to group
+func (r *registration) inGroup(group string) bool {
+    for _, g := range r.groups {
+        if g == group {
+            return true
+        }
+    }
+    return false
+}
@@ -0,0 +1,187 @@
+package container
+
+import (
+    "errors"
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+// tagged is a decorated group member recording the wrappers applied to it
+type tagged struct {
+    inner interface{}
+    tag   string
+}
+
+func tagWith(tag string) Decorator {
+    return func(qualifier string, service interface{}) (interface{}, error) {
+        return &tagged{inner: service, tag: tag}, nil
+    }
+}
+
+func TestContainer_ResolveGroup(t *testing.T) {
+    container := NewContainer()
+    users := &testServiceImpl{name: "users"}
+    orders := &testServiceImpl{name: "orders"}
+    require.NoError(t, container.Register("users", users, InGroup("handlers")))
+    require.NoError(t, container.Register("unrelated", &testServiceImpl{name: "unrelated"}))
+    require.NoError(t, container.Register("orders", orders, InGroup("handlers"), InGroup("audited")))
+
+    members, err := container.ResolveGroup("handlers")
+    require.NoError(t, err)
+    assert.Equal(t, []interface{}{users, orders}, members)
+
+    members, err = container.ResolveGroup("empty")
+    require.NoError(t, err)
+    assert.Empty(t, members)
+}
+
+func TestContainer_DecorateGroup(t *testing.T) {
+    container := NewContainer()
+    users := &testServiceImpl{name: "users"}
+    require.NoError(t, container.Register("users", users, InGroup("handlers")))
+    require.NoError(t, container.Register("plain", &testServiceImpl{name: "plain"}))
+
+    require.NoError(t, container.DecorateGroup("handlers", tagWith("auth")))
+    require.NoError(t, container.DecorateGroup("handlers", tagWith("metrics")))
+
+    // Current members are wrapped once, first decorator innermost
+    got, err := container.Resolve("users")
+    require.NoError(t, err)
+    assert.Equal(t, &tagged{inner: &tagged{inner: users, tag: "auth"}, tag: "metrics"}, got)
+    again, err := container.Resolve("users")
+    require.NoError(t, err)
+    assert.Same(t, got, again)
+
+    // Future singleton and weak members are wrapped as well
+    orders := &testServiceImpl{name: "orders"}
+    require.NoError(t, container.Register("orders", orders, InGroup("handlers")))
+    got, err = container.Resolve("orders")
+    require.NoError(t, err)
+    assert.Equal(t, "metrics", got.(*tagged).tag)
+
+    require.NoError(t, container.RegisterWeak("reports", func() (interface{}, error) {
+        return &testServiceImpl{name: "reports"}, nil
+    }, InGroup("handlers")))
+    got, err = container.Resolve("reports")
+    require.NoError(t, err)
+    assert.Equal(t, "metrics", got.(*tagged).tag)
+
+    // Swapped in instances too, while non-members are untouched
+    _, err = container.Swap("users", &testServiceImpl{name: "users2"})
+    require.NoError(t, err)
+    got, err = container.Resolve("users")
+    require.NoError(t, err)
+    assert.Equal(t, "metrics", got.(*tagged).tag)
+
+    got, err = container.Resolve("plain")
+    require.NoError(t, err)
+    assert.IsType(t, &testServiceImpl{}, got)
+}
+
+func TestContainer_DecorateGroupErrors(t *testing.T) {
+    container := NewContainer()
+    users := &testServiceImpl{name: "users"}
+    require.NoError(t, container.Register("users", users, InGroup("handlers")))
+
+    assert.Error(t, container.DecorateGroup("handlers", nil))
+
+    err := container.DecorateGroup("handlers", func(string, interface{}) (interface{}, error) {
+        return nil, errors.New("denied")
+    })
+    assert.ErrorContains(t, err, "denied")
+
+    // A failed decorator is not kept and leaves members unchanged
+    got, err := container.Resolve("users")
+    require.NoError(t, err)
+    assert.Same(t, users, got)
+    require.NoError(t, container.Register("orders", &testServiceImpl{name: "orders"}, InGroup("handlers")))
+
+    err = container.DecorateGroup("handlers", func(string, interface{}) (interface{}, error) {
+        return nil, nil
+    })
+    assert.ErrorContains(t, err, "returned nil")
+}
+
+type pluginRegistry struct {
+    Inject   `di:"prefix=web."`
+    Plugins  map[string]TestService `di:"map:plugins"`
+    Handlers map[string]interface{} `di:"map:handlers"`
+}
+
+func TestContainer_InjectGroupMap(t *testing.T) {
+    container := NewContainer()
+    users := &testServiceImpl{name: "users"}
+    orders := &testServiceImpl{name: "orders"}
+    require.NoError(t, container.Register("users", users, InGroup("plugins")))
+    require.NoError(t, container.Register("unrelated", &testServiceImpl{name: "unrelated"}))
+    require.NoError(t, container.RegisterWeak("orders", func() (interface{}, error) {
+        return orders, nil
+    }, InGroup("plugins")))
+
+    var registry pluginRegistry
+    result, err := container.InjectStructWithResult(&registry)
+    require.NoError(t, err)
+    assert.Equal(t, map[string]TestService{"users": users, "orders": orders}, registry.Plugins)
+
+    // An empty group gives an empty map rather than nil
+    assert.NotNil(t, registry.Handlers)
+    assert.Empty(t, registry.Handlers)
+    assert.Equal(t, "map:plugins", result.Fields[0].Qualifier)
+    assert.Equal(t, FieldInjected, result.Fields[0].Status)
+}
+
+func TestContainer_InjectGroupMapErrors(t *testing.T) {
+    container := NewContainer()
+    require.NoError(t, container.Register("users", &testServiceImpl{}, InGroup("plugins")))
+    require.NoError(t, container.Register("count", 3, InGroup("plugins")))
+
+    var wrongValue struct {
+        Plugins map[string]TestService `di:"map:plugins"`
+    }
+    assert.ErrorContains(t, container.InjectStruct(&wrongValue),
+        "failed to inject group plugins into field Plugins: service count has type int, which is not assignable to container.TestService")
+
+    var wrongKey struct {
+        Plugins map[int]interface{} `di:"map:plugins"`
+    }
+    assert.ErrorContains(t, container.InjectStruct(&wrongKey), "a map:plugins field must be a map keyed by string, got map[int]interface {}")
+}
+
+func TestContainer_ResolveGroupOrdering(t *testing.T) {
+    container := NewContainer()
+    auth := &testServiceImpl{name: "auth"}
+    logging := &testServiceImpl{name: "logging"}
+    recovery := &testServiceImpl{name: "recovery"}
+    metrics := &testServiceImpl{name: "metrics"}
+
+    // Registered by different modules, out of order
+    require.NoError(t, container.Register("authMiddleware", auth, InGroup("middleware"), After("recoveryMiddleware")))
+    require.NoError(t, container.Register("metricsMiddleware", metrics, InGroup("middleware")))
+    require.NoError(t, container.Register("loggingMiddleware", logging, InGroup("middleware"), Before("authMiddleware"), After("tracingMiddleware")))
+    require.NoError(t, container.Register("recoveryMiddleware", recovery, InGroup("middleware"), Before("loggingMiddleware")))
+
+    // Constraints on non-members are ignored; free members keep registration order
+    members, err := container.ResolveGroup("middleware")
+    require.NoError(t, err)
+    assert.Equal(t, []interface{}{metrics, recovery, logging, auth}, members)
+
+    again, err := container.ResolveGroup("middleware")
+    require.NoError(t, err)
+    assert.Equal(t, members, again)
+}
+
+func TestContainer_ResolveGroupOrderingCycle(t *testing.T) {
+    container := NewContainer()
+    require.NoError(t, container.Register("a", &testServiceImpl{}, InGroup("middleware"), Before("b")))
+    require.NoError(t, container.Register("b", &testServiceImpl{}, InGroup("middleware"), Before("a")))
+    require.NoError(t, container.Register("c", &testServiceImpl{}, InGroup("middleware")))
+
+    _, err := container.ResolveGroup("middleware")
+    assert.ErrorContains(t, err, "group middleware: Before and After constraints form a cycle, cannot order a, b")
+
+    err = container.Validate()
+    assert.ErrorContains(t, err, "found 1 wiring problems")
+    assert.ErrorContains(t, err, "cannot order a, b")
+}
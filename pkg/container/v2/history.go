@@ -0,0 +1,150 @@
+package container
+
+import (
+    "fmt"
+    "io"
+    "sync"
+    "time"
+)
+
+// DefaultHistorySize is the number of mutations kept by History
+const DefaultHistorySize = 256
+
+// MutationKind names a change made to the container
+type MutationKind string
+
+const (
+    MutationRegister   MutationKind = "register"   // Register, RegisterWeak and helpers built on them
+    MutationSwap       MutationKind = "swap"       // An instance replaced with Swap
+    MutationUnregister MutationKind = "unregister" // A service removed with Unregister
+    MutationRename     MutationKind = "rename"     // A deprecated qualifier redirected
+    MutationDecorate   MutationKind = "decorate"   // A decorator added to a group
+    MutationFreeze     MutationKind = "freeze"     // The container closed for registration
+    MutationScopeOpen  MutationKind = "scope_open"  // A scope or child scope started
+    MutationScopeClose MutationKind = "scope_close" // A scope closed, with its open children
+)
+
+// Mutation is one recorded change to the container
+type Mutation struct {
+    Seq       uint64 // Increases by one per mutation, including dropped ones
+    Time      time.Time
+    Kind      MutationKind
+    Qualifier string // Affected qualifier, group or scope ID, empty for Freeze
+    Detail    string // Kind specific detail such as the new type
+    Caller    string // file:line of the first caller outside the container
+    Goroutine uint64
+}
+
+// String formats the mutation as a single log line
+func (m Mutation) String() string {
+    line := fmt.Sprintf("#%d %s %s", m.Seq, m.Time.Format(time.RFC3339Nano), m.Kind)
+    if m.Qualifier != "" {
+        line += " " + m.Qualifier
+    }
+    if m.Detail != "" {
+        line += " (" + m.Detail + ")"
+    }
+    return line + fmt.Sprintf(" by goroutine %d at %s", m.Goroutine, m.Caller)
+}
+
+// mutationLog is a fixed-size ring buffer of mutations
+type mutationLog struct {
+    mu      sync.Mutex
+    entries []Mutation // Ring storage, len(entries) is the capacity
+    next    int        // Index the next mutation is written to
+    count   int        // Number of valid entries
+    seq     uint64
+}
+
+func newMutationLog(size int) *mutationLog {
+    return &mutationLog{entries: make([]Mutation, size)}
+}
+
+// add stores m, overwriting the oldest entry when full
+func (l *mutationLog) add(m Mutation) {
+    l.mu.Lock()
+    defer l.mu.Unlock()
+
+    l.seq++
+    m.Seq = l.seq
+    if len(l.entries) == 0 {
+        return
+    }
+    l.entries[l.next] = m
+    l.next = (l.next + 1) % len(l.entries)
+    if l.count < len(l.entries) {
+        l.count++
+    }
+}
+
+// snapshot returns the kept mutations, oldest first
+func (l *mutationLog) snapshot() []Mutation {
+    l.mu.Lock()
+    defer l.mu.Unlock()
+
+    mutations := make([]Mutation, 0, l.count)
+    start := (l.next - l.count + len(l.entries)) % max(len(l.entries), 1)
+    for i := 0; i < l.count; i++ {
+        mutations = append(mutations, l.entries[(start+i)%len(l.entries)])
+    }
+    return mutations
+}
+
+// resize keeps the newest mutations that fit in size
+func (l *mutationLog) resize(size int) {
+    kept := l.snapshot()
+    if len(kept) > size {
+        kept = kept[len(kept)-size:]
+    }
+
+    l.mu.Lock()
+    defer l.mu.Unlock()
+    l.entries = make([]Mutation, size)
+    copy(l.entries, kept)
+    l.count = len(kept)
+    l.next = 0
+    if size > 0 {
+        l.next = len(kept) % size
+    }
+}
+
+// History returns the most recent container mutations, oldest first. Use it
+// to reconstruct how the container reached its current state.
+func (c *Container) History() []Mutation {
+    return c.history.snapshot()
+}
+
+// SetHistorySize changes how many mutations are kept. Zero disables history.
+func (c *Container) SetHistorySize(size int) {
+    if size < 0 {
+        size = 0
+    }
+    c.log.Infow("Setting mutation history size", "size", size)
+    c.history.resize(size)
+}
+
+// DumpHistory writes the kept mutations to w, one per line
+func (c *Container) DumpHistory(w io.Writer) error {
+    for _, mutation := range c.History() {
+        if _, err := fmt.Fprintln(w, mutation); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// recordMutation appends a mutation attributed to the current caller and
+// emits it as an event. It may be called with or without c.mu held.
+func (c *Container) recordMutation(kind MutationKind, qualifier, detail string) {
+    caller := currentAccess()
+    now := c.clock.Now()
+    c.history.add(Mutation{
+        Time:      now,
+        Kind:      kind,
+        Qualifier: qualifier,
+        Detail:    detail,
+        Caller:    caller.site,
+        Goroutine: caller.goroutine,
+    })
+    c.emit(Event{Time: now, Kind: EventKind(kind), Qualifier: qualifier, Detail: detail})
+}
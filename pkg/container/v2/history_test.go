@@ -0,0 +1,99 @@
+package container
+
+import (
+    "bytes"
+    "context"
+    "errors"
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+func TestContainer_History(t *testing.T) {
+    container := NewContainer()
+    require.NoError(t, container.Register("users", &testServiceImpl{}, InModule("core")))
+    require.NoError(t, container.RegisterWeak("report", func() (interface{}, error) { return "r", nil }))
+    container.Rename("oldUsers", "users")
+    _, err := container.Swap("users", &testServiceImpl{name: "v2"})
+    require.NoError(t, err)
+    require.NoError(t, container.DecorateGroup("handlers", tagWith("auth")))
+    container.Freeze()
+
+    history := container.History()
+    require.Len(t, history, 6)
+
+    var kinds []MutationKind
+    for _, mutation := range history {
+        kinds = append(kinds, mutation.Kind)
+        assert.Contains(t, mutation.Caller, "history_test.go")
+    }
+    assert.Equal(t, []MutationKind{
+        MutationRegister, MutationRegister, MutationRename,
+        MutationSwap, MutationDecorate, MutationFreeze,
+    }, kinds)
+    assert.Equal(t, uint64(1), history[0].Seq)
+    assert.Equal(t, "singleton, module core", history[0].Detail)
+    assert.Equal(t, "weak", history[1].Detail)
+    assert.Equal(t, "-> users", history[2].Detail)
+
+    var buf bytes.Buffer
+    require.NoError(t, container.DumpHistory(&buf))
+    assert.Contains(t, buf.String(), "#4 ")
+    assert.Contains(t, buf.String(), "swap users (*container.testServiceImpl -> *container.testServiceImpl) by goroutine")
+}
+
+func TestContainer_HistoryScopes(t *testing.T) {
+    container := NewContainer()
+    scope := container.NewScope(context.Background())
+    child, err := scope.NewScope(context.Background())
+    require.NoError(t, err)
+    require.NoError(t, scope.Close(errors.New("rolled back")))
+
+    history := container.History()
+    require.Len(t, history, 4)
+    assert.Equal(t, MutationScopeOpen, history[0].Kind)
+    assert.Equal(t, scope.ID(), history[0].Qualifier)
+    assert.Equal(t, MutationScopeOpen, history[1].Kind)
+    assert.Equal(t, "child of "+scope.ID(), history[1].Detail)
+    assert.Equal(t, MutationScopeClose, history[2].Kind)
+    assert.Equal(t, scope.ID(), history[2].Qualifier)
+    assert.Equal(t, "failed: rolled back", history[2].Detail)
+    assert.Equal(t, child.ID(), history[3].Qualifier)
+    assert.Contains(t, history[0].Caller, "history_test.go")
+}
+
+func TestContainer_HistoryRing(t *testing.T) {
+    container := NewContainer()
+    container.SetHistorySize(2)
+    for _, qualifier := range []string{"a", "b", "c"} {
+        require.NoError(t, container.Register(qualifier, &testServiceImpl{}))
+    }
+
+    history := container.History()
+    require.Len(t, history, 2)
+    assert.Equal(t, "b", history[0].Qualifier)
+    assert.Equal(t, uint64(3), history[1].Seq)
+
+    // Growing keeps what is there, shrinking keeps the newest
+    container.SetHistorySize(3)
+    require.NoError(t, container.Register("d", &testServiceImpl{}))
+    assert.Len(t, container.History(), 3)
+    container.SetHistorySize(1)
+    history = container.History()
+    require.Len(t, history, 1)
+    assert.Equal(t, "d", history[0].Qualifier)
+
+    container.SetHistorySize(0)
+    require.NoError(t, container.Register("e", &testServiceImpl{}))
+    assert.Empty(t, container.History())
+}
+
+func TestContainer_HistoryOnStartFailure(t *testing.T) {
+    container := NewContainer()
+    require.NoError(t, container.Register("users", &testServiceImpl{}))
+    container.validators = append(container.validators, func() error { return errors.New("invalid") })
+
+    assert.Error(t, container.Start(context.Background()))
+    assert.Len(t, container.History(), 1)
+}
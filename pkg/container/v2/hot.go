@@ -0,0 +1,137 @@
+package container
+
+import (
+    "fmt"
+    "reflect"
+    "strings"
+    "sync/atomic"
+)
+
+// Hot holds a hot-swappable dependency. InjectStruct fills Hot[T] and
+// *Hot[T] fields, and Swap later replaces the value atomically, so
+// consumers read the current instance without locks:
+//
+//	type Handler struct {
+//	    Pricing container.Hot[PricingService] `di:"pricing"`
+//	}
+//
+//	price := h.Pricing.Load().Quote(item)
+//
+// Fields of type *atomic.Pointer[T] are supported the same way for a
+// service of type *T. The container keeps every injected field reachable
+// so it can update it; inject long-lived structs only.
+type Hot[T any] struct {
+    value atomic.Pointer[T]
+}
+
+// Load returns the current value, or the zero value before injection
+func (h *Hot[T]) Load() T {
+    if current := h.value.Load(); current != nil {
+        return *current
+    }
+    var zero T
+    return zero
+}
+
+// hotField is implemented by *Hot[T] so InjectStruct can fill hot fields
+// without knowing T
+type hotField interface {
+    valueType() reflect.Type
+    store(service interface{})
+}
+
+func (h *Hot[T]) valueType() reflect.Type {
+    return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+func (h *Hot[T]) store(service interface{}) {
+    value := service.(T)
+    h.value.Store(&value)
+}
+
+// hotBinding is an injected hot field updated by Swap
+type hotBinding struct {
+    site      string       // Field description used in errors
+    valueType reflect.Type // Type a swapped-in service must be assignable to
+    store     func(service interface{})
+}
+
+// asHotField returns the binding target of a hot field, allocating nil
+// *Hot[T] and *atomic.Pointer[T] fields. ok is false for other fields.
+func asHotField(field reflect.Value) (valueType reflect.Type, store func(interface{}), ok bool) {
+    if field.Kind() == reflect.Ptr && field.IsNil() && isHotType(field.Type().Elem()) {
+        field.Set(reflect.New(field.Type().Elem()))
+    }
+
+    target := field
+    if field.Kind() != reflect.Ptr {
+        target = field.Addr()
+    }
+    if hot, isHot := target.Interface().(hotField); isHot {
+        return hot.valueType(), hot.store, true
+    }
+
+    // *atomic.Pointer[T] stores *T values through its Store method
+    if field.Kind() == reflect.Ptr && isAtomicPointer(field.Type().Elem()) {
+        storeMethod := field.MethodByName("Store")
+        valueType := storeMethod.Type().In(0)
+        return valueType, func(service interface{}) {
+            storeMethod.Call([]reflect.Value{reflect.ValueOf(service)})
+        }, true
+    }
+    return nil, nil, false
+}
+
+// isHotType reports whether t is Hot[T] or atomic.Pointer[T]
+func isHotType(t reflect.Type) bool {
+    return reflect.PointerTo(t).Implements(reflect.TypeOf((*hotField)(nil)).Elem()) || isAtomicPointer(t)
+}
+
+// isAtomicPointer reports whether t is an instance of sync/atomic.Pointer
+func isAtomicPointer(t reflect.Type) bool {
+    return t.Kind() == reflect.Struct && t.PkgPath() == "sync/atomic" && strings.HasPrefix(t.Name(), "Pointer[")
+}
+
+// injectHot fills a hot field with service and binds it to qualifier so
+// Swap updates it
+func (c *Container) injectHot(qualifier string, service interface{}, site string, valueType reflect.Type, store func(interface{})) error {
+    serviceType := reflect.TypeOf(service)
+    if !serviceType.AssignableTo(valueType) {
+        c.log.Errorw("Type mismatch during hot injection",
+            "field", site,
+            "expectedType", valueType,
+            "actualType", serviceType)
+        return &TypeMismatchError{Qualifier: qualifier, Type: serviceType, Want: valueType, Target: "hot field type"}
+    }
+    store(service)
+
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    c.hot[qualifier] = append(c.hot[qualifier], hotBinding{site: site, valueType: valueType, store: store})
+    return nil
+}
+
+// checkHotLocked verifies every hot field bound to qualifier can hold
+// service. Callers must hold c.mu.
+func (c *Container) checkHotLocked(qualifier string, service interface{}) error {
+    serviceType := reflect.TypeOf(service)
+    for _, binding := range c.hot[qualifier] {
+        if !serviceType.AssignableTo(binding.valueType) {
+            return fmt.Errorf("cannot swap %s: %w", qualifier, &TypeMismatchError{
+                Qualifier: qualifier,
+                Type:      serviceType,
+                Want:      binding.valueType,
+                Target:    fmt.Sprintf("hot field %s of type", binding.site),
+            })
+        }
+    }
+    return nil
+}
+
+// publishHotLocked stores service in every hot field bound to qualifier.
+// Callers must hold c.mu.
+func (c *Container) publishHotLocked(qualifier string, service interface{}) {
+    for _, binding := range c.hot[qualifier] {
+        binding.store(service)
+    }
+}
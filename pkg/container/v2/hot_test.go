@@ -0,0 +1,98 @@
+package container
+
+import (
+    "sync"
+    "sync/atomic"
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+type pricing interface {
+    Price() int
+}
+
+type fixedPricing struct {
+    price int
+}
+
+func (f *fixedPricing) Price() int {
+    return f.price
+}
+
+type hotConsumer struct {
+    Pricing    Hot[pricing]                  `di:"pricing"`
+    PricingPtr *Hot[pricing]                 `di:"pricing"`
+    Atomic     *atomic.Pointer[fixedPricing] `di:"pricing"`
+}
+
+func TestHot_SwapUpdatesInjectedFields(t *testing.T) {
+    c := NewContainer()
+    require.NoError(t, c.Register("pricing", &fixedPricing{price: 10}))
+
+    var consumer hotConsumer
+    require.NoError(t, c.InjectStruct(&consumer))
+    assert.Equal(t, 10, consumer.Pricing.Load().Price())
+    assert.Equal(t, 10, consumer.PricingPtr.Load().Price())
+    assert.Equal(t, 10, consumer.Atomic.Load().Price())
+
+    _, err := c.Swap("pricing", &fixedPricing{price: 12})
+    require.NoError(t, err)
+    assert.Equal(t, 12, consumer.Pricing.Load().Price())
+    assert.Equal(t, 12, consumer.PricingPtr.Load().Price())
+    assert.Equal(t, 12, consumer.Atomic.Load().Price())
+}
+
+func TestHot_SwapRejectsIncompatibleService(t *testing.T) {
+    c := NewContainer()
+    require.NoError(t, c.Register("pricing", &fixedPricing{price: 10}))
+
+    var consumer hotConsumer
+    require.NoError(t, c.InjectStruct(&consumer))
+
+    _, err := c.Swap("pricing", "not pricing")
+    assert.ErrorContains(t, err, "is not assignable to hot field container.hotConsumer.Pricing")
+
+    // Nothing changed
+    service, err := c.Resolve("pricing")
+    require.NoError(t, err)
+    assert.Equal(t, 10, service.(pricing).Price())
+    assert.Equal(t, 10, consumer.Atomic.Load().Price())
+}
+
+func TestHot_InjectTypeMismatch(t *testing.T) {
+    c := NewContainer()
+    require.NoError(t, c.Register("pricing", "flat"))
+
+    var consumer hotConsumer
+    err := c.InjectStruct(&consumer)
+    assert.ErrorContains(t, err, "not assignable to hot field type")
+}
+
+func TestHot_ZeroBeforeInjection(t *testing.T) {
+    var hot Hot[pricing]
+    assert.Nil(t, hot.Load())
+}
+
+func TestHot_ConcurrentLoadDuringSwap(t *testing.T) {
+    c := NewContainer()
+    require.NoError(t, c.Register("pricing", &fixedPricing{price: 1}))
+    var consumer hotConsumer
+    require.NoError(t, c.InjectStruct(&consumer))
+
+    var wg sync.WaitGroup
+    wg.Add(1)
+    go func() {
+        defer wg.Done()
+        for i := 2; i < 50; i++ {
+            _, err := c.Swap("pricing", &fixedPricing{price: i})
+            assert.NoError(t, err)
+        }
+    }()
+    for i := 0; i < 1000; i++ {
+        assert.Positive(t, consumer.Pricing.Load().Price())
+    }
+    wg.Wait()
+    assert.Equal(t, 49, consumer.Atomic.Load().Price())
+}
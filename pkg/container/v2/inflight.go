@@ -0,0 +1,28 @@
+package container
+
+import (
+    "fmt"
+    "reflect"
+)
+
+// beginInjection marks the struct at addr as being injected. It fails if
+// another goroutine is already injecting the same struct, since concurrent
+// writes to its fields would race. The returned func releases the mark.
+func (c *Container) beginInjection(addr uintptr, structType reflect.Type) (func(), error) {
+    c.inflightMu.Lock()
+    defer c.inflightMu.Unlock()
+
+    if _, busy := c.inflight[addr]; busy {
+        c.log.Errorw("Concurrent injection into the same struct",
+            "structType", structType,
+            "address", fmt.Sprintf("%#x", addr))
+        return nil, fmt.Errorf("struct %v at %#x is already being injected by another goroutine", structType, addr)
+    }
+
+    c.inflight[addr] = struct{}{}
+    return func() {
+        c.inflightMu.Lock()
+        defer c.inflightMu.Unlock()
+        delete(c.inflight, addr)
+    }, nil
+}
@@ -0,0 +1,33 @@
+package container
+
+import (
+    "reflect"
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+func TestContainer_BeginInjection(t *testing.T) {
+    container := NewContainer()
+    require.NoError(t, container.Register("testService", &testServiceImpl{}))
+    target := &TestStruct{}
+    addr := reflect.ValueOf(target).Pointer()
+    structType := reflect.TypeOf(*target)
+
+    release, err := container.beginInjection(addr, structType)
+    require.NoError(t, err)
+
+    // A second injection of the same struct is rejected while the first runs
+    _, err = container.beginInjection(addr, structType)
+    assert.Error(t, err)
+    assert.Error(t, container.InjectStruct(target))
+
+    // Other structs are unaffected
+    assert.NoError(t, container.InjectStruct(&TestStruct{}))
+
+    // Once released the struct can be injected again
+    release()
+    assert.NoError(t, container.InjectStruct(target))
+    assert.Empty(t, container.inflight)
+}
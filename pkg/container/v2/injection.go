@@ -0,0 +1,162 @@
+package container
+
+import (
+    "fmt"
+    "reflect"
+    "strings"
+    "time"
+)
+
+// FieldStatus is the outcome of injecting one field
+type FieldStatus string
+
+const (
+    FieldInjected   FieldStatus = "injected"   // The service was set on the field
+    FieldMissing    FieldStatus = "missing"    // No service; the field is optional
+    FieldUnexported FieldStatus = "unexported" // The field cannot be set
+    FieldGuarded    FieldStatus = "guarded"    // The ifPresent guard is not registered
+    FieldUnchanged  FieldStatus = "unchanged"  // ReinjectStruct kept the current value
+)
+
+// FieldInjection describes how a single field was injected
+type FieldInjection struct {
+    Field     string
+    Requested string        // Qualifier from the tag, including any prefix
+    Qualifier string        // Qualifier resolved after renames
+    Status    FieldStatus
+    Type      reflect.Type  // Concrete type injected, nil unless injected into a plain field
+    Lifetime  Lifetime      // Lifetime of the source registration
+    Module    string        // Module of the source registration
+    Duration  time.Duration // Time spent resolving the service
+}
+
+// InjectionResult reports what InjectStructWithResult did for every field
+// with a di tag
+type InjectionResult struct {
+    Type     reflect.Type
+    Fields   []FieldInjection
+    Duration time.Duration
+}
+
+// Injected returns the number of fields that received a service
+func (r *InjectionResult) Injected() int {
+    count := 0
+    for _, field := range r.Fields {
+        if field.Status == FieldInjected {
+            count++
+        }
+    }
+    return count
+}
+
+// String renders the result as one line per field, suitable for logging
+// once at startup
+func (r *InjectionResult) String() string {
+    var b strings.Builder
+    fmt.Fprintf(&b, "%v: %d/%d fields injected in %v\n", r.Type, r.Injected(), len(r.Fields), r.Duration)
+    for _, field := range r.Fields {
+        fmt.Fprintf(&b, "  %s <- %s: %s", field.Field, field.Qualifier, field.Status)
+        if field.Requested != field.Qualifier {
+            fmt.Fprintf(&b, " (renamed from %s)", field.Requested)
+        }
+        if field.Status == FieldInjected {
+            fmt.Fprintf(&b, " %s", field.Lifetime)
+            if field.Module != "" {
+                fmt.Fprintf(&b, " from module %s", field.Module)
+            }
+            fmt.Fprintf(&b, " in %v", field.Duration)
+        }
+        b.WriteString("\n")
+    }
+    return b.String()
+}
+
+// registrationSource returns the lifetime and module of a registration
+func (c *Container) registrationSource(qualifier string) (Lifetime, string) {
+    c.mu.RLock()
+    defer c.mu.RUnlock()
+
+    if reg, ok := c.regs[qualifier]; ok {
+        return reg.lifetime, reg.module
+    }
+    return Singleton, ""
+}
+
+// InjectionLogging controls how InjectStruct logs its work
+type InjectionLogging int
+
+const (
+    // InjectionLogSummary logs one Info entry per struct with counts, and
+    // the per-field details in one Debug entry. It is the default.
+    InjectionLogSummary InjectionLogging = iota
+    // InjectionLogVerbose also logs every field at Info level
+    InjectionLogVerbose
+    // InjectionLogQuiet logs the summary at Debug level only
+    InjectionLogQuiet
+)
+
+// SetInjectionLogging sets how InjectStruct logs its work
+func (c *Container) SetInjectionLogging(mode InjectionLogging) {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    c.injectionLogging = mode
+}
+
+// counts returns the number of injected, missing and skipped fields.
+// Unexported and guarded fields count as skipped.
+func (r *InjectionResult) counts() (injected, missing, skipped int) {
+    for _, field := range r.Fields {
+        switch field.Status {
+        case FieldInjected:
+            injected++
+        case FieldMissing:
+            missing++
+        case FieldUnexported, FieldGuarded, FieldUnchanged:
+            skipped++
+        }
+    }
+    return injected, missing, skipped
+}
+
+// logInjection writes the buffered outcome of one InjectStruct call
+func (c *Container) logInjection(result *InjectionResult) {
+    c.mu.RLock()
+    mode := c.injectionLogging
+    c.mu.RUnlock()
+
+    injected, missing, skipped := result.counts()
+    summary := []interface{}{
+        "structType", result.Type,
+        "injected", injected,
+        "missing", missing,
+        "skipped", skipped,
+        "duration", result.Duration,
+    }
+
+    switch mode {
+    case InjectionLogQuiet:
+        c.log.Debugw("Completed struct injection", summary...)
+    case InjectionLogVerbose:
+        for _, field := range result.Fields {
+            c.log.Infow("Injected field",
+                "structType", result.Type,
+                "field", field.Field,
+                "qualifier", field.Qualifier,
+                "status", field.Status,
+                "duration", field.Duration)
+        }
+        c.log.Infow("Completed struct injection", summary...)
+    default:
+        c.log.Infow("Completed struct injection", summary...)
+    }
+
+    if mode != InjectionLogVerbose && len(result.Fields) > 0 {
+        details := make([]string, 0, len(result.Fields))
+        for _, field := range result.Fields {
+            details = append(details, fmt.Sprintf("%s<-%s:%s", field.Field, field.Qualifier, field.Status))
+        }
+        c.log.Debugw("Struct injection details",
+            "structType", result.Type,
+            "fields", details)
+    }
+}
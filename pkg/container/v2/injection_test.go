@@ -0,0 +1,144 @@
+package container
+
+import (
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+    "go.uber.org/zap"
+    "go.uber.org/zap/zapcore"
+    "go.uber.org/zap/zaptest/observer"
+)
+
+type reportedStruct struct {
+    Service  TestService           `di:"testService"`
+    Legacy   TestService           `di:"oldService,optional"`
+    Cache    Optional[TestService] `di:"cache"`
+    Missing  TestService           `di:"missingService,optional"`
+    hidden   TestService           `di:"testService"`
+    Untagged int
+}
+
+func TestContainer_InjectStructWithResult(t *testing.T) {
+    container := NewContainer()
+    require.NoError(t, container.Install(Module{
+        Name: "core",
+        Setup: func(c *Container) error {
+            return c.Register("testService", &testServiceImpl{name: "test"})
+        },
+    }))
+    container.Rename("oldService", "testService")
+
+    target := &reportedStruct{}
+    result, err := container.InjectStructWithResult(target)
+    require.NoError(t, err)
+    assert.Nil(t, target.hidden)
+
+    require.Len(t, result.Fields, 5)
+    assert.Equal(t, 2, result.Injected())
+
+    service := result.Fields[0]
+    assert.Equal(t, "Service", service.Field)
+    assert.Equal(t, FieldInjected, service.Status)
+    assert.Equal(t, "core", service.Module)
+    assert.Equal(t, Singleton, service.Lifetime)
+    assert.Equal(t, "*container.testServiceImpl", service.Type.String())
+
+    legacy := result.Fields[1]
+    assert.Equal(t, "oldService", legacy.Requested)
+    assert.Equal(t, "testService", legacy.Qualifier)
+
+    assert.Equal(t, FieldMissing, result.Fields[2].Status)
+    assert.Equal(t, FieldMissing, result.Fields[3].Status)
+    assert.Equal(t, FieldUnexported, result.Fields[4].Status)
+
+    report := result.String()
+    assert.Contains(t, report, "2/5 fields injected")
+    assert.Contains(t, report, "Legacy <- testService: injected (renamed from oldService) singleton from module core")
+    assert.Contains(t, report, "Missing <- missingService: missing")
+}
+
+// observeLogs replaces the container logger with one recording entries at
+// Debug level and above
+func observeLogs(container *Container) *observer.ObservedLogs {
+    core, logs := observer.New(zapcore.DebugLevel)
+    container.log = zap.New(core).Sugar()
+    return logs
+}
+
+func TestContainer_InjectionLogging(t *testing.T) {
+    newContainer := func() (*Container, *observer.ObservedLogs) {
+        container := NewContainer()
+        require.NoError(t, container.Register("testService", &testServiceImpl{name: "test"}))
+        return container, observeLogs(container)
+    }
+
+    t.Run("summary", func(t *testing.T) {
+        container, logs := newContainer()
+        require.NoError(t, container.InjectStruct(&reportedStruct{}))
+
+        summaries := logs.FilterMessage("Completed struct injection").All()
+        require.Len(t, summaries, 1)
+        assert.Equal(t, zapcore.InfoLevel, summaries[0].Level)
+        fields := summaries[0].ContextMap()
+        assert.Equal(t, int64(1), fields["injected"])
+        assert.Equal(t, int64(3), fields["missing"])
+        assert.Equal(t, int64(1), fields["skipped"])
+
+        details := logs.FilterMessage("Struct injection details").All()
+        require.Len(t, details, 1)
+        assert.Equal(t, zapcore.DebugLevel, details[0].Level)
+        assert.Empty(t, logs.FilterMessage("Injected field").All())
+    })
+
+    t.Run("verbose", func(t *testing.T) {
+        container, logs := newContainer()
+        container.SetInjectionLogging(InjectionLogVerbose)
+        require.NoError(t, container.InjectStruct(&reportedStruct{}))
+
+        assert.Len(t, logs.FilterMessage("Injected field").All(), 5)
+        assert.Empty(t, logs.FilterMessage("Struct injection details").All())
+    })
+
+    t.Run("quiet", func(t *testing.T) {
+        container, logs := newContainer()
+        container.SetInjectionLogging(InjectionLogQuiet)
+        require.NoError(t, container.InjectStruct(&reportedStruct{}))
+
+        for _, entry := range logs.FilterMessageSnippet("injection").All() {
+            assert.Equal(t, zapcore.DebugLevel, entry.Level)
+        }
+    })
+}
+
+func TestContainer_InjectStructAggregatesErrors(t *testing.T) {
+    container := NewContainer()
+    require.NoError(t, container.Register("testService", &testServiceImpl{}))
+    require.NoError(t, container.Register("count", 3))
+    require.NoError(t, container.Register("plugin", &testServiceImpl{}, InGroup("plugins")))
+    require.NoError(t, container.Register("broken", "not a service", InGroup("plugins")))
+
+    target := &struct {
+        Service TestService            `di:"testService"`
+        Count   string                 `di:"count"`
+        Missing TestService            `di:"missingService"`
+        Cache   Optional[TestService]  `di:"count"`
+        Plugins map[string]TestService `di:"map:plugins"`
+    }{}
+    err := container.InjectStruct(target)
+    require.Error(t, err)
+
+    // One error per broken field, in field order
+    assert.Contains(t, err.Error(), "failed to inject 4 fields of")
+    assert.Contains(t, err.Error(), "field Count: service type int is not assignable to field type string")
+    assert.Contains(t, err.Error(), `required service "missingService" for field Missing not found`)
+    assert.Contains(t, err.Error(), "field Cache: service type int is not assignable to optional field type container.TestService")
+    assert.Contains(t, err.Error(), "failed to inject group plugins into field Plugins")
+
+    var joined interface{ Unwrap() []error }
+    require.ErrorAs(t, err, &joined)
+    assert.Len(t, joined.Unwrap(), 4)
+
+    // Fields without errors are still set
+    assert.NotNil(t, target.Service)
+}
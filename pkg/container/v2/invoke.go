@@ -0,0 +1,172 @@
+package container
+
+import (
+    "fmt"
+    "reflect"
+)
+
+// Invoke calls fn with its parameters resolved from the container and
+// registers what it returns, giving constructor injection on top of
+// qualifiers:
+//
+//	err := c.Invoke(func(users UserService, deps struct {
+//	    Mailer Mailer `di:"mailer"`
+//	}) (*SignupHandler, error) {
+//	    return NewSignupHandler(users, deps.Mailer), nil
+//	})
+//
+// A *Container parameter receives c. A struct parameter with di tagged
+// fields is a parameter object filled by InjectStruct, so its fields are
+// resolved by qualifier. Any other parameter is resolved by type, see
+// ResolveByType.
+//
+// A trailing error result is returned. A struct result with di tagged
+// fields is a result object whose non-nil fields are registered under
+// their qualifiers. Any other result is registered under its type name,
+// e.g. "*handlers.SignupHandler", and can be resolved by type. When one
+// of them fails to register, those registered before it are unregistered.
+func (c *Container) Invoke(fn interface{}) error {
+    fnValue := reflect.ValueOf(fn)
+    if fnValue.Kind() != reflect.Func || fnValue.IsNil() {
+        return fmt.Errorf("Invoke requires a function, got %T", fn)
+    }
+    fnType := fnValue.Type()
+    if fnType.IsVariadic() {
+        return fmt.Errorf("Invoke does not support variadic function %v", fnType)
+    }
+
+    c.log.Debugw("Invoking function", "type", fnType)
+
+    args := make([]reflect.Value, fnType.NumIn())
+    for i := range args {
+        arg, err := c.invokeArgument(fnType.In(i))
+        if err != nil {
+            c.log.Errorw("Failed to resolve Invoke parameter",
+                "index", i,
+                "type", fnType.In(i),
+                "error", err)
+            return fmt.Errorf("parameter %d (%v) of %v: %w", i, fnType.In(i), fnType, err)
+        }
+        args[i] = arg
+    }
+
+    results := fnValue.Call(args)
+    if n := len(results); n > 0 && fnType.Out(n-1) == errorType {
+        if err, _ := results[n-1].Interface().(error); err != nil {
+            return fmt.Errorf("invoked function failed: %w", err)
+        }
+        results = results[:n-1]
+    }
+
+    var entries []invokeResult
+    for _, result := range results {
+        resultEntries, err := resultEntries(result)
+        if err != nil {
+            return err
+        }
+        entries = append(entries, resultEntries...)
+    }
+
+    var registered []string
+    for _, entry := range entries {
+        _, existed := c.staticType(entry.qualifier)
+        if err := c.Register(entry.qualifier, entry.value); err != nil {
+            c.rollbackInvoke(registered)
+            return err
+        }
+        if !existed {
+            registered = append(registered, entry.qualifier)
+        }
+    }
+    return nil
+}
+
+// invokeResult is a service returned by an invoked function
+type invokeResult struct {
+    qualifier string
+    value     interface{}
+}
+
+// rollbackInvoke unregisters the results of an invoked function registered
+// before one of them failed, latest first
+func (c *Container) rollbackInvoke(registered []string) {
+    for i := len(registered) - 1; i >= 0; i-- {
+        if err := c.Unregister(registered[i]); err != nil {
+            c.log.Warnw("Failed to roll back Invoke result",
+                "qualifier", registered[i],
+                "error", err)
+        }
+    }
+}
+
+// invokeArgument resolves one parameter of an invoked function
+func (c *Container) invokeArgument(paramType reflect.Type) (reflect.Value, error) {
+    if paramType == containerPtrType {
+        return reflect.ValueOf(c), nil
+    }
+
+    if isTaggedStruct(paramType) {
+        params := reflect.New(paramType)
+        if err := c.InjectStruct(params.Interface()); err != nil {
+            return reflect.Value{}, err
+        }
+        return params.Elem(), nil
+    }
+
+    service, err := c.ResolveByType(paramType)
+    if err != nil {
+        return reflect.Value{}, err
+    }
+    return reflect.ValueOf(service), nil
+}
+
+// resultEntries returns the services of one result of an invoked function
+func resultEntries(result reflect.Value) ([]invokeResult, error) {
+    if isTaggedStruct(result.Type()) {
+        var entries []invokeResult
+        for i := 0; i < result.NumField(); i++ {
+            field := result.Type().Field(i)
+            tag, ok := field.Tag.Lookup("di")
+            if !ok || !field.IsExported() || field.Type == injectMarkerType {
+                continue
+            }
+            qualifier := parseTag(tag).qualifier
+            if qualifier == "" {
+                return nil, fmt.Errorf("field %s of result %v has no qualifier", field.Name, result.Type())
+            }
+            value := result.Field(i)
+            if isNilable(value.Kind()) && value.IsNil() {
+                continue
+            }
+            entries = append(entries, invokeResult{qualifier: qualifier, value: value.Interface()})
+        }
+        return entries, nil
+    }
+
+    if isNilable(result.Kind()) && result.IsNil() {
+        return nil, fmt.Errorf("invoked function returned nil %v", result.Type())
+    }
+    return []invokeResult{{qualifier: result.Type().String(), value: result.Interface()}}, nil
+}
+
+// isTaggedStruct reports whether t is a struct with di tagged fields
+func isTaggedStruct(t reflect.Type) bool {
+    if t.Kind() != reflect.Struct {
+        return false
+    }
+    for i := 0; i < t.NumField(); i++ {
+        if _, ok := t.Field(i).Tag.Lookup("di"); ok {
+            return true
+        }
+    }
+    return false
+}
+
+// isNilable reports whether values of kind can be nil
+func isNilable(kind reflect.Kind) bool {
+    switch kind {
+    case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice, reflect.Chan, reflect.Func:
+        return true
+    }
+    return false
+}
@@ -0,0 +1,126 @@
+package container
+
+import (
+    "errors"
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+type signupHandler struct {
+    migrations migrationProvider
+    mailer     string
+}
+
+type signupParams struct {
+    Mailer string `di:"mailer"`
+}
+
+type signupResults struct {
+    Handler *signupHandler `di:"signup"`
+    Admin   *signupHandler `di:"admin"`
+}
+
+func TestInvoke_ResolvesParametersAndRegistersResults(t *testing.T) {
+    c := NewContainer()
+    require.NoError(t, c.Register("users", usersMigrations{}))
+    require.NoError(t, c.Register("mailer", "smtp"))
+
+    var got *Container
+    err := c.Invoke(func(migrations migrationProvider, params signupParams, container *Container) (*signupHandler, error) {
+        got = container
+        return &signupHandler{migrations: migrations, mailer: params.Mailer}, nil
+    })
+    require.NoError(t, err)
+    assert.Same(t, c, got)
+
+    // Results are registered under their type name and resolvable by type
+    service, err := c.Resolve("*container.signupHandler")
+    require.NoError(t, err)
+    assert.Equal(t, &signupHandler{migrations: usersMigrations{}, mailer: "smtp"}, service)
+    handler, err := ResolveInterface[*signupHandler](c)
+    require.NoError(t, err)
+    assert.Same(t, service, handler)
+}
+
+func TestInvoke_ResultObject(t *testing.T) {
+    c := NewContainer()
+    require.NoError(t, c.Invoke(func() signupResults {
+        return signupResults{Handler: &signupHandler{mailer: "smtp"}}
+    }))
+
+    handler, err := ResolveAs[*signupHandler](c, "signup")
+    require.NoError(t, err)
+    assert.Equal(t, "smtp", handler.mailer)
+    _, err = c.Resolve("admin") // Nil fields are skipped
+    assert.Error(t, err)
+}
+
+func TestInvoke_Errors(t *testing.T) {
+    c := NewContainer()
+    require.NoError(t, c.Register("users", usersMigrations{}))
+
+    assert.EqualError(t, c.Invoke(42), "Invoke requires a function, got int")
+    assert.ErrorContains(t, c.Invoke(func(...int) {}), "does not support variadic")
+
+    err := c.Invoke(func(port int) {})
+    assert.EqualError(t, err, "parameter 0 (int) of func(int): no service assignable to int")
+
+    err = c.Invoke(func() (*signupHandler, error) { return nil, errors.New("boom") })
+    assert.EqualError(t, err, "invoked function failed: boom")
+
+    err = c.Invoke(func() *signupHandler { return nil })
+    assert.EqualError(t, err, "invoked function returned nil *container.signupHandler")
+
+    // Parameter objects follow InjectStruct rules
+    err = c.Invoke(func(params struct {
+        Inject `di:"required"`
+        Mailer string `di:"mailer"`
+    }) {
+    })
+    assert.ErrorContains(t, err, `required service "mailer" for field Mailer not found`)
+}
+
+func TestInvoke_RollsBackResultsOnFailure(t *testing.T) {
+    c := NewContainer()
+    require.NoError(t, c.Register("admin", "taken"))
+
+    // signup registers, then admin collides and signup is rolled back
+    err := c.Invoke(func() signupResults {
+        return signupResults{Handler: &signupHandler{}, Admin: &signupHandler{}}
+    })
+    var dup *DuplicateRegistrationError
+    require.ErrorAs(t, err, &dup)
+    assert.Equal(t, "admin", dup.Qualifier)
+    _, err = c.Resolve("signup")
+    assert.Error(t, err)
+    admin, err := c.Resolve("admin")
+    require.NoError(t, err)
+    assert.Equal(t, "taken", admin)
+
+    // A result registered under an existing qualifier kept by
+    // DuplicateKeepFirst is not rolled back
+    c = NewContainer(WithDuplicatePolicy(DuplicateKeepFirst))
+    require.NoError(t, c.Register("signup", "first"))
+    require.NoError(t, c.Invoke(func() signupResults {
+        return signupResults{Handler: &signupHandler{}}
+    }))
+    signup, err := c.Resolve("signup")
+    require.NoError(t, err)
+    assert.Equal(t, "first", signup)
+}
+
+func TestInvoke_ResultFieldWithoutQualifier(t *testing.T) {
+    type unnamedResults struct {
+        Handler *signupHandler `di:"signup"`
+        Other   *signupHandler `di:""`
+    }
+    c := NewContainer()
+    err := c.Invoke(func() unnamedResults {
+        return unnamedResults{Handler: &signupHandler{}, Other: &signupHandler{}}
+    })
+    assert.EqualError(t, err, "field Other of result container.unnamedResults has no qualifier")
+    _, err = c.Resolve("signup")
+    assert.Error(t, err)
+}
@@ -0,0 +1,80 @@
+package container
+
+import (
+    "encoding/json"
+    "fmt"
+    "reflect"
+)
+
+// InjectJSON fills target from a JSON document and the container in one
+// step: data fields are decoded from the JSON, then every field with a di
+// tag, including those of the nested structs InjectStruct descends into,
+// is reset and injected from the container. Service fields therefore never
+// take their value from the payload. Like InjectStruct, it fails while
+// another goroutine is injecting the same target, before decoding into it.
+func (c *Container) InjectJSON(data []byte, target interface{}) error {
+    c.log.Info("Starting JSON injection")
+    begin := c.clock.Now()
+
+    targetValue := reflect.ValueOf(target)
+    if targetValue.Kind() != reflect.Ptr || targetValue.IsNil() || targetValue.Elem().Kind() != reflect.Struct {
+        c.log.Errorw("Target must be a non-nil pointer to struct",
+            "type", reflect.TypeOf(target))
+        return fmt.Errorf("target must be a non-nil pointer to struct, got: %v", reflect.TypeOf(target))
+    }
+    structValue := targetValue.Elem()
+
+    // Decoding writes the target too, so it needs the in-flight mark first
+    release, err := c.beginInjection(targetValue.Pointer(), structValue.Type())
+    if err != nil {
+        return err
+    }
+    defer release()
+
+    // Decode data fields from the payload
+    if err := json.Unmarshal(data, target); err != nil {
+        c.log.Errorw("Failed to decode JSON payload", "error", err)
+        return fmt.Errorf("failed to decode JSON into %v: %w", structValue.Type(), err)
+    }
+
+    // Drop anything the payload wrote into service fields
+    in := &injection{
+        visited:    map[uintptr]bool{targetValue.Pointer(): true},
+        cachePlans: c.plansEnabled || c.Frozen(),
+    }
+    c.resetTaggedFields(in, structValue, c.plan(in, structValue.Type()), 0)
+
+    _, err = c.injectGuarded(structValue, c.resolveTraced, nil, begin)
+    return err
+}
+
+// resetTaggedFields zeroes the di-tagged fields of structValue and of the
+// nested structs injectFields would descend into, following the same plans
+func (c *Container) resetTaggedFields(in *injection, structValue reflect.Value, plan *injectionPlan, depth int) {
+    for _, fp := range plan.fields {
+        fieldValue, settable := c.settableField(structValue.FieldByIndex(fp.field.Index))
+        if !fp.nested {
+            if settable {
+                fieldValue.Set(reflect.Zero(fp.field.Type))
+            }
+            continue
+        }
+
+        // Nested structs the payload left nil have nothing to reset
+        if depth >= maxNestedDepth {
+            continue
+        }
+        nested := fieldValue
+        if nested.Kind() == reflect.Ptr {
+            if nested.IsNil() || in.visited[nested.Pointer()] {
+                continue
+            }
+            in.visited[nested.Pointer()] = true
+            nested = nested.Elem()
+        }
+        if nested.Kind() != reflect.Struct {
+            continue
+        }
+        c.resetTaggedFields(in, nested, c.plan(in, nested.Type()), depth+1)
+    }
+}
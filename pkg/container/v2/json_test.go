@@ -0,0 +1,77 @@
+package container
+
+import (
+    "reflect"
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+type webhookJob struct {
+    Name    string      `json:"name"`
+    Retries int         `json:"retries"`
+    Service interface{} `json:"service" di:"testService"`
+}
+
+type webhookTarget struct {
+    URL     string      `json:"url"`
+    Service interface{} `json:"service" di:"testService"`
+}
+
+type nestedWebhookJob struct {
+    Name   string         `json:"name"`
+    Target *webhookTarget `json:"target" di:"inject"`
+}
+
+func TestContainer_InjectJSON(t *testing.T) {
+    container := NewContainer()
+    testService := &testServiceImpl{name: "test"}
+    require.NoError(t, container.Register("testService", testService))
+
+    t.Run("data and services", func(t *testing.T) {
+        job := &webhookJob{}
+        err := container.InjectJSON([]byte(`{"name":"nightly","retries":3,"service":"from-payload"}`), job)
+        require.NoError(t, err)
+
+        assert.Equal(t, "nightly", job.Name)
+        assert.Equal(t, 3, job.Retries)
+        assert.Equal(t, testService, job.Service)
+    })
+
+    t.Run("nested service fields", func(t *testing.T) {
+        job := &nestedWebhookJob{}
+        payload := `{"name":"nightly","target":{"url":"https://example.com","service":"from-payload"}}`
+        require.NoError(t, container.InjectJSON([]byte(payload), job))
+
+        assert.Equal(t, "https://example.com", job.Target.URL)
+        assert.Equal(t, testService, job.Target.Service)
+    })
+
+    t.Run("target being injected", func(t *testing.T) {
+        job := &webhookJob{Name: "before"}
+        release, err := container.beginInjection(reflect.ValueOf(job).Pointer(), reflect.TypeOf(*job))
+        require.NoError(t, err)
+        defer release()
+
+        err = container.InjectJSON([]byte(`{"name":"nightly"}`), job)
+        assert.ErrorContains(t, err, "already being injected")
+        assert.Equal(t, "before", job.Name) // The payload was not decoded
+    })
+
+    t.Run("invalid json", func(t *testing.T) {
+        err := container.InjectJSON([]byte(`{"name":`), &webhookJob{})
+        assert.Error(t, err)
+    })
+
+    t.Run("non-pointer target", func(t *testing.T) {
+        err := container.InjectJSON([]byte(`{}`), webhookJob{})
+        assert.Error(t, err)
+    })
+
+    t.Run("nil pointer target", func(t *testing.T) {
+        var job *webhookJob
+        err := container.InjectJSON([]byte(`{}`), job)
+        assert.Error(t, err)
+    })
+}
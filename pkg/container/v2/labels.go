@@ -0,0 +1,60 @@
+package container
+
+import (
+    "context"
+    "runtime/pprof"
+)
+
+// Profiler label keys set while the container runs service code
+const (
+    LabelService = "di.service" // Qualifier or hook name
+    LabelPhase   = "di.phase"   // What the container is doing: build, warmup, start, stop, selftest
+)
+
+// withServiceLabels runs fn under pprof labels naming the service and phase,
+// so CPU profiles attribute provider and lifecycle work to specific services.
+// The labels are added to those of ctx, and fn's context becomes the
+// context of the work this goroutine does for the container, so builds
+// nested in fn keep its labels.
+func (c *Container) withServiceLabels(ctx context.Context, phase, service string, fn func(ctx context.Context) error) error {
+    var err error
+    pprof.Do(ctx, pprof.Labels(LabelService, service, LabelPhase, phase), func(ctx context.Context) {
+        leave := c.enterContext(ctx)
+        defer leave()
+        err = fn(ctx)
+    })
+    return err
+}
+
+// enterContext makes ctx the context of the work this goroutine does for
+// the container until the returned func is called
+func (c *Container) enterContext(ctx context.Context) func() {
+    gid := goroutineID()
+
+    c.resolvingMu.Lock()
+    defer c.resolvingMu.Unlock()
+    c.contexts[gid] = append(c.contexts[gid], ctx)
+
+    return func() {
+        c.resolvingMu.Lock()
+        defer c.resolvingMu.Unlock()
+
+        if stack := c.contexts[gid]; len(stack) > 1 {
+            c.contexts[gid] = stack[:len(stack)-1]
+        } else {
+            delete(c.contexts, gid)
+        }
+    }
+}
+
+// currentContext returns the context of the work this goroutine is doing
+// for the container, see ResolveContext, or context.Background
+func (c *Container) currentContext() context.Context {
+    c.resolvingMu.Lock()
+    defer c.resolvingMu.Unlock()
+
+    if stack := c.contexts[goroutineID()]; len(stack) > 0 {
+        return stack[len(stack)-1]
+    }
+    return context.Background()
+}
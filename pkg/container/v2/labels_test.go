@@ -0,0 +1,75 @@
+package container
+
+import (
+    "context"
+    "runtime/pprof"
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+// labelRecorder captures the profiler labels active when it runs
+type labelRecorder struct {
+    labels map[string]string
+}
+
+func (r *labelRecorder) capture(ctx context.Context) error {
+    r.labels = make(map[string]string)
+    pprof.ForLabels(ctx, func(key, value string) bool {
+        r.labels[key] = value
+        return true
+    })
+    return nil
+}
+
+func (r *labelRecorder) Warmup(ctx context.Context) error {
+    return r.capture(ctx)
+}
+
+func TestContainer_ProfilerLabels(t *testing.T) {
+    container := NewContainer()
+
+    warmer := &labelRecorder{}
+    require.NoError(t, container.Register("cache", warmer))
+
+    hook := &labelRecorder{}
+    container.Append(Hook{Name: "http", OnStart: hook.capture})
+
+    require.NoError(t, container.Start(context.Background()))
+    assert.Equal(t, map[string]string{LabelService: "cache", LabelPhase: "warmup"}, warmer.labels)
+    assert.Equal(t, map[string]string{LabelService: "http", LabelPhase: "start"}, hook.labels)
+}
+
+func TestWithServiceLabels(t *testing.T) {
+    recorder := &labelRecorder{}
+    require.NoError(t, NewContainer().withServiceLabels(context.Background(), "build", "templates", recorder.capture))
+    assert.Equal(t, "templates", recorder.labels[LabelService])
+    assert.Equal(t, "build", recorder.labels[LabelPhase])
+}
+
+func TestResolveContext_KeepsCallerLabels(t *testing.T) {
+    c := NewContainer()
+    outer, inner := &labelRecorder{}, &labelRecorder{}
+    require.NoError(t, c.RegisterFactory("templates", func(c *Container) (interface{}, error) {
+        return "templates", inner.capture(c.currentContext())
+    }))
+    require.NoError(t, c.RegisterFactory("renderer", func(c *Container) (interface{}, error) {
+        if _, err := c.Resolve("templates"); err != nil {
+            return nil, err
+        }
+        return "renderer", outer.capture(c.currentContext())
+    }))
+
+    ctx := pprof.WithLabels(context.Background(), pprof.Labels("request", "42"))
+    _, err := c.ResolveContext(ctx, "renderer")
+    require.NoError(t, err)
+
+    // The nested build replaces the service and phase, keeping the caller's labels
+    assert.Equal(t, map[string]string{"request": "42", LabelService: "renderer", LabelPhase: "build"}, outer.labels)
+    assert.Equal(t, map[string]string{"request": "42", LabelService: "templates", LabelPhase: "build"}, inner.labels)
+
+    c.resolvingMu.Lock()
+    defer c.resolvingMu.Unlock()
+    assert.Empty(t, c.contexts)
+}
@@ -0,0 +1,242 @@
+package container
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "sort"
+    "time"
+
+    "di-example/pkg/logger"
+)
+
+// Hook is a pair of callbacks invoked when the container starts and stops
+type Hook struct {
+    Name    string                          // Name used in logs and errors
+    OnStart func(ctx context.Context) error // Optional start callback
+    OnStop  func(ctx context.Context) error // Optional stop callback
+    Stage   int                             // Init stage; lower stages start first
+    service bool                            // Added for a Starter or Stopper service
+    module  string                          // Module that appended the hook, see ModuleUsage
+}
+
+// Append adds a lifecycle hook. Hooks start stage by stage, in the order they
+// were appended within a stage, and stop in reverse start order. A hook
+// appended while a module is installed is attributed to that module.
+func (c *Container) Append(hook Hook) {
+    c.mu.RLock()
+    hook.module = c.installing[goroutineID()]
+    c.mu.RUnlock()
+
+    c.lifecycleMu.Lock()
+    defer c.lifecycleMu.Unlock()
+
+    c.log.Debugw("Appending lifecycle hook", "hook", hook.Name)
+    c.hooks = append(c.hooks, hook)
+}
+
+// Start installs the enabled modules deferred by their enable key, then
+// brings the container up in phases: validate, construct, warmup and
+// finally the OnStart callback of every hook in order. If a hook fails, the
+// hooks that already started are stopped in reverse order and the start
+// error is returned. Phase durations are available through StartupReport.
+func (c *Container) Start(ctx context.Context) error {
+    // Module setup may append hooks, so it runs before lifecycleMu is taken
+    if err := c.installDeferredModules(); err != nil {
+        c.emit(Event{Kind: EventStartFailed, Err: err})
+        return err
+    }
+
+    c.lifecycleMu.Lock()
+    defer c.lifecycleMu.Unlock()
+
+    // Services built while starting inherit the labels of ctx
+    leave := c.enterContext(ctx)
+    defer leave()
+
+    c.log.Infow("Starting container", "hooks", len(c.hooks))
+    c.emit(Event{Kind: EventStarting})
+    if err := c.runStartPhases(ctx); err != nil {
+        c.emit(Event{Kind: EventStartFailed, Err: err})
+        return err
+    }
+
+    c.log.Info("Container started")
+    c.emit(Event{Kind: EventStarted})
+    return nil
+}
+
+// startHooksLocked runs pending start hooks of every stage in ascending stage
+// order; callers must hold lifecycleMu
+func (c *Container) startHooksLocked(ctx context.Context) error {
+    c.addServiceHooksLocked()
+    for _, stage := range c.hookStages() {
+        if err := c.startStageHooksLocked(ctx, stage); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// StartStage warms up the services and starts the hooks registered in a
+// single init stage, letting applications bring stages up one at a time.
+// Hooks and services that already started are skipped.
+func (c *Container) StartStage(ctx context.Context, stage int) error {
+    c.lifecycleMu.Lock()
+    defer c.lifecycleMu.Unlock()
+
+    c.log.Infow("Starting init stage", "stage", stage)
+    if err := c.warmupStage(ctx, stage); err != nil {
+        return err
+    }
+    c.addServiceHooksLocked()
+    return c.startStageHooksLocked(ctx, stage)
+}
+
+// startStageHooksLocked runs the pending start hooks of one stage, those of
+// Starter services first. On failure every started hook is stopped again.
+func (c *Container) startStageHooksLocked(ctx context.Context, stage int) error {
+    var order []int
+    for _, services := range []bool{true, false} {
+        for i, hook := range c.hooks {
+            if hook.service == services {
+                order = append(order, i)
+            }
+        }
+    }
+
+    for _, i := range order {
+        hook := c.hooks[i]
+        if hook.Stage != stage || c.hookStarted(i) {
+            continue
+        }
+
+        if hook.OnStart != nil {
+            c.log.Debugw("Running start hook",
+                "hook", hook.Name,
+                "stage", stage)
+            if err := c.withServiceLabels(ctx, "start", hook.Name, c.asModule(hook.module, hook.OnStart)); err != nil {
+                c.log.Errorw("Start hook failed",
+                    "hook", hook.Name,
+                    "stage", stage,
+                    "error", err)
+                startErr := fmt.Errorf("start hook %q failed: %w", hook.Name, err)
+                return errors.Join(startErr, c.stopLocked(ctx))
+            }
+        }
+        c.started = append(c.started, i)
+        c.trackLifecycle(hook.module, 1)
+    }
+    return nil
+}
+
+// hookStarted reports whether the hook at index i has been started
+func (c *Container) hookStarted(i int) bool {
+    for _, started := range c.started {
+        if started == i {
+            return true
+        }
+    }
+    return false
+}
+
+// hookStages returns the distinct stages of all hooks in ascending order
+func (c *Container) hookStages() []int {
+    seen := make(map[int]bool)
+    var stages []int
+    for _, hook := range c.hooks {
+        if !seen[hook.Stage] {
+            seen[hook.Stage] = true
+            stages = append(stages, hook.Stage)
+        }
+    }
+    sort.Ints(stages)
+    return stages
+}
+
+// Stop runs the OnStop callback of every started hook in reverse start
+// order, then cancels the workers started with Go and waits for them,
+// calls PreDestroy on the services implementing PreDestroyer and finally
+// closes the services implementing io.Closer, dependents first, see Close.
+// All hooks are attempted; their errors are joined.
+func (c *Container) Stop(ctx context.Context) error {
+    c.lifecycleMu.Lock()
+    defer c.lifecycleMu.Unlock()
+
+    return c.stopLocked(ctx)
+}
+
+// Shutdown is Stop, named for symmetry with the Stopper interface: started
+// services and hooks stop in reverse start order, so a service stops before
+// the services it depends on.
+func (c *Container) Shutdown(ctx context.Context) error {
+    return c.Stop(ctx)
+}
+
+// stopLocked stops started hooks; callers must hold lifecycleMu
+func (c *Container) stopLocked(ctx context.Context) error {
+    c.log.Infow("Stopping container", "hooks", len(c.started))
+
+    var errs []error
+    for len(c.started) > 0 {
+        hook := c.hooks[c.started[len(c.started)-1]]
+        c.started = c.started[:len(c.started)-1]
+        c.trackLifecycle(hook.module, -1)
+        if hook.OnStop == nil {
+            continue
+        }
+
+        c.log.Debugw("Running stop hook", "hook", hook.Name)
+        if err := c.withServiceLabels(ctx, "stop", hook.Name, c.asModule(hook.module, hook.OnStop)); err != nil {
+            c.log.Errorw("Stop hook failed",
+                "hook", hook.Name,
+                "error", err)
+            errs = append(errs, fmt.Errorf("stop hook %q failed: %w", hook.Name, err))
+        }
+    }
+
+    // Workers outlive the hooks that started them, so they stop last
+    if err := c.stopWorkers(ctx); err != nil {
+        c.log.Errorw("Workers did not stop", "error", err)
+        errs = append(errs, err)
+    }
+    if err := c.preDestroy(); err != nil {
+        errs = append(errs, err)
+    }
+    if err := c.closeLocked(); err != nil {
+        errs = append(errs, err)
+    }
+
+    c.log.Info("Container stopped")
+    err := errors.Join(errs...)
+    if err != nil {
+        c.emit(Event{Kind: EventStopFailed, Err: err})
+    } else {
+        c.emit(Event{Kind: EventStopped})
+    }
+    return err
+}
+
+// StopOnExit arranges for Stop to run, bounded by timeout, when the process
+// exits through logger.Exit or a Fatal log. The timeout also bounds the wait
+// for a Start or Stop in progress, so a Fatal log from a start hook, which
+// runs while Start holds the lifecycle lock, exits once it expires.
+func (c *Container) StopOnExit(timeout time.Duration) {
+    logger.OnExit(func() {
+        ctx, cancel := context.WithTimeout(context.Background(), timeout)
+        defer cancel()
+
+        done := make(chan struct{})
+        go func() {
+            defer close(done)
+            if err := c.Stop(ctx); err != nil {
+                c.log.Errorw("Container stop on exit failed", "error", err)
+            }
+        }()
+        select {
+        case <-done:
+        case <-ctx.Done():
+            c.log.Errorw("Container stop on exit timed out", "timeout", timeout)
+        }
+    })
+}
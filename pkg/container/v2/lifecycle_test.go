@@ -0,0 +1,182 @@
+package container
+
+import (
+    "context"
+    "errors"
+    "os"
+    "os/exec"
+    "testing"
+    "time"
+
+    "di-example/pkg/logger"
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+// recordingHook returns a hook that appends its start/stop events to calls
+func recordingHook(name string, calls *[]string) Hook {
+    return Hook{
+        Name: name,
+        OnStart: func(ctx context.Context) error {
+            *calls = append(*calls, "start:"+name)
+            return nil
+        },
+        OnStop: func(ctx context.Context) error {
+            *calls = append(*calls, "stop:"+name)
+            return nil
+        },
+    }
+}
+
+func TestContainer_StartStopOrder(t *testing.T) {
+    container := NewContainer()
+    var calls []string
+
+    container.Append(recordingHook("db", &calls))
+    container.Append(recordingHook("http", &calls))
+
+    require.NoError(t, container.Start(context.Background()))
+    require.NoError(t, container.Stop(context.Background()))
+
+    assert.Equal(t, []string{"start:db", "start:http", "stop:http", "stop:db"}, calls)
+}
+
+func TestContainer_StartFailureRollsBack(t *testing.T) {
+    container := NewContainer()
+    var calls []string
+
+    container.Append(recordingHook("db", &calls))
+    container.Append(Hook{
+        Name: "broken",
+        OnStart: func(ctx context.Context) error {
+            return errors.New("boom")
+        },
+        OnStop: func(ctx context.Context) error {
+            calls = append(calls, "stop:broken")
+            return nil
+        },
+    })
+
+    err := container.Start(context.Background())
+    assert.Error(t, err)
+    assert.Contains(t, err.Error(), "broken")
+
+    // Only hooks that started are stopped
+    assert.Equal(t, []string{"start:db", "stop:db"}, calls)
+}
+
+func TestContainer_StopWithoutStart(t *testing.T) {
+    container := NewContainer()
+    var calls []string
+    container.Append(recordingHook("db", &calls))
+
+    assert.NoError(t, container.Stop(context.Background()))
+    assert.Empty(t, calls)
+}
+
+func TestContainer_StopJoinsErrors(t *testing.T) {
+    container := NewContainer()
+    for _, name := range []string{"a", "b"} {
+        name := name
+        container.Append(Hook{
+            Name: name,
+            OnStop: func(ctx context.Context) error {
+                return errors.New(name + " failed")
+            },
+        })
+    }
+
+    require.NoError(t, container.Start(context.Background()))
+    err := container.Stop(context.Background())
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "a failed")
+    assert.Contains(t, err.Error(), "b failed")
+}
+
+func TestContainer_StopOnExit(t *testing.T) {
+    container := NewContainer()
+    var calls []string
+    container.Append(recordingHook("db", &calls))
+    require.NoError(t, container.Start(context.Background()))
+
+    container.StopOnExit(time.Second)
+    logger.RunExitHooks()
+
+    assert.Equal(t, []string{"start:db", "stop:db"}, calls)
+}
+
+func TestContainer_StopOnExitFatalInStartHook(t *testing.T) {
+    // The Fatal log exits the process, so it runs in a child test binary
+    if os.Getenv("DI_FATAL_IN_START_HOOK") == "1" {
+        container := NewContainer()
+        container.StopOnExit(100 * time.Millisecond)
+        container.Append(Hook{
+            Name: "db",
+            OnStart: func(ctx context.Context) error {
+                logger.Get().Fatal("cannot connect")
+                return nil
+            },
+        })
+        _ = container.Start(context.Background())
+        return
+    }
+
+    ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+    defer cancel()
+    cmd := exec.CommandContext(ctx, os.Args[0], "-test.run=^TestContainer_StopOnExitFatalInStartHook$")
+    cmd.Env = append(os.Environ(), "DI_FATAL_IN_START_HOOK=1")
+    err := cmd.Run()
+    require.NoError(t, ctx.Err(), "Fatal in a start hook did not exit")
+    var exitErr *exec.ExitError
+    require.ErrorAs(t, err, &exitErr)
+    assert.Equal(t, 1, exitErr.ExitCode())
+}
+
+func TestContainer_StartStages(t *testing.T) {
+    container := NewContainer()
+    var calls []string
+
+    transport := recordingHook("http", &calls)
+    transport.Stage = StageTransport
+    infrastructure := recordingHook("db", &calls)
+    infrastructure.Stage = StageInfrastructure
+    domain := recordingHook("billing", &calls)
+    domain.Stage = StageDomain
+
+    container.Append(transport)
+    container.Append(domain)
+    container.Append(infrastructure)
+
+    require.NoError(t, container.Start(context.Background()))
+    require.NoError(t, container.Stop(context.Background()))
+
+    assert.Equal(t, []string{
+        "start:db", "start:billing", "start:http",
+        "stop:http", "stop:billing", "stop:db",
+    }, calls)
+}
+
+func TestContainer_StartStage(t *testing.T) {
+    container := NewContainer()
+    var calls []string
+
+    domain := recordingHook("billing", &calls)
+    domain.Stage = StageDomain
+    container.Append(domain)
+    container.Append(recordingHook("db", &calls))
+
+    infraWarmer := &warmingService{}
+    domainWarmer := &warmingService{}
+    require.NoError(t, container.Register("cache", infraWarmer, InStage(StageInfrastructure)))
+    require.NoError(t, container.Register("pricing", domainWarmer, InStage(StageDomain)))
+
+    require.NoError(t, container.StartStage(context.Background(), StageInfrastructure))
+    assert.Equal(t, []string{"start:db"}, calls)
+    assert.True(t, infraWarmer.warmed)
+    assert.False(t, domainWarmer.warmed)
+
+    // A full Start only runs what has not started yet
+    require.NoError(t, container.Start(context.Background()))
+    assert.Equal(t, []string{"start:db", "start:billing"}, calls)
+    assert.True(t, domainWarmer.warmed)
+}
@@ -0,0 +1,158 @@
+package container
+
+import (
+    "context"
+    "fmt"
+    "reflect"
+)
+
+// WithLifetime selects how Register keeps a service. Singleton, the
+// default, registers the given instance. The other lifetimes take a
+// constructor instead of an instance, called whenever a new instance is
+// needed:
+//
+//	c.Register("request", newRequestContext, container.WithLifetime(container.Transient))
+//
+// Supported constructors are func() T, func() (T, error),
+// func(*Container) (T, error) and, for Scoped, func(*Scope) (T, error).
+func WithLifetime(lifetime Lifetime) RegisterOption {
+    return func(r *registration) {
+        r.lifetime = lifetime
+    }
+}
+
+// requestedLifetime returns the lifetime selected by opts
+func requestedLifetime(opts []RegisterOption) Lifetime {
+    reg := &registration{}
+    for _, opt := range opts {
+        opt(reg)
+    }
+    return reg.lifetime
+}
+
+// constructor builds an instance; scope is nil outside a scope
+type constructor func(c *Container, scope *Scope) (interface{}, error)
+
+var (
+    containerPtrType = reflect.TypeOf((*Container)(nil))
+    scopePtrType     = reflect.TypeOf((*Scope)(nil))
+    errorType        = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// adaptConstructor wraps a constructor function of any supported shape
+func adaptConstructor(qualifier string, fn interface{}, lifetime Lifetime) (constructor, error) {
+    fnValue := reflect.ValueOf(fn)
+    fnType := fnValue.Type()
+    if fnType.Kind() != reflect.Func {
+        return nil, fmt.Errorf("%s lifetime of %s requires a constructor function, got %v", lifetime, qualifier, fnType)
+    }
+
+    valid := fnType.NumIn() <= 1 && !fnType.IsVariadic() &&
+        (fnType.NumOut() == 1 || (fnType.NumOut() == 2 && fnType.Out(1) == errorType))
+    if valid && fnType.NumIn() == 1 {
+        valid = fnType.In(0) == containerPtrType || (fnType.In(0) == scopePtrType && lifetime == Scoped)
+    }
+    if !valid {
+        return nil, fmt.Errorf("unsupported constructor %v for %s lifetime of %s", fnType, lifetime, qualifier)
+    }
+
+    return func(c *Container, scope *Scope) (interface{}, error) {
+        var args []reflect.Value
+        if fnType.NumIn() == 1 {
+            if fnType.In(0) == scopePtrType {
+                args = []reflect.Value{reflect.ValueOf(scope)}
+            } else {
+                args = []reflect.Value{reflect.ValueOf(c)}
+            }
+        }
+
+        results := fnValue.Call(args)
+        if len(results) == 2 && !results[1].IsNil() {
+            return nil, results[1].Interface().(error)
+        }
+        return results[0].Interface(), nil
+    }, nil
+}
+
+// registerConstructor registers a constructor with a lifetime other than
+// Singleton
+func (c *Container) registerConstructor(qualifier string, fn interface{}, lifetime Lifetime, opts []RegisterOption) error {
+    build, err := adaptConstructor(qualifier, fn, lifetime)
+    if err != nil {
+        c.log.Errorw("Invalid constructor",
+            "qualifier", qualifier,
+            "lifetime", lifetime,
+            "error", err)
+        return err
+    }
+
+    // Matching by type uses the declared result instead of building one
+    declared := reflect.TypeOf(fn).Out(0)
+    opts = append(opts, func(r *registration) { r.declared = declared })
+
+    switch lifetime {
+    case Weak:
+        return c.RegisterWeak(qualifier, func() (interface{}, error) { return build(c, nil) }, opts...)
+    case Scoped:
+        return c.RegisterScoped(qualifier, func(s *Scope) (interface{}, error) { return build(c, s) }, opts...)
+    case Transient:
+        return c.registerTransient(qualifier, func() (interface{}, error) { return build(c, nil) }, opts)
+    }
+    return fmt.Errorf("unknown lifetime %v for %s", lifetime, qualifier)
+}
+
+// registerTransient registers a service built anew by every resolve
+func (c *Container) registerTransient(qualifier string, build func() (interface{}, error), opts []RegisterOption) error {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+
+    c.log.Infow("Registering transient service", "qualifier", qualifier)
+
+    reg := c.newRegistrationLocked(qualifier, opts)
+    if err := c.admitLocked(reg); err != nil {
+        return admitted(err)
+    }
+
+    c.transient[qualifier] = build
+    c.recordLocked(reg)
+    return nil
+}
+
+// resolveTransient builds a fresh instance of a transient service
+func (c *Container) resolveTransient(qualifier string, build func() (interface{}, error)) (interface{}, error) {
+    leave, err := c.enterResolution(qualifier)
+    if err != nil {
+        return nil, err
+    }
+    defer leave()
+
+    if err := c.waitForQualifier(c.currentContext(), qualifier); err != nil {
+        return nil, err
+    }
+
+    var service interface{}
+    err = c.withServiceLabels(c.currentContext(), "build", qualifier, func(context.Context) error {
+        var buildErr error
+        service, buildErr = build()
+        return buildErr
+    })
+    if err != nil {
+        c.log.Errorw("Transient constructor failed",
+            "qualifier", qualifier,
+            "error", err)
+        return nil, fmt.Errorf("failed to build transient service %s: %w", qualifier, err)
+    }
+    if service == nil {
+        return nil, fmt.Errorf("constructor for %s returned nil", qualifier)
+    }
+    if err := c.checkTypedNil(qualifier, service, "transient constructor result"); err != nil {
+        return nil, err
+    }
+    return c.decorateWeak(qualifier, service)
+}
+
+// lifetimeOf returns the lifetime of a registration, Singleton when unknown
+func (c *Container) lifetimeOf(qualifier string) Lifetime {
+    lifetime, _ := c.registrationSource(qualifier)
+    return lifetime
+}
@@ -0,0 +1,114 @@
+package container
+
+import (
+    "context"
+    "errors"
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+type requestContext struct {
+    id int
+}
+
+func TestRegister_Transient(t *testing.T) {
+    c := NewContainer()
+    next := 0
+    require.NoError(t, c.Register("request", func() *requestContext {
+        next++
+        return &requestContext{id: next}
+    }, WithLifetime(Transient)))
+
+    first, err := c.Resolve("request")
+    require.NoError(t, err)
+    second, err := c.Resolve("request")
+    require.NoError(t, err)
+    assert.NotSame(t, first, second)
+    assert.Equal(t, 2, second.(*requestContext).id)
+
+    descriptor, ok := c.Describe("request")
+    require.True(t, ok)
+    assert.Equal(t, "transient", descriptor.Lifetime.String())
+
+    // Start does not build transient services to warm them
+    require.NoError(t, c.Start(context.Background()))
+    assert.Equal(t, 2, next)
+}
+
+func TestRegister_SingletonKeepsInstance(t *testing.T) {
+    c := NewContainer()
+    handler := func() string { return "handler" } // Funcs are valid singleton services
+    require.NoError(t, c.Register("handler", handler, WithLifetime(Singleton)))
+
+    service, err := c.Resolve("handler")
+    require.NoError(t, err)
+    assert.Equal(t, "handler", service.(func() string)())
+}
+
+func TestRegister_ConstructorShapes(t *testing.T) {
+    c := NewContainer()
+    require.NoError(t, c.Register("dsn", "postgres://db"))
+
+    require.NoError(t, c.Register("withContainer", func(c *Container) (string, error) {
+        dsn, err := c.Resolve("dsn")
+        if err != nil {
+            return "", err
+        }
+        return "conn(" + dsn.(string) + ")", nil
+    }, WithLifetime(Transient)))
+    require.NoError(t, c.Register("failing", func() (string, error) {
+        return "", errors.New("refused")
+    }, WithLifetime(Transient)))
+    require.NoError(t, c.Register("weak", func() string { return "rebuilt" }, WithLifetime(Weak)))
+    require.NoError(t, c.Register("scoped", func(s *Scope) (*requestContext, error) {
+        return &requestContext{}, nil
+    }, WithLifetime(Scoped)))
+
+    service, err := c.Resolve("withContainer")
+    require.NoError(t, err)
+    assert.Equal(t, "conn(postgres://db)", service)
+
+    _, err = c.Resolve("failing")
+    assert.ErrorContains(t, err, "failed to build transient service failing: refused")
+
+    service, err = c.Resolve("weak")
+    require.NoError(t, err)
+    assert.Equal(t, "rebuilt", service)
+
+    scope := c.NewScope(context.Background())
+    a, err := scope.Resolve("scoped")
+    require.NoError(t, err)
+    b, err := scope.Resolve("scoped")
+    require.NoError(t, err)
+    assert.Same(t, a, b)
+}
+
+func TestRegister_InvalidConstructors(t *testing.T) {
+    c := NewContainer()
+
+    err := c.Register("instance", &requestContext{}, WithLifetime(Transient))
+    assert.ErrorContains(t, err, "requires a constructor function")
+
+    err = c.Register("params", func(a, b int) int { return a + b }, WithLifetime(Transient))
+    assert.ErrorContains(t, err, "unsupported constructor")
+
+    err = c.Register("scopeOutsideScoped", func(s *Scope) (int, error) { return 0, nil }, WithLifetime(Transient))
+    assert.ErrorContains(t, err, "unsupported constructor")
+
+    err = c.Register("secondNotError", func() (int, int) { return 0, 0 }, WithLifetime(Transient))
+    assert.ErrorContains(t, err, "unsupported constructor")
+}
+
+func TestRegister_TransientCycle(t *testing.T) {
+    c := NewContainer()
+    require.NoError(t, c.Register("loop", func(c *Container) (interface{}, error) {
+        return c.Resolve("loop")
+    }, WithLifetime(Transient)))
+
+    withinDeadline(t, func() {
+        _, err := c.Resolve("loop")
+        assert.ErrorContains(t, err, "loop -> loop")
+    })
+}
@@ -0,0 +1,303 @@
+package container
+
+import (
+    "errors"
+    "fmt"
+    "math"
+    "reflect"
+    "sort"
+    "sync"
+    "time"
+)
+
+// Limits protects a shared downstream behind a service by capping the calls
+// made through it. Calls wait up to MaxWait for a free slot and a rate
+// token, then fail with ErrBulkheadFull or ErrRateLimited.
+type Limits struct {
+    MaxConcurrent int           // Bulkhead: calls in progress at once, 0 for unlimited
+    Rate          float64       // Calls per second, 0 for unlimited
+    Burst         int           // Calls allowed at once on top of the rate, at least 1
+    MaxWait       time.Duration // How long a call may wait, 0 fails at once
+}
+
+// Errors of calls rejected by a Guard, wrapped in a *LimitError
+var (
+    ErrBulkheadFull = errors.New("bulkhead full")
+    ErrRateLimited  = errors.New("rate limited")
+)
+
+// LimitError is returned for a call rejected by a Guard. It unwraps to
+// ErrBulkheadFull or ErrRateLimited.
+type LimitError struct {
+    Qualifier string
+    Method    string
+    Err       error
+}
+
+func (e *LimitError) Error() string {
+    return fmt.Sprintf("call to %s.%s rejected: %v", e.Qualifier, e.Method, e.Err)
+}
+
+func (e *LimitError) Unwrap() error {
+    return e.Err
+}
+
+// Guard enforces Limits on the calls made through the proxies of one
+// service. A guard outlives the instances it protects, so the limits hold
+// across Swap and weak rebuilds.
+type Guard struct {
+    qualifier string
+    limits    Limits
+    slots     chan struct{} // Bulkhead slots, nil when unlimited
+    onReject  func(method string, err error)
+
+    mu     sync.Mutex // Guards tokens and last
+    tokens float64    // Rate tokens available
+    last   time.Time  // When tokens were last refilled
+    now    func() time.Time
+}
+
+// NewGuard returns a guard enforcing limits on the calls to qualifier
+func NewGuard(qualifier string, limits Limits) *Guard {
+    g := &Guard{qualifier: qualifier, limits: limits, now: time.Now}
+    if limits.MaxConcurrent > 0 {
+        g.slots = make(chan struct{}, limits.MaxConcurrent)
+    }
+    if g.limits.Burst < 1 {
+        g.limits.Burst = 1
+    }
+    g.tokens = float64(g.limits.Burst)
+    g.last = g.now()
+    return g
+}
+
+// Do runs call, the body of the proxied method, within the limits. It
+// returns a *LimitError without running call when the call is rejected.
+func (g *Guard) Do(method string, call func()) error {
+    deadline := g.now().Add(g.limits.MaxWait)
+    if err := g.takeToken(deadline); err != nil {
+        return g.reject(method, err)
+    }
+    if err := g.acquireSlot(deadline); err != nil {
+        return g.reject(method, err)
+    }
+    if g.slots != nil {
+        defer func() { <-g.slots }()
+    }
+    call()
+    return nil
+}
+
+// InFlight returns the number of calls in progress, 0 without a bulkhead
+func (g *Guard) InFlight() int {
+    return len(g.slots)
+}
+
+// takeToken waits for a rate token until deadline
+func (g *Guard) takeToken(deadline time.Time) error {
+    if g.limits.Rate <= 0 {
+        return nil
+    }
+    for {
+        g.mu.Lock()
+        now := g.now()
+        g.tokens = math.Min(float64(g.limits.Burst), g.tokens+now.Sub(g.last).Seconds()*g.limits.Rate)
+        g.last = now
+        if g.tokens >= 1 {
+            g.tokens--
+            g.mu.Unlock()
+            return nil
+        }
+        wait := time.Duration((1 - g.tokens) / g.limits.Rate * float64(time.Second))
+        g.mu.Unlock()
+
+        if now.Add(wait).After(deadline) {
+            return ErrRateLimited
+        }
+        time.Sleep(wait)
+    }
+}
+
+// acquireSlot waits for a bulkhead slot until deadline
+func (g *Guard) acquireSlot(deadline time.Time) error {
+    if g.slots == nil {
+        return nil
+    }
+    select {
+    case g.slots <- struct{}{}:
+        return nil
+    default:
+    }
+
+    wait := deadline.Sub(g.now())
+    if wait <= 0 {
+        return ErrBulkheadFull
+    }
+    timer := time.NewTimer(wait)
+    defer timer.Stop()
+    select {
+    case g.slots <- struct{}{}:
+        return nil
+    case <-timer.C:
+        return ErrBulkheadFull
+    }
+}
+
+func (g *Guard) reject(method string, err error) error {
+    if g.onReject != nil {
+        g.onReject(method, err)
+    }
+    return &LimitError{Qualifier: g.qualifier, Method: method, Err: err}
+}
+
+// GuardProxy wraps real in a proxy implementing T whose methods run through
+// guard.Do:
+//
+//	func (p *userServiceProxy) GetUser(id string) (user User, err error) {
+//	    if limitErr := p.guard.Do("GetUser", func() { user, err = p.real.GetUser(id) }); limitErr != nil {
+//	        return User{}, limitErr
+//	    }
+//	    return user, err
+//	}
+type GuardProxy[T any] func(real T, guard *Guard) T
+
+var (
+    guardProxiesMu sync.Mutex
+    guardProxies   = make(map[reflect.Type]func(real interface{}, guard *Guard) interface{}) // Interface type -> proxy
+)
+
+// RegisterGuardProxy installs the proxy that guards services implementing
+// the interface T. Go cannot implement interfaces at runtime, so guarded
+// interfaces need a proxy written once; function services are guarded
+// without one.
+func RegisterGuardProxy[T any](proxy GuardProxy[T]) {
+    guardProxiesMu.Lock()
+    defer guardProxiesMu.Unlock()
+    guardProxies[reflect.TypeOf((*T)(nil)).Elem()] = func(real interface{}, guard *Guard) interface{} {
+        return proxy(real.(T), guard)
+    }
+}
+
+// guardService wraps service in a proxy running its calls through guard
+func guardService(service interface{}, guard *Guard) (interface{}, error) {
+    serviceType := reflect.TypeOf(service)
+    if serviceType.Kind() == reflect.Func {
+        return guardFunc(service, guard), nil
+    }
+
+    guardProxiesMu.Lock()
+    var names []string
+    var proxy func(interface{}, *Guard) interface{}
+    for iface, candidate := range guardProxies {
+        if serviceType.Implements(iface) {
+            names = append(names, iface.String())
+            proxy = candidate
+        }
+    }
+    guardProxiesMu.Unlock()
+
+    switch len(names) {
+    case 0:
+        return nil, fmt.Errorf("no guard proxy for %v; install one with RegisterGuardProxy", serviceType)
+    case 1:
+        return proxy(service, guard), nil
+    }
+    sort.Strings(names)
+    return nil, fmt.Errorf("several guard proxies match %v: %v", serviceType, names)
+}
+
+// guardFunc wraps a function service with reflect.MakeFunc. A rejected call
+// returns the *LimitError as the function's last result if it is an error,
+// and panics with it otherwise.
+func guardFunc(service interface{}, guard *Guard) interface{} {
+    fn := reflect.ValueOf(service)
+    fnType := fn.Type()
+    return reflect.MakeFunc(fnType, func(args []reflect.Value) []reflect.Value {
+        var results []reflect.Value
+        err := guard.Do("call", func() {
+            if fnType.IsVariadic() {
+                results = fn.CallSlice(args)
+            } else {
+                results = fn.Call(args)
+            }
+        })
+        if err == nil {
+            return results
+        }
+
+        last := fnType.NumOut() - 1
+        if last < 0 || fnType.Out(last) != errorType {
+            panic(err)
+        }
+        results = make([]reflect.Value, fnType.NumOut())
+        for i := range results {
+            results[i] = reflect.Zero(fnType.Out(i))
+        }
+        results[last] = reflect.ValueOf(err)
+        return results
+    }).Interface()
+}
+
+// Limit returns a decorator guarding every member of a group with limits,
+// one Guard per member:
+//
+//	c.DecorateGroup("downstreams", container.Limit(container.Limits{MaxConcurrent: 10}))
+//
+// Interface members need a proxy installed with RegisterGuardProxy.
+//
+// Experimental: see Decorator.
+func Limit(limits Limits) Decorator {
+    var mu sync.Mutex
+    guards := make(map[string]*Guard)
+    return func(qualifier string, service interface{}) (interface{}, error) {
+        mu.Lock()
+        guard, ok := guards[qualifier]
+        if !ok {
+            guard = NewGuard(qualifier, limits)
+            guards[qualifier] = guard
+        }
+        mu.Unlock()
+        return guardService(service, guard)
+    }
+}
+
+// WithLimits guards the registered service with limits. Every instance
+// behind the qualifier, including swapped and rebuilt ones, shares one
+// Guard, which counts rejections in the di_limit_rejections metric.
+func WithLimits(limits Limits) RegisterOption {
+    return func(r *registration) {
+        r.limits = &limits
+    }
+}
+
+// guardLocked returns the guard of a registration with limits, creating it
+// on first use. Callers must hold c.mu, for reading at least.
+func (c *Container) guardLocked(reg *registration) *Guard {
+    c.guardsMu.Lock()
+    defer c.guardsMu.Unlock()
+    if guard, ok := c.guards[reg.qualifier]; ok {
+        return guard
+    }
+    guard := NewGuard(reg.qualifier, *reg.limits)
+    guard.onReject = func(method string, err error) {
+        c.log.Warnw("Call rejected by limits",
+            "qualifier", reg.qualifier,
+            "method", method,
+            "reason", err)
+        limit := "rate"
+        if errors.Is(err, ErrBulkheadFull) {
+            limit = "bulkhead"
+        }
+        c.metricsSink().IncCounter("di_limit_rejections", map[string]string{"qualifier": reg.qualifier, "limit": limit})
+    }
+    c.guards[reg.qualifier] = guard
+    return guard
+}
+
+// Guard returns the guard of a service registered WithLimits
+func (c *Container) Guard(qualifier string) (*Guard, bool) {
+    c.guardsMu.Lock()
+    defer c.guardsMu.Unlock()
+    guard, ok := c.guards[qualifier]
+    return guard, ok
+}
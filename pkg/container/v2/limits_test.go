@@ -0,0 +1,210 @@
+package container
+
+import (
+    "errors"
+    "testing"
+    "time"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+type priceService interface {
+    Quote(symbol string) (float64, error)
+}
+
+type fixedPrices struct{ price float64 }
+
+func (q fixedPrices) Quote(string) (float64, error) { return q.price, nil }
+
+// guardedPrices is the guard proxy of priceService
+type guardedPrices struct {
+    real  priceService
+    guard *Guard
+}
+
+func (p *guardedPrices) Quote(symbol string) (price float64, err error) {
+    if limitErr := p.guard.Do("Quote", func() { price, err = p.real.Quote(symbol) }); limitErr != nil {
+        return 0, limitErr
+    }
+    return price, err
+}
+
+func init() {
+    RegisterGuardProxy(func(real priceService, guard *Guard) priceService {
+        return &guardedPrices{real: real, guard: guard}
+    })
+}
+
+// holdCall starts a call through guard that blocks until release is closed
+func holdCall(t *testing.T, guard *Guard) (release func()) {
+    started, done := make(chan struct{}), make(chan struct{})
+    go func() {
+        assert.NoError(t, guard.Do("hold", func() {
+            close(started)
+            <-done
+        }))
+    }()
+    <-started
+    return func() { close(done) }
+}
+
+func TestGuard_Bulkhead(t *testing.T) {
+    guard := NewGuard("db", Limits{MaxConcurrent: 1})
+    release := holdCall(t, guard)
+    assert.Equal(t, 1, guard.InFlight())
+
+    ran := false
+    err := guard.Do("Query", func() { ran = true })
+    assert.ErrorIs(t, err, ErrBulkheadFull)
+    assert.False(t, ran)
+
+    var limitErr *LimitError
+    require.ErrorAs(t, err, &limitErr)
+    assert.Equal(t, "db", limitErr.Qualifier)
+    assert.Equal(t, "Query", limitErr.Method)
+    assert.EqualError(t, err, "call to db.Query rejected: bulkhead full")
+
+    release()
+    assert.Eventually(t, func() bool { return guard.InFlight() == 0 }, time.Second, time.Millisecond)
+    assert.NoError(t, guard.Do("Query", func() {}))
+}
+
+func TestGuard_BulkheadWaitsForSlot(t *testing.T) {
+    guard := NewGuard("db", Limits{MaxConcurrent: 1, MaxWait: time.Second})
+    release := holdCall(t, guard)
+    time.AfterFunc(10*time.Millisecond, release)
+
+    assert.NoError(t, guard.Do("Query", func() {}))
+}
+
+func TestGuard_Rate(t *testing.T) {
+    now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+    guard := NewGuard("api", Limits{Rate: 1, Burst: 2})
+    guard.now = func() time.Time { return now }
+    guard.last = now
+
+    assert.NoError(t, guard.Do("Get", func() {}))
+    assert.NoError(t, guard.Do("Get", func() {}))
+    assert.ErrorIs(t, guard.Do("Get", func() {}), ErrRateLimited)
+
+    now = now.Add(time.Second)
+    assert.NoError(t, guard.Do("Get", func() {}))
+    assert.ErrorIs(t, guard.Do("Get", func() {}), ErrRateLimited)
+}
+
+func TestGuard_RateWaitsForToken(t *testing.T) {
+    guard := NewGuard("api", Limits{Rate: 100, MaxWait: time.Second})
+
+    assert.NoError(t, guard.Do("Get", func() {}))
+    assert.NoError(t, guard.Do("Get", func() {}))
+}
+
+func TestContainer_WithLimitsFunc(t *testing.T) {
+    metrics := newRecordingMetrics()
+    container := NewContainer(WithMetrics(metrics))
+
+    release := make(chan struct{})
+    charge := func(amount int) error {
+        <-release
+        return nil
+    }
+    require.NoError(t, container.Register("charge", charge, WithLimits(Limits{MaxConcurrent: 1})))
+
+    service, err := container.Resolve("charge")
+    require.NoError(t, err)
+    guarded := service.(func(int) error)
+
+    done := make(chan error)
+    go func() { done <- guarded(10) }()
+    guard, ok := container.Guard("charge")
+    require.True(t, ok)
+    require.Eventually(t, func() bool { return guard.InFlight() == 1 }, time.Second, time.Millisecond)
+
+    err = guarded(20)
+    assert.ErrorIs(t, err, ErrBulkheadFull)
+    assert.Equal(t, 1, metrics.counters["di_limit_rejections"])
+
+    close(release)
+    assert.NoError(t, <-done)
+}
+
+func TestContainer_WithLimitsFuncWithoutError(t *testing.T) {
+    container := NewContainer()
+    require.NoError(t, container.Register("next", func() int { return 1 }, WithLimits(Limits{Rate: 0.001})))
+
+    service, err := container.Resolve("next")
+    require.NoError(t, err)
+    next := service.(func() int)
+
+    assert.Equal(t, 1, next())
+    assert.PanicsWithError(t, "call to next.call rejected: rate limited", func() { next() })
+}
+
+func TestContainer_WithLimitsInterface(t *testing.T) {
+    container := NewContainer()
+    require.NoError(t, container.Register("quotes", fixedPrices{price: 42}, WithLimits(Limits{Rate: 0.001})))
+
+    quotes, err := ResolveAs[priceService](container, "quotes")
+    require.NoError(t, err)
+    assert.IsType(t, &guardedPrices{}, quotes)
+
+    price, err := quotes.Quote("ACME")
+    require.NoError(t, err)
+    assert.Equal(t, 42.0, price)
+
+    _, err = quotes.Quote("ACME")
+    assert.ErrorIs(t, err, ErrRateLimited)
+}
+
+func TestContainer_WithLimitsSurvivesSwap(t *testing.T) {
+    container := NewContainer()
+    require.NoError(t, container.Register("quotes", fixedPrices{price: 1}, WithLimits(Limits{Rate: 0.001})))
+
+    quotes, err := ResolveAs[priceService](container, "quotes")
+    require.NoError(t, err)
+    _, err = quotes.Quote("ACME")
+    require.NoError(t, err)
+
+    _, err = container.Swap("quotes", fixedPrices{price: 2})
+    require.NoError(t, err)
+    quotes, err = ResolveAs[priceService](container, "quotes")
+    require.NoError(t, err)
+
+    // The swapped instance shares the guard whose token was spent
+    _, err = quotes.Quote("ACME")
+    assert.ErrorIs(t, err, ErrRateLimited)
+}
+
+func TestContainer_WithLimitsWithoutProxy(t *testing.T) {
+    container := NewContainer()
+
+    err := container.Register("name", "alice", WithLimits(Limits{MaxConcurrent: 1}))
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "failed to apply limits to name")
+    assert.Contains(t, err.Error(), "no guard proxy for string")
+}
+
+func TestLimit_DecoratesGroup(t *testing.T) {
+    container := NewContainer()
+    require.NoError(t, container.DecorateGroup("downstreams", Limit(Limits{Rate: 0.001})))
+    require.NoError(t, container.Register("stocks", fixedPrices{price: 1}, InGroup("downstreams")))
+    require.NoError(t, container.Register("bonds", fixedPrices{price: 2}, InGroup("downstreams")))
+
+    stocks, err := ResolveAs[priceService](container, "stocks")
+    require.NoError(t, err)
+    bonds, err := ResolveAs[priceService](container, "bonds")
+    require.NoError(t, err)
+
+    // Each member has its own guard
+    _, err = stocks.Quote("ACME")
+    require.NoError(t, err)
+    _, err = bonds.Quote("T10")
+    require.NoError(t, err)
+
+    _, err = stocks.Quote("ACME")
+    var limitErr *LimitError
+    require.True(t, errors.As(err, &limitErr))
+    assert.Equal(t, "stocks", limitErr.Qualifier)
+    assert.Equal(t, "Quote", limitErr.Method)
+}
@@ -0,0 +1,92 @@
+package container
+
+import (
+    "fmt"
+    "sort"
+    "strings"
+    "sync"
+)
+
+// Reference is a di tag qualifier found in a compiled package. Code generated
+// by digen declares the references of a package from its init function, so
+// the manifest covers every package linked into the binary.
+type Reference struct {
+    Qualifier string // Qualifier after applying any Inject marker prefix
+    Site      string // Struct field holding the tag, e.g. "models.Injectable.UserService"
+    Optional  bool   // Declared optional through an Inject marker or Optional[T]
+}
+
+var (
+    manifestMu sync.Mutex  // Guards manifest
+    manifest   []Reference // References declared by generated code
+)
+
+// DeclareReferences adds references to the binary manifest checked by Build
+func DeclareReferences(refs ...Reference) {
+    manifestMu.Lock()
+    defer manifestMu.Unlock()
+    manifest = append(manifest, refs...)
+}
+
+// Manifest returns every reference declared in the binary, sorted by site
+func Manifest() []Reference {
+    manifestMu.Lock()
+    defer manifestMu.Unlock()
+
+    refs := append([]Reference(nil), manifest...)
+    sort.Slice(refs, func(a, b int) bool { return refs[a].Site < refs[b].Site })
+    return refs
+}
+
+// Build installs the enabled modules deferred by their enable key, checks
+// that every required qualifier in the manifest is registered, runs
+// Validate and constructs the providers in dependency order, failing fast
+// before Start. All missing qualifiers are reported together.
+func (c *Container) Build() error {
+    c.log.Info("Building container")
+
+    if err := c.installDeferredModules(); err != nil {
+        c.log.Errorw("Container build failed", "error", err)
+        return err
+    }
+    if err := c.checkManifest(); err != nil {
+        c.log.Errorw("Container build failed", "error", err)
+        return err
+    }
+    if err := c.Validate(); err != nil {
+        c.log.Errorw("Container build failed", "error", err)
+        return err
+    }
+    if err := c.constructProviders(); err != nil {
+        c.log.Errorw("Container build failed", "error", err)
+        return err
+    }
+
+    c.log.Info("Container built")
+    return nil
+}
+
+// checkManifest lists required references whose qualifier is not registered
+func (c *Container) checkManifest() error {
+    var missing []string
+    for _, ref := range Manifest() {
+        if ref.Optional {
+            continue
+        }
+        site := ref.Site
+        qualifier := c.renamed(ref.Qualifier, func() string { return site })
+
+        c.mu.RLock()
+        _, registered := c.regs[qualifier]
+        c.mu.RUnlock()
+        if !registered {
+            missing = append(missing, fmt.Sprintf("%s (%s)", ref.Qualifier, ref.Site))
+        }
+    }
+
+    if len(missing) > 0 {
+        return fmt.Errorf("%d referenced qualifiers are not registered: %s",
+            len(missing), strings.Join(missing, ", "))
+    }
+    return nil
+}
@@ -0,0 +1,67 @@
+package container
+
+import (
+    "errors"
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+// withManifest replaces the binary manifest for the duration of a test
+func withManifest(t *testing.T, refs ...Reference) {
+    manifestMu.Lock()
+    saved := manifest
+    manifest = nil
+    manifestMu.Unlock()
+
+    DeclareReferences(refs...)
+    t.Cleanup(func() {
+        manifestMu.Lock()
+        manifest = saved
+        manifestMu.Unlock()
+    })
+}
+
+func TestContainer_BuildManifest(t *testing.T) {
+    withManifest(t,
+        Reference{Qualifier: "users", Site: "app.Handler.Users"},
+        Reference{Qualifier: "mailer", Site: "app.Handler.Mailer"},
+        Reference{Qualifier: "cache", Site: "app.Handler.Cache", Optional: true},
+        Reference{Qualifier: "oldBilling", Site: "app.Handler.Billing"},
+    )
+
+    container := NewContainer()
+    require.NoError(t, container.Register("users", &testServiceImpl{name: "users"}))
+    require.NoError(t, container.Register("billing", &testServiceImpl{name: "billing"}))
+    container.Rename("oldBilling", "billing")
+
+    err := container.Build()
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "1 referenced qualifiers")
+    assert.Contains(t, err.Error(), "mailer (app.Handler.Mailer)")
+    assert.NotContains(t, err.Error(), "cache")
+
+    require.NoError(t, container.Register("mailer", &testServiceImpl{name: "mailer"}))
+    assert.NoError(t, container.Build())
+}
+
+func TestContainer_BuildRunsValidators(t *testing.T) {
+    withManifest(t)
+
+    container := NewContainer()
+    container.validators = append(container.validators, func() error {
+        return errors.New("invalid graph")
+    })
+    assert.EqualError(t, container.Build(), "invalid graph")
+}
+
+func TestManifest_SortedBySite(t *testing.T) {
+    withManifest(t,
+        Reference{Qualifier: "b", Site: "p.T.B"},
+        Reference{Qualifier: "a", Site: "p.T.A"},
+    )
+    refs := Manifest()
+    require.Len(t, refs, 2)
+    assert.Equal(t, "p.T.A", refs[0].Site)
+}
@@ -0,0 +1,163 @@
+package container
+
+import (
+    "errors"
+    "fmt"
+    "reflect"
+    "unicode"
+    "unicode/utf8"
+)
+
+// InjectMethodPrefix starts the names of the methods InjectStruct calls
+// once the fields of a target are set, passing services resolved like the
+// parameters of Invoke: a *Container receives the container, a struct with
+// di tagged fields is filled by qualifier and anything else is resolved by
+// type. It suits types that keep their dependencies unexported:
+//
+//	type OrderService struct{ repo Repository }
+//
+//	func (s *OrderService) InjectRepository(repo Repository) { s.repo = repo }
+//
+// Methods of the pointer's method set count. They take at least one
+// parameter, return nothing or an error and run in name order, before
+// PostConstruct. WithInjectMethods adds further naming conventions.
+// ReinjectStruct does not call them again.
+const InjectMethodPrefix = "Inject"
+
+// isInjectMethod is the default convention: InjectMethodPrefix followed by
+// an uppercase letter, such as InjectRepository
+func isInjectMethod(name string) bool {
+    if len(name) <= len(InjectMethodPrefix) || name[:len(InjectMethodPrefix)] != InjectMethodPrefix {
+        return false
+    }
+    first, _ := utf8.DecodeRuneInString(name[len(InjectMethodPrefix):])
+    return unicode.IsUpper(first)
+}
+
+// WithInjectMethods makes InjectStruct also call the methods whose names
+// match, such as setters:
+//
+//	container.WithInjectMethods(func(name string) bool {
+//	    return strings.HasPrefix(name, "Set")
+//	})
+//
+// See InjectMethodPrefix for the methods it calls.
+func WithInjectMethods(match func(name string) bool) Option {
+    return func(o *containerOptions) {
+        if match != nil {
+            o.methodConventions = append(o.methodConventions, match)
+        }
+    }
+}
+
+// injectMethodsOf returns the injection methods of structType in name
+// order, and an error for every matching method with the wrong signature
+func (c *Container) injectMethodsOf(structType reflect.Type) ([]reflect.Method, []error) {
+    var methods []reflect.Method
+    var errs []error
+    ptrType := reflect.PointerTo(structType)
+    for i := 0; i < ptrType.NumMethod(); i++ {
+        method := ptrType.Method(i)
+        if !c.isInjectMethodName(method.Name) {
+            continue
+        }
+        methodType := method.Type // The receiver is the first parameter
+        switch {
+        case methodType.NumIn() < 2:
+            errs = append(errs, fmt.Errorf("method %s of %v has no parameters to inject", method.Name, structType))
+        case methodType.IsVariadic():
+            errs = append(errs, fmt.Errorf("method %s of %v is variadic", method.Name, structType))
+        case methodType.NumOut() > 1 || (methodType.NumOut() == 1 && methodType.Out(0) != errorType):
+            errs = append(errs, fmt.Errorf("method %s of %v must return nothing or an error", method.Name, structType))
+        default:
+            methods = append(methods, method)
+        }
+    }
+    return methods, errs
+}
+
+// isInjectMethodName reports whether a method is named by a convention
+func (c *Container) isInjectMethodName(name string) bool {
+    if isInjectMethod(name) {
+        return true
+    }
+    for _, match := range c.methodConventions {
+        if match(name) {
+            return true
+        }
+    }
+    return false
+}
+
+// callInjectMethods calls the injection methods of the target. Every method
+// is attempted; the errors are returned together.
+func (c *Container) callInjectMethods(in *injection, targetValue reflect.Value, plan *injectionPlan) error {
+    targetType := targetValue.Type()
+    errs := append([]error(nil), plan.methodErrs...)
+    for _, method := range plan.methods {
+        if err := c.callInjectMethod(in, targetValue.Addr(), targetType, method); err != nil {
+            errs = append(errs, err)
+        }
+    }
+    if len(errs) == 0 {
+        return nil
+    }
+    c.log.Errorw("Method injection failed",
+        "structType", targetType,
+        "errors", len(errs))
+    return fmt.Errorf("failed to call %d injection methods of %v: %w", len(errs), targetType, errors.Join(errs...))
+}
+
+// callInjectMethod resolves the parameters of method and calls it
+func (c *Container) callInjectMethod(in *injection, receiver reflect.Value, targetType reflect.Type, method reflect.Method) error {
+    methodType := method.Type
+    args := []reflect.Value{receiver}
+    for i := 1; i < methodType.NumIn(); i++ {
+        arg, err := c.methodArgument(in, methodType.In(i))
+        if err != nil {
+            return fmt.Errorf("method %s of %v: parameter %d (%v): %w", method.Name, targetType, i-1, methodType.In(i), err)
+        }
+        args = append(args, arg)
+    }
+
+    c.log.Debugw("Calling injection method",
+        "structType", targetType,
+        "method", method.Name)
+    results := method.Func.Call(args)
+    if len(results) == 1 {
+        if err, _ := results[0].Interface().(error); err != nil {
+            return fmt.Errorf("method %s of %v failed: %w", method.Name, targetType, err)
+        }
+    }
+    return nil
+}
+
+// methodArgument resolves a parameter of an injection method like
+// invokeArgument, resolving the matched qualifier the way the fields of
+// the injection are
+func (c *Container) methodArgument(in *injection, paramType reflect.Type) (reflect.Value, error) {
+    if paramType == containerPtrType || isTaggedStruct(paramType) {
+        return c.invokeArgument(paramType)
+    }
+
+    qualifier, err := c.qualifierForType(paramType)
+    if err != nil {
+        return reflect.Value{}, err
+    }
+    if qualifier == "" {
+        return reflect.Value{}, &ServiceNotFoundError{Type: paramType}
+    }
+    service, err := in.resolve(qualifier)
+    if err != nil {
+        return reflect.Value{}, err
+    }
+    if !reflect.TypeOf(service).AssignableTo(paramType) {
+        return reflect.Value{}, &TypeMismatchError{
+            Qualifier: qualifier,
+            Type:      reflect.TypeOf(service),
+            Want:      paramType,
+            Detail:    mismatchDetail(reflect.TypeOf(service), paramType),
+        }
+    }
+    return reflect.ValueOf(service), nil
+}
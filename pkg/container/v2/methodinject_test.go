@@ -0,0 +1,132 @@
+package container
+
+import (
+    "errors"
+    "strings"
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+// methodTarget keeps its dependencies unexported
+type methodTarget struct {
+    Name    TestService `di:"name"`
+    db      TestService
+    options methodOptions
+    c       *Container
+    calls   []string
+}
+
+type methodOptions struct {
+    Logger TestService `di:"logger"`
+}
+
+func (m *methodTarget) InjectDB(db TestService) {
+    m.db = db
+    m.calls = append(m.calls, "InjectDB")
+}
+
+func (m *methodTarget) InjectOptions(options methodOptions, c *Container) error {
+    m.options, m.c = options, c
+    m.calls = append(m.calls, "InjectOptions")
+    return nil
+}
+
+func (m *methodTarget) PostConstruct() error {
+    m.calls = append(m.calls, "PostConstruct")
+    return nil
+}
+
+func (m *methodTarget) Injected() bool { return m.db != nil } // Not an injection method
+
+func TestInjectStruct_Methods(t *testing.T) {
+    c := NewContainer()
+    require.NoError(t, c.Register("db", &testServiceImpl{}))
+    require.NoError(t, c.Register("logger", &testServiceImpl{}))
+    require.NoError(t, c.Bind((*TestService)(nil), "db"))
+    require.NoError(t, c.Register("name", &testServiceImpl{}))
+
+    target := &methodTarget{}
+    require.NoError(t, c.InjectStruct(target))
+    assert.NotNil(t, target.Name)
+    assert.True(t, target.Injected())
+    assert.NotNil(t, target.options.Logger)
+    assert.Same(t, c, target.c)
+    assert.Equal(t, []string{"InjectDB", "InjectOptions", "PostConstruct"}, target.calls)
+
+    // Reinjection leaves the methods alone
+    _, err := c.ReinjectStruct(target)
+    require.NoError(t, err)
+    assert.Len(t, target.calls, 3)
+}
+
+type failingMethods struct {
+    ready bool
+}
+
+func (f *failingMethods) InjectAudit(audit TestService) {}
+
+func (f *failingMethods) InjectClock(n int) error {
+    return errors.New("no clock")
+}
+
+func (f *failingMethods) InjectNothing() {}
+
+func (f *failingMethods) InjectTwice(n int) (int, error) { return n, nil }
+
+func (f *failingMethods) PostConstruct() error {
+    f.ready = true
+    return nil
+}
+
+func TestInjectStruct_MethodErrors(t *testing.T) {
+    c := NewContainer()
+    require.NoError(t, c.Register("count", 3))
+
+    target := &failingMethods{}
+    err := c.InjectStruct(target)
+    require.Error(t, err)
+    assert.False(t, target.ready)
+    assert.Contains(t, err.Error(), "failed to call 4 injection methods of container.failingMethods")
+    assert.Contains(t, err.Error(), "method InjectNothing of container.failingMethods has no parameters to inject")
+    assert.Contains(t, err.Error(), "method InjectTwice of container.failingMethods must return nothing or an error")
+    assert.Contains(t, err.Error(), "method InjectAudit of container.failingMethods: parameter 0 (container.TestService)")
+    assert.Contains(t, err.Error(), "method InjectClock of container.failingMethods failed: no clock")
+}
+
+type setterTarget struct {
+    db TestService
+}
+
+func (s *setterTarget) SetDB(db TestService) { s.db = db }
+
+func TestInjectStruct_WithInjectMethods(t *testing.T) {
+    c := NewContainer(WithInjectMethods(func(name string) bool {
+        return strings.HasPrefix(name, "Set")
+    }))
+    require.NoError(t, c.Register("db", &testServiceImpl{}))
+
+    target := &setterTarget{}
+    require.NoError(t, c.InjectStruct(target))
+    assert.NotNil(t, target.db)
+
+    // Without the convention setters are left alone
+    target = &setterTarget{}
+    c = NewContainer()
+    require.NoError(t, c.Register("db", &testServiceImpl{}))
+    require.NoError(t, c.InjectStruct(target))
+    assert.Nil(t, target.db)
+}
+
+func TestIsInjectMethod(t *testing.T) {
+    for name, want := range map[string]bool{
+        "InjectDB":     true,
+        "InjectLogger": true,
+        "Inject":       false,
+        "Injected":     false,
+        "Setup":        false,
+    } {
+        assert.Equal(t, want, isInjectMethod(name), name)
+    }
+}
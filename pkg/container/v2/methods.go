@@ -0,0 +1,54 @@
+package container
+
+import (
+    "fmt"
+    "reflect"
+)
+
+// RegisterMethods registers every exported method of facade as a separate
+// function service whose qualifier is the method name. It suits
+// handler-per-method routing, where each route resolves one function:
+//
+//	type UserHandlers struct{ ... }
+//	func (h *UserHandlers) GetUser(w http.ResponseWriter, r *http.Request) { ... }
+//
+//	names, err := container.RegisterMethods(c, &UserHandlers{})
+//	handler, _ := c.Resolve("GetUser") // func(http.ResponseWriter, *http.Request)
+//
+// The methods of T's method set are used, so pass a pointer to include
+// pointer-receiver methods. Nothing is registered if any qualifier is taken.
+// It returns the registered qualifiers in method name order.
+func RegisterMethods[T any](c *Container, facade T, opts ...RegisterOption) ([]string, error) {
+    facadeValue := reflect.ValueOf(facade)
+    if !facadeValue.IsValid() || isTypedNil(facade) {
+        return nil, fmt.Errorf("cannot register methods of nil %v", reflect.TypeOf((*T)(nil)).Elem())
+    }
+
+    facadeType := facadeValue.Type()
+    if facadeType.NumMethod() == 0 {
+        return nil, fmt.Errorf("%v has no exported methods to register", facadeType)
+    }
+
+    // Refuse up front so a conflict does not leave half the facade registered
+    for i := 0; i < facadeType.NumMethod(); i++ {
+        name := facadeType.Method(i).Name
+        if _, taken := c.Describe(name); taken {
+            return nil, fmt.Errorf("cannot register method %s of %v: %w",
+                name, facadeType, &DuplicateRegistrationError{Qualifier: name})
+        }
+    }
+
+    var names []string
+    for i := 0; i < facadeType.NumMethod(); i++ {
+        name := facadeType.Method(i).Name
+        if err := c.Register(name, facadeValue.Method(i).Interface(), opts...); err != nil {
+            return names, fmt.Errorf("failed to register method %s of %v: %w", name, facadeType, err)
+        }
+        names = append(names, name)
+    }
+
+    c.log.Infow("Registered facade methods",
+        "facade", facadeType,
+        "methods", names)
+    return names, nil
+}
@@ -0,0 +1,63 @@
+package container
+
+import (
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+type userHandlers struct {
+    greeting string
+}
+
+func (h *userHandlers) GetUser(id int) string { return h.greeting + " user" }
+
+func (h *userHandlers) DeleteUser(id int) error { return nil }
+
+func (h userHandlers) Describe() string { return "user handlers" }
+
+func (h *userHandlers) internal() {}
+
+type routeTable struct {
+    GetUser func(int) string `di:"GetUser"`
+}
+
+func TestRegisterMethods(t *testing.T) {
+    container := NewContainer()
+    names, err := RegisterMethods(container, &userHandlers{greeting: "hello"}, InGroup("routes"))
+    require.NoError(t, err)
+    assert.Equal(t, []string{"DeleteUser", "Describe", "GetUser"}, names)
+
+    // Method values keep their receiver
+    routes := &routeTable{}
+    require.NoError(t, container.InjectStruct(routes))
+    assert.Equal(t, "hello user", routes.GetUser(1))
+
+    members, err := container.ResolveGroup("routes")
+    require.NoError(t, err)
+    assert.Len(t, members, 3)
+}
+
+func TestRegisterMethods_ValueReceiver(t *testing.T) {
+    container := NewContainer()
+    names, err := RegisterMethods(container, userHandlers{})
+    require.NoError(t, err)
+    assert.Equal(t, []string{"Describe"}, names)
+}
+
+func TestRegisterMethods_Errors(t *testing.T) {
+    container := NewContainer()
+    var nilHandlers *userHandlers
+    _, err := RegisterMethods(container, nilHandlers)
+    assert.ErrorContains(t, err, "nil")
+
+    _, err = RegisterMethods(container, struct{}{})
+    assert.ErrorContains(t, err, "no exported methods")
+
+    require.NoError(t, container.Register("GetUser", "taken"))
+    _, err = RegisterMethods(container, &userHandlers{})
+    assert.ErrorContains(t, err, "GetUser")
+    _, ok := container.Describe("DeleteUser")
+    assert.False(t, ok)
+}
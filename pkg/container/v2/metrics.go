@@ -0,0 +1,39 @@
+package container
+
+import "time"
+
+// MetricsSink receives measurements emitted by the container
+type MetricsSink interface {
+    // ObserveDuration records how long the named operation took
+    ObserveDuration(name string, d time.Duration, labels map[string]string)
+    // SetGauge records the current value of the named quantity
+    SetGauge(name string, value float64, labels map[string]string)
+    // IncCounter increments the named counter by one
+    IncCounter(name string, labels map[string]string)
+}
+
+// nopMetrics discards all measurements
+type nopMetrics struct{}
+
+func (nopMetrics) ObserveDuration(string, time.Duration, map[string]string) {}
+func (nopMetrics) SetGauge(string, float64, map[string]string)             {}
+func (nopMetrics) IncCounter(string, map[string]string)                    {}
+
+// SetMetricsSink installs the sink that receives container measurements.
+// A nil sink disables metrics.
+func (c *Container) SetMetricsSink(sink MetricsSink) {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+
+    if sink == nil {
+        sink = nopMetrics{}
+    }
+    c.metrics = sink
+}
+
+// metricsSink returns the current sink under the read lock
+func (c *Container) metricsSink() MetricsSink {
+    c.mu.RLock()
+    defer c.mu.RUnlock()
+    return c.metrics
+}
@@ -0,0 +1,58 @@
+package container
+
+import (
+    "fmt"
+    "reflect"
+    "strings"
+)
+
+// mismatchDetail explains why actual does not implement the interface
+// want, listing the missing methods. When actual implements part of an
+// interface embedding others, it suggests composing the interface from
+// several services with a digen //di:compose annotation. It returns ""
+// when want is not an interface.
+func mismatchDetail(actual, want reflect.Type) string {
+    if want.Kind() != reflect.Interface {
+        return ""
+    }
+
+    var missing []string
+    for i := 0; i < want.NumMethod(); i++ {
+        method := want.Method(i)
+        candidate, ok := actual.MethodByName(method.Name)
+        if !ok {
+            missing = append(missing, method.Name)
+            continue
+        }
+        // Methods of concrete types include the receiver
+        candidateType := candidate.Type
+        if actual.Kind() != reflect.Interface {
+            candidateType = withoutReceiver(candidateType)
+        }
+        if candidateType != method.Type {
+            missing = append(missing, fmt.Sprintf("%s (has %v, want %v)", method.Name, candidateType, method.Type))
+        }
+    }
+    if len(missing) == 0 {
+        return ""
+    }
+
+    detail := fmt.Sprintf(": missing methods %s", strings.Join(missing, ", "))
+    if len(missing) < want.NumMethod() {
+        detail += fmt.Sprintf("; to combine services implementing parts of %v, annotate it with //di:compose", want)
+    }
+    return detail
+}
+
+// withoutReceiver drops the receiver parameter of a method expression type
+func withoutReceiver(method reflect.Type) reflect.Type {
+    in := make([]reflect.Type, 0, method.NumIn()-1)
+    for i := 1; i < method.NumIn(); i++ {
+        in = append(in, method.In(i))
+    }
+    out := make([]reflect.Type, 0, method.NumOut())
+    for i := 0; i < method.NumOut(); i++ {
+        out = append(out, method.Out(i))
+    }
+    return reflect.FuncOf(in, out, method.IsVariadic())
+}
@@ -0,0 +1,55 @@
+package container
+
+import (
+    "reflect"
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+type storeReader interface {
+    Read(key string) string
+}
+
+type storeWriter interface {
+    Write(key, value string) error
+}
+
+type readWriteStore interface {
+    storeReader
+    storeWriter
+}
+
+type memoryReader struct{}
+
+func (memoryReader) Read(key string) string { return key }
+
+// badWriter has a Write method with the wrong signature
+type badWriter struct{}
+
+func (badWriter) Write(value string) {}
+
+func TestMismatchDetail(t *testing.T) {
+    storeType := reflect.TypeOf((*readWriteStore)(nil)).Elem()
+
+    assert.Equal(t, ": missing methods Write; to combine services implementing parts of container.readWriteStore, annotate it with //di:compose",
+        mismatchDetail(reflect.TypeOf(memoryReader{}), storeType))
+    assert.Equal(t, ": missing methods Read, Write (has func(string), want func(string, string) error)",
+        mismatchDetail(reflect.TypeOf(badWriter{}), storeType))
+    assert.Equal(t, ": missing methods Write",
+        mismatchDetail(reflect.TypeOf((*storeReader)(nil)).Elem(), reflect.TypeOf((*storeWriter)(nil)).Elem()))
+    assert.Empty(t, mismatchDetail(reflect.TypeOf(1), reflect.TypeOf("")))
+}
+
+func TestInjectStruct_EmbeddedInterfaceMismatch(t *testing.T) {
+    c := NewContainer()
+    require.NoError(t, c.Register("store", memoryReader{}))
+
+    target := &struct {
+        Store readWriteStore `di:"store"`
+    }{}
+    err := c.InjectStruct(target)
+    assert.ErrorContains(t, err, "field Store: service type container.memoryReader is not assignable to field type container.readWriteStore"+
+        ": missing methods Write; to combine services implementing parts of container.readWriteStore, annotate it with //di:compose")
+}
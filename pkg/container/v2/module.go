@@ -0,0 +1,129 @@
+package container
+
+import (
+    "fmt"
+    "strings"
+)
+
+// DefaultProfile is the active profile of a new container
+const DefaultProfile = "default"
+
+// Module groups related registrations under a name. Registrations made by
+// Setup are attributed to the module for quotas and diagnostics.
+//
+// Profiles restricts the module to some container profiles: it is installed
+// when the list is empty, names the active profile, or contains "!name"
+// entries none of which match the active profile. This lets a "test"
+// variant of a module register the same qualifiers as the production one.
+//
+// EnableKey makes the module optional: Install defers it, and Build or
+// Start installs it only if the key, e.g. "modules.billing.enabled", is
+// not false in the bound config, see ModuleEnabled. A disabled module's
+// Setup never runs, so none of its services, hooks or group members exist.
+type Module struct {
+    Name      string
+    Profiles  []string
+    EnableKey string
+    Setup     func(c *Container) error
+}
+
+// activeIn reports whether the module applies to profile
+func (m Module) activeIn(profile string) bool {
+    if len(m.Profiles) == 0 {
+        return true
+    }
+
+    excludedOnly := true
+    for _, entry := range m.Profiles {
+        if excluded, ok := strings.CutPrefix(entry, "!"); ok {
+            if excluded == profile {
+                return false
+            }
+            continue
+        }
+        excludedOnly = false
+        if entry == profile {
+            return true
+        }
+    }
+    return excludedOnly
+}
+
+// SetProfile selects the active profile used to pick module variants
+func (c *Container) SetProfile(profile string) {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+
+    c.log.Infow("Setting container profile", "profile", profile)
+    c.profile = profile
+}
+
+// Profile returns the active profile
+func (c *Container) Profile() string {
+    c.mu.RLock()
+    defer c.mu.RUnlock()
+    return c.profile
+}
+
+// Install runs the Setup function of each module in order, stopping at the
+// first failure
+func (c *Container) Install(modules ...Module) error {
+    for _, module := range modules {
+        if err := c.install(module); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// install runs one module's Setup, or defers it when it has an enable key
+func (c *Container) install(module Module) error {
+    if profile := c.Profile(); !module.activeIn(profile) {
+        c.log.Infow("Skipping module not active in profile",
+            "module", module.Name,
+            "profile", profile)
+        return nil
+    }
+    if module.EnableKey != "" {
+        c.log.Infow("Deferring module until build",
+            "module", module.Name,
+            "enableKey", module.EnableKey)
+        c.mu.Lock()
+        defer c.mu.Unlock()
+        c.deferredModules = append(c.deferredModules, module)
+        return nil
+    }
+    return c.installNow(module)
+}
+
+// installNow runs one module's Setup with registrations attributed to it
+func (c *Container) installNow(module Module) error {
+    c.log.Infow("Installing module", "module", module.Name)
+
+    gid := goroutineID()
+    c.mu.Lock()
+    previous, nested := c.installing[gid]
+    c.installing[gid] = module.Name
+    c.mu.Unlock()
+
+    defer func() {
+        c.mu.Lock()
+        defer c.mu.Unlock()
+        if nested {
+            c.installing[gid] = previous
+        } else {
+            delete(c.installing, gid)
+        }
+    }()
+
+    if module.Setup == nil {
+        return nil
+    }
+    if err := module.Setup(c); err != nil {
+        c.log.Errorw("Module installation failed",
+            "module", module.Name,
+            "error", err)
+        return fmt.Errorf("failed to install module %s: %w", module.Name, err)
+    }
+    return nil
+}
@@ -0,0 +1,121 @@
+package container
+
+import (
+    "fmt"
+    "reflect"
+    "strings"
+    "time"
+)
+
+// NestedTag marks a struct field whose own di tagged fields are injected,
+// recursively:
+//
+//	type Server struct {
+//	    Repo  Repository `di:"inject"` // Repo.DB is injected
+//	    Cache *Cache     `di:"inject"` // Allocated when nil
+//	}
+//
+// It applies to struct fields, pointers to structs and embedded structs.
+// Embedded structs need no tag: their fields are injected like those of the
+// embedding struct, through an embedded pointer unless it is nil.
+// WithNestedInjection descends into untagged struct fields as well. Nested
+// fields are reported by path, such as Repo.DB or Base.Logger.
+const NestedTag = "inject"
+
+// maxNestedDepth bounds nested injection, which a struct holding a pointer
+// to its own type would otherwise allocate forever
+const maxNestedDepth = 32
+
+// injection carries the state of one injectStruct call through nested structs
+type injection struct {
+    resolve func(qualifier string) (interface{}, error)
+    keep    func(field, qualifier string) bool
+    result  *InjectionResult
+    errs    []error          // Field errors, reported together after the last field
+    visited map[uintptr]bool // Addresses of structs already injected, breaking pointer cycles
+
+    constructed []postConstruct // PostConstruct hooks of nested structs, innermost first
+
+    cachePlans bool          // Whether plans are cached, see WithInjectionPlans
+    frozen     bool          // Whether the container was frozen
+    planning   time.Duration // Time spent building plans
+    planned    int           // Plans built
+}
+
+// isNestedField reports whether field holds a struct to inject field by field
+func (c *Container) isNestedField(field reflect.StructField, tag string, tagged bool) bool {
+    if tagged {
+        return parseTag(tag).qualifier == NestedTag
+    }
+    if field.Type == injectMarkerType || (!field.Anonymous && (!c.nested || !field.IsExported())) {
+        return false
+    }
+    fieldType := field.Type
+    if fieldType.Kind() == reflect.Ptr {
+        fieldType = fieldType.Elem()
+    }
+    return fieldType.Kind() == reflect.Struct
+}
+
+// injectNested injects the struct held by field of parent. A nil pointer
+// is allocated when tagged di:"inject" and skipped otherwise.
+func (c *Container) injectNested(in *injection, parent reflect.Value, field reflect.StructField, name string, tagged bool, depth int) {
+    fieldValue, _ := c.settableField(parent.FieldByIndex(field.Index))
+    if depth >= maxNestedDepth {
+        in.errs = append(in.errs, fmt.Errorf("field %s: nested injection deeper than %d levels", name, maxNestedDepth))
+        return
+    }
+    structValue := fieldValue
+    if fieldValue.Kind() == reflect.Ptr && fieldValue.Type().Elem().Kind() == reflect.Struct {
+        if fieldValue.IsNil() {
+            if !tagged {
+                return
+            }
+            if !fieldValue.CanSet() {
+                in.errs = append(in.errs, fmt.Errorf("field %s is unexported and cannot be injected", name))
+                return
+            }
+            fieldValue.Set(reflect.New(fieldValue.Type().Elem()))
+        }
+        if in.visited[fieldValue.Pointer()] {
+            return
+        }
+        in.visited[fieldValue.Pointer()] = true
+        structValue = fieldValue.Elem()
+    }
+    if structValue.Kind() != reflect.Struct {
+        in.errs = append(in.errs, fmt.Errorf("field %s: di:%q needs a struct or pointer to struct, got %v", name, NestedTag, fieldValue.Type()))
+        return
+    }
+
+    plan := c.plan(in, structValue.Type())
+    if plan.markerErr != nil {
+        in.errs = append(in.errs, fmt.Errorf("field %s: %w", name, plan.markerErr))
+        return
+    }
+    c.log.Debugw("Injecting nested struct",
+        "field", name,
+        "structType", structValue.Type())
+    c.injectFields(in, structValue, plan, name+".", depth+1)
+    in.addPostConstruct(structValue, parent, name, field.Anonymous)
+}
+
+// fieldByPath finds the possibly nested struct field named by path, such as
+// Repo.DB
+func fieldByPath(structType reflect.Type, path string) (reflect.StructField, bool) {
+    var field reflect.StructField
+    for _, name := range strings.Split(path, ".") {
+        for structType.Kind() == reflect.Ptr {
+            structType = structType.Elem()
+        }
+        if structType.Kind() != reflect.Struct {
+            return reflect.StructField{}, false
+        }
+        var ok bool
+        if field, ok = structType.FieldByName(name); !ok {
+            return reflect.StructField{}, false
+        }
+        structType = field.Type
+    }
+    return field, true
+}
@@ -0,0 +1,189 @@
+package container
+
+import (
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+type nestedRepository struct {
+    DB TestService `di:"db"`
+}
+
+type nestedCache struct {
+    Inject `di:"prefix=cache.,optional"`
+    Store TestService `di:"store"`
+}
+
+type nestedBase struct {
+    Logger TestService `di:"logger"`
+}
+
+type nestedServer struct {
+    nestedBase `di:"inject"`
+    Repo       nestedRepository `di:"inject"`
+    Cache      *nestedCache     `di:"inject"`
+    Name       TestService      `di:"name"`
+    Untagged   nestedRepository
+}
+
+type nestedNode struct {
+    Next *nestedNode `di:"inject"`
+}
+
+func newNestedContainer(t *testing.T, opts ...Option) *Container {
+    c := NewContainer(opts...)
+    for _, qualifier := range []string{"db", "logger", "name", "cache.store"} {
+        require.NoError(t, c.Register(qualifier, &testServiceImpl{}))
+    }
+    return c
+}
+
+func TestInjectStruct_NestedTag(t *testing.T) {
+    c := newNestedContainer(t)
+
+    var server nestedServer
+    result, err := c.InjectStructWithResult(&server)
+    require.NoError(t, err)
+
+    assert.NotNil(t, server.Logger)
+    assert.NotNil(t, server.Repo.DB)
+    require.NotNil(t, server.Cache)
+    assert.NotNil(t, server.Cache.Store)
+    assert.NotNil(t, server.Name)
+    assert.Nil(t, server.Untagged.DB)
+
+    var fields []string
+    for _, field := range result.Fields {
+        fields = append(fields, field.Field)
+    }
+    assert.Equal(t, []string{"nestedBase.Logger", "Repo.DB", "Cache.Store", "Name"}, fields)
+    assert.Equal(t, "cache.store", result.Fields[2].Qualifier)
+}
+
+func TestInjectStruct_NestedKeepsPointer(t *testing.T) {
+    c := newNestedContainer(t)
+
+    cache := &nestedCache{}
+    server := nestedServer{Cache: cache}
+    require.NoError(t, c.InjectStruct(&server))
+    assert.Same(t, cache, server.Cache)
+    assert.NotNil(t, cache.Store)
+}
+
+func TestInjectStruct_NestedErrorsUsePaths(t *testing.T) {
+    c := NewContainer()
+    require.NoError(t, c.Register("logger", &testServiceImpl{}))
+
+    var server nestedServer
+    err := c.InjectStruct(&server)
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "failed to inject 2 fields")
+    assert.Contains(t, err.Error(), `required service "db" for field Repo.DB not found`)
+    assert.Contains(t, err.Error(), `required service "name" for field Name not found`)
+    assert.NotNil(t, server.Logger)
+}
+
+func TestInjectStruct_NestedTagOnNonStruct(t *testing.T) {
+    c := NewContainer()
+
+    var target struct {
+        Count int `di:"inject"`
+    }
+    err := c.InjectStruct(&target)
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), `field Count: di:"inject" needs a struct or pointer to struct, got int`)
+}
+
+func TestInjectStruct_NestedDepthLimit(t *testing.T) {
+    c := NewContainer()
+
+    var node nestedNode
+    err := c.InjectStruct(&node)
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "nested injection deeper than 32 levels")
+}
+
+func TestInjectStruct_NestedPointerCycle(t *testing.T) {
+    c := NewContainer()
+
+    node := &nestedNode{}
+    node.Next = node
+    require.NoError(t, c.InjectStruct(node))
+}
+
+func TestInjectStruct_WithNestedInjection(t *testing.T) {
+    c := newNestedContainer(t, WithNestedInjection())
+
+    var target struct {
+        Repo     nestedRepository
+        Optional *nestedRepository // Nil pointers are left alone
+        hidden   nestedRepository
+    }
+    result, err := c.InjectStructWithResult(&target)
+    require.NoError(t, err)
+    assert.NotNil(t, target.Repo.DB)
+    assert.Nil(t, target.Optional)
+    assert.Nil(t, target.hidden.DB)
+    assert.Equal(t, 1, result.Injected())
+}
+
+func TestReinjectStruct_Nested(t *testing.T) {
+    c := newNestedContainer(t)
+
+    var server nestedServer
+    _, err := c.ReinjectStruct(&server)
+    require.NoError(t, err)
+
+    _, err = c.Swap("db", &testServiceImpl{})
+    require.NoError(t, err)
+    result, err := c.ReinjectStruct(&server)
+    require.NoError(t, err)
+    for _, field := range result.Fields {
+        if field.Field == "Repo.DB" {
+            assert.Equal(t, FieldInjected, field.Status)
+        } else {
+            assert.Equal(t, FieldUnchanged, field.Status, field.Field)
+        }
+    }
+}
+
+type embeddedAudit struct {
+    Audit TestService `di:"audit,optional"`
+}
+
+type embeddedHandler struct {
+    nestedBase
+    *nestedCache
+    *embeddedAudit
+    Name TestService `di:"name"`
+}
+
+func TestInjectStruct_Embedded(t *testing.T) {
+    c := newNestedContainer(t)
+
+    handler := embeddedHandler{nestedCache: &nestedCache{}}
+    result, err := c.InjectStructWithResult(&handler)
+    require.NoError(t, err)
+
+    assert.NotNil(t, handler.Logger)
+    assert.NotNil(t, handler.Store)
+    assert.NotNil(t, handler.Name)
+    assert.Nil(t, handler.embeddedAudit) // Nil embedded pointers are left alone
+
+    var fields []string
+    for _, field := range result.Fields {
+        fields = append(fields, field.Field)
+    }
+    assert.Equal(t, []string{"nestedBase.Logger", "nestedCache.Store", "Name"}, fields)
+}
+
+func TestInjectStruct_EmbeddedErrorsUsePaths(t *testing.T) {
+    c := NewContainer()
+
+    var handler embeddedHandler
+    err := c.InjectStruct(&handler)
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), `required service "logger" for field nestedBase.Logger not found`)
+}
@@ -0,0 +1,79 @@
+package container
+
+import (
+    "fmt"
+    "reflect"
+)
+
+// NilPolicy decides what happens when a typed nil, such as a nil *T stored
+// in an interface, is registered or returned by a provider. Such values pass
+// the untyped nil check but panic once a method dereferences them.
+type NilPolicy int
+
+const (
+    // NilReject fails the registration or resolution. It is the default.
+    NilReject NilPolicy = iota
+    // NilWarn logs a warning and accepts the value
+    NilWarn
+)
+
+// String returns the lowercase name of the policy
+func (p NilPolicy) String() string {
+    switch p {
+    case NilReject:
+        return "reject"
+    case NilWarn:
+        return "warn"
+    default:
+        return fmt.Sprintf("nilpolicy(%d)", int(p))
+    }
+}
+
+// SetNilPolicy sets how typed nil services are handled
+func (c *Container) SetNilPolicy(policy NilPolicy) {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+
+    c.log.Infow("Setting typed nil policy", "policy", policy)
+    c.nilPolicy = policy
+}
+
+// isTypedNil reports whether service is a non-nil interface holding a nil
+// pointer, map, func or channel. Nil slices are usable and not reported.
+func isTypedNil(service interface{}) bool {
+    value := reflect.ValueOf(service)
+    switch value.Kind() {
+    case reflect.Ptr, reflect.Map, reflect.Func, reflect.Chan, reflect.UnsafePointer:
+        return value.IsNil()
+    }
+    return false
+}
+
+// checkTypedNilLocked applies the nil policy to a service about to be stored
+// or returned; source describes where it came from. Callers must hold c.mu,
+// for reading at least.
+func (c *Container) checkTypedNilLocked(qualifier string, service interface{}, source string) error {
+    if !isTypedNil(service) {
+        return nil
+    }
+
+    if c.nilPolicy == NilWarn {
+        c.log.Warnw("Typed nil service accepted",
+            "qualifier", qualifier,
+            "type", reflect.TypeOf(service),
+            "source", source)
+        return nil
+    }
+    c.log.Errorw("Typed nil service rejected",
+        "qualifier", qualifier,
+        "type", reflect.TypeOf(service),
+        "source", source)
+    return &NilServiceError{Qualifier: qualifier, Type: reflect.TypeOf(service), Source: source}
+}
+
+// checkTypedNil is checkTypedNilLocked for callers not holding c.mu
+func (c *Container) checkTypedNil(qualifier string, service interface{}, source string) error {
+    c.mu.RLock()
+    defer c.mu.RUnlock()
+    return c.checkTypedNilLocked(qualifier, service, source)
+}
@@ -0,0 +1,52 @@
+package container
+
+import (
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+func TestIsTypedNil(t *testing.T) {
+    var impl *testServiceImpl
+    var service TestService = impl
+    var m map[string]int
+    var s []int
+
+    assert.True(t, isTypedNil(impl))
+    assert.True(t, isTypedNil(service))
+    assert.True(t, isTypedNil(m))
+    assert.False(t, isTypedNil(s))
+    assert.False(t, isTypedNil(&testServiceImpl{}))
+    assert.False(t, isTypedNil(42))
+}
+
+func TestContainer_TypedNilRejected(t *testing.T) {
+    container := NewContainer()
+    var impl *testServiceImpl
+
+    err := container.Register("svc", impl)
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "nil *container.testServiceImpl")
+
+    require.NoError(t, container.Register("real", &testServiceImpl{}))
+    _, err = container.Swap("real", impl)
+    assert.Error(t, err)
+
+    require.NoError(t, container.RegisterWeak("weak", func() (interface{}, error) {
+        return impl, nil
+    }))
+    _, err = container.Resolve("weak")
+    assert.ErrorContains(t, err, "weak provider result")
+}
+
+func TestContainer_TypedNilWarn(t *testing.T) {
+    container := NewContainer()
+    container.SetNilPolicy(NilWarn)
+    var impl *testServiceImpl
+
+    require.NoError(t, container.Register("svc", impl))
+    got, err := container.Resolve("svc")
+    require.NoError(t, err)
+    assert.Nil(t, got.(*testServiceImpl))
+}
@@ -0,0 +1,92 @@
+package container
+
+import (
+    "reflect"
+)
+
+// Optional wraps a dependency that may be absent. InjectStruct fills an
+// Optional[T] field with the resolved service when its qualifier is
+// registered and marks it empty otherwise, so consumers check presence
+// explicitly instead of relying on nil interfaces:
+//
+//	type Handler struct {
+//	    Cache container.Optional[Cache] `di:"cache"`
+//	}
+//
+//	if cache, ok := h.Cache.Get(); ok { ... }
+type Optional[T any] struct {
+    value   T
+    present bool
+}
+
+// Some returns an Optional holding value
+func Some[T any](value T) Optional[T] {
+    return Optional[T]{value: value, present: true}
+}
+
+// Get returns the wrapped value and whether it is present
+func (o Optional[T]) Get() (T, bool) {
+    return o.value, o.present
+}
+
+// Present reports whether a value is present
+func (o Optional[T]) Present() bool {
+    return o.present
+}
+
+// OrElse returns the wrapped value, or fallback when absent
+func (o Optional[T]) OrElse(fallback T) T {
+    if o.present {
+        return o.value
+    }
+    return fallback
+}
+
+// optionalField is implemented by *Optional[T] so InjectStruct can fill
+// optional fields without knowing T
+type optionalField interface {
+    valueType() reflect.Type
+    fill(service interface{})
+    clear()
+}
+
+func (o *Optional[T]) valueType() reflect.Type {
+    return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+func (o *Optional[T]) fill(service interface{}) {
+    o.value, o.present = service.(T)
+}
+
+func (o *Optional[T]) clear() {
+    var zero T
+    o.value, o.present = zero, false
+}
+
+// injectOptional fills an Optional field and reports whether the service was
+// present. A missing service leaves the field empty; a service of the wrong
+// type is still an error.
+func (c *Container) injectOptional(opt optionalField, qualifier string, field reflect.StructField, resolve func(string) (interface{}, error)) (bool, error) {
+    service, err := resolve(qualifier)
+    if err != nil {
+        opt.clear()
+        return false, nil
+    }
+
+    serviceType := reflect.TypeOf(service)
+    if !serviceType.AssignableTo(opt.valueType()) {
+        c.log.Errorw("Type mismatch during optional injection",
+            "field", field.Name,
+            "expectedType", opt.valueType(),
+            "actualType", serviceType)
+        return false, &TypeMismatchError{
+            Qualifier: qualifier,
+            Type:      serviceType,
+            Want:      opt.valueType(),
+            Target:    "optional field type",
+        }
+    }
+
+    opt.fill(service)
+    return true, nil
+}
@@ -0,0 +1,56 @@
+package container
+
+import (
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+type optionalStruct struct {
+    Inject   `di:"required"`
+    Present  Optional[TestService] `di:"testService"`
+    Absent   Optional[TestService] `di:"missingService"`
+    Mismatch Optional[int]         `di:"wrongType"`
+}
+
+func TestOptional(t *testing.T) {
+    var empty Optional[string]
+    _, ok := empty.Get()
+    assert.False(t, ok)
+    assert.False(t, empty.Present())
+    assert.Equal(t, "fallback", empty.OrElse("fallback"))
+
+    some := Some("value")
+    value, ok := some.Get()
+    assert.True(t, ok)
+    assert.Equal(t, "value", value)
+    assert.Equal(t, "value", some.OrElse("fallback"))
+}
+
+func TestContainer_InjectOptional(t *testing.T) {
+    container := NewContainer()
+    testService := &testServiceImpl{name: "test"}
+    require.NoError(t, container.Register("testService", testService))
+
+    target := &struct {
+        Present Optional[TestService] `di:"testService"`
+        Absent  Optional[TestService] `di:"missingService"`
+    }{
+        Absent: Some[TestService](&testServiceImpl{name: "stale"}),
+    }
+    require.NoError(t, container.InjectStruct(target))
+
+    service, ok := target.Present.Get()
+    assert.True(t, ok)
+    assert.Equal(t, testService, service)
+
+    // Absent services are never an error, even in required structs, and clear stale values
+    _, ok = target.Absent.Get()
+    assert.False(t, ok)
+
+    require.NoError(t, container.Register("wrongType", "not an int"))
+    err := container.InjectStruct(&optionalStruct{})
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "optional field type int")
+}
@@ -0,0 +1,165 @@
+package container
+
+import (
+    "fmt"
+    "reflect"
+)
+
+// OptionsTag is the di tag injecting the option struct of the field's type:
+//
+//	type ServerOptions struct {
+//	    Port    int    `default:"8080"`
+//	    Timeout string `default:"30s"`
+//	}
+//
+//	type Server struct {
+//	    Options ServerOptions `di:"options"` // or *ServerOptions
+//	}
+//
+// The field receives the defaults of its default tags merged with every
+// RegisterOptions call for that type, even when nothing was registered.
+const OptionsTag = "options"
+
+// optionsQualifier is the qualifier option structs of t are registered under
+func optionsQualifier(t reflect.Type) string {
+    return "options:" + t.String()
+}
+
+// optionsType returns the option struct type of a field, which may be a
+// struct or a pointer to one
+func optionsType(fieldType reflect.Type) reflect.Type {
+    if fieldType.Kind() == reflect.Ptr {
+        return fieldType.Elem()
+    }
+    return fieldType
+}
+
+// RegisterOptions registers the option struct T. Fields left at their zero
+// value keep their default:"value" tag, and repeated calls merge: non-zero
+// fields of later calls override earlier ones. Use a pointer field to
+// override a default with a zero value.
+func RegisterOptions[T any](c *Container, opts T, regOpts ...RegisterOption) error {
+    t := reflect.TypeOf((*T)(nil)).Elem()
+    if t.Kind() != reflect.Struct {
+        return fmt.Errorf("options type %v must be a struct", t)
+    }
+    qualifier := optionsQualifier(t)
+
+    c.mu.RLock()
+    current, exists := c.services[qualifier]
+    c.mu.RUnlock()
+
+    base := reflect.ValueOf(current)
+    if !exists {
+        var err error
+        if base, err = defaultOptions(t); err != nil {
+            return err
+        }
+    }
+    merged := reflect.New(t).Elem()
+    merged.Set(base)
+    mergeOptions(merged, reflect.ValueOf(opts))
+
+    if exists {
+        _, err := c.Swap(qualifier, merged.Interface())
+        return err
+    }
+    return c.Register(qualifier, merged.Interface(), regOpts...)
+}
+
+// Options returns the option struct T: the registered value, or the
+// defaults of T when RegisterOptions was never called
+func Options[T any](c *Container) (T, error) {
+    var opts T
+    value, err := c.options(reflect.TypeOf(&opts).Elem())
+    if err != nil {
+        return opts, err
+    }
+    return value.Interface().(T), nil
+}
+
+// options resolves the option struct of type t, falling back to defaults
+func (c *Container) options(t reflect.Type) (reflect.Value, error) {
+    if t.Kind() != reflect.Struct {
+        return reflect.Value{}, fmt.Errorf("options type %v must be a struct", t)
+    }
+
+    c.mu.RLock()
+    current, exists := c.services[optionsQualifier(t)]
+    c.mu.RUnlock()
+    if exists {
+        return reflect.ValueOf(current), nil
+    }
+    return defaultOptions(t)
+}
+
+// injectOptions sets an options field, copying the struct for pointer
+// fields so consumers never share one instance
+func (c *Container) injectOptions(field reflect.Value) error {
+    value, err := c.options(optionsType(field.Type()))
+    if err != nil {
+        return err
+    }
+    if field.Kind() == reflect.Ptr {
+        copied := reflect.New(value.Type())
+        copied.Elem().Set(value)
+        value = copied
+    }
+    field.Set(value)
+    return nil
+}
+
+// defaultOptions builds a value of t with the default tags of its fields,
+// including those of nested structs
+func defaultOptions(t reflect.Type) (reflect.Value, error) {
+    value := reflect.New(t).Elem()
+    for i := 0; i < t.NumField(); i++ {
+        field := t.Field(i)
+        if !field.IsExported() {
+            continue
+        }
+
+        if field.Type.Kind() == reflect.Struct {
+            nested, err := defaultOptions(field.Type)
+            if err != nil {
+                return value, err
+            }
+            value.Field(i).Set(nested)
+            continue
+        }
+
+        tag, ok := field.Tag.Lookup("default")
+        if !ok {
+            continue
+        }
+        parsed, err := parseDefault(field.Type, tag)
+        if err != nil {
+            return value, fmt.Errorf("field %s of %v: %w", field.Name, t, err)
+        }
+        // Allocate every level of pointer fields, e.g. **int
+        target := value.Field(i)
+        for target.Kind() == reflect.Ptr {
+            target.Set(reflect.New(target.Type().Elem()))
+            target = target.Elem()
+        }
+        target.Set(reflect.ValueOf(parsed).Convert(target.Type()))
+    }
+    return value, nil
+}
+
+// mergeOptions copies the non-zero fields of override into base
+func mergeOptions(base, override reflect.Value) {
+    for i := 0; i < base.NumField(); i++ {
+        if !base.Type().Field(i).IsExported() {
+            continue
+        }
+        field := override.Field(i)
+        if field.Kind() == reflect.Struct {
+            mergeOptions(base.Field(i), field)
+            continue
+        }
+        if !field.IsZero() {
+            base.Field(i).Set(field)
+        }
+    }
+}
@@ -0,0 +1,116 @@
+package container
+
+import (
+    "testing"
+    "time"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+type retryOptions struct {
+    Attempts int     `default:"3"`
+    Backoff  float64 `default:"1.5"`
+}
+
+type serverOptions struct {
+    Host    string `default:"localhost"`
+    Port    int    `default:"8080"`
+    Verbose *bool  `default:"true"`
+    Retry   retryOptions
+}
+
+type optionsConsumer struct {
+    Options serverOptions  `di:"options"`
+    Shared  *serverOptions `di:"options"`
+}
+
+func TestOptions_Defaults(t *testing.T) {
+    c := NewContainer()
+
+    opts, err := Options[serverOptions](c)
+    require.NoError(t, err)
+    assert.Equal(t, "localhost", opts.Host)
+    assert.Equal(t, 8080, opts.Port)
+    require.NotNil(t, opts.Verbose)
+    assert.True(t, *opts.Verbose)
+    assert.Equal(t, retryOptions{Attempts: 3, Backoff: 1.5}, opts.Retry)
+}
+
+func TestOptions_DefaultsThroughPointerChain(t *testing.T) {
+    type chainedOptions struct {
+        Limit **int `default:"10"`
+    }
+    c := NewContainer()
+
+    opts, err := Options[chainedOptions](c)
+    require.NoError(t, err)
+    require.NotNil(t, opts.Limit)
+    require.NotNil(t, *opts.Limit)
+    assert.Equal(t, 10, **opts.Limit)
+}
+
+func TestOptions_DurationDefaults(t *testing.T) {
+    type timeoutOptions struct {
+        Timeout time.Duration  `default:"30s"`
+        Idle    *time.Duration `default:"1m30s"`
+    }
+    c := NewContainer()
+
+    opts, err := Options[timeoutOptions](c)
+    require.NoError(t, err)
+    assert.Equal(t, 30*time.Second, opts.Timeout)
+    require.NotNil(t, opts.Idle)
+    assert.Equal(t, 90*time.Second, *opts.Idle)
+
+    type badOptions struct {
+        Timeout time.Duration `default:"30"`
+    }
+    _, err = Options[badOptions](c)
+    assert.ErrorContains(t, err, `invalid default "30" for time.Duration`)
+}
+
+func TestRegisterOptions_MergesOverrides(t *testing.T) {
+    c := NewContainer()
+    verbose := false
+
+    require.NoError(t, RegisterOptions(c, serverOptions{Port: 9090}))
+    require.NoError(t, RegisterOptions(c, serverOptions{Verbose: &verbose, Retry: retryOptions{Attempts: 5}}))
+
+    opts, err := Options[serverOptions](c)
+    require.NoError(t, err)
+    assert.Equal(t, "localhost", opts.Host) // Default kept
+    assert.Equal(t, 9090, opts.Port)        // First override kept
+    assert.False(t, *opts.Verbose)          // Pointer overrides a default with zero
+    assert.Equal(t, retryOptions{Attempts: 5, Backoff: 1.5}, opts.Retry)
+}
+
+func TestRegisterOptions_RejectsNonStruct(t *testing.T) {
+    c := NewContainer()
+    assert.ErrorContains(t, RegisterOptions(c, 42), "must be a struct")
+}
+
+func TestInjectStruct_Options(t *testing.T) {
+    c := NewContainer()
+    require.NoError(t, RegisterOptions(c, serverOptions{Host: "example.com"}))
+
+    var consumer optionsConsumer
+    result, err := c.InjectStructWithResult(&consumer)
+    require.NoError(t, err)
+
+    assert.Equal(t, "example.com", consumer.Options.Host)
+    assert.Equal(t, 8080, consumer.Options.Port)
+    require.NotNil(t, consumer.Shared)
+    assert.Equal(t, consumer.Options, *consumer.Shared)
+    assert.Equal(t, "options:container.serverOptions", result.Fields[0].Qualifier)
+    assert.Equal(t, 2, result.Injected())
+}
+
+func TestInjectStruct_OptionsDefaultsWithoutRegistration(t *testing.T) {
+    c := NewContainer()
+
+    var consumer optionsConsumer
+    require.NoError(t, c.InjectStruct(&consumer))
+    assert.Equal(t, "localhost", consumer.Options.Host)
+    assert.Equal(t, 3, consumer.Shared.Retry.Attempts)
+}
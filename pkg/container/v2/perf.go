@@ -0,0 +1,219 @@
+package container
+
+import (
+    "fmt"
+    "reflect"
+    "strings"
+    "sync"
+    "text/tabwriter"
+    "time"
+)
+
+// injectionPlan is the analysis of a struct type that InjectStruct repeats
+// for every injection unless plans are cached: the Inject marker, the
+// fields to inject, with their tags parsed, and the injection methods
+type injectionPlan struct {
+    defaults    structDefaults
+    markerIndex int
+    markerErr   error
+    fields      []fieldPlan
+    methods     []reflect.Method // Injection methods, see InjectMethodPrefix
+    methodErrs  []error          // Injection methods with the wrong signature
+}
+
+// fieldPlan is a tagged or nested field of an injectionPlan
+type fieldPlan struct {
+    field     reflect.StructField
+    tagged    bool
+    nested    bool
+    spec      tagSpec
+    optionErr error  // Unknown tag options, reported in strict mode
+    requested string // Qualifier before renames; di:"" fields are wired by type
+    group     string // Group of a di:"map:group" field
+    isMap     bool
+}
+
+// planCache holds the injection plans of a container by struct type
+type planCache struct {
+    mu    sync.RWMutex
+    plans map[reflect.Type]*injectionPlan
+}
+
+// plan returns the injection plan of structType, building it when plans
+// are not cached or not built yet. Time spent building counts as planning
+// in the container's PerfReport.
+func (c *Container) plan(in *injection, structType reflect.Type) *injectionPlan {
+    if in.cachePlans {
+        c.plans.mu.RLock()
+        plan, ok := c.plans.plans[structType]
+        c.plans.mu.RUnlock()
+        if ok {
+            return plan
+        }
+    }
+
+    begin := c.clock.Now()
+    plan := c.buildPlan(structType)
+    in.planning += c.since(begin)
+    in.planned++
+
+    if in.cachePlans {
+        c.plans.mu.Lock()
+        if c.plans.plans == nil {
+            c.plans.plans = make(map[reflect.Type]*injectionPlan)
+        }
+        c.plans.plans[structType] = plan
+        c.plans.mu.Unlock()
+    }
+    return plan
+}
+
+// buildPlan analyses structType for injection
+func (c *Container) buildPlan(structType reflect.Type) *injectionPlan {
+    plan := &injectionPlan{}
+    plan.defaults, plan.markerIndex, plan.markerErr = readStructDefaults(structType)
+    for i := 0; i < structType.NumField(); i++ {
+        if i == plan.markerIndex {
+            continue
+        }
+        field := structType.Field(i)
+        tag, tagged := field.Tag.Lookup("di")
+        if c.isNestedField(field, tag, tagged) {
+            plan.fields = append(plan.fields, fieldPlan{field: field, tagged: tagged, nested: true})
+            continue
+        }
+        if !tagged {
+            continue
+        }
+
+        spec := parseTag(tag)
+        fp := fieldPlan{
+            field:     field,
+            tagged:    true,
+            spec:      spec,
+            optionErr: spec.checkOptions(),
+            requested: plan.defaults.prefix + spec.qualifier,
+        }
+        fp.group, fp.isMap = strings.CutPrefix(spec.qualifier, MapTagPrefix)
+        if fp.isMap {
+            fp.requested = spec.qualifier // Groups are not prefixed
+        }
+        if spec.qualifier == OptionsTag {
+            fp.requested = optionsQualifier(optionsType(field.Type))
+        }
+        plan.fields = append(plan.fields, fp)
+    }
+    plan.methods, plan.methodErrs = c.injectMethodsOf(structType)
+    return plan
+}
+
+// InjectionCost sums up the cost of a set of injections
+type InjectionCost struct {
+    Injections int
+    Total      time.Duration // Time spent in InjectStruct
+    Planning   time.Duration // Part of Total spent analysing struct types
+}
+
+// Mean returns the mean cost of one injection
+func (ic InjectionCost) Mean() time.Duration {
+    if ic.Injections == 0 {
+        return 0
+    }
+    return ic.Total / time.Duration(ic.Injections)
+}
+
+// add records one injection
+func (ic *InjectionCost) add(total, planning time.Duration) {
+    ic.Injections++
+    ic.Total += total
+    ic.Planning += planning
+}
+
+// PerfReport compares the cost of the container's successful injections by
+// how they analysed their struct types. Reflective injections analysed at
+// least one struct type; planned injections found every plan cached,
+// which WithInjectionPlans and Freeze enable.
+type PerfReport struct {
+    PlansCached bool // Whether plans are cached now
+    CachedPlans int  // Struct types with a cached plan
+    Reflective  InjectionCost
+    Planned     InjectionCost
+    Frozen      InjectionCost // Injections into the frozen container
+    Unfrozen    InjectionCost // Injections before Freeze
+}
+
+// PlanningShare returns the share of the time of reflective injections
+// spent analysing struct types, which cached plans would save
+func (r *PerfReport) PlanningShare() float64 {
+    if r.Reflective.Total <= 0 {
+        return 0
+    }
+    return float64(r.Reflective.Planning) / float64(r.Reflective.Total)
+}
+
+// String renders the report as a table
+func (r *PerfReport) String() string {
+    var b strings.Builder
+    w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+    fmt.Fprintln(w, "MODE\tINJECTIONS\tMEAN\tPLANNING")
+    for _, row := range []struct {
+        mode string
+        cost InjectionCost
+    }{
+        {"reflective", r.Reflective},
+        {"planned", r.Planned},
+        {"unfrozen", r.Unfrozen},
+        {"frozen", r.Frozen},
+    } {
+        fmt.Fprintf(w, "%s\t%d\t%v\t%v\n", row.mode, row.cost.Injections, row.cost.Mean(), row.cost.Planning)
+    }
+    w.Flush()
+    fmt.Fprintf(&b, "plans cached: %t (%d types), planning share of reflective injections: %.1f%%\n",
+        r.PlansCached, r.CachedPlans, 100*r.PlanningShare())
+    return b.String()
+}
+
+// perfStats accumulates the PerfReport of a container
+type perfStats struct {
+    mu     sync.Mutex
+    report PerfReport
+}
+
+// recordInjection adds a successful injection to the PerfReport
+func (c *Container) recordInjection(in *injection, total time.Duration) {
+    c.perf.mu.Lock()
+    defer c.perf.mu.Unlock()
+
+    report := &c.perf.report
+    if in.planned > 0 {
+        report.Reflective.add(total, in.planning)
+    } else {
+        report.Planned.add(total, in.planning)
+    }
+    if in.frozen {
+        report.Frozen.add(total, in.planning)
+    } else {
+        report.Unfrozen.add(total, in.planning)
+    }
+}
+
+// PerfReport returns the cost of the injections so far, to judge whether
+// caching injection plans, with WithInjectionPlans or Freeze, is worth it
+// for the workload. The benchmarks of the package measure the same on
+// synthetic structs.
+func (c *Container) PerfReport() *PerfReport {
+    c.perf.mu.Lock()
+    report := c.perf.report
+    c.perf.mu.Unlock()
+
+    c.plans.mu.RLock()
+    report.CachedPlans = len(c.plans.plans)
+    c.plans.mu.RUnlock()
+    report.PlansCached = c.cachesPlans()
+    return &report
+}
+
+// cachesPlans reports whether injection plans are cached
+func (c *Container) cachesPlans() bool {
+    return c.plansEnabled || c.Frozen()
+}
@@ -0,0 +1,102 @@
+package container
+
+import (
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+    "go.uber.org/zap"
+)
+
+type perfTarget struct {
+    Inject `di:"optional"`
+    DB     TestService `di:"db"`
+    Logger TestService `di:"logger"`
+    Name   TestService `di:"name"`
+    Cache  TestService `di:"cache.store"`
+    Repo   nestedRepository `di:"inject"`
+    Count  int
+    Label  string
+}
+
+func newPerfContainer(tb testing.TB, opts ...Option) *Container {
+    c := NewContainer(append([]Option{WithLogger(zap.NewNop().Sugar())}, opts...)...)
+    for _, qualifier := range []string{"db", "logger", "name", "cache.store"} {
+        require.NoError(tb, c.Register(qualifier, &testServiceImpl{}))
+    }
+    return c
+}
+
+func TestPerfReport(t *testing.T) {
+    c := newPerfContainer(t)
+    for i := 0; i < 3; i++ {
+        require.NoError(t, c.InjectStruct(&perfTarget{}))
+    }
+
+    report := c.PerfReport()
+    assert.False(t, report.PlansCached)
+    assert.Zero(t, report.CachedPlans)
+    assert.Equal(t, 3, report.Reflective.Injections)
+    assert.Zero(t, report.Planned.Injections)
+    assert.Equal(t, 3, report.Unfrozen.Injections)
+
+    // Freezing caches plans: the first injection builds them, later ones reuse them
+    c.Freeze()
+    for i := 0; i < 2; i++ {
+        require.NoError(t, c.InjectStruct(&perfTarget{}))
+    }
+    report = c.PerfReport()
+    assert.True(t, report.PlansCached)
+    assert.Equal(t, 2, report.CachedPlans) // perfTarget and nestedRepository
+    assert.Equal(t, 4, report.Reflective.Injections)
+    assert.Equal(t, 1, report.Planned.Injections)
+    assert.Zero(t, report.Planned.Planning)
+    assert.Equal(t, 2, report.Frozen.Injections)
+
+    out := report.String()
+    assert.Contains(t, out, "MODE")
+    assert.Contains(t, out, "plans cached: true (2 types)")
+}
+
+func TestPerfReport_FailedInjectionsAreNotCounted(t *testing.T) {
+    c := NewContainer(WithInjectionPlans())
+    assert.Error(t, c.InjectStruct(&perfTarget{}))
+    assert.Zero(t, c.PerfReport().Reflective.Injections)
+    assert.True(t, c.PerfReport().PlansCached)
+}
+
+func TestInjectionPlans_KeepStrictMode(t *testing.T) {
+    c := NewContainer(WithStrictMode(), WithInjectionPlans())
+    require.NoError(t, c.Register("db", &testServiceImpl{}))
+
+    var target struct {
+        DB TestService `di:"db,optinal"`
+    }
+    for i := 0; i < 2; i++ {
+        assert.ErrorContains(t, c.InjectStruct(&target), `unknown option "optinal"`)
+    }
+}
+
+func benchmarkInjectStruct(b *testing.B, c *Container) {
+    b.ReportAllocs()
+    b.ResetTimer()
+    for i := 0; i < b.N; i++ {
+        if err := c.InjectStruct(&perfTarget{}); err != nil {
+            b.Fatal(err)
+        }
+    }
+}
+
+func BenchmarkInjectStruct_Reflective(b *testing.B) {
+    benchmarkInjectStruct(b, newPerfContainer(b))
+}
+
+func BenchmarkInjectStruct_Planned(b *testing.B) {
+    benchmarkInjectStruct(b, newPerfContainer(b, WithInjectionPlans()))
+}
+
+func BenchmarkInjectStruct_Frozen(b *testing.B) {
+    c := newPerfContainer(b)
+    c.Freeze()
+    benchmarkInjectStruct(b, c)
+}
@@ -0,0 +1,140 @@
+package container
+
+import (
+    "errors"
+    "fmt"
+    "reflect"
+)
+
+// PostConstructor is implemented by structs that finish their setup once
+// InjectStruct has wired their fields, e.g. to validate them or derive
+// state from them. Nested structs, see NestedTag, are set up before the
+// struct holding them. ReinjectStruct does not call it again.
+type PostConstructor interface {
+    PostConstruct() error
+}
+
+// PreDestroyer is implemented by services that clean up when the container
+// stops. Stop calls PreDestroy once per built singleton and cached weak
+// instance, in reverse registration order, after the stop hooks and workers.
+type PreDestroyer interface {
+    PreDestroy() error
+}
+
+// postConstruct is a PostConstruct hook of a nested struct waiting for
+// injection to finish
+type postConstruct struct {
+    field  string
+    target PostConstructor
+}
+
+// addPostConstruct queues the PostConstruct hook of the nested struct in
+// structValue. An embedded struct's hook is promoted to the struct
+// embedding it, which calls it instead.
+func (in *injection) addPostConstruct(structValue, parent reflect.Value, name string, embedded bool) {
+    target, ok := postConstructorOf(structValue)
+    if !ok {
+        return
+    }
+    if _, promoted := postConstructorOf(parent); embedded && promoted {
+        return
+    }
+    in.constructed = append(in.constructed, postConstruct{field: name, target: target})
+}
+
+// postConstructorOf returns the PostConstructor of a struct, taking its
+// address when addressable so pointer receivers count
+func postConstructorOf(structValue reflect.Value) (PostConstructor, bool) {
+    if structValue.CanAddr() {
+        structValue = structValue.Addr()
+    }
+    if !structValue.CanInterface() {
+        return nil, false
+    }
+    target, ok := structValue.Interface().(PostConstructor)
+    return target, ok
+}
+
+// runPostConstruct calls the queued hooks of nested structs, innermost
+// first, then the hook of the target. The first error stops it.
+func (c *Container) runPostConstruct(in *injection, targetValue reflect.Value) error {
+    targetType := targetValue.Type()
+    for _, hook := range in.constructed {
+        c.log.Debugw("Running PostConstruct", "structType", targetType, "field", hook.field)
+        if err := hook.target.PostConstruct(); err != nil {
+            c.log.Errorw("PostConstruct failed",
+                "structType", targetType,
+                "field", hook.field,
+                "error", err)
+            return fmt.Errorf("PostConstruct of field %s (%T) of %v failed: %w", hook.field, hook.target, targetType, err)
+        }
+    }
+
+    target, ok := postConstructorOf(targetValue)
+    if !ok {
+        return nil
+    }
+    c.log.Debugw("Running PostConstruct", "structType", targetType)
+    if err := target.PostConstruct(); err != nil {
+        c.log.Errorw("PostConstruct failed",
+            "structType", targetType,
+            "error", err)
+        return fmt.Errorf("PostConstruct of %v failed: %w", targetType, err)
+    }
+    return nil
+}
+
+// preDestroy calls PreDestroy on every built instance implementing
+// PreDestroyer that has not been destroyed yet. All are attempted; their
+// errors are joined.
+func (c *Container) preDestroy() error {
+    var errs []error
+    destroyedInstances := make(map[interface{}]bool)
+    order := c.snapshotOrder()
+    for i := len(order) - 1; i >= 0; i-- {
+        qualifier := order[i]
+        destroyer, ok := c.destroyerOf(qualifier)
+        if !ok {
+            continue
+        }
+        if reflect.TypeOf(destroyer).Comparable() {
+            if destroyedInstances[destroyer] {
+                continue
+            }
+            destroyedInstances[destroyer] = true
+        }
+
+        c.log.Debugw("Running PreDestroy", "qualifier", qualifier)
+        if err := destroyer.PreDestroy(); err != nil {
+            c.log.Errorw("PreDestroy failed",
+                "qualifier", qualifier,
+                "type", reflect.TypeOf(destroyer),
+                "error", err)
+            errs = append(errs, fmt.Errorf("PreDestroy of %s (%T) failed: %w", qualifier, destroyer, err))
+        }
+    }
+    return errors.Join(errs...)
+}
+
+// destroyerOf returns the built instance of qualifier if it implements
+// PreDestroyer and was not destroyed yet, marking it destroyed
+func (c *Container) destroyerOf(qualifier string) (PreDestroyer, bool) {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+
+    if c.destroyed[qualifier] {
+        return nil, false
+    }
+    service, ok := c.services[qualifier]
+    if !ok {
+        if _, weak := c.weak[qualifier]; weak {
+            service, ok = c.weakLRU.peek(qualifier)
+        }
+    }
+    destroyer, isDestroyer := service.(PreDestroyer)
+    if !ok || !isDestroyer {
+        return nil, false
+    }
+    c.destroyed[qualifier] = true
+    return destroyer, true
+}
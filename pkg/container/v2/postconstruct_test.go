@@ -0,0 +1,140 @@
+package container
+
+import (
+    "context"
+    "errors"
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+// constructLog records the order PostConstruct and PreDestroy hooks run in
+type constructLog []string
+
+type constructedRepo struct {
+    DB   TestService `di:"db"`
+    log  *constructLog
+    fail error
+}
+
+func (r *constructedRepo) PostConstruct() error {
+    *r.log = append(*r.log, "repo")
+    return r.fail
+}
+
+type constructedBase struct {
+    Logger TestService `di:"logger"`
+    log    *constructLog
+}
+
+func (b *constructedBase) PostConstruct() error {
+    *b.log = append(*b.log, "base")
+    return nil
+}
+
+type constructedServer struct {
+    Repo  *constructedRepo `di:"inject"`
+    Ready bool
+    log   *constructLog
+}
+
+func (s *constructedServer) PostConstruct() error {
+    s.Ready = s.Repo.DB != nil
+    *s.log = append(*s.log, "server")
+    return nil
+}
+
+// constructedHandler inherits the PostConstruct of constructedBase
+type constructedHandler struct {
+    constructedBase
+    Name TestService `di:"name"`
+}
+
+func TestInjectStruct_PostConstruct(t *testing.T) {
+    c := newNestedContainer(t)
+
+    var log constructLog
+    server := &constructedServer{Repo: &constructedRepo{log: &log}, log: &log}
+    require.NoError(t, c.InjectStruct(server))
+    assert.True(t, server.Ready)
+    assert.Equal(t, constructLog{"repo", "server"}, log)
+
+    // Reinjection does not set the struct up again
+    _, err := c.ReinjectStruct(server)
+    require.NoError(t, err)
+    assert.Equal(t, constructLog{"repo", "server"}, log)
+}
+
+func TestInjectStruct_PostConstructPromoted(t *testing.T) {
+    c := newNestedContainer(t)
+
+    var log constructLog
+    handler := &constructedHandler{constructedBase: constructedBase{log: &log}}
+    require.NoError(t, c.InjectStruct(handler))
+    assert.NotNil(t, handler.Logger)
+    assert.Equal(t, constructLog{"base"}, log) // Once, through the embedding struct
+}
+
+func TestInjectStruct_PostConstructErrors(t *testing.T) {
+    c := newNestedContainer(t)
+
+    var log constructLog
+    server := &constructedServer{Repo: &constructedRepo{log: &log, fail: errors.New("no schema")}, log: &log}
+    err := c.InjectStruct(server)
+    require.Error(t, err)
+    assert.Equal(t, "PostConstruct of field Repo (*container.constructedRepo) of container.constructedServer failed: no schema", err.Error())
+    assert.False(t, server.Ready)
+
+    // Hooks do not run when fields are missing
+    log = nil
+    err = NewContainer().InjectStruct(&constructedServer{Repo: &constructedRepo{log: &log}, log: &log})
+    assert.ErrorContains(t, err, "failed to inject 1 fields")
+    assert.Empty(t, log)
+
+    err = c.InjectStruct(&rejectingTarget{})
+    assert.EqualError(t, err, "PostConstruct of container.rejectingTarget failed: name is reserved")
+}
+
+type rejectingTarget struct {
+    Name TestService `di:"name"`
+}
+
+func (r rejectingTarget) PostConstruct() error {
+    return errors.New("name is reserved")
+}
+
+type destroyedService struct {
+    name string
+    log  *constructLog
+    fail error
+}
+
+func (d *destroyedService) PreDestroy() error {
+    *d.log = append(*d.log, d.name)
+    return d.fail
+}
+
+func TestStop_PreDestroy(t *testing.T) {
+    c := NewContainer()
+    var log constructLog
+    db := &destroyedService{name: "db", log: &log}
+    require.NoError(t, c.Register("db", db))
+    require.NoError(t, c.Register("db.alias", db))
+    require.NoError(t, c.Register("cache", &destroyedService{name: "cache", log: &log, fail: errors.New("flush failed")}))
+    require.NoError(t, c.Register("plain", &testServiceImpl{}))
+
+    err := c.Stop(context.Background())
+    assert.EqualError(t, err, "PreDestroy of cache (*container.destroyedService) failed: flush failed")
+    assert.Equal(t, constructLog{"cache", "db"}, log)
+
+    // Instances are destroyed once
+    require.NoError(t, c.Stop(context.Background()))
+    assert.Equal(t, constructLog{"cache", "db"}, log)
+
+    // A swapped in instance is destroyed on the next stop
+    _, err = c.Swap("cache", &destroyedService{name: "new cache", log: &log})
+    require.NoError(t, err)
+    require.NoError(t, c.Stop(context.Background()))
+    assert.Equal(t, constructLog{"cache", "db", "new cache"}, log)
+}
@@ -0,0 +1,272 @@
+package container
+
+import (
+    "fmt"
+    "reflect"
+    "strings"
+)
+
+// provider is a constructor registered with Provide
+type provider struct {
+    ctor     reflect.Value
+    out      reflect.Type // Type of the service the constructor returns
+    declared []string     // Dependencies declared with DependsOn
+}
+
+// Provide registers a singleton built by calling ctor, any function whose
+// parameters are other services:
+//
+//	c.Provide("signup", func(users UserService, mailer Mailer) (*SignupHandler, error) {
+//	    return NewSignupHandler(users, mailer), nil
+//	})
+//
+// Parameters are resolved like those of Invoke: a *Container receives c, a
+// struct with di tagged fields is filled by qualifier and anything else is
+// resolved by type. ctor returns the service, optionally followed by an
+// error.
+//
+// Build and Start derive a dependency graph from the signatures of all
+// providers, record it as DependsOn so budgets and hooks follow it, and
+// construct every provider in topological order, handing the independent
+// providers of each level to the Executor set with SetExecutor. Until then,
+// a provider is built on its first Resolve like a factory.
+func (c *Container) Provide(qualifier string, ctor interface{}, opts ...RegisterOption) error {
+    ctorValue := reflect.ValueOf(ctor)
+    if ctorValue.Kind() != reflect.Func || ctorValue.IsNil() {
+        return fmt.Errorf("provider for %s must be a function, got %T", qualifier, ctor)
+    }
+    ctorType := ctorValue.Type()
+    if ctorType.IsVariadic() {
+        return fmt.Errorf("provider for %s cannot be variadic: %v", qualifier, ctorType)
+    }
+    if n := ctorType.NumOut(); n == 0 || n > 2 || (n == 2 && ctorType.Out(1) != errorType) {
+        return fmt.Errorf("provider for %s must return a service and optionally an error, got %v", qualifier, ctorType)
+    }
+
+    p := &provider{ctor: ctorValue, out: ctorType.Out(0)}
+    factory := func(c *Container) (interface{}, error) {
+        return c.callProvider(qualifier, p)
+    }
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    if err := c.registerFactoryLocked(qualifier, factory, opts); err != nil {
+        return admitted(err)
+    }
+    p.declared = append([]string(nil), c.regs[qualifier].dependsOn...)
+    c.providers[qualifier] = p
+    return nil
+}
+
+// callProvider resolves the parameters of a provider and calls it
+func (c *Container) callProvider(qualifier string, p *provider) (interface{}, error) {
+    ctorType := p.ctor.Type()
+    args := make([]reflect.Value, ctorType.NumIn())
+    for i := range args {
+        arg, err := c.invokeArgument(ctorType.In(i))
+        if err != nil {
+            c.log.Errorw("Failed to resolve provider parameter",
+                "qualifier", qualifier,
+                "index", i,
+                "type", ctorType.In(i),
+                "error", err)
+            return nil, fmt.Errorf("parameter %d (%v) of provider %s: %w", i, ctorType.In(i), qualifier, err)
+        }
+        args[i] = arg
+    }
+
+    results := p.ctor.Call(args)
+    if len(results) == 2 {
+        if err, _ := results[1].Interface().(error); err != nil {
+            return nil, fmt.Errorf("provider %s failed: %w", qualifier, err)
+        }
+    }
+    service := results[0]
+    if isNilable(service.Kind()) && service.IsNil() {
+        return nil, fmt.Errorf("provider %s returned nil %v", qualifier, p.out)
+    }
+    return service.Interface(), nil
+}
+
+// providerType returns the type a provider that was not built yet returns
+func (c *Container) providerType(qualifier string) (reflect.Type, bool) {
+    c.mu.RLock()
+    defer c.mu.RUnlock()
+
+    p, ok := c.providers[qualifier]
+    if !ok {
+        return nil, false
+    }
+    if _, built := c.services[qualifier]; built {
+        return nil, false
+    }
+    return p.out, true
+}
+
+// providerDependencies returns the qualifiers the parameters of a provider
+// resolve to
+func (c *Container) providerDependencies(qualifier string, p *provider) ([]string, error) {
+    var deps []string
+    ctorType := p.ctor.Type()
+    for i := 0; i < ctorType.NumIn(); i++ {
+        paramType := ctorType.In(i)
+        switch {
+        case paramType == containerPtrType:
+        case isTaggedStruct(paramType):
+            deps = append(deps, c.taggedQualifiers(paramType)...)
+        default:
+            dep, err := c.qualifierForType(paramType)
+            if err != nil {
+                return nil, fmt.Errorf("parameter %d (%v) of provider %s: %w", i, paramType, qualifier, err)
+            }
+            if dep == "" {
+                return nil, fmt.Errorf("parameter %d of provider %s: no service assignable to %v", i, qualifier, paramType)
+            }
+            deps = append(deps, dep)
+        }
+    }
+    return deps, nil
+}
+
+// taggedQualifiers returns the final qualifiers of the di tagged fields of a
+// parameter object, skipping di:"" and options fields
+func (c *Container) taggedQualifiers(structType reflect.Type) []string {
+    defaults, markerIndex, _ := readStructDefaults(structType)
+    var qualifiers []string
+    for i := 0; i < structType.NumField(); i++ {
+        tag, ok := structType.Field(i).Tag.Lookup("di")
+        if !ok || i == markerIndex {
+            continue
+        }
+        spec := parseTag(tag)
+        if spec.qualifier == "" || spec.qualifier == OptionsTag {
+            continue
+        }
+        qualifiers = append(qualifiers, c.renamed(defaults.prefix+spec.qualifier, func() string {
+            return fmt.Sprintf("parameter %v", structType)
+        }))
+    }
+    return qualifiers
+}
+
+// wireProviders records the dependencies of every provider as DependsOn
+// and rejects cycles between providers. It runs in the validate phase.
+func (c *Container) wireProviders() error {
+    c.mu.RLock()
+    providers := make(map[string]*provider, len(c.providers))
+    for qualifier, p := range c.providers {
+        providers[qualifier] = p
+    }
+    c.mu.RUnlock()
+    if len(providers) == 0 {
+        return nil
+    }
+
+    graph := make(map[string][]string, len(providers))
+    for _, qualifier := range c.snapshotOrder() {
+        p, ok := providers[qualifier]
+        if !ok {
+            continue
+        }
+        deps, err := c.providerDependencies(qualifier, p)
+        if err != nil {
+            return err
+        }
+        graph[qualifier] = deps
+    }
+    if err := providerCycle(c.snapshotOrder(), graph); err != nil {
+        return err
+    }
+
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    for qualifier, deps := range graph {
+        c.regs[qualifier].dependsOn = append(append([]string(nil), providers[qualifier].declared...), deps...)
+    }
+    c.log.Debugw("Wired providers", "providers", len(graph))
+    return nil
+}
+
+// providerCycle returns an error naming the first cycle in graph, visiting
+// qualifiers in order
+func providerCycle(order []string, graph map[string][]string) error {
+    const (
+        unvisited = iota
+        visiting
+        done
+    )
+    state := make(map[string]int, len(graph))
+    var path []string
+    var visit func(qualifier string) error
+    visit = func(qualifier string) error {
+        switch state[qualifier] {
+        case visiting:
+            start := 0
+            for path[start] != qualifier {
+                start++
+            }
+            cycle := append(append([]string(nil), path[start:]...), qualifier)
+            return fmt.Errorf("provider dependency cycle: %s", strings.Join(cycle, " -> "))
+        case done:
+            return nil
+        }
+
+        state[qualifier] = visiting
+        path = append(path, qualifier)
+        for _, dep := range graph[qualifier] {
+            if _, isProvider := graph[dep]; !isProvider {
+                continue
+            }
+            if err := visit(dep); err != nil {
+                return err
+            }
+        }
+        path = path[:len(path)-1]
+        state[qualifier] = done
+        return nil
+    }
+
+    for _, qualifier := range order {
+        if _, isProvider := graph[qualifier]; !isProvider {
+            continue
+        }
+        if err := visit(qualifier); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// constructProviders builds every provider in dependency order. It runs in
+// Build and in the construct phase of Start, after wireProviders. The
+// providers of one dependency level are independent, so each level is
+// handed to the configured Executor.
+func (c *Container) constructProviders() error {
+    ctx := c.currentContext()
+    for _, level := range c.dependencyLevels() {
+        executor := c.newExecutor()
+        for _, qualifier := range level {
+            c.mu.RLock()
+            _, isProvider := c.providers[qualifier]
+            c.mu.RUnlock()
+            if !isProvider {
+                continue
+            }
+
+            qualifier := qualifier
+            executor.Go(func() error {
+                c.log.Debugw("Constructing provider", "qualifier", qualifier)
+                if _, err := c.ResolveContext(ctx, qualifier); err != nil {
+                    c.log.Errorw("Provider construction failed",
+                        "qualifier", qualifier,
+                        "error", err)
+                    return fmt.Errorf("failed to construct %s: %w", qualifier, err)
+                }
+                return nil
+            })
+        }
+        if err := executor.Wait(); err != nil {
+            return err
+        }
+    }
+    return nil
+}
@@ -0,0 +1,157 @@
+package container
+
+import (
+    "context"
+    "errors"
+    "sync"
+    "testing"
+    "time"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+type providedStore struct{ dsn string }
+
+type providedRepo struct{ store *providedStore }
+
+type providedHandler struct {
+    repo *providedRepo
+    name string
+}
+
+func TestProvide_BuildConstructsInDependencyOrder(t *testing.T) {
+    c := NewContainer()
+    var built []string
+
+    // Registered before their dependencies; Build sorts them out
+    require.NoError(t, c.Provide("handler", func(repo *providedRepo, deps struct {
+        Name string `di:"name"`
+    }) *providedHandler {
+        built = append(built, "handler")
+        return &providedHandler{repo: repo, name: deps.Name}
+    }))
+    require.NoError(t, c.Provide("repo", func(store *providedStore) (*providedRepo, error) {
+        built = append(built, "repo")
+        return &providedRepo{store: store}, nil
+    }))
+    require.NoError(t, c.Provide("store", func() *providedStore {
+        built = append(built, "store")
+        return &providedStore{dsn: "memory"}
+    }))
+    require.NoError(t, c.Register("name", "signup"))
+    assert.Empty(t, built, "providers are lazy until Build")
+
+    require.NoError(t, c.Build())
+    assert.Equal(t, []string{"store", "repo", "handler"}, built)
+
+    handler, err := ResolveAs[*providedHandler](c, "handler")
+    require.NoError(t, err)
+    assert.Equal(t, "memory", handler.repo.store.dsn)
+    assert.Equal(t, "signup", handler.name)
+
+    c.mu.RLock()
+    defer c.mu.RUnlock()
+    assert.Equal(t, []string{"repo", "name"}, c.regs["handler"].dependsOn)
+}
+
+func TestProvide_BuildConstructsLevelsInParallel(t *testing.T) {
+    c := NewContainer()
+    c.SetExecutor(Parallel(0))
+
+    // The two stores only finish once both are being built at once
+    var started sync.WaitGroup
+    started.Add(2)
+    bothStarted := make(chan struct{})
+    go func() {
+        started.Wait()
+        close(bothStarted)
+    }()
+    store := func(dsn string) func() (*providedStore, error) {
+        return func() (*providedStore, error) {
+            started.Done()
+            select {
+            case <-bothStarted:
+                return &providedStore{dsn: dsn}, nil
+            case <-time.After(5 * time.Second):
+                return nil, errors.New("independent providers were built one at a time")
+            }
+        }
+    }
+    require.NoError(t, c.Provide("primary", store("primary")))
+    require.NoError(t, c.Provide("replica", store("replica")))
+    require.NoError(t, c.Provide("repo", func(deps struct {
+        Primary *providedStore `di:"primary"`
+        Replica *providedStore `di:"replica"`
+    }) *providedRepo {
+        return &providedRepo{store: deps.Primary}
+    }))
+
+    require.NoError(t, c.Build())
+    repo, err := ResolveAs[*providedRepo](c, "repo")
+    require.NoError(t, err)
+    assert.Equal(t, "primary", repo.store.dsn)
+    assert.Equal(t, [][]string{{"primary", "replica"}, {"repo"}}, c.dependencyLevels())
+}
+
+func TestProvide_StartConstructsProviders(t *testing.T) {
+    c := NewContainer()
+    require.NoError(t, c.Provide("store", func() *providedStore {
+        return &providedStore{}
+    }))
+    require.NoError(t, c.Start(context.Background()))
+
+    c.mu.RLock()
+    _, built := c.services["store"]
+    c.mu.RUnlock()
+    assert.True(t, built)
+}
+
+func TestProvide_RejectsCycles(t *testing.T) {
+    c := NewContainer()
+    require.NoError(t, c.Provide("repo", func(store *providedStore) *providedRepo {
+        return &providedRepo{store: store}
+    }))
+    require.NoError(t, c.Provide("store", func(repo *providedRepo) *providedStore {
+        return &providedStore{}
+    }))
+
+    assert.EqualError(t, c.Build(), "provider dependency cycle: repo -> store -> repo")
+}
+
+func TestProvide_ReportsMissingDependencies(t *testing.T) {
+    c := NewContainer()
+    require.NoError(t, c.Provide("repo", func(store *providedStore) *providedRepo {
+        return &providedRepo{store: store}
+    }))
+
+    assert.ErrorContains(t, c.Build(), "parameter 0 of provider repo: no service assignable to *container.providedStore")
+}
+
+func TestProvide_ReportsConstructorErrors(t *testing.T) {
+    c := NewContainer()
+    require.NoError(t, c.Provide("store", func() (*providedStore, error) {
+        return nil, errors.New("connection refused")
+    }))
+
+    err := c.Build()
+    assert.ErrorContains(t, err, "failed to construct store")
+    assert.ErrorContains(t, err, "provider store failed: connection refused")
+}
+
+func TestProvide_RejectsInvalidConstructors(t *testing.T) {
+    c := NewContainer()
+
+    assert.ErrorContains(t, c.Provide("a", "not a function"), "must be a function")
+    assert.ErrorContains(t, c.Provide("b", func() {}), "must return a service")
+    assert.ErrorContains(t, c.Provide("c", func() (int, int) { return 0, 0 }), "must return a service")
+    assert.ErrorContains(t, c.Provide("d", func(...int) int { return 0 }), "cannot be variadic")
+}
+
+func TestProvide_NilResultFails(t *testing.T) {
+    c := NewContainer()
+    require.NoError(t, c.Provide("store", func() *providedStore { return nil }))
+
+    _, err := c.Resolve("store")
+    assert.ErrorContains(t, err, "provider store returned nil *container.providedStore")
+}
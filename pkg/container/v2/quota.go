@@ -0,0 +1,76 @@
+package container
+
+import "fmt"
+
+// Quota limits how many services can be registered. Zero values mean
+// unlimited. Quotas catch runaway dynamic registration, e.g. a bug that
+// registers a new service per request.
+type Quota struct {
+    MaxRegistrations int            // Limit across the whole container
+    MaxPerModule     int            // Default limit for every module
+    ModuleLimits     map[string]int // Per-module overrides of MaxPerModule
+}
+
+// SetQuota installs registration limits. Existing registrations are kept
+// even if they exceed the new limits; only new registrations are rejected.
+func (c *Container) SetQuota(quota Quota) {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+
+    c.log.Infow("Setting registration quota",
+        "maxRegistrations", quota.MaxRegistrations,
+        "maxPerModule", quota.MaxPerModule)
+    c.quota = quota
+}
+
+// moduleLimit returns the limit that applies to module, 0 for unlimited
+func (q Quota) moduleLimit(module string) int {
+    if limit, ok := q.ModuleLimits[module]; ok {
+        return limit
+    }
+    if module == "" {
+        return 0 // Top-level registrations only count toward MaxRegistrations
+    }
+    return q.MaxPerModule
+}
+
+// checkQuotaLocked rejects reg if it would exceed a quota. Callers must hold c.mu.
+func (c *Container) checkQuotaLocked(reg *registration) error {
+    if limit := c.quota.MaxRegistrations; limit > 0 && len(c.regs) >= limit {
+        c.metrics.IncCounter("di_quota_rejections_total", map[string]string{"scope": "container"})
+        c.log.Errorw("Registration quota exceeded",
+            "qualifier", reg.qualifier,
+            "limit", limit)
+        return fmt.Errorf("registration quota of %d services exceeded by %s", limit, reg.qualifier)
+    }
+
+    if limit := c.quota.moduleLimit(reg.module); limit > 0 && c.moduleCountLocked(reg.module) >= limit {
+        c.metrics.IncCounter("di_quota_rejections_total", map[string]string{"scope": "module", "module": reg.module})
+        c.log.Errorw("Module registration quota exceeded",
+            "qualifier", reg.qualifier,
+            "module", reg.module,
+            "limit", limit)
+        return fmt.Errorf("module %s registration quota of %d services exceeded by %s", reg.module, limit, reg.qualifier)
+    }
+    return nil
+}
+
+// moduleCountLocked counts the registrations owned by module
+func (c *Container) moduleCountLocked(module string) int {
+    count := 0
+    for _, reg := range c.regs {
+        if reg.module == module {
+            count++
+        }
+    }
+    return count
+}
+
+// publishRegistrationGaugesLocked reports registration counts to the metrics sink
+func (c *Container) publishRegistrationGaugesLocked(module string) {
+    c.metrics.SetGauge("di_registrations", float64(len(c.regs)), nil)
+    if module != "" {
+        c.metrics.SetGauge("di_module_registrations", float64(c.moduleCountLocked(module)),
+            map[string]string{"module": module})
+    }
+}
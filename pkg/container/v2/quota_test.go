@@ -0,0 +1,117 @@
+package container
+
+import (
+    "errors"
+    "fmt"
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+func TestContainer_Install(t *testing.T) {
+    container := NewContainer()
+
+    err := container.Install(Module{
+        Name: "billing",
+        Setup: func(c *Container) error {
+            if err := c.Register("invoices", &testServiceImpl{name: "invoices"}); err != nil {
+                return err
+            }
+            return c.Register("shared", &testServiceImpl{name: "shared"}, InModule("platform"))
+        },
+    })
+    require.NoError(t, err)
+    require.NoError(t, container.Register("topLevel", &testServiceImpl{name: "top"}))
+
+    assert.Equal(t, "billing", container.regs["invoices"].module)
+    assert.Equal(t, "platform", container.regs["shared"].module)
+    assert.Equal(t, "", container.regs["topLevel"].module)
+    assert.Empty(t, container.installing)
+
+    err = container.Install(Module{
+        Name:  "broken",
+        Setup: func(c *Container) error { return errors.New("no config") },
+    })
+    assert.ErrorContains(t, err, "module broken")
+}
+
+func TestContainer_Quota(t *testing.T) {
+    container := NewContainer()
+    metrics := newRecordingMetrics()
+    container.SetMetricsSink(metrics)
+    container.SetQuota(Quota{
+        MaxRegistrations: 5,
+        MaxPerModule:     2,
+        ModuleLimits:     map[string]int{"plugins": 3},
+    })
+
+    registered := 0
+    register := func(module string, count int) error {
+        for i := 0; i < count; i++ {
+            registered++
+            qualifier := fmt.Sprintf("%s-%d", module, registered)
+            if err := container.Register(qualifier, &testServiceImpl{name: qualifier}, InModule(module)); err != nil {
+                return err
+            }
+        }
+        return nil
+    }
+
+    require.NoError(t, register("billing", 2))
+    assert.ErrorContains(t, register("billing", 3), "module billing registration quota of 2")
+    require.NoError(t, register("plugins", 3))
+    assert.ErrorContains(t, register("", 1), "registration quota of 5 services")
+
+    assert.Equal(t, float64(5), metrics.gauges["di_registrations"])
+    assert.Equal(t, float64(3), metrics.gauges["di_module_registrations{plugins}"])
+    assert.Equal(t, 1, metrics.counters["di_quota_rejections_total{billing}"])
+    assert.Equal(t, 1, metrics.counters["di_quota_rejections_total"])
+}
+
+func TestModule_ActiveIn(t *testing.T) {
+    tests := []struct {
+        profiles []string
+        profile  string
+        want     bool
+    }{
+        {profiles: nil, profile: "test", want: true},
+        {profiles: []string{"test"}, profile: "test", want: true},
+        {profiles: []string{"test"}, profile: DefaultProfile, want: false},
+        {profiles: []string{"!test"}, profile: DefaultProfile, want: true},
+        {profiles: []string{"!test"}, profile: "test", want: false},
+        {profiles: []string{"staging", "!test"}, profile: "prod", want: false},
+    }
+
+    for _, tt := range tests {
+        got := Module{Profiles: tt.profiles}.activeIn(tt.profile)
+        assert.Equal(t, tt.want, got, "profiles %v in %s", tt.profiles, tt.profile)
+    }
+}
+
+func TestContainer_InstallProfiles(t *testing.T) {
+    production := Module{
+        Name:     "email",
+        Profiles: []string{"!test"},
+        Setup: func(c *Container) error {
+            return c.Register("emailService", &testServiceImpl{name: "smtp"})
+        },
+    }
+    fake := Module{
+        Name:     "email-test",
+        Profiles: []string{"test"},
+        Setup: func(c *Container) error {
+            return c.Register("emailService", &testServiceImpl{name: "fake"})
+        },
+    }
+
+    for profile, want := range map[string]string{DefaultProfile: "smtp", "test": "fake"} {
+        container := NewContainer()
+        container.SetProfile(profile)
+        require.NoError(t, container.Install(production, fake))
+
+        service, err := container.Resolve("emailService")
+        require.NoError(t, err)
+        assert.Equal(t, want, service.(*testServiceImpl).name)
+    }
+}
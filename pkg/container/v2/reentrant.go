@@ -0,0 +1,212 @@
+package container
+
+import (
+    "fmt"
+    "strings"
+    "sync"
+)
+
+// Providers and decorators may call back into the container: a weak
+// provider can Resolve its own dependencies and a decorator can Resolve the
+// services it wraps with. Three rules keep this safe:
+//
+//   - No user code runs while c.mu is held. Register, Swap and DecorateGroup
+//     serialize on c.writeMu instead and only take c.mu to read or commit.
+//   - Every goroutine keeps a stack of the weak and factory services it is
+//     building, so a provider that resolves, directly or not, the service
+//     it is building fails with a cycle error instead of recursing forever.
+//   - Goroutines building factories that resolve each other, A -> B on one
+//     and B -> A on another, would wait for each other's build locks
+//     forever. The container tracks who holds and who waits for every build
+//     lock, and a goroutine about to close such a loop fails with a cycle
+//     error instead of waiting.
+
+// enterResolution pushes qualifier on this goroutine's build stack. It fails
+// if the qualifier is already being built by this goroutine. The returned
+// func pops the qualifier.
+func (c *Container) enterResolution(qualifier string) (func(), error) {
+    gid := goroutineID()
+
+    c.resolvingMu.Lock()
+    defer c.resolvingMu.Unlock()
+
+    stack := c.resolving[gid]
+    for i, building := range stack {
+        if building == qualifier {
+            cycle := append(append([]string(nil), stack[i:]...), qualifier)
+            c.log.Errorw("Dependency cycle during resolution",
+                "qualifier", qualifier,
+                "cycle", cycle)
+            return nil, fmt.Errorf("dependency cycle while building %s: %s", qualifier, strings.Join(cycle, " -> "))
+        }
+    }
+
+    c.resolving[gid] = append(stack, qualifier)
+    c.builds.Add(1)
+    return func() {
+        c.builds.Add(-1)
+        c.resolvingMu.Lock()
+        defer c.resolvingMu.Unlock()
+
+        if stack := c.resolving[gid]; len(stack) > 1 {
+            c.resolving[gid] = stack[:len(stack)-1]
+        } else {
+            delete(c.resolving, gid)
+        }
+    }, nil
+}
+
+// acquireBuild takes mu, the build lock of qualifier, unless waiting for it
+// would deadlock: when its holder waits, directly or through other
+// goroutines, for a build lock the calling goroutine holds. The returned
+// func releases mu.
+func (c *Container) acquireBuild(qualifier string, mu *sync.Mutex) (func(), error) {
+    gid := goroutineID()
+
+    c.resolvingMu.Lock()
+    if !mu.TryLock() {
+        // Follow the holders and what they wait for back to this goroutine
+        chain := []string{qualifier}
+        for next := qualifier; ; {
+            holder, held := c.builders[next]
+            if !held {
+                break
+            }
+            if holder == gid {
+                c.resolvingMu.Unlock()
+                cycle := append([]string{next}, chain...)
+                c.log.Errorw("Dependency cycle across goroutines",
+                    "qualifier", qualifier,
+                    "cycle", cycle)
+                return nil, fmt.Errorf("dependency cycle while building %s: %s, built by concurrent goroutines", qualifier, strings.Join(cycle, " -> "))
+            }
+            if next = c.waiting[holder]; next == "" {
+                break
+            }
+            chain = append(chain, next)
+        }
+        c.waiting[gid] = qualifier
+        c.resolvingMu.Unlock()
+
+        mu.Lock()
+        c.resolvingMu.Lock()
+        delete(c.waiting, gid)
+    }
+    c.builders[qualifier] = gid
+    c.resolvingMu.Unlock()
+
+    return func() {
+        c.resolvingMu.Lock()
+        delete(c.builders, qualifier)
+        c.resolvingMu.Unlock()
+        mu.Unlock()
+    }, nil
+}
+
+// recordDependency records that the service this goroutine is building
+// resolved qualifier, an edge of the dependency graph Close follows. It
+// costs nothing while no service is being built.
+func (c *Container) recordDependency(qualifier string) {
+    if c.builds.Load() == 0 {
+        return
+    }
+    gid := goroutineID()
+
+    c.resolvingMu.Lock()
+    defer c.resolvingMu.Unlock()
+
+    stack := c.resolving[gid]
+    if len(stack) == 0 {
+        return
+    }
+    dependent := stack[len(stack)-1]
+    if dependent == qualifier {
+        return
+    }
+    for _, dependency := range c.observed[dependent] {
+        if dependency == qualifier {
+            return
+        }
+    }
+    c.observed[dependent] = append(c.observed[dependent], qualifier)
+}
+
+// observedDependencies snapshots the dependencies recorded while building
+func (c *Container) observedDependencies() map[string][]string {
+    c.resolvingMu.Lock()
+    defer c.resolvingMu.Unlock()
+
+    observed := make(map[string][]string, len(c.observed))
+    for qualifier, dependencies := range c.observed {
+        observed[qualifier] = append([]string(nil), dependencies...)
+    }
+    return observed
+}
+
+// groupDecorators is the decorators of one group, copied so they can run
+// without holding c.mu
+type groupDecorators struct {
+    group      string
+    decorators []Decorator
+}
+
+// decoratorsLocked snapshots the decorators of every group of reg, after
+// the guard of its limits, which has no group. A qualifier without a
+// registration, a nil reg, has no decorators. Callers must hold c.mu.
+func (c *Container) decoratorsLocked(reg *registration) []groupDecorators {
+    if reg == nil {
+        return nil
+    }
+    var snapshot []groupDecorators
+    if reg.limits != nil {
+        guard := c.guardLocked(reg)
+        snapshot = append(snapshot, groupDecorators{decorators: []Decorator{
+            func(_ string, service interface{}) (interface{}, error) {
+                return guardService(service, guard)
+            },
+        }})
+    }
+    for _, group := range reg.groups {
+        if decorators := c.decorators[group]; len(decorators) > 0 {
+            snapshot = append(snapshot, groupDecorators{
+                group:      group,
+                decorators: append([]Decorator(nil), decorators...),
+            })
+        }
+    }
+    return snapshot
+}
+
+// decorate applies a decorator snapshot to service. It must be called
+// without holding c.mu.
+func (c *Container) decorate(qualifier string, service interface{}, snapshot []groupDecorators) (interface{}, error) {
+    for _, group := range snapshot {
+        decorated, err := applyDecorators(qualifier, service, group.decorators)
+        if err != nil && group.group == "" {
+            c.log.Errorw("Applying limits failed", "qualifier", qualifier, "error", err)
+            return nil, fmt.Errorf("failed to apply limits to %s: %w", qualifier, err)
+        }
+        if err != nil {
+            c.log.Errorw("Group decorator failed",
+                "group", group.group,
+                "qualifier", qualifier,
+                "error", err)
+            return nil, fmt.Errorf("failed to decorate %s in group %s: %w", qualifier, group.group, err)
+        }
+        service = decorated
+    }
+    return service, nil
+}
+
+// isBuilding reports whether the calling goroutine is building qualifier
+func (c *Container) isBuilding(qualifier string) bool {
+    c.resolvingMu.Lock()
+    defer c.resolvingMu.Unlock()
+
+    for _, building := range c.resolving[goroutineID()] {
+        if building == qualifier {
+            return true
+        }
+    }
+    return false
+}
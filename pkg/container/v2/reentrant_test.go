@@ -0,0 +1,191 @@
+package container
+
+import (
+    "fmt"
+    "sync"
+    "testing"
+    "time"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+// withinDeadline fails the test if run does not return in time, which is how
+// a deadlock shows up
+func withinDeadline(t *testing.T, run func()) {
+    t.Helper()
+    done := make(chan struct{})
+    go func() {
+        defer close(done)
+        run()
+    }()
+    select {
+    case <-done:
+    case <-time.After(2 * time.Second):
+        t.Fatal("container call deadlocked")
+    }
+}
+
+func TestReentrant_NestedWeakProviders(t *testing.T) {
+    c := NewContainer()
+    require.NoError(t, c.Register("dsn", "postgres://db"))
+    require.NoError(t, c.RegisterWeak("pool", func() (interface{}, error) {
+        dsn, err := c.Resolve("dsn")
+        if err != nil {
+            return nil, err
+        }
+        return "pool(" + dsn.(string) + ")", nil
+    }))
+    require.NoError(t, c.RegisterWeak("repo", func() (interface{}, error) {
+        pool, err := c.Resolve("pool")
+        if err != nil {
+            return nil, err
+        }
+        return "repo(" + pool.(string) + ")", nil
+    }))
+
+    withinDeadline(t, func() {
+        repo, err := c.Resolve("repo")
+        require.NoError(t, err)
+        assert.Equal(t, "repo(pool(postgres://db))", repo)
+    })
+}
+
+func TestReentrant_Cycle(t *testing.T) {
+    c := NewContainer()
+    resolveIn := func(qualifier string) WeakProvider {
+        return func() (interface{}, error) {
+            return c.Resolve(qualifier)
+        }
+    }
+    require.NoError(t, c.RegisterWeak("a", resolveIn("b")))
+    require.NoError(t, c.RegisterWeak("b", resolveIn("a")))
+    require.NoError(t, c.RegisterWeak("self", resolveIn("self")))
+
+    withinDeadline(t, func() {
+        _, err := c.Resolve("a")
+        assert.ErrorContains(t, err, "dependency cycle while building a: a -> b -> a")
+
+        _, err = c.Resolve("self")
+        assert.ErrorContains(t, err, "self -> self")
+    })
+
+    // The build stack is cleared after a failure
+    c.resolvingMu.Lock()
+    assert.Empty(t, c.resolving)
+    c.resolvingMu.Unlock()
+}
+
+func TestReentrant_ConcurrentBuildsAreNotCycles(t *testing.T) {
+    c := NewContainer()
+    require.NoError(t, c.Register("dsn", "postgres://db"))
+    require.NoError(t, c.RegisterWeak("pool", func() (interface{}, error) {
+        time.Sleep(time.Millisecond) // Overlap the builds of both goroutines
+        return c.Resolve("dsn")
+    }))
+    c.SetWeakCapacity(0) // Every resolution rebuilds
+
+    var wg sync.WaitGroup
+    for i := 0; i < 8; i++ {
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+            _, err := c.Resolve("pool")
+            assert.NoError(t, err)
+        }()
+    }
+    wg.Wait()
+}
+
+func TestReentrant_CycleAcrossGoroutines(t *testing.T) {
+    c := NewContainer()
+    var started sync.WaitGroup
+    started.Add(2)
+    resolveIn := func(qualifier string) Factory {
+        var once sync.Once
+        return func(c *Container) (interface{}, error) {
+            // Both first builds hold their own lock before resolving the other
+            once.Do(started.Done)
+            started.Wait()
+            return c.Resolve(qualifier)
+        }
+    }
+    require.NoError(t, c.RegisterFactory("a", resolveIn("b")))
+    require.NoError(t, c.RegisterFactory("b", resolveIn("a")))
+
+    errs := make([]error, 2)
+    withinDeadline(t, func() {
+        var wg sync.WaitGroup
+        for i, qualifier := range []string{"a", "b"} {
+            wg.Add(1)
+            go func(i int, qualifier string) {
+                defer wg.Done()
+                _, errs[i] = c.Resolve(qualifier)
+            }(i, qualifier)
+        }
+        wg.Wait()
+    })
+    for _, err := range errs {
+        assert.ErrorContains(t, err, "dependency cycle")
+    }
+
+    c.resolvingMu.Lock()
+    defer c.resolvingMu.Unlock()
+    assert.Empty(t, c.builders)
+    assert.Empty(t, c.waiting)
+}
+
+func TestReentrant_DecoratorsResolve(t *testing.T) {
+    c := NewContainer()
+    require.NoError(t, c.Register("prefix", "auth"))
+    require.NoError(t, c.Register("users", "users", InGroup("handlers")))
+
+    wrapWithPrefix := func(qualifier string, service interface{}) (interface{}, error) {
+        prefix, err := c.Resolve("prefix")
+        if err != nil {
+            return nil, err
+        }
+        return fmt.Sprintf("%s(%v)", prefix, service), nil
+    }
+
+    withinDeadline(t, func() {
+        require.NoError(t, c.DecorateGroup("handlers", wrapWithPrefix))
+        require.NoError(t, c.Register("orders", "orders", InGroup("handlers")))
+        _, err := c.Swap("users", "users2")
+        require.NoError(t, err)
+        require.NoError(t, c.RegisterWeak("reports", func() (interface{}, error) {
+            return "reports", nil
+        }, InGroup("handlers")))
+
+        members, err := c.ResolveGroup("handlers")
+        require.NoError(t, err)
+        assert.Equal(t, []interface{}{"auth(users2)", "auth(orders)", "auth(reports)"}, members)
+    })
+}
+
+func TestReentrant_DecoratorsOfMissingRegistration(t *testing.T) {
+    c := NewContainer()
+    require.NoError(t, c.DecorateGroup("handlers", func(_ string, service interface{}) (interface{}, error) {
+        return service, nil
+    }))
+
+    c.mu.RLock()
+    defer c.mu.RUnlock()
+    assert.NotPanics(t, func() {
+        assert.Nil(t, c.decoratorsLocked(nil))
+    })
+}
+
+func TestReentrant_RegisterRechecksAfterDecorators(t *testing.T) {
+    c := NewContainer()
+    require.NoError(t, c.DecorateGroup("handlers", func(qualifier string, service interface{}) (interface{}, error) {
+        // Takes the qualifier while Register is decorating
+        require.NoError(t, c.RegisterWeak(qualifier, func() (interface{}, error) { return "weak", nil }))
+        return service, nil
+    }))
+
+    withinDeadline(t, func() {
+        err := c.Register("users", "users", InGroup("handlers"))
+        assert.ErrorContains(t, err, "already registered")
+    })
+}
@@ -0,0 +1,180 @@
+package container
+
+import (
+    "errors"
+    "fmt"
+    "reflect"
+    "sort"
+    "sync/atomic"
+)
+
+// Init stages commonly used with InStage. Lower stages start first.
+const (
+    StageInfrastructure = 0 // Databases, queues, caches
+    StageDomain         = 1 // Business services
+    StageTransport      = 2 // HTTP servers, consumers
+)
+
+// Lifetime describes how the container keeps instances of a service
+type Lifetime int
+
+const (
+    // Singleton services have one instance for the life of the container
+    Singleton Lifetime = iota
+    // Weak services are cached in a bounded LRU and rebuilt after eviction
+    Weak
+    // Scoped services have one instance per Scope
+    Scoped
+    // Transient services are built anew by every resolve
+    Transient
+)
+
+// String returns the lowercase name of the lifetime
+func (l Lifetime) String() string {
+    switch l {
+    case Singleton:
+        return "singleton"
+    case Weak:
+        return "weak"
+    case Scoped:
+        return "scoped"
+    case Transient:
+        return "transient"
+    default:
+        return fmt.Sprintf("lifetime(%d)", int(l))
+    }
+}
+
+// registration holds the metadata attached to a registered service
+type registration struct {
+    qualifier string
+    stage     int      // Init stage used by Start and StartStage
+    lifetime  Lifetime // How instances are kept
+    module    string   // Owning module, empty for top-level registrations
+    profile   string   // Profile active when the service was registered
+    groups    []string // Groups the service is a member of
+    before    []string // Group members the service is ordered ahead of
+    after     []string // Group members the service is ordered behind
+    dependsOn []string // Declared dependencies, used by budgets
+    config    bool     // Config struct published through ConfigSchemas
+    sensitive bool     // Accesses emit audit events
+    waitFor   []Probe  // Readiness probes run before the service is built
+    ready     atomic.Bool // Whether the probes of waitFor succeeded once
+    limits    *Limits  // Limits guarding calls to the service, nil for none
+    retry     *RetryPolicy // Retries of a failed construction, nil for none
+    factory   bool     // Registered with a factory or provider
+    declared  reflect.Type // Result type of a constructor, nil when unknown
+    location  string   // file:line of the registration, see Explain
+    version   uint64   // Bumped when the instance behind the qualifier changes
+}
+
+// RegisterOption customizes a registration
+type RegisterOption func(*registration)
+
+// InStage assigns the registration to an init stage
+func InStage(stage int) RegisterOption {
+    return func(r *registration) {
+        r.stage = stage
+    }
+}
+
+// InModule attributes the registration to a module, overriding the module
+// being installed
+func InModule(module string) RegisterOption {
+    return func(r *registration) {
+        r.module = module
+    }
+}
+
+// newRegistrationLocked applies opts to a fresh registration owned by the
+// module this goroutine is installing, if any. Callers must hold c.mu.
+func (c *Container) newRegistrationLocked(qualifier string, opts []RegisterOption) *registration {
+    reg := &registration{qualifier: qualifier, profile: c.profile, location: callSite()}
+    if len(c.installing) > 0 {
+        reg.module = c.installing[goroutineID()]
+    }
+    for _, opt := range opts {
+        opt(reg)
+    }
+    return reg
+}
+
+// admitLocked rejects registrations after Freeze, duplicate qualifiers and
+// registrations over quota. Callers must hold c.mu.
+func (c *Container) admitLocked(reg *registration) error {
+    if err := c.checkFrozenLocked(reg); err != nil {
+        return err
+    }
+    if _, exists := c.regs[reg.qualifier]; exists {
+        if c.duplicates == DuplicateKeepFirst {
+            c.log.Warnw("Ignoring duplicate registration, keeping the first",
+                "qualifier", reg.qualifier)
+            return errKeptFirst
+        }
+        c.log.Errorw("Service already registered",
+            "qualifier", reg.qualifier)
+        return &DuplicateRegistrationError{Qualifier: reg.qualifier}
+    }
+    return c.checkQuotaLocked(reg)
+}
+
+// errKeptFirst is returned by admitLocked for a duplicate ignored under
+// DuplicateKeepFirst. Registration methods turn it into success with
+// admitted.
+var errKeptFirst = errors.New("duplicate registration ignored")
+
+// admitted returns the error a registration method reports for an
+// admitLocked error
+func admitted(err error) error {
+    if err == errKeptFirst {
+        return nil
+    }
+    return err
+}
+
+// recordLocked stores the registration metadata and publishes the new
+// registration counts. Callers must hold c.mu.
+func (c *Container) recordLocked(reg *registration) {
+    c.regs[reg.qualifier] = reg
+    if reg.sensitive {
+        atomic.AddInt32(&c.sensitiveCount, 1)
+    }
+    c.order = append(c.order, reg.qualifier)
+    c.bumpVersionLocked(reg.qualifier)
+    c.annotateRegisterLocked(reg.qualifier)
+    detail := reg.lifetime.String()
+    if reg.module != "" {
+        detail += ", module " + reg.module
+    }
+    c.recordMutation(MutationRegister, reg.qualifier, detail)
+    c.publishRegistrationGaugesLocked(reg.module)
+}
+
+// stageOf returns the init stage of a registration, 0 when unknown
+func (c *Container) stageOf(qualifier string) int {
+    c.mu.RLock()
+    defer c.mu.RUnlock()
+
+    if reg, ok := c.regs[qualifier]; ok {
+        return reg.stage
+    }
+    return 0
+}
+
+// registrationStages returns the distinct stages of all registrations in
+// ascending order
+func (c *Container) registrationStages() []int {
+    c.mu.RLock()
+    defer c.mu.RUnlock()
+
+    seen := make(map[int]bool)
+    var stages []int
+    for _, reg := range c.regs {
+        if !seen[reg.stage] {
+            seen[reg.stage] = true
+            stages = append(stages, reg.stage)
+        }
+    }
+    sort.Ints(stages)
+    return stages
+}
@@ -0,0 +1,144 @@
+package container
+
+import (
+    "reflect"
+    "weak"
+)
+
+// injectedVersion is the registration a field was last injected from
+type injectedVersion struct {
+    qualifier string
+    version   uint64
+    hot       bool // Hot fields follow Swap on their own
+}
+
+// bumpVersionLocked marks the instance behind qualifier as changed. Callers
+// must hold c.mu.
+func (c *Container) bumpVersionLocked(qualifier string) {
+    if reg, ok := c.regs[qualifier]; ok {
+        c.versionSeq++
+        reg.version = c.versionSeq
+    }
+}
+
+// registrationVersions returns the current version of every registration
+func (c *Container) registrationVersions() map[string]uint64 {
+    c.mu.RLock()
+    defer c.mu.RUnlock()
+
+    versions := make(map[string]uint64, len(c.regs))
+    for qualifier, reg := range c.regs {
+        versions[qualifier] = reg.version
+    }
+    return versions
+}
+
+// ReinjectStruct injects target like InjectStructWithResult, then remembers
+// which registration and version every field came from. Later calls only
+// overwrite fields whose registration changed since, through Register, Swap,
+// a group decorator or a rename, and report the others as FieldUnchanged.
+// This lets long-lived components refresh their dependencies periodically
+// without rebuilding weak or transient services they already hold.
+//
+// Versions are tracked until ForgetStruct or until target is garbage
+// collected; tracking does not keep target alive.
+func (c *Container) ReinjectStruct(target interface{}) (*InjectionResult, error) {
+    targetValue := reflect.ValueOf(target)
+    if target == nil || targetValue.Kind() != reflect.Ptr || targetValue.IsNil() ||
+        targetValue.Elem().Kind() != reflect.Struct {
+        // injectStruct reports the error
+        return c.injectStruct(target, c.resolveTraced, nil)
+    }
+    key := reinjectKey(targetValue)
+    targetType := targetValue.Elem().Type()
+
+    // Versions are read before resolving, so a change made while injecting
+    // is picked up by the next call
+    versions := c.registrationVersions()
+    c.inflightMu.Lock()
+    tracked := c.reinjected[key]
+    c.inflightMu.Unlock()
+
+    keep := func(field, qualifier string) bool {
+        last, ok := tracked[field]
+        if !ok || last.qualifier != qualifier {
+            return false
+        }
+        version, registered := versions[qualifier]
+        return last.hot || (registered && version == last.version)
+    }
+
+    result, err := c.injectStruct(target, c.resolveTraced, keep)
+    if err != nil {
+        // Tracking is left as it was, so every changed field is retried
+        return nil, err
+    }
+
+    current := make(map[string]injectedVersion, len(result.Fields))
+    updated := 0
+    for _, field := range result.Fields {
+        switch field.Status {
+        case FieldUnchanged:
+            current[field.Field] = tracked[field.Field]
+        case FieldInjected:
+            structField, _ := fieldByPath(targetType, field.Field)
+            current[field.Field] = injectedVersion{
+                qualifier: field.Qualifier,
+                version:   versions[field.Qualifier],
+                hot:       isHotFieldType(structField.Type),
+            }
+            updated++
+        }
+    }
+
+    c.inflightMu.Lock()
+    if tracked == nil {
+        c.dropCollectedLocked()
+    }
+    c.reinjected[key] = current
+    c.inflightMu.Unlock()
+
+    c.log.Debugw("Reinjected struct",
+        "structType", targetType,
+        "updated", updated,
+        "unchanged", len(current)-updated)
+    return result, nil
+}
+
+// ForgetStruct drops the versions ReinjectStruct tracks for target, so the
+// next ReinjectStruct injects every field again. Call it when a reinjected
+// component is discarded.
+func (c *Container) ForgetStruct(target interface{}) {
+    targetValue := reflect.ValueOf(target)
+    if target == nil || targetValue.Kind() != reflect.Ptr {
+        return
+    }
+
+    c.inflightMu.Lock()
+    defer c.inflightMu.Unlock()
+    delete(c.reinjected, reinjectKey(targetValue))
+}
+
+// reinjectKey identifies the struct target points to without keeping it
+// alive. Unlike its address, the key of a collected struct is not reused.
+func reinjectKey(target reflect.Value) weak.Pointer[byte] {
+    return weak.Make((*byte)(target.UnsafePointer()))
+}
+
+// dropCollectedLocked drops the tracking of structs that were garbage
+// collected. Callers must hold c.inflightMu.
+func (c *Container) dropCollectedLocked() {
+    for key := range c.reinjected {
+        if key.Value() == nil {
+            delete(c.reinjected, key)
+        }
+    }
+}
+
+// isHotFieldType reports whether a field of type t is updated by Swap
+func isHotFieldType(t reflect.Type) bool {
+    if t.Kind() == reflect.Ptr {
+        t = t.Elem()
+    }
+    return isHotType(t)
+}
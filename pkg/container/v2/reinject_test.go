@@ -0,0 +1,162 @@
+package container
+
+import (
+    "runtime"
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+type refreshedComponent struct {
+    Inject  `di:"optional"`
+    Pricing pricing `di:"pricing"`
+    Name    string  `di:"name"`
+    Region  string  `di:"region"`
+}
+
+func fieldStatuses(result *InjectionResult) map[string]FieldStatus {
+    statuses := make(map[string]FieldStatus, len(result.Fields))
+    for _, field := range result.Fields {
+        statuses[field.Field] = field.Status
+    }
+    return statuses
+}
+
+func TestReinjectStruct_OnlyOverwritesChangedFields(t *testing.T) {
+    c := NewContainer()
+    require.NoError(t, c.Register("pricing", &fixedPricing{price: 10}))
+    require.NoError(t, c.Register("name", "checkout"))
+
+    var component refreshedComponent
+    result, err := c.ReinjectStruct(&component)
+    require.NoError(t, err)
+    assert.Equal(t, map[string]FieldStatus{
+        "Pricing": FieldInjected,
+        "Name":    FieldInjected,
+        "Region":  FieldMissing,
+    }, fieldStatuses(result))
+
+    // Local edits survive while the registrations are unchanged
+    component.Name = "edited"
+    result, err = c.ReinjectStruct(&component)
+    require.NoError(t, err)
+    assert.Equal(t, FieldUnchanged, fieldStatuses(result)["Pricing"])
+    assert.Equal(t, FieldUnchanged, fieldStatuses(result)["Name"])
+    assert.Equal(t, "edited", component.Name)
+
+    _, err = c.Swap("pricing", &fixedPricing{price: 12})
+    require.NoError(t, err)
+    require.NoError(t, c.Register("region", "eu"))
+
+    result, err = c.ReinjectStruct(&component)
+    require.NoError(t, err)
+    assert.Equal(t, map[string]FieldStatus{
+        "Pricing": FieldInjected,
+        "Name":    FieldUnchanged,
+        "Region":  FieldInjected,
+    }, fieldStatuses(result))
+    assert.Equal(t, 12, component.Pricing.Price())
+    assert.Equal(t, "edited", component.Name)
+    assert.Equal(t, "eu", component.Region)
+}
+
+func TestReinjectStruct_FollowsRenames(t *testing.T) {
+    c := NewContainer()
+    require.NoError(t, c.Register("pricing", &fixedPricing{price: 10}))
+    require.NoError(t, c.Register("pricing.v2", &fixedPricing{price: 20}))
+
+    var component refreshedComponent
+    _, err := c.ReinjectStruct(&component)
+    require.NoError(t, err)
+
+    c.Rename("pricing", "pricing.v2")
+    result, err := c.ReinjectStruct(&component)
+    require.NoError(t, err)
+    assert.Equal(t, FieldInjected, fieldStatuses(result)["Pricing"])
+    assert.Equal(t, 20, component.Pricing.Price())
+}
+
+func TestReinjectStruct_GroupDecoratorChangesVersion(t *testing.T) {
+    c := NewContainer()
+    require.NoError(t, c.Register("name", "checkout", InGroup("names")))
+
+    var component refreshedComponent
+    _, err := c.ReinjectStruct(&component)
+    require.NoError(t, err)
+
+    require.NoError(t, c.DecorateGroup("names", func(qualifier string, service interface{}) (interface{}, error) {
+        return service.(string) + "-decorated", nil
+    }))
+    result, err := c.ReinjectStruct(&component)
+    require.NoError(t, err)
+    assert.Equal(t, FieldInjected, fieldStatuses(result)["Name"])
+    assert.Equal(t, "checkout-decorated", component.Name)
+}
+
+func TestReinjectStruct_ForgetStructInjectsEverything(t *testing.T) {
+    c := NewContainer()
+    require.NoError(t, c.Register("name", "checkout"))
+
+    var component refreshedComponent
+    _, err := c.ReinjectStruct(&component)
+    require.NoError(t, err)
+
+    component.Name = "edited"
+    c.ForgetStruct(&component)
+    result, err := c.ReinjectStruct(&component)
+    require.NoError(t, err)
+    assert.Equal(t, FieldInjected, fieldStatuses(result)["Name"])
+    assert.Equal(t, "checkout", component.Name)
+}
+
+func TestReinjectStruct_CollectedTargetsAreDropped(t *testing.T) {
+    c := NewContainer()
+    require.NoError(t, c.Register("pricing", &fixedPricing{price: 10}))
+    require.NoError(t, c.Register("name", "checkout"))
+
+    for i := 0; i < 100; i++ {
+        // A struct allocated where a collected one was is injected afresh
+        component := &refreshedComponent{}
+        result, err := c.ReinjectStruct(component)
+        require.NoError(t, err)
+        assert.Equal(t, FieldInjected, fieldStatuses(result)["Name"])
+        assert.Equal(t, "checkout", component.Name)
+        runtime.GC()
+    }
+
+    c.inflightMu.Lock()
+    defer c.inflightMu.Unlock()
+    assert.Less(t, len(c.reinjected), 5)
+}
+
+func TestReinjectStruct_HotFieldsAreNotRebound(t *testing.T) {
+    c := NewContainer()
+    require.NoError(t, c.Register("pricing", &fixedPricing{price: 10}))
+
+    var consumer hotConsumer
+    _, err := c.ReinjectStruct(&consumer)
+    require.NoError(t, err)
+
+    _, err = c.Swap("pricing", &fixedPricing{price: 12})
+    require.NoError(t, err)
+    result, err := c.ReinjectStruct(&consumer)
+    require.NoError(t, err)
+    assert.Equal(t, FieldUnchanged, fieldStatuses(result)["Pricing"])
+    assert.Equal(t, 12, consumer.Pricing.Load().Price())
+
+    c.mu.RLock()
+    defer c.mu.RUnlock()
+    assert.Len(t, c.hot["pricing"], 3)
+}
+
+func TestReinjectStruct_RejectsInvalidTargets(t *testing.T) {
+    c := NewContainer()
+
+    _, err := c.ReinjectStruct(nil)
+    assert.ErrorIs(t, err, ErrNilTarget)
+
+    var name string
+    _, err = c.ReinjectStruct(&name)
+    assert.Error(t, err)
+}
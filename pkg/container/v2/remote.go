@@ -0,0 +1,123 @@
+package container
+
+import (
+    "context"
+    "fmt"
+    "strings"
+)
+
+// Endpoint is where service discovery says a remote service runs
+type Endpoint struct {
+    Address    string // host:port
+    Protocol   string // "http", "https", "grpc" or "tcp"
+    HealthPath string // Path checked over http(s), "/healthz" when empty
+}
+
+// Discovery looks up remote services by name (Consul, DNS SRV, a config
+// file, ...)
+type Discovery interface {
+    Lookup(ctx context.Context, service string) (Endpoint, error)
+}
+
+// StaticDiscovery is a Discovery backed by a fixed table, e.g. loaded from
+// configuration
+type StaticDiscovery map[string]Endpoint
+
+// Lookup returns the endpoint configured for service
+func (d StaticDiscovery) Lookup(ctx context.Context, service string) (Endpoint, error) {
+    endpoint, ok := d[service]
+    if !ok {
+        return Endpoint{}, fmt.Errorf("service %s is not configured for discovery", service)
+    }
+    return endpoint, nil
+}
+
+// RemoteService maps a local qualifier to a service running in another
+// process. Stub builds the client for the discovered endpoint; it usually
+// returns a type implementing the same interface as a local implementation,
+// so injected fields cannot tell the difference.
+type RemoteService struct {
+    Qualifier string                                // Local qualifier of the stub
+    Service   string                                // Name looked up in discovery
+    Stub      func(endpoint Endpoint) (interface{}, error)
+}
+
+// RegisterRemote registers a client stub for a remote service. The stub is
+// built on first resolve, once the endpoint passes its health check, and
+// the same check backs the service's entry in SelfTest unless the stub
+// implements SelfTester itself.
+func (c *Container) RegisterRemote(discovery Discovery, remote RemoteService, opts ...RegisterOption) error {
+    if discovery == nil || remote.Stub == nil {
+        return fmt.Errorf("remote service %s needs a discovery and a stub", remote.Qualifier)
+    }
+
+    c.log.Infow("Registering remote service",
+        "qualifier", remote.Qualifier,
+        "service", remote.Service)
+
+    probe := remoteHealthProbe(discovery, remote.Service)
+    factory := func(*Container) (interface{}, error) {
+        endpoint, err := discovery.Lookup(context.Background(), remote.Service)
+        if err != nil {
+            return nil, fmt.Errorf("failed to discover %s: %w", remote.Service, err)
+        }
+        c.log.Infow("Connecting remote service",
+            "qualifier", remote.Qualifier,
+            "address", endpoint.Address,
+            "protocol", endpoint.Protocol)
+        return remote.Stub(endpoint)
+    }
+    opts = append(opts, WaitFor(probe))
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    if err := c.registerFactoryLocked(remote.Qualifier, factory, opts); err != nil {
+        return admitted(err)
+    }
+    c.remotes[remote.Qualifier] = probe
+    return nil
+}
+
+// remoteHealthProbe discovers the endpoint of service on every check and
+// probes it according to its protocol
+func remoteHealthProbe(discovery Discovery, service string) Probe {
+    return ProbeFunc("remote "+service, func(ctx context.Context) error {
+        endpoint, err := discovery.Lookup(ctx, service)
+        if err != nil {
+            return err
+        }
+        return endpointProbe(endpoint).Check(ctx)
+    })
+}
+
+// endpointProbe checks an http(s) endpoint's health path and any other
+// protocol by opening a TCP connection
+func endpointProbe(endpoint Endpoint) Probe {
+    switch endpoint.Protocol {
+    case "http", "https":
+        path := endpoint.HealthPath
+        if path == "" {
+            path = "/healthz"
+        }
+        if !strings.HasPrefix(path, "/") {
+            path = "/" + path
+        }
+        return HTTPProbe(endpoint.Protocol + "://" + endpoint.Address + path)
+    default:
+        return TCPProbe(endpoint.Address)
+    }
+}
+
+// remoteProbe returns the health check of a remote service
+func (c *Container) remoteProbe(qualifier string) (Probe, bool) {
+    c.mu.RLock()
+    defer c.mu.RUnlock()
+    probe, ok := c.remotes[qualifier]
+    return probe, ok
+}
+
+// probeTester runs a probe as a self-test
+type probeTester Probe
+
+func (p probeTester) SelfTest(ctx context.Context) error {
+    return p.Check(ctx)
+}
@@ -0,0 +1,87 @@
+package container
+
+import (
+    "context"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "sync/atomic"
+    "testing"
+    "time"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+type quoteService interface {
+    Quote() string
+}
+
+// quoteStub stands in for a generated client
+type quoteStub struct {
+    endpoint Endpoint
+}
+
+func (s *quoteStub) Quote() string {
+    return "quote from " + s.endpoint.Protocol + "://" + s.endpoint.Address
+}
+
+type quoteConsumer struct {
+    Quotes quoteService `di:"quotes"`
+}
+
+func TestRegisterRemote_InjectsStubAndChecksHealth(t *testing.T) {
+    var healthy atomic.Bool
+    healthy.Store(true)
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if r.URL.Path != "/ready" || !healthy.Load() {
+            w.WriteHeader(http.StatusServiceUnavailable)
+        }
+    }))
+    defer server.Close()
+
+    discovery := StaticDiscovery{
+        "quote-api": {Address: strings.TrimPrefix(server.URL, "http://"), Protocol: "http", HealthPath: "ready"},
+    }
+    c := NewContainer()
+    c.SetWaitPolicy(WaitPolicy{Timeout: 200 * time.Millisecond, InitialBackoff: 10 * time.Millisecond})
+    require.NoError(t, c.RegisterRemote(discovery, RemoteService{
+        Qualifier: "quotes",
+        Service:   "quote-api",
+        Stub: func(endpoint Endpoint) (interface{}, error) {
+            return &quoteStub{endpoint: endpoint}, nil
+        },
+    }))
+
+    var consumer quoteConsumer
+    require.NoError(t, c.InjectStruct(&consumer))
+    assert.Equal(t, "quote from "+server.URL, consumer.Quotes.Quote())
+
+    report := c.SelfTest(context.Background())
+    require.Len(t, report.Results, 1)
+    assert.True(t, report.Passed())
+
+    healthy.Store(false)
+    report = c.SelfTest(context.Background())
+    assert.ErrorContains(t, report.Err(), `self-test of "quotes" failed`)
+}
+
+func TestRegisterRemote_WaitsForHealthyEndpoint(t *testing.T) {
+    c := NewContainer()
+    c.SetWaitPolicy(WaitPolicy{Timeout: 50 * time.Millisecond, InitialBackoff: 10 * time.Millisecond})
+    built := false
+    require.NoError(t, c.RegisterRemote(StaticDiscovery{}, RemoteService{
+        Qualifier: "quotes",
+        Service:   "quote-api",
+        Stub: func(endpoint Endpoint) (interface{}, error) {
+            built = true
+            return &quoteStub{}, nil
+        },
+    }))
+
+    _, err := c.Resolve("quotes")
+    assert.ErrorContains(t, err, "not configured for discovery")
+    assert.False(t, built)
+
+    assert.Error(t, c.RegisterRemote(nil, RemoteService{Qualifier: "other"}))
+}
@@ -0,0 +1,127 @@
+package container
+
+import (
+    "context"
+    "fmt"
+    "sync"
+    "time"
+)
+
+// ConfigSource is the subset of a remote configuration service (etcd,
+// Consul, ...) needed to drive bindings
+type ConfigSource interface {
+    // Get returns the current value of key
+    Get(ctx context.Context, key string) (string, error)
+    // Watch calls onChange with every new value of key until ctx is done.
+    // It returns once the watch is established.
+    Watch(ctx context.Context, key string, onChange func(value string)) error
+}
+
+// RemoteBinding selects the implementation registered under Qualifier from
+// Choices, using the value of Key in a ConfigSource
+type RemoteBinding struct {
+    Qualifier string
+    Key       string
+    Choices   map[string]interface{} // Config value -> implementation
+}
+
+// BindRemote registers the implementation currently selected by the remote
+// config and swaps it whenever the value changes, until ctx is done. Values
+// without a matching choice are logged and ignored, keeping the current
+// implementation.
+func (c *Container) BindRemote(ctx context.Context, source ConfigSource, binding RemoteBinding, opts ...RegisterOption) error {
+    value, err := source.Get(ctx, binding.Key)
+    if err != nil {
+        return fmt.Errorf("failed to read remote config %s for %s: %w", binding.Key, binding.Qualifier, err)
+    }
+    implementation, ok := binding.Choices[value]
+    if !ok {
+        return fmt.Errorf("remote config %s=%q selects no implementation for %s", binding.Key, value, binding.Qualifier)
+    }
+    if err := c.Register(binding.Qualifier, implementation, opts...); err != nil {
+        return err
+    }
+
+    var mu sync.Mutex
+    current := value
+    return source.Watch(ctx, binding.Key, func(value string) {
+        mu.Lock()
+        defer mu.Unlock()
+
+        if value == current {
+            return
+        }
+        implementation, ok := binding.Choices[value]
+        if !ok {
+            c.log.Warnw("Remote config selects unknown implementation",
+                "qualifier", binding.Qualifier,
+                "key", binding.Key,
+                "value", value)
+            return
+        }
+        if _, err := c.Swap(binding.Qualifier, implementation); err != nil {
+            c.log.Errorw("Failed to swap remotely selected implementation",
+                "qualifier", binding.Qualifier,
+                "error", err)
+            return
+        }
+        current = value
+    })
+}
+
+// CachingSource wraps a ConfigSource, serving Get from a cache for ttl and
+// refreshing cached values from watch notifications
+type CachingSource struct {
+    source ConfigSource
+    ttl    time.Duration
+    now    func() time.Time
+
+    mu      sync.Mutex
+    entries map[string]cachedValue
+}
+
+type cachedValue struct {
+    value   string
+    fetched time.Time
+}
+
+// NewCachingSource returns a caching wrapper around source
+func NewCachingSource(source ConfigSource, ttl time.Duration) *CachingSource {
+    return &CachingSource{
+        source:  source,
+        ttl:     ttl,
+        now:     time.Now,
+        entries: make(map[string]cachedValue),
+    }
+}
+
+// Get returns the cached value of key while it is fresh
+func (s *CachingSource) Get(ctx context.Context, key string) (string, error) {
+    s.mu.Lock()
+    entry, ok := s.entries[key]
+    s.mu.Unlock()
+    if ok && s.now().Sub(entry.fetched) < s.ttl {
+        return entry.value, nil
+    }
+
+    value, err := s.source.Get(ctx, key)
+    if err != nil {
+        return "", err
+    }
+    s.store(key, value)
+    return value, nil
+}
+
+// Watch forwards changes to onChange and keeps the cache up to date
+func (s *CachingSource) Watch(ctx context.Context, key string, onChange func(value string)) error {
+    return s.source.Watch(ctx, key, func(value string) {
+        s.store(key, value)
+        onChange(value)
+    })
+}
+
+func (s *CachingSource) store(key, value string) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    s.entries[key] = cachedValue{value: value, fetched: s.now()}
+}
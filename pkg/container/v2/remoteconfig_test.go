@@ -0,0 +1,132 @@
+package container
+
+import (
+    "context"
+    "errors"
+    "sync"
+    "testing"
+    "time"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+// memorySource is an in-memory ConfigSource with synchronous notifications
+type memorySource struct {
+    mu       sync.Mutex
+    values   map[string]string
+    watchers map[string][]func(string)
+    gets     int
+}
+
+func newMemorySource(values map[string]string) *memorySource {
+    return &memorySource{values: values, watchers: make(map[string][]func(string))}
+}
+
+func (s *memorySource) Get(ctx context.Context, key string) (string, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    s.gets++
+    value, ok := s.values[key]
+    if !ok {
+        return "", errors.New("key not found")
+    }
+    return value, nil
+}
+
+func (s *memorySource) Watch(ctx context.Context, key string, onChange func(string)) error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    s.watchers[key] = append(s.watchers[key], onChange)
+    return nil
+}
+
+func (s *memorySource) set(key, value string) {
+    s.mu.Lock()
+    s.values[key] = value
+    watchers := s.watchers[key]
+    s.mu.Unlock()
+    for _, watcher := range watchers {
+        watcher(value)
+    }
+}
+
+func TestContainer_Swap(t *testing.T) {
+    container := NewContainer()
+    first := &testServiceImpl{name: "first"}
+    second := &testServiceImpl{name: "second"}
+    require.NoError(t, container.Register("svc", first))
+
+    old, err := container.Swap("svc", second)
+    require.NoError(t, err)
+    assert.Equal(t, first, old)
+
+    got, err := container.Resolve("svc")
+    require.NoError(t, err)
+    assert.Equal(t, second, got)
+
+    _, err = container.Swap("missing", second)
+    assert.Error(t, err)
+    _, err = container.Swap("svc", nil)
+    assert.Error(t, err)
+}
+
+func TestContainer_BindRemote(t *testing.T) {
+    container := NewContainer()
+    source := newMemorySource(map[string]string{"ranker": "v1"})
+    v1 := &testServiceImpl{name: "v1"}
+    v2 := &testServiceImpl{name: "v2"}
+
+    err := container.BindRemote(context.Background(), source, RemoteBinding{
+        Qualifier: "ranker",
+        Key:       "ranker",
+        Choices:   map[string]interface{}{"v1": v1, "v2": v2},
+    })
+    require.NoError(t, err)
+
+    resolve := func() interface{} {
+        got, err := container.Resolve("ranker")
+        require.NoError(t, err)
+        return got
+    }
+    assert.Equal(t, v1, resolve())
+
+    source.set("ranker", "v2")
+    assert.Equal(t, v2, resolve())
+
+    // Unknown values keep the current implementation
+    source.set("ranker", "v3")
+    assert.Equal(t, v2, resolve())
+
+    err = container.BindRemote(context.Background(), source, RemoteBinding{Qualifier: "other", Key: "missing"})
+    assert.Error(t, err)
+}
+
+func TestCachingSource(t *testing.T) {
+    source := newMemorySource(map[string]string{"flag": "on"})
+    cached := NewCachingSource(source, time.Minute)
+    now := time.Now()
+    cached.now = func() time.Time { return now }
+
+    for i := 0; i < 3; i++ {
+        value, err := cached.Get(context.Background(), "flag")
+        require.NoError(t, err)
+        assert.Equal(t, "on", value)
+    }
+    assert.Equal(t, 1, source.gets)
+
+    // Watch notifications refresh the cache
+    var seen []string
+    require.NoError(t, cached.Watch(context.Background(), "flag", func(value string) { seen = append(seen, value) }))
+    source.set("flag", "off")
+    value, err := cached.Get(context.Background(), "flag")
+    require.NoError(t, err)
+    assert.Equal(t, "off", value)
+    assert.Equal(t, []string{"off"}, seen)
+
+    // Stale entries are fetched again
+    now = now.Add(2 * time.Minute)
+    _, err = cached.Get(context.Background(), "flag")
+    require.NoError(t, err)
+    assert.Equal(t, 2, source.gets)
+}
@@ -0,0 +1,69 @@
+package container
+
+import (
+    "fmt"
+    "path/filepath"
+    "runtime"
+    "strings"
+)
+
+// Rename maps a deprecated qualifier to its replacement. Resolve calls and di
+// tags that still use the old name are redirected to the new one and log a
+// warning naming the caller, so migrations can happen incrementally.
+func (c *Container) Rename(oldQualifier, newQualifier string) {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+
+    c.log.Infow("Installing qualifier rename",
+        "old", oldQualifier,
+        "new", newQualifier)
+    c.renames[oldQualifier] = newQualifier
+    c.recordMutation(MutationRename, oldQualifier, "-> "+newQualifier)
+}
+
+// Renames installs every old -> new pair of the given table
+func (c *Container) Renames(table map[string]string) {
+    for oldQualifier, newQualifier := range table {
+        c.Rename(oldQualifier, newQualifier)
+    }
+}
+
+// renamed returns the current name for qualifier, following chained renames.
+// When a rename applies, a warning with the location from site is logged.
+func (c *Container) renamed(qualifier string, site func() string) string {
+    c.mu.RLock()
+    defer c.mu.RUnlock()
+
+    current := qualifier
+    for hops := 0; hops <= len(c.renames); hops++ {
+        next, ok := c.renames[current]
+        if !ok {
+            break
+        }
+        current = next
+    }
+    if current == qualifier {
+        return qualifier
+    }
+
+    c.log.Warnw("Deprecated qualifier used",
+        "old", qualifier,
+        "new", current,
+        "caller", site())
+    return current
+}
+
+// callerLocation returns "file:line" of the frame skip levels above its
+// caller, or above the v1 wrapper found there
+func callerLocation(skip int) string {
+    for {
+        _, file, line, ok := runtime.Caller(skip + 1)
+        if !ok {
+            return "unknown"
+        }
+        if filepath.Dir(file) != legacyDir || strings.HasSuffix(file, "_test.go") {
+            return fmt.Sprintf("%s:%d", file, line)
+        }
+        skip++
+    }
+}
@@ -0,0 +1,45 @@
+package container
+
+import (
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+func TestContainer_Rename(t *testing.T) {
+    container := NewContainer()
+    testService := &testServiceImpl{name: "test"}
+    require.NoError(t, container.Register("testService", testService))
+
+    container.Renames(map[string]string{
+        "legacyService": "oldService",
+        "oldService":    "testService",
+    })
+
+    t.Run("resolve follows chained renames", func(t *testing.T) {
+        got, err := container.Resolve("legacyService")
+        require.NoError(t, err)
+        assert.Equal(t, testService, got)
+    })
+
+    t.Run("tags use renames", func(t *testing.T) {
+        target := &struct {
+            Service TestService `di:"oldService"`
+        }{}
+        require.NoError(t, container.InjectStruct(target))
+        assert.Equal(t, testService, target.Service)
+    })
+
+    t.Run("rename cycle terminates", func(t *testing.T) {
+        container.Rename("loopA", "loopB")
+        container.Rename("loopB", "loopA")
+        _, err := container.Resolve("loopA")
+        assert.Error(t, err)
+    })
+}
+
+func TestCallerLocation(t *testing.T) {
+    location := callerLocation(0)
+    assert.Contains(t, location, "renames_test.go")
+}
@@ -0,0 +1,121 @@
+package container
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "time"
+)
+
+// RetryPolicy controls how a failed construction is retried
+type RetryPolicy struct {
+    Attempts       int                  // Total attempts, including the first; below 2 never retries
+    InitialBackoff time.Duration        // Delay after the first failed attempt
+    MaxBackoff     time.Duration        // Upper bound of the doubling delay
+    Retryable      func(err error) bool // Errors worth retrying, nil for all
+}
+
+// RetryConstruction makes the container retry the factory or provider of
+// the registration when it fails, so a transient failure during Start,
+// such as a DNS hiccup or a dependency still warming up, does not fail the
+// whole boot:
+//
+//	c.Provide("db", openDB, container.RetryConstruction(container.RetryPolicy{
+//	    Attempts:       5,
+//	    InitialBackoff: 200 * time.Millisecond,
+//	    MaxBackoff:     2 * time.Second,
+//	    Retryable:      func(err error) bool { return errors.Is(err, syscall.ECONNREFUSED) },
+//	}))
+//
+// Retries are logged and listed in the StartupReport of Start. The backoff
+// is timed by the container's clock, see WithClock, and ends when the
+// context of the resolution is done, see ResolveContext and Start.
+func RetryConstruction(policy RetryPolicy) RegisterOption {
+    return func(r *registration) {
+        r.retry = &policy
+    }
+}
+
+// ConstructionRetry describes a construction that was retried
+type ConstructionRetry struct {
+    Qualifier string
+    Retries   int   // Attempts after the first
+    Err       error // Error of the last failed attempt
+    Recovered bool  // Whether a retry succeeded
+}
+
+// construct calls build, retrying it according to the retry policy of
+// qualifier, if any. The backoff between attempts ends early when ctx is
+// done, giving up.
+func (c *Container) construct(ctx context.Context, qualifier string, build func() (interface{}, error)) (interface{}, error) {
+    c.mu.RLock()
+    var policy *RetryPolicy
+    if reg, ok := c.regs[qualifier]; ok {
+        policy = reg.retry
+    }
+    c.mu.RUnlock()
+
+    service, err := build()
+    if err == nil || policy == nil {
+        return service, err
+    }
+
+    retry := ConstructionRetry{Qualifier: qualifier}
+    backoff := policy.InitialBackoff
+    cancelled := false
+    for attempt := 1; err != nil && attempt < policy.Attempts; attempt++ {
+        if policy.Retryable != nil && !policy.Retryable(err) {
+            break
+        }
+        c.log.Warnw("Construction failed, retrying",
+            "qualifier", qualifier,
+            "attempt", attempt,
+            "retryIn", backoff,
+            "error", err)
+        select {
+        case <-ctx.Done():
+            cancelled = true
+        case <-c.after(backoff):
+        }
+        if cancelled {
+            err = errors.Join(err, ctx.Err())
+            break
+        }
+        backoff *= 2
+        if policy.MaxBackoff > 0 && backoff > policy.MaxBackoff {
+            backoff = policy.MaxBackoff
+        }
+
+        retry.Retries++
+        retry.Err = err
+        c.metricsSink().IncCounter("di_construction_retries_total", map[string]string{"qualifier": qualifier})
+        service, err = build()
+    }
+    if retry.Retries == 0 && !cancelled {
+        return service, err
+    }
+
+    if retry.Retries > 0 {
+        retry.Recovered = err == nil
+        c.mu.Lock()
+        c.retries = append(c.retries, retry)
+        c.mu.Unlock()
+    }
+    if err != nil {
+        return nil, fmt.Errorf("gave up after %d attempts: %w", retry.Retries+1, err)
+    }
+    c.log.Infow("Construction recovered",
+        "qualifier", qualifier,
+        "retries", retry.Retries)
+    return service, nil
+}
+
+// takeRetries returns the retries recorded since the last call
+func (c *Container) takeRetries() []ConstructionRetry {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+
+    retries := c.retries
+    c.retries = nil
+    return retries
+}
@@ -0,0 +1,127 @@
+package container
+
+import (
+    "context"
+    "errors"
+    "testing"
+    "time"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+var errColdStart = errors.New("connection refused")
+
+// flakyStore returns a constructor failing its first failures calls
+func flakyStore(failures int) (func() (*providedStore, error), *int) {
+    calls := 0
+    return func() (*providedStore, error) {
+        calls++
+        if calls <= failures {
+            return nil, errColdStart
+        }
+        return &providedStore{}, nil
+    }, &calls
+}
+
+func TestRetryConstruction_RecoversDuringStart(t *testing.T) {
+    metrics := newRecordingMetrics()
+    c := NewContainer(WithMetrics(metrics))
+    ctor, calls := flakyStore(2)
+    require.NoError(t, c.Provide("store", ctor, RetryConstruction(RetryPolicy{
+        Attempts:       3,
+        InitialBackoff: time.Millisecond,
+    })))
+
+    require.NoError(t, c.Start(context.Background()))
+    assert.Equal(t, 3, *calls)
+
+    report := c.StartupReport()
+    require.Len(t, report.Retries, 1)
+    assert.Equal(t, "store", report.Retries[0].Qualifier)
+    assert.Equal(t, 2, report.Retries[0].Retries)
+    assert.True(t, report.Retries[0].Recovered)
+    assert.ErrorIs(t, report.Retries[0].Err, errColdStart)
+    assert.Equal(t, 2, metrics.counters["di_construction_retries_total"])
+}
+
+func TestRetryConstruction_GivesUp(t *testing.T) {
+    c := NewContainer()
+    ctor, calls := flakyStore(5)
+    require.NoError(t, c.Provide("store", ctor, RetryConstruction(RetryPolicy{
+        Attempts:       3,
+        InitialBackoff: time.Millisecond,
+    })))
+
+    _, err := c.Resolve("store")
+    assert.ErrorIs(t, err, errColdStart)
+    assert.ErrorContains(t, err, "gave up after 3 attempts")
+    assert.Equal(t, 3, *calls)
+}
+
+func TestRetryConstruction_OnlyRetryableErrors(t *testing.T) {
+    c := NewContainer()
+    calls := 0
+    require.NoError(t, c.RegisterFactory("config", func(*Container) (interface{}, error) {
+        calls++
+        return nil, errors.New("invalid config")
+    }, RetryConstruction(RetryPolicy{
+        Attempts:  3,
+        Retryable: func(err error) bool { return errors.Is(err, errColdStart) },
+    })))
+
+    _, err := c.Resolve("config")
+    assert.EqualError(t, err, "failed to build lazy service config: invalid config")
+    assert.Equal(t, 1, calls)
+}
+
+// manualClock is a Clock whose delays end only when released
+type manualClock struct {
+    fixedClock
+    delays chan chan time.Time
+}
+
+func (c *manualClock) After(d time.Duration) <-chan time.Time {
+    fired := make(chan time.Time, 1)
+    c.delays <- fired
+    return fired
+}
+
+func TestRetryConstruction_BackoffUsesClock(t *testing.T) {
+    clock := &manualClock{delays: make(chan chan time.Time)}
+    c := NewContainer(WithClock(clock))
+    ctor, calls := flakyStore(1)
+    require.NoError(t, c.Provide("store", ctor, RetryConstruction(RetryPolicy{
+        Attempts:       2,
+        InitialBackoff: time.Hour,
+    })))
+
+    go func() {
+        fired := <-clock.delays
+        fired <- time.Time{}
+    }()
+    withinDeadline(t, func() {
+        _, err := c.Resolve("store")
+        require.NoError(t, err)
+    })
+    assert.Equal(t, 2, *calls)
+}
+
+func TestRetryConstruction_CancelledStart(t *testing.T) {
+    c := NewContainer()
+    ctor, calls := flakyStore(5)
+    require.NoError(t, c.Provide("store", ctor, RetryConstruction(RetryPolicy{
+        Attempts:       5,
+        InitialBackoff: time.Hour,
+    })))
+
+    ctx, cancel := context.WithCancel(context.Background())
+    time.AfterFunc(10*time.Millisecond, cancel)
+    withinDeadline(t, func() {
+        err := c.Start(ctx)
+        assert.ErrorIs(t, err, errColdStart)
+        assert.ErrorIs(t, err, context.Canceled)
+        assert.ErrorContains(t, err, "gave up after 1 attempts")
+    })
+    assert.Equal(t, 1, *calls)
+}
@@ -0,0 +1,225 @@
+package container
+
+import (
+    "fmt"
+    "reflect"
+    "sort"
+    "strconv"
+    "strings"
+    "time"
+)
+
+// SchemaDraft is the JSON schema dialect produced by SchemaOf
+const SchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is the subset of JSON schema needed to describe config structs
+type Schema struct {
+    Schema               string             `json:"$schema,omitempty"`
+    Title                string             `json:"title,omitempty"`
+    Type                 string             `json:"type,omitempty"`
+    Properties           map[string]*Schema `json:"properties,omitempty"`
+    Required             []string           `json:"required,omitempty"`
+    Items                *Schema            `json:"items,omitempty"`
+    AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+    Default              interface{}        `json:"default,omitempty"`
+    Ref                  string             `json:"$ref,omitempty"`
+    Defs                 map[string]*Schema `json:"$defs,omitempty"`
+}
+
+// AsConfig marks a registration as a config struct. ConfigSchemas, the
+// debug handler and the application's -config-schema flag publish a JSON
+// schema for every config registration.
+func AsConfig() RegisterOption {
+    return func(r *registration) {
+        r.config = true
+    }
+}
+
+// ConfigSchemas returns the JSON schema of every config registration by
+// qualifier
+func (c *Container) ConfigSchemas() (map[string]*Schema, error) {
+    c.mu.RLock()
+    types := make(map[string]reflect.Type)
+    for qualifier, reg := range c.regs {
+        if service, ok := c.services[qualifier]; ok && reg.config {
+            types[qualifier] = reflect.TypeOf(service)
+        }
+    }
+    c.mu.RUnlock()
+
+    schemas := make(map[string]*Schema, len(types))
+    for qualifier, configType := range types {
+        schema, err := SchemaOf(configType)
+        if err != nil {
+            return nil, fmt.Errorf("failed to generate schema for %s: %w", qualifier, err)
+        }
+        schema.Title = qualifier
+        schemas[qualifier] = schema
+    }
+    return schemas, nil
+}
+
+// SchemaOf generates the JSON schema of a config type, following the json
+// tags of its fields. A default:"value" tag sets the default. Fields are
+// required unless they are pointers, have a default or are tagged omitempty.
+// A struct type that contains itself is described once under $defs and
+// referenced with $ref.
+func SchemaOf(configType reflect.Type) (*Schema, error) {
+    builder := &schemaBuilder{expanding: make(map[reflect.Type]bool), recursive: make(map[reflect.Type]bool)}
+    schema, err := builder.schemaOf(configType)
+    if err != nil {
+        return nil, err
+    }
+    schema.Schema = SchemaDraft
+    schema.Defs = builder.defs
+    return schema, nil
+}
+
+// schemaBuilder describes the types reachable from one config type
+type schemaBuilder struct {
+    expanding map[reflect.Type]bool // Struct types being described
+    recursive map[reflect.Type]bool // Struct types found inside themselves
+    defs      map[string]*Schema    // Schemas of recursive types by name
+}
+
+// schemaOf describes one type without the top-level $schema marker
+func (b *schemaBuilder) schemaOf(t reflect.Type) (*Schema, error) {
+    for t.Kind() == reflect.Ptr {
+        t = t.Elem()
+    }
+
+    switch t.Kind() {
+    case reflect.Bool:
+        return &Schema{Type: "boolean"}, nil
+    case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+        reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+        return &Schema{Type: "integer"}, nil
+    case reflect.Float32, reflect.Float64:
+        return &Schema{Type: "number"}, nil
+    case reflect.String:
+        return &Schema{Type: "string"}, nil
+    case reflect.Interface:
+        return &Schema{}, nil // Any JSON value
+    case reflect.Slice, reflect.Array:
+        items, err := b.schemaOf(t.Elem())
+        if err != nil {
+            return nil, err
+        }
+        return &Schema{Type: "array", Items: items}, nil
+    case reflect.Map:
+        if t.Key().Kind() != reflect.String {
+            return nil, fmt.Errorf("map key type %v is not supported", t.Key())
+        }
+        values, err := b.schemaOf(t.Elem())
+        if err != nil {
+            return nil, err
+        }
+        return &Schema{Type: "object", AdditionalProperties: values}, nil
+    case reflect.Struct:
+        ref := "#/$defs/" + t.String()
+        if b.expanding[t] {
+            b.recursive[t] = true
+            return &Schema{Ref: ref}, nil
+        }
+        b.expanding[t] = true
+        defer delete(b.expanding, t)
+
+        schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+        if err := b.addStructProperties(schema, t); err != nil {
+            return nil, err
+        }
+        sort.Strings(schema.Required)
+        if !b.recursive[t] {
+            return schema, nil
+        }
+        if b.defs == nil {
+            b.defs = make(map[string]*Schema)
+        }
+        b.defs[t.String()] = schema
+        return &Schema{Ref: ref}, nil
+    }
+    return nil, fmt.Errorf("type %v cannot be described by a JSON schema", t)
+}
+
+// addStructProperties adds the fields of t to schema, flattening embedded
+// structs like encoding/json does
+func (b *schemaBuilder) addStructProperties(schema *Schema, t reflect.Type) error {
+    for i := 0; i < t.NumField(); i++ {
+        field := t.Field(i)
+        name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+        if name == "-" && options == "" {
+            continue
+        }
+
+        if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+            if err := b.addStructProperties(schema, field.Type); err != nil {
+                return err
+            }
+            continue
+        }
+        if !field.IsExported() {
+            continue
+        }
+        if name == "" {
+            name = field.Name
+        }
+
+        property, err := b.schemaOf(field.Type)
+        if err != nil {
+            return fmt.Errorf("field %s: %w", field.Name, err)
+        }
+        if value, ok := field.Tag.Lookup("default"); ok {
+            if property.Default, err = parseDefault(field.Type, value); err != nil {
+                return fmt.Errorf("field %s: %w", field.Name, err)
+            }
+        }
+        schema.Properties[name] = property
+
+        optional := field.Type.Kind() == reflect.Ptr || property.Default != nil ||
+            strings.Contains(","+options+",", ",omitempty,")
+        if !optional {
+            schema.Required = append(schema.Required, name)
+        }
+    }
+    return nil
+}
+
+// durationType is parsed with time.ParseDuration rather than as an integer
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// parseDefault converts a default tag to the JSON value of the field's type.
+// time.Duration defaults use time.ParseDuration syntax, e.g. "30s", and
+// yield nanoseconds like encoding/json.
+func parseDefault(t reflect.Type, value string) (interface{}, error) {
+    for t.Kind() == reflect.Ptr {
+        t = t.Elem()
+    }
+    if t == durationType {
+        d, err := time.ParseDuration(value)
+        if err != nil {
+            return nil, fmt.Errorf("invalid default %q for %v", value, t)
+        }
+        return int64(d), nil
+    }
+
+    var parsed interface{}
+    var err error
+    switch t.Kind() {
+    case reflect.Bool:
+        parsed, err = strconv.ParseBool(value)
+    case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+        parsed, err = strconv.ParseInt(value, 10, 64)
+    case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+        parsed, err = strconv.ParseUint(value, 10, 64)
+    case reflect.Float32, reflect.Float64:
+        parsed, err = strconv.ParseFloat(value, 64)
+    case reflect.String:
+        parsed = value
+    default:
+        return nil, fmt.Errorf("default values are not supported for %v", t)
+    }
+    if err != nil {
+        return nil, fmt.Errorf("invalid default %q for %v", value, t)
+    }
+    return parsed, nil
+}
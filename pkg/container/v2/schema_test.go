@@ -0,0 +1,124 @@
+package container
+
+import (
+    "reflect"
+    "testing"
+    "time"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+type databaseConfig struct {
+    Host    string `json:"host"`
+    Port    int    `json:"port" default:"5432"`
+    Replica *string
+}
+
+type serverConfig struct {
+    databaseConfig
+    Name     string            `json:"name"`
+    Debug    bool              `json:"debug,omitempty"`
+    Ratio    float64           `json:"ratio" default:"0.5"`
+    Tags     []string          `json:"tags,omitempty"`
+    Limits   map[string]int    `json:"limits,omitempty"`
+    Upstream databaseConfig    `json:"upstream"`
+    Extra    interface{}       `json:"extra,omitempty"`
+    Ignored  string            `json:"-"`
+    internal string
+}
+
+func TestSchemaOf(t *testing.T) {
+    schema, err := SchemaOf(reflect.TypeOf(&serverConfig{}))
+    require.NoError(t, err)
+
+    assert.Equal(t, SchemaDraft, schema.Schema)
+    assert.Equal(t, "object", schema.Type)
+    assert.Equal(t, []string{"host", "name", "upstream"}, schema.Required)
+
+    // Embedded fields are flattened
+    assert.Equal(t, "integer", schema.Properties["port"].Type)
+    assert.Equal(t, int64(5432), schema.Properties["port"].Default)
+    assert.Equal(t, "string", schema.Properties["Replica"].Type)
+
+    assert.Equal(t, 0.5, schema.Properties["ratio"].Default)
+    assert.Equal(t, "string", schema.Properties["tags"].Items.Type)
+    assert.Equal(t, "integer", schema.Properties["limits"].AdditionalProperties.Type)
+    assert.Equal(t, "object", schema.Properties["upstream"].Type)
+    assert.Empty(t, schema.Properties["upstream"].Schema)
+    assert.Equal(t, &Schema{}, schema.Properties["extra"])
+    assert.NotContains(t, schema.Properties, "Ignored")
+    assert.NotContains(t, schema.Properties, "internal")
+}
+
+type treeNode struct {
+    Name     string     `json:"name"`
+    Children []treeNode `json:"children,omitempty"`
+    Next     *treeNode  `json:"next"`
+}
+
+type treeConfig struct {
+    Root  treeNode  `json:"root"`
+    Spare *treeNode `json:"spare"`
+}
+
+func TestSchemaOf_RecursiveTypes(t *testing.T) {
+    schema, err := SchemaOf(reflect.TypeOf(treeConfig{}))
+    require.NoError(t, err)
+
+    ref := "#/$defs/container.treeNode"
+    assert.Equal(t, "object", schema.Type)
+    assert.Equal(t, ref, schema.Properties["root"].Ref)
+    assert.Equal(t, ref, schema.Properties["spare"].Ref)
+    require.Contains(t, schema.Defs, "container.treeNode")
+    node := schema.Defs["container.treeNode"]
+    assert.Equal(t, ref, node.Properties["next"].Ref)
+    assert.Equal(t, ref, node.Properties["children"].Items.Ref)
+    assert.Equal(t, []string{"name"}, node.Required)
+
+    // A recursive root refers to its own definition
+    schema, err = SchemaOf(reflect.TypeOf(&treeNode{}))
+    require.NoError(t, err)
+    assert.Equal(t, SchemaDraft, schema.Schema)
+    assert.Equal(t, ref, schema.Ref)
+    assert.Contains(t, schema.Defs, "container.treeNode")
+}
+
+func TestSchemaOf_Errors(t *testing.T) {
+    type badDefault struct {
+        Port int `default:"http"`
+    }
+    type badMap struct {
+        Weights map[int]string
+    }
+
+    _, err := SchemaOf(reflect.TypeOf(badDefault{}))
+    assert.ErrorContains(t, err, `invalid default "http"`)
+    _, err = SchemaOf(reflect.TypeOf(badMap{}))
+    assert.ErrorContains(t, err, "map key type int")
+    _, err = SchemaOf(reflect.TypeOf(make(chan int)))
+    assert.Error(t, err)
+}
+
+func TestContainer_ConfigSchemas(t *testing.T) {
+    container := NewContainer()
+    require.NoError(t, container.Register("database", &databaseConfig{}, AsConfig()))
+    require.NoError(t, container.Register("users", &testServiceImpl{}))
+
+    schemas, err := container.ConfigSchemas()
+    require.NoError(t, err)
+    require.Len(t, schemas, 1)
+    assert.Equal(t, "database", schemas["database"].Title)
+}
+
+func TestSchemaOf_DurationDefault(t *testing.T) {
+    type timeoutConfig struct {
+        Timeout time.Duration `json:"timeout" default:"30s"`
+    }
+    schema, err := SchemaOf(reflect.TypeOf(timeoutConfig{}))
+    require.NoError(t, err)
+
+    // Durations encode as nanoseconds, like encoding/json
+    assert.Equal(t, "integer", schema.Properties["timeout"].Type)
+    assert.Equal(t, int64(30*time.Second), schema.Properties["timeout"].Default)
+}
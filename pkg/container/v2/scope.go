@@ -0,0 +1,384 @@
+package container
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "sync"
+)
+
+// ScopedProvider builds the instance of a scoped service for one scope. It
+// may resolve other services, scoped or not, through the scope and
+// register cleanup with Scope.OnClose.
+//
+// Experimental: the signature may gain the unit of work's outcome.
+type ScopedProvider func(s *Scope) (interface{}, error)
+
+// RegisterScoped registers a service with the Scoped lifetime: every Scope
+// builds its own instance with provider on first use and keeps it until
+// the scope is closed. Resolving a scoped service from the container
+// itself is an error.
+//
+// Experimental: see Scope.
+func (c *Container) RegisterScoped(qualifier string, provider ScopedProvider, opts ...RegisterOption) error {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+
+    c.log.Infow("Registering scoped service", "qualifier", qualifier)
+
+    if provider == nil {
+        c.log.Errorw("Cannot register nil scoped provider", "qualifier", qualifier)
+        return fmt.Errorf("cannot register %w", &NilServiceError{Qualifier: qualifier, Source: "provider"})
+    }
+    reg := c.newRegistrationLocked(qualifier, opts)
+    reg.lifetime = Scoped
+    if err := c.admitLocked(reg); err != nil {
+        return admitted(err)
+    }
+
+    c.scoped[qualifier] = provider
+    c.recordLocked(reg)
+    return nil
+}
+
+// Scope is a child container for a unit of work, such as a request or a
+// transaction, holding the instances of scoped services and the services
+// registered in it. Anything else resolves from the parent scope, if any,
+// then from the container. A scope serves one unit of work and must not be
+// used from several goroutines at once.
+//
+// Experimental: scopes and their methods may change in any release while
+// their interaction with lifetimes and experiments settles.
+type Scope struct {
+    c         *Container
+    id        string
+    parent    *Scope
+    ctx       context.Context
+    mu        sync.Mutex
+    instances map[string]interface{}   // Scoped instances built so far
+    locals    map[string]interface{}   // Services registered in the scope
+    children  []*Scope                 // Child scopes not closed yet
+    overrides map[string]override      // Experiment arms by overridden qualifier
+    exposed   map[string]bool          // Overridden qualifiers whose exposure was logged
+    closers   []func(err error) error  // Cleanup in registration order
+    budget    ScopeBudget              // Limits set by scope middleware
+    building  int                      // Scoped instances being built, counted by the budget
+    err       error                    // Why the scope middleware rejected the scope
+    opened    bool                     // Whether the scope middleware succeeded
+    closed    bool
+}
+
+// NewScope starts a scope whose providers see ctx through Scope.Context,
+// prepared by the scope middleware, see UseScope. A scope rejected by its
+// middleware is returned closed, with the reason in Scope.Err; OpenScope
+// returns the error instead.
+//
+// Experimental: see Scope.
+func (c *Container) NewScope(ctx context.Context) *Scope {
+    scope := newScope(c, nil, ctx)
+    c.log.Debugw("Starting scope", "scope", scope.id)
+    c.recordMutation(MutationScopeOpen, scope.id, "")
+    c.openScope(scope) // Failures are kept in scope.err
+    return scope
+}
+
+// NewScope starts a child scope, e.g. for a job spawned by a request. The
+// child sees the services registered in its ancestors but builds its own
+// scoped instances, and is closed on its own or, at the latest, with its
+// parent. The scope middleware prepares the child too; its error is
+// returned.
+func (s *Scope) NewScope(ctx context.Context) (*Scope, error) {
+    s.mu.Lock()
+    if s.closed {
+        s.mu.Unlock()
+        return nil, fmt.Errorf("cannot start child scope: scope is closed")
+    }
+
+    child := newScope(s.c, s, ctx)
+    s.c.log.Debugw("Starting child scope",
+        "scope", child.id,
+        "parent", s.id)
+    s.children = append(s.children, child)
+    s.mu.Unlock()
+    s.c.recordMutation(MutationScopeOpen, child.id, "child of "+s.id)
+
+    if err := s.c.openScope(child); err != nil {
+        return nil, err
+    }
+    return child, nil
+}
+
+func newScope(c *Container, parent *Scope, ctx context.Context) *Scope {
+    return &Scope{
+        c:         c,
+        id:        c.random.id(),
+        parent:    parent,
+        ctx:       ctx,
+        instances: make(map[string]interface{}),
+        locals:    make(map[string]interface{}),
+        overrides: make(map[string]override),
+        exposed:   make(map[string]bool),
+    }
+}
+
+// Register adds a service visible only to the scope and its children, such
+// as the current request or user. The qualifier must be unregistered in the
+// container or registered with the Scoped lifetime, in which case service
+// replaces what the provider would build.
+func (s *Scope) Register(qualifier string, service interface{}) error {
+    s.c.log.Debugw("Registering service in scope", "qualifier", qualifier)
+
+    if service == nil {
+        return fmt.Errorf("cannot register %w", &NilServiceError{Qualifier: qualifier, InScope: true})
+    }
+    if err := s.c.checkTypedNil(qualifier, service, "scope service"); err != nil {
+        return err
+    }
+
+    s.c.mu.RLock()
+    reg, registered := s.c.regs[qualifier]
+    s.c.mu.RUnlock()
+    if registered && reg.lifetime != Scoped {
+        return fmt.Errorf("cannot register %s in scope: registered in the container with the %s lifetime", qualifier, reg.lifetime)
+    }
+
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    if s.closed {
+        return fmt.Errorf("cannot register %s: scope is closed", qualifier)
+    }
+    if _, exists := s.locals[qualifier]; exists {
+        return &DuplicateRegistrationError{Qualifier: qualifier, InScope: true}
+    }
+    if _, built := s.instances[qualifier]; built {
+        return fmt.Errorf("cannot register %s in scope: already built", qualifier)
+    }
+    s.locals[qualifier] = service
+    return nil
+}
+
+// local finds a service registered in the scope or one of its ancestors
+func (s *Scope) local(qualifier string) (interface{}, bool, error) {
+    for scope := s; scope != nil; scope = scope.parent {
+        scope.mu.Lock()
+        closed := scope.closed
+        service, ok := scope.locals[qualifier]
+        scope.mu.Unlock()
+        if closed {
+            return nil, false, fmt.Errorf("cannot resolve %s: scope is closed", qualifier)
+        }
+        if ok {
+            return service, true, nil
+        }
+    }
+    return nil, false, nil
+}
+
+// ID identifies the scope in logs. IDs are random unless the container is
+// seeded with SetSeed.
+func (s *Scope) ID() string {
+    return s.id
+}
+
+// Context returns the context the scope was started with
+func (s *Scope) Context() context.Context {
+    return s.ctx
+}
+
+// Resolve returns the scope's instance of a scoped service, building it on
+// first use, or the container's service for any other qualifier
+func (s *Scope) Resolve(qualifier string) (interface{}, error) {
+    // Frames above callerLocation: site closure, renamed, Resolve, caller
+    qualifier = s.c.renamed(qualifier, func() string { return callerLocation(3) })
+
+    service, err := s.resolve(qualifier)
+    s.c.audit(AuditResolve, qualifier, "", err)
+    if err != nil {
+        s.c.emit(Event{Kind: EventResolveFailed, Qualifier: qualifier, Err: err})
+    }
+    return service, err
+}
+
+// InjectStruct is Container.InjectStruct resolving through the scope
+func (s *Scope) InjectStruct(target interface{}) error {
+    _, err := s.c.injectStruct(target, s.resolve, nil)
+    return err
+}
+
+// resolve looks up a final qualifier
+func (s *Scope) resolve(qualifier string) (interface{}, error) {
+    qualifier = s.experimentVariant(qualifier)
+    if service, ok, err := s.local(qualifier); err != nil || ok {
+        return service, err
+    }
+
+    s.c.mu.RLock()
+    provider, scoped := s.c.scoped[qualifier]
+    s.c.mu.RUnlock()
+    if !scoped {
+        return s.c.resolveTraced(qualifier)
+    }
+
+    s.mu.Lock()
+    if s.closed {
+        s.mu.Unlock()
+        return nil, fmt.Errorf("cannot resolve %s: scope is closed", qualifier)
+    }
+    service, built := s.instances[qualifier]
+    if built {
+        s.mu.Unlock()
+        return service, nil
+    }
+    // Instances still being built, such as the provider resolving this
+    // one, count toward the budget
+    if max := s.budget.MaxInstances; max > 0 && len(s.instances)+s.building >= max {
+        s.mu.Unlock()
+        s.c.log.Errorw("Scope budget exceeded",
+            "scope", s.id,
+            "qualifier", qualifier,
+            "maxInstances", max)
+        return nil, fmt.Errorf("cannot build scoped service %s: scope budget of %d instances exhausted", qualifier, max)
+    }
+    s.building++
+    s.mu.Unlock()
+    defer func() {
+        s.mu.Lock()
+        s.building--
+        s.mu.Unlock()
+    }()
+
+    // The provider may resolve through the scope, but not what it builds
+    leave, err := s.c.enterResolution(qualifier)
+    if err != nil {
+        return nil, err
+    }
+    defer leave()
+
+    s.c.log.Debugw("Building scoped service", "qualifier", qualifier)
+    err = s.c.withServiceLabels(s.ctx, "build", qualifier, func(context.Context) error {
+        var buildErr error
+        service, buildErr = provider(s)
+        return buildErr
+    })
+    if err != nil {
+        s.c.log.Errorw("Scoped provider failed",
+            "qualifier", qualifier,
+            "error", err)
+        return nil, fmt.Errorf("failed to build scoped service %s: %w", qualifier, err)
+    }
+    if service == nil {
+        return nil, fmt.Errorf("scoped provider for %s returned nil", qualifier)
+    }
+    if err := s.c.checkTypedNil(qualifier, service, "scoped provider result"); err != nil {
+        return nil, err
+    }
+    if service, err = s.c.decorateWeak(qualifier, service); err != nil {
+        return nil, err
+    }
+
+    s.mu.Lock()
+    if s.closed {
+        // The scope, or one of its parents, closed during the build
+        s.mu.Unlock()
+        s.c.log.Warnw("Discarding scoped service built after its scope closed",
+            "scope", s.id,
+            "qualifier", qualifier)
+        if err := discardInstance(service); err != nil {
+            return nil, fmt.Errorf("cannot resolve %s: scope is closed; discarding it failed: %w", qualifier, err)
+        }
+        return nil, fmt.Errorf("cannot resolve %s: scope is closed", qualifier)
+    }
+    defer s.mu.Unlock()
+    s.instances[qualifier] = service
+    return service, nil
+}
+
+// discardInstance cleans up an instance nobody will use, calling PreDestroy
+// and then Close when it implements them
+func discardInstance(instance interface{}) error {
+    var errs []error
+    if destroyer, ok := instance.(PreDestroyer); ok {
+        if err := destroyer.PreDestroy(); err != nil {
+            errs = append(errs, err)
+        }
+    }
+    if err := closeInstance(instance); err != nil {
+        errs = append(errs, err)
+    }
+    return errors.Join(errs...)
+}
+
+// OnClose registers cleanup run by Close with the outcome of the unit of
+// work, e.g. to commit or roll back a transaction. Cleanup runs in reverse
+// registration order.
+func (s *Scope) OnClose(cleanup func(err error) error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    s.closers = append(s.closers, cleanup)
+}
+
+// Close ends the scope, passing outcome, the error of the unit of work or
+// nil, to every cleanup. Child scopes still open are closed first with the
+// same outcome. It returns the cleanup errors joined. Closing a closed
+// scope does nothing; the parent of a closed child is unaffected. The
+// OnScopeClose hooks run last.
+func (s *Scope) Close(outcome error) error {
+    s.mu.Lock()
+    if s.closed {
+        s.mu.Unlock()
+        return nil
+    }
+    s.closed = true
+    opened := s.opened
+    children := s.children
+    closers := s.closers
+    s.children = nil
+    s.closers = nil
+    s.instances = nil
+    s.locals = nil
+    s.overrides = nil
+    s.mu.Unlock()
+
+    if s.parent != nil {
+        s.parent.forget(s)
+    }
+
+    s.c.log.Debugw("Closing scope",
+        "scope", s.id,
+        "children", len(children),
+        "cleanups", len(closers),
+        "failed", outcome != nil)
+    detail := "succeeded"
+    if outcome != nil {
+        detail = "failed: " + outcome.Error()
+    }
+    s.c.recordMutation(MutationScopeClose, s.id, detail)
+
+    var errs []error
+    for i := len(children) - 1; i >= 0; i-- {
+        if err := children[i].Close(outcome); err != nil {
+            errs = append(errs, err)
+        }
+    }
+    for i := len(closers) - 1; i >= 0; i-- {
+        if err := closers[i](outcome); err != nil {
+            errs = append(errs, err)
+        }
+    }
+    if opened {
+        s.c.closeHooks(s, outcome)
+    }
+    return errors.Join(errs...)
+}
+
+// forget drops a closed child
+func (s *Scope) forget(child *Scope) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    for i, candidate := range s.children {
+        if candidate == child {
+            s.children = append(s.children[:i], s.children[i+1:]...)
+            return
+        }
+    }
+}
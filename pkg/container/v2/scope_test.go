@@ -0,0 +1,210 @@
+package container
+
+import (
+    "context"
+    "errors"
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+type requestInfo struct {
+    id int
+}
+
+type scopedHandler struct {
+    Request *requestInfo `di:"request"`
+    Config  string       `di:"config"`
+}
+
+func TestScope_InstancePerScope(t *testing.T) {
+    c := NewContainer()
+    require.NoError(t, c.Register("config", "prod"))
+    next := 0
+    require.NoError(t, c.RegisterScoped("request", func(s *Scope) (interface{}, error) {
+        next++
+        return &requestInfo{id: next}, nil
+    }))
+
+    first := c.NewScope(context.Background())
+    a, err := first.Resolve("request")
+    require.NoError(t, err)
+    b, err := first.Resolve("request")
+    require.NoError(t, err)
+    assert.Same(t, a, b)
+
+    second := c.NewScope(context.Background())
+    var handler scopedHandler
+    require.NoError(t, second.InjectStruct(&handler))
+    assert.Equal(t, 2, handler.Request.id)
+    assert.Equal(t, "prod", handler.Config) // Non-scoped services come from the container
+
+    descriptor, ok := c.Describe("request")
+    require.True(t, ok)
+    assert.Equal(t, Scoped, descriptor.Lifetime)
+}
+
+func TestScope_NotResolvableFromContainer(t *testing.T) {
+    c := NewContainer()
+    require.NoError(t, c.RegisterScoped("request", func(s *Scope) (interface{}, error) {
+        return &requestInfo{}, nil
+    }))
+
+    _, err := c.Resolve("request")
+    assert.ErrorContains(t, err, "service request is scoped")
+
+    // Fields are required by default, so injection fails
+    _, err = c.InjectStructWithResult(&scopedHandler{})
+    assert.ErrorContains(t, err, `required service "request" for field Request not found: service request is scoped`)
+}
+
+func TestScope_CloseRunsCleanupInReverse(t *testing.T) {
+    c := NewContainer()
+    scope := c.NewScope(context.Background())
+
+    var calls []string
+    scope.OnClose(func(err error) error {
+        calls = append(calls, "first")
+        return errors.New("first failed")
+    })
+    scope.OnClose(func(err error) error {
+        calls = append(calls, "second: "+err.Error())
+        return nil
+    })
+
+    err := scope.Close(errors.New("work failed"))
+    assert.ErrorContains(t, err, "first failed")
+    assert.Equal(t, []string{"second: work failed", "first"}, calls)
+
+    // Closing again is a no-op and the scope no longer builds services
+    assert.NoError(t, scope.Close(nil))
+    require.NoError(t, c.RegisterScoped("request", func(s *Scope) (interface{}, error) {
+        return &requestInfo{}, nil
+    }))
+    _, err = scope.Resolve("request")
+    assert.ErrorContains(t, err, "scope is closed")
+}
+
+func TestScope_ProviderErrors(t *testing.T) {
+    c := NewContainer()
+    assert.ErrorContains(t, c.RegisterScoped("nil", nil), "nil provider")
+
+    require.NoError(t, c.RegisterScoped("broken", func(s *Scope) (interface{}, error) {
+        return nil, errors.New("no session")
+    }))
+    require.NoError(t, c.RegisterScoped("loop", func(s *Scope) (interface{}, error) {
+        return s.Resolve("loop")
+    }))
+
+    scope := c.NewScope(context.Background())
+    _, err := scope.Resolve("broken")
+    assert.ErrorContains(t, err, "failed to build scoped service broken: no session")
+    withinDeadline(t, func() {
+        _, err = scope.Resolve("loop")
+        assert.ErrorContains(t, err, "loop -> loop")
+    })
+}
+
+func TestScope_RegisterIsLocal(t *testing.T) {
+    c := NewContainer()
+    require.NoError(t, c.Register("config", "prod"))
+    require.NoError(t, c.RegisterScoped("request", func(s *Scope) (interface{}, error) {
+        return &requestInfo{id: -1}, nil
+    }))
+
+    scope := c.NewScope(context.Background())
+    require.NoError(t, scope.Register("request", &requestInfo{id: 7}))
+    require.NoError(t, scope.Register("user", "alice"))
+
+    var handler scopedHandler
+    require.NoError(t, scope.InjectStruct(&handler))
+    assert.Equal(t, 7, handler.Request.id) // Replaces the provider
+    user, err := scope.Resolve("user")
+    require.NoError(t, err)
+    assert.Equal(t, "alice", user)
+
+    // Neither the container nor other scopes see scope services
+    _, err = c.Resolve("user")
+    assert.Error(t, err)
+    _, err = c.NewScope(context.Background()).Resolve("user")
+    assert.Error(t, err)
+
+    assert.ErrorContains(t, scope.Register("user", "bob"), "already registered in scope")
+    assert.ErrorContains(t, scope.Register("config", "dev"), "singleton lifetime")
+    assert.Error(t, scope.Register("nothing", nil))
+}
+
+func TestScope_ChildFallsBackToParent(t *testing.T) {
+    c := NewContainer()
+    next := 0
+    require.NoError(t, c.RegisterScoped("request", func(s *Scope) (interface{}, error) {
+        next++
+        return &requestInfo{id: next}, nil
+    }))
+
+    parent := c.NewScope(context.Background())
+    require.NoError(t, parent.Register("user", "alice"))
+    fromParent, err := parent.Resolve("request")
+    require.NoError(t, err)
+
+    child, err := parent.NewScope(context.Background())
+    require.NoError(t, err)
+    user, err := child.Resolve("user")
+    require.NoError(t, err)
+    assert.Equal(t, "alice", user)
+
+    // The child keeps its own scoped instances
+    fromChild, err := child.Resolve("request")
+    require.NoError(t, err)
+    assert.NotSame(t, fromParent, fromChild)
+    require.NoError(t, child.Register("job", 42))
+    _, err = parent.Resolve("job")
+    assert.Error(t, err)
+}
+
+func TestScope_CloseChildIndependently(t *testing.T) {
+    c := NewContainer()
+    var closed []string
+
+    parent := c.NewScope(context.Background())
+    parent.OnClose(func(error) error { closed = append(closed, "parent"); return nil })
+    first, err := parent.NewScope(context.Background())
+    require.NoError(t, err)
+    first.OnClose(func(error) error { closed = append(closed, "first"); return nil })
+    second, err := parent.NewScope(context.Background())
+    require.NoError(t, err)
+    second.OnClose(func(err error) error { closed = append(closed, "second: "+err.Error()); return nil })
+
+    // Closing a child leaves the parent usable
+    require.NoError(t, first.Close(nil))
+    require.NoError(t, parent.Register("user", "alice"))
+    _, err = first.Resolve("user")
+    assert.ErrorContains(t, err, "scope is closed")
+
+    // Closing the parent closes the children still open first
+    require.NoError(t, parent.Close(errors.New("boom")))
+    assert.Equal(t, []string{"first", "second: boom", "parent"}, closed)
+    _, err = second.Resolve("user")
+    assert.ErrorContains(t, err, "scope is closed")
+    _, err = parent.NewScope(context.Background())
+    assert.ErrorContains(t, err, "scope is closed")
+}
+
+func TestScope_ClosedWhileBuilding(t *testing.T) {
+    c := NewContainer()
+    var closed []string
+    parent := c.NewScope(context.Background())
+    child, err := parent.NewScope(context.Background())
+    require.NoError(t, err)
+
+    // The parent closes, closing the child, while the child builds
+    require.NoError(t, c.RegisterScoped("tx", func(s *Scope) (interface{}, error) {
+        require.NoError(t, parent.Close(nil))
+        return &closeRecorder{name: "tx", log: &closed}, nil
+    }))
+
+    _, err = child.Resolve("tx")
+    assert.ErrorContains(t, err, "cannot resolve tx: scope is closed")
+    assert.Equal(t, []string{"tx"}, closed)
+}
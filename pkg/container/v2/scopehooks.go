@@ -0,0 +1,155 @@
+package container
+
+import (
+    "context"
+    "errors"
+    "fmt"
+)
+
+// ScopeMiddleware prepares every new scope, root or child, before it is
+// used: it may seed services with Scope.Register, add cleanup with
+// Scope.OnClose or set a budget with Scope.SetBudget. An error rejects the
+// scope, see OpenScope.
+//
+// Experimental: see Scope.
+type ScopeMiddleware func(s *Scope) error
+
+// ScopeBudget limits the work a single scope may do. Zero values mean
+// unlimited.
+//
+// Experimental: see Scope.
+type ScopeBudget struct {
+    MaxInstances int // Scoped instances the scope may build
+}
+
+// UseScope adds middleware run, in the order added, for every scope started
+// afterwards
+//
+// Experimental: see Scope.
+func (c *Container) UseScope(middleware ...ScopeMiddleware) {
+    c.scopeHooksMu.Lock()
+    defer c.scopeHooksMu.Unlock()
+    c.scopeMiddleware = append(c.scopeMiddleware, middleware...)
+}
+
+// OnScopeOpen adds a hook called with every scope, root or child, once its
+// middleware succeeded
+//
+// Experimental: see Scope.
+func (c *Container) OnScopeOpen(hook func(s *Scope)) {
+    c.scopeHooksMu.Lock()
+    defer c.scopeHooksMu.Unlock()
+    c.scopeOpenHooks = append(c.scopeOpenHooks, hook)
+}
+
+// OnScopeClose adds a hook called with every opened scope and its outcome
+// after the scope's cleanup ran
+//
+// Experimental: see Scope.
+func (c *Container) OnScopeClose(hook func(s *Scope, outcome error)) {
+    c.scopeHooksMu.Lock()
+    defer c.scopeHooksMu.Unlock()
+    c.scopeCloseHooks = append(c.scopeCloseHooks, hook)
+}
+
+// OpenScope is NewScope reporting the error of a failed scope middleware
+// instead of returning a closed scope.
+//
+// Experimental: see Scope.
+func (c *Container) OpenScope(ctx context.Context) (*Scope, error) {
+    scope := newScope(c, nil, ctx)
+    c.log.Debugw("Starting scope", "scope", scope.id)
+    if err := c.openScope(scope); err != nil {
+        return nil, err
+    }
+    return scope, nil
+}
+
+// openScope runs the middleware and open hooks for a new scope. A scope
+// rejected by its middleware is closed with the middleware error as its
+// outcome, running the cleanup the middleware added so far.
+func (c *Container) openScope(s *Scope) error {
+    c.scopeHooksMu.Lock()
+    middleware := c.scopeMiddleware
+    openHooks := c.scopeOpenHooks
+    c.scopeHooksMu.Unlock()
+
+    for _, prepare := range middleware {
+        if err := prepare(s); err != nil {
+            c.log.Errorw("Scope middleware failed",
+                "scope", s.id,
+                "error", err)
+            err = fmt.Errorf("scope middleware failed: %w", err)
+            s.mu.Lock()
+            s.err = err
+            s.mu.Unlock()
+            return errors.Join(err, s.Close(err))
+        }
+    }
+
+    s.mu.Lock()
+    s.opened = true
+    s.mu.Unlock()
+    for _, hook := range openHooks {
+        hook(s)
+    }
+    return nil
+}
+
+// closeHooks calls the close hooks of an opened scope
+func (c *Container) closeHooks(s *Scope, outcome error) {
+    c.scopeHooksMu.Lock()
+    closeHooks := c.scopeCloseHooks
+    c.scopeHooksMu.Unlock()
+
+    for _, hook := range closeHooks {
+        hook(s, outcome)
+    }
+}
+
+// SetBudget limits the work of the scope, typically from a ScopeMiddleware.
+// Instances built before are kept but count toward the budget.
+func (s *Scope) SetBudget(budget ScopeBudget) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    s.budget = budget
+}
+
+// Err returns the error of the middleware that rejected the scope, or nil
+func (s *Scope) Err() error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    return s.err
+}
+
+// MaxScopedInstances returns middleware giving every scope a budget of max
+// scoped instances
+//
+// Experimental: see Scope.
+func MaxScopedInstances(max int) ScopeMiddleware {
+    return func(s *Scope) error {
+        s.SetBudget(ScopeBudget{MaxInstances: max})
+        return nil
+    }
+}
+
+// SeedScope returns middleware registering the value built by seed in
+// every scope, such as a request ID or tenant read from the scope's
+// context:
+//
+//	c.UseScope(container.SeedScope("tenant", func(s *container.Scope) (interface{}, error) {
+//	    return tenantFrom(s.Context())
+//	}))
+//
+// Child scopes seed their own value, which hides their parent's.
+//
+// Experimental: see Scope.
+func SeedScope(qualifier string, seed func(s *Scope) (interface{}, error)) ScopeMiddleware {
+    return func(s *Scope) error {
+        service, err := seed(s)
+        if err != nil {
+            return fmt.Errorf("failed to seed %s: %w", qualifier, err)
+        }
+        return s.Register(qualifier, service)
+    }
+}
@@ -0,0 +1,145 @@
+package container
+
+import (
+    "context"
+    "errors"
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+type tenantKey struct{}
+
+func TestScope_OpenAndCloseHooks(t *testing.T) {
+    c := NewContainer()
+    var log []string
+    c.OnScopeOpen(func(s *Scope) { log = append(log, "open "+s.ID()) })
+    c.OnScopeClose(func(s *Scope, outcome error) {
+        log = append(log, "close "+s.ID())
+        if outcome != nil {
+            log = append(log, "outcome "+outcome.Error())
+        }
+    })
+
+    scope := c.NewScope(context.Background())
+    child, err := scope.NewScope(context.Background())
+    require.NoError(t, err)
+    scope.OnClose(func(error) error {
+        log = append(log, "cleanup")
+        return nil
+    })
+
+    require.NoError(t, scope.Close(errors.New("boom")))
+    require.NoError(t, scope.Close(nil)) // Closing again runs no hooks
+    assert.Equal(t, []string{
+        "open " + scope.ID(),
+        "open " + child.ID(),
+        "close " + child.ID(),
+        "outcome boom",
+        "cleanup",
+        "close " + scope.ID(),
+        "outcome boom",
+    }, log)
+}
+
+func TestScope_SeedScope(t *testing.T) {
+    c := NewContainer()
+    c.UseScope(SeedScope("tenant", func(s *Scope) (interface{}, error) {
+        tenant, ok := s.Context().Value(tenantKey{}).(string)
+        if !ok {
+            return nil, errors.New("no tenant in context")
+        }
+        return tenant, nil
+    }))
+
+    ctx := context.WithValue(context.Background(), tenantKey{}, "acme")
+    scope, err := c.OpenScope(ctx)
+    require.NoError(t, err)
+    tenant, err := scope.Resolve("tenant")
+    require.NoError(t, err)
+    assert.Equal(t, "acme", tenant)
+
+    // Child scopes seed their own value
+    child, err := scope.NewScope(context.WithValue(ctx, tenantKey{}, "globex"))
+    require.NoError(t, err)
+    tenant, err = child.Resolve("tenant")
+    require.NoError(t, err)
+    assert.Equal(t, "globex", tenant)
+}
+
+func TestScope_MiddlewareRejectsScope(t *testing.T) {
+    c := NewContainer()
+    var log []string
+    c.UseScope(func(s *Scope) error {
+        s.OnClose(func(outcome error) error {
+            log = append(log, "cleanup: "+outcome.Error())
+            return nil
+        })
+        return nil
+    }, func(s *Scope) error {
+        return errors.New("no tenant")
+    })
+    c.OnScopeOpen(func(s *Scope) { log = append(log, "open") })
+    c.OnScopeClose(func(s *Scope, outcome error) { log = append(log, "close") })
+
+    _, err := c.OpenScope(context.Background())
+    assert.EqualError(t, err, "scope middleware failed: no tenant")
+    assert.Equal(t, []string{"cleanup: scope middleware failed: no tenant"}, log)
+
+    scope := c.NewScope(context.Background())
+    assert.EqualError(t, scope.Err(), "scope middleware failed: no tenant")
+    _, err = scope.Resolve("anything")
+    assert.ErrorContains(t, err, "scope is closed")
+}
+
+func TestScope_ChildMiddlewareError(t *testing.T) {
+    c := NewContainer()
+    scope := c.NewScope(context.Background())
+    c.UseScope(func(s *Scope) error { return errors.New("children not allowed") })
+
+    _, err := scope.NewScope(context.Background())
+    assert.EqualError(t, err, "scope middleware failed: children not allowed")
+    assert.Empty(t, scope.children)
+}
+
+func TestScope_MaxScopedInstances(t *testing.T) {
+    c := NewContainer()
+    c.UseScope(MaxScopedInstances(2))
+    for _, qualifier := range []string{"first", "second", "third"} {
+        require.NoError(t, c.RegisterScoped(qualifier, func(s *Scope) (interface{}, error) {
+            return &requestInfo{}, nil
+        }))
+    }
+
+    scope := c.NewScope(context.Background())
+    _, err := scope.Resolve("first")
+    require.NoError(t, err)
+    _, err = scope.Resolve("second")
+    require.NoError(t, err)
+    _, err = scope.Resolve("first") // Built instances do not count again
+    require.NoError(t, err)
+
+    _, err = scope.Resolve("third")
+    assert.EqualError(t, err, "cannot build scoped service third: scope budget of 2 instances exhausted")
+
+    // Every scope gets its own budget
+    other := c.NewScope(context.Background())
+    _, err = other.Resolve("third")
+    assert.NoError(t, err)
+}
+
+func TestScope_BudgetCountsNestedBuilds(t *testing.T) {
+    c := NewContainer()
+    c.UseScope(MaxScopedInstances(1))
+    require.NoError(t, c.RegisterScoped("inner", func(s *Scope) (interface{}, error) {
+        return &requestInfo{}, nil
+    }))
+    require.NoError(t, c.RegisterScoped("outer", func(s *Scope) (interface{}, error) {
+        return s.Resolve("inner")
+    }))
+
+    scope := c.NewScope(context.Background())
+    _, err := scope.Resolve("outer")
+    assert.ErrorContains(t, err, "cannot build scoped service inner: scope budget of 1 instances exhausted")
+}
@@ -0,0 +1,58 @@
+package container
+
+import (
+    "encoding/binary"
+    "encoding/hex"
+    "math/rand/v2"
+    "sync"
+)
+
+// randomSource is the only source of randomness in the container
+type randomSource struct {
+    mu     sync.Mutex
+    rng    *rand.Rand
+    seeded bool
+}
+
+func newRandomSource() *randomSource {
+    return &randomSource{rng: rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))}
+}
+
+// SetSeed puts the container in deterministic mode for tests and golden
+// files: scope IDs are drawn from a generator seeded with seed, so they
+// repeat across runs, and startup work runs sequentially in registration
+// order whatever executor is configured.
+func (c *Container) SetSeed(seed uint64) {
+    c.log.Infow("Seeding container randomness", "seed", seed)
+    c.random.seed(seed)
+}
+
+func (r *randomSource) seed(seed uint64) {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+    r.rng = rand.New(rand.NewPCG(seed, seed))
+    r.seeded = true
+}
+
+// isSeeded reports whether SetSeed was called
+func (r *randomSource) isSeeded() bool {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+    return r.seeded
+}
+
+// id returns 8 random bytes in hex
+func (r *randomSource) id() string {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+    var buf [8]byte
+    binary.BigEndian.PutUint64(buf[:], r.rng.Uint64())
+    return hex.EncodeToString(buf[:])
+}
+
+// float64 returns a random number in [0, 1)
+func (r *randomSource) float64() float64 {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+    return r.rng.Float64()
+}
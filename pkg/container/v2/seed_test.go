@@ -0,0 +1,59 @@
+package container
+
+import (
+    "context"
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+func scopeIDs(c *Container) []string {
+    parent := c.NewScope(context.Background())
+    child, _ := parent.NewScope(context.Background())
+    return []string{parent.ID(), child.ID()}
+}
+
+func TestSetSeed_ReproducibleScopeIDs(t *testing.T) {
+    first := NewContainer()
+    first.SetSeed(42)
+    second := NewContainer()
+    second.SetSeed(42)
+    assert.Equal(t, scopeIDs(first), scopeIDs(second))
+
+    ids := scopeIDs(first)
+    assert.Len(t, ids[0], 16)
+    assert.NotEqual(t, ids[0], ids[1])
+
+    other := NewContainer()
+    other.SetSeed(7)
+    assert.NotEqual(t, scopeIDs(first), scopeIDs(other))
+
+    // Unseeded containers differ
+    assert.NotEqual(t, scopeIDs(NewContainer()), scopeIDs(NewContainer()))
+}
+
+func TestSetSeed_FreezesStartupOrder(t *testing.T) {
+    c := NewContainer()
+    c.SetExecutor(Parallel(0))
+    _, parallel := c.newExecutor().(*parallelExecutor)
+    assert.True(t, parallel)
+
+    c.SetSeed(1)
+    var order []string
+    for _, name := range []string{"a", "b", "c", "d"} {
+        require.NoError(t, c.Register(name, &recordingWarmer{name: name, order: &order}))
+    }
+    require.NoError(t, c.Start(context.Background()))
+    assert.Equal(t, []string{"a", "b", "c", "d"}, order)
+}
+
+type recordingWarmer struct {
+    name  string
+    order *[]string
+}
+
+func (w *recordingWarmer) Warmup(ctx context.Context) error {
+    *w.order = append(*w.order, w.name)
+    return nil
+}
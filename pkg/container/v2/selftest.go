@@ -0,0 +1,99 @@
+package container
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "sync"
+    "time"
+)
+
+// SelfTester is implemented by services that can verify their own health,
+// e.g. by round-tripping a request to a dependency
+type SelfTester interface {
+    SelfTest(ctx context.Context) error
+}
+
+// SelfTestResult is the outcome of one service's self-test
+type SelfTestResult struct {
+    Qualifier string
+    Duration  time.Duration
+    Err       error
+}
+
+// SelfTestReport aggregates the results of SelfTest in registration order
+type SelfTestReport struct {
+    Results []SelfTestResult
+}
+
+// Passed reports whether every self-test succeeded
+func (r *SelfTestReport) Passed() bool {
+    return r.Err() == nil
+}
+
+// Err joins the errors of all failed self-tests, or returns nil
+func (r *SelfTestReport) Err() error {
+    var errs []error
+    for _, result := range r.Results {
+        if result.Err != nil {
+            errs = append(errs, fmt.Errorf("self-test of %q failed: %w", result.Qualifier, result.Err))
+        }
+    }
+    return errors.Join(errs...)
+}
+
+// SelfTest concurrently runs SelfTest on every registered service that
+// implements SelfTester and returns the aggregated report
+func (c *Container) SelfTest(ctx context.Context) *SelfTestReport {
+    c.log.Info("Running service self-tests")
+
+    // Collect testers up front so the container lock is not held while tests run
+    var qualifiers []string
+    var testers []SelfTester
+    for _, qualifier := range c.snapshotOrder() {
+        service, err := c.Resolve(qualifier)
+        if err != nil {
+            continue
+        }
+        if tester, ok := service.(SelfTester); ok {
+            qualifiers = append(qualifiers, qualifier)
+            testers = append(testers, tester)
+        } else if probe, ok := c.remoteProbe(qualifier); ok {
+            // Remote stubs are tested through their health check
+            qualifiers = append(qualifiers, qualifier)
+            testers = append(testers, probeTester(probe))
+        }
+    }
+
+    report := &SelfTestReport{Results: make([]SelfTestResult, len(testers))}
+    var wg sync.WaitGroup
+    for i := range testers {
+        wg.Add(1)
+        go func(i int) {
+            defer wg.Done()
+
+            begin := c.clock.Now()
+            err := c.withServiceLabels(ctx, "selftest", qualifiers[i], testers[i].SelfTest)
+            report.Results[i] = SelfTestResult{
+                Qualifier: qualifiers[i],
+                Duration:  c.since(begin),
+                Err:       err,
+            }
+        }(i)
+    }
+    wg.Wait()
+
+    for _, result := range report.Results {
+        if result.Err != nil {
+            c.log.Errorw("Self-test failed",
+                "qualifier", result.Qualifier,
+                "duration", result.Duration,
+                "error", result.Err)
+        } else {
+            c.log.Infow("Self-test passed",
+                "qualifier", result.Qualifier,
+                "duration", result.Duration)
+        }
+    }
+    return report
+}
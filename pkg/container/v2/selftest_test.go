@@ -0,0 +1,41 @@
+package container
+
+import (
+    "context"
+    "errors"
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+type selfTestingService struct {
+    err error
+}
+
+func (s *selfTestingService) SelfTest(ctx context.Context) error {
+    return s.err
+}
+
+func TestContainer_SelfTest(t *testing.T) {
+    container := NewContainer()
+    require.NoError(t, container.Register("healthy", &selfTestingService{}))
+    require.NoError(t, container.Register("plain", &testServiceImpl{name: "plain"}))
+    require.NoError(t, container.Register("broken", &selfTestingService{err: errors.New("no route")}))
+
+    report := container.SelfTest(context.Background())
+    require.Len(t, report.Results, 2)
+    assert.Equal(t, "healthy", report.Results[0].Qualifier)
+    assert.NoError(t, report.Results[0].Err)
+    assert.Equal(t, "broken", report.Results[1].Qualifier)
+    assert.Error(t, report.Results[1].Err)
+
+    assert.False(t, report.Passed())
+    assert.Contains(t, report.Err().Error(), "no route")
+}
+
+func TestContainer_SelfTestEmpty(t *testing.T) {
+    report := NewContainer().SelfTest(context.Background())
+    assert.Empty(t, report.Results)
+    assert.True(t, report.Passed())
+}
@@ -0,0 +1,95 @@
+package container
+
+import (
+    "encoding/json"
+    "fmt"
+    "io"
+    "reflect"
+    "sort"
+    "time"
+)
+
+// DiagnosticStater is implemented by services that can describe their
+// internal state for support bundles. The state must marshal to JSON and
+// must not contain secrets.
+type DiagnosticStater interface {
+    DiagnosticState() any
+}
+
+// ServiceSnapshot is the diagnostic state of one service
+type ServiceSnapshot struct {
+    Type  string          `json:"type"`
+    State json.RawMessage `json:"state,omitempty"`
+    Error string          `json:"error,omitempty"` // Set when the state could not be marshaled
+}
+
+// Snapshot is a one-shot diagnostic bundle of the container
+type Snapshot struct {
+    TakenAt   time.Time                  `json:"takenAt"`
+    Container string                     `json:"container"`
+    Services  map[string]ServiceSnapshot `json:"services"`
+}
+
+// Snapshot writes the diagnostic state of every built service implementing
+// DiagnosticStater to w as indented JSON. Weak services that are not cached
+// are not built for the snapshot. A service whose state fails to marshal is
+// reported with an error instead of failing the bundle.
+func (c *Container) Snapshot(w io.Writer) error {
+    snapshot := Snapshot{
+        TakenAt:   c.clock.Now(),
+        Container: c.String(),
+        Services:  make(map[string]ServiceSnapshot),
+    }
+
+    // Collect instances first so DiagnosticState runs without the lock
+    instances := c.builtInstances()
+    qualifiers := make([]string, 0, len(instances))
+    for qualifier := range instances {
+        qualifiers = append(qualifiers, qualifier)
+    }
+    sort.Strings(qualifiers)
+
+    for _, qualifier := range qualifiers {
+        stater, ok := instances[qualifier].(DiagnosticStater)
+        if !ok {
+            continue
+        }
+
+        entry := ServiceSnapshot{Type: reflect.TypeOf(stater).String()}
+        state, err := json.Marshal(stater.DiagnosticState())
+        if err != nil {
+            c.log.Warnw("Failed to marshal diagnostic state",
+                "qualifier", qualifier,
+                "error", err)
+            entry.Error = err.Error()
+        } else {
+            entry.State = state
+        }
+        snapshot.Services[qualifier] = entry
+    }
+
+    encoder := json.NewEncoder(w)
+    encoder.SetIndent("", "  ")
+    if err := encoder.Encode(snapshot); err != nil {
+        return fmt.Errorf("failed to write diagnostic snapshot: %w", err)
+    }
+    c.log.Infow("Wrote diagnostic snapshot", "services", len(snapshot.Services))
+    return nil
+}
+
+// builtInstances returns the singletons and cached weak instances by qualifier
+func (c *Container) builtInstances() map[string]interface{} {
+    c.mu.RLock()
+    defer c.mu.RUnlock()
+
+    instances := make(map[string]interface{}, len(c.services))
+    for qualifier, service := range c.services {
+        instances[qualifier] = service
+    }
+    for qualifier := range c.weak {
+        if service, ok := c.weakLRU.peek(qualifier); ok {
+            instances[qualifier] = service
+        }
+    }
+    return instances
+}
@@ -0,0 +1,61 @@
+package container
+
+import (
+    "bytes"
+    "encoding/json"
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+// poolService reports its connection counts as diagnostic state
+type poolService struct {
+    open, idle int
+}
+
+func (p *poolService) DiagnosticState() any {
+    return map[string]int{"open": p.open, "idle": p.idle}
+}
+
+// brokenStater returns state that cannot be marshaled
+type brokenStater struct{}
+
+func (brokenStater) DiagnosticState() any {
+    return make(chan int)
+}
+
+func TestContainer_Snapshot(t *testing.T) {
+    container := NewContainer()
+    require.NoError(t, container.Register("pool", &poolService{open: 3, idle: 1}))
+    require.NoError(t, container.Register("broken", brokenStater{}))
+    require.NoError(t, container.Register("users", &testServiceImpl{}))
+
+    built := 0
+    require.NoError(t, container.RegisterWeak("cachedPool", func() (interface{}, error) {
+        built++
+        return &poolService{open: 1}, nil
+    }))
+    require.NoError(t, container.RegisterWeak("lazyPool", func() (interface{}, error) {
+        built++
+        return &poolService{}, nil
+    }))
+    _, err := container.Resolve("cachedPool")
+    require.NoError(t, err)
+
+    var buf bytes.Buffer
+    require.NoError(t, container.Snapshot(&buf))
+    assert.Equal(t, 1, built, "snapshot must not build weak services")
+
+    var snapshot Snapshot
+    require.NoError(t, json.Unmarshal(buf.Bytes(), &snapshot))
+    assert.False(t, snapshot.TakenAt.IsZero())
+    assert.Contains(t, snapshot.Container, "registrations: 5")
+
+    require.Len(t, snapshot.Services, 3)
+    assert.Equal(t, "*container.poolService", snapshot.Services["pool"].Type)
+    assert.JSONEq(t, `{"open":3,"idle":1}`, string(snapshot.Services["pool"].State))
+    assert.JSONEq(t, `{"open":1,"idle":0}`, string(snapshot.Services["cachedPool"].State))
+    assert.Contains(t, snapshot.Services["broken"].Error, "unsupported type")
+    assert.NotContains(t, snapshot.Services, "users")
+}
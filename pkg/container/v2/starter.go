@@ -0,0 +1,149 @@
+package container
+
+import "context"
+
+// Starter is implemented by services that start work when the container
+// starts, such as a server listening or a consumer polling
+type Starter interface {
+    OnStart(ctx context.Context) error
+}
+
+// Stopper is implemented by services that release resources when the
+// container stops
+type Stopper interface {
+    OnStop(ctx context.Context) error
+}
+
+// addServiceHooksLocked adds a hook for every singleton implementing
+// Starter or Stopper that has none yet. Hooks are added in dependency
+// order (see DependsOn), ties broken by registration order, so a service
+// starts after and stops before the services it depends on. Within a stage
+// service hooks start before appended hooks. Callers must hold lifecycleMu.
+func (c *Container) addServiceHooksLocked() {
+    for _, qualifier := range c.dependencyOrder() {
+        c.mu.RLock()
+        service, singleton := c.services[qualifier]
+        added := c.serviceHooks[qualifier]
+        c.mu.RUnlock()
+        if !singleton || added {
+            continue
+        }
+
+        starter, isStarter := service.(Starter)
+        _, isStopper := service.(Stopper)
+        if !isStarter && !isStopper {
+            continue
+        }
+        _, module := c.registrationSource(qualifier)
+        hook := Hook{Name: qualifier, Stage: c.stageOf(qualifier), service: true, module: module}
+        if isStarter {
+            hook.OnStart = starter.OnStart
+        }
+        if isStopper {
+            hook.OnStop = c.stopCurrent(qualifier)
+        }
+
+        c.log.Debugw("Adding service lifecycle hook",
+            "qualifier", qualifier,
+            "starter", isStarter,
+            "stopper", isStopper)
+        c.hooks = append(c.hooks, hook)
+        c.mu.Lock()
+        c.serviceHooks[qualifier] = true
+        c.mu.Unlock()
+    }
+}
+
+// stopCurrent returns the OnStop hook of qualifier. It stops the instance
+// registered when Stop runs, not the one the hook was added for, which a
+// Swap may have replaced, and does nothing if that instance is no Stopper
+// or the service is gone.
+func (c *Container) stopCurrent(qualifier string) func(ctx context.Context) error {
+    return func(ctx context.Context) error {
+        c.mu.RLock()
+        service, registered := c.services[qualifier]
+        c.mu.RUnlock()
+        current, isStopper := service.(Stopper)
+        if !registered || !isStopper {
+            c.log.Debugw("Service no longer stoppable",
+                "qualifier", qualifier,
+                "registered", registered)
+            return nil
+        }
+        return current.OnStop(ctx)
+    }
+}
+
+// dependencyLevels groups the qualifiers by depth in the dependency graph
+// used by dependencyOrder: the first level depends on nothing, and every
+// later level only on the levels before it, so the qualifiers of one level
+// are independent of each other.
+func (c *Container) dependencyLevels() [][]string {
+    observed := c.observedDependencies()
+
+    c.mu.RLock()
+    defer c.mu.RUnlock()
+
+    depth := make(map[string]int, len(c.order))
+    var levels [][]string
+    var visit func(qualifier string) int
+    visit = func(qualifier string) int {
+        reg, ok := c.regs[qualifier]
+        if !ok {
+            return -1
+        }
+        if d, ok := depth[qualifier]; ok {
+            return d // Also breaks cycles, like dependencyOrder
+        }
+        depth[qualifier] = 0
+        d := 0
+        for _, dependencies := range [][]string{reg.dependsOn, observed[qualifier]} {
+            for _, dependency := range dependencies {
+                d = max(d, visit(dependency)+1)
+            }
+        }
+        depth[qualifier] = d
+        for len(levels) <= d {
+            levels = append(levels, nil)
+        }
+        levels[d] = append(levels[d], qualifier)
+        return d
+    }
+    for _, qualifier := range c.order {
+        visit(qualifier)
+    }
+    return levels
+}
+
+// dependencyOrder returns the qualifiers with their dependencies before
+// them, otherwise in registration order. Dependencies are declared with
+// DependsOn, wired from provider signatures or resolved while building the
+// service. Cycles are broken where they are found.
+func (c *Container) dependencyOrder() []string {
+    observed := c.observedDependencies()
+
+    c.mu.RLock()
+    defer c.mu.RUnlock()
+
+    visited := make(map[string]bool, len(c.order))
+    order := make([]string, 0, len(c.order))
+    var visit func(qualifier string)
+    visit = func(qualifier string) {
+        reg, ok := c.regs[qualifier]
+        if !ok || visited[qualifier] {
+            return
+        }
+        visited[qualifier] = true
+        for _, dependency := range reg.dependsOn {
+            visit(dependency)
+        }
+        for _, dependency := range observed[qualifier] {
+            visit(dependency)
+        }
+        order = append(order, qualifier)
+    }
+    for _, qualifier := range c.order {
+        visit(qualifier)
+    }
+    return order
+}
@@ -0,0 +1,87 @@
+package container
+
+import (
+    "context"
+    "errors"
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+// lifecycleService records its start and stop in a shared log
+type lifecycleService struct {
+    name     string
+    log      *[]string
+    startErr error
+}
+
+func (s *lifecycleService) OnStart(ctx context.Context) error {
+    *s.log = append(*s.log, "start "+s.name)
+    return s.startErr
+}
+
+func (s *lifecycleService) OnStop(ctx context.Context) error {
+    *s.log = append(*s.log, "stop "+s.name)
+    return nil
+}
+
+// stopOnly only implements Stopper
+type stopOnly struct {
+    log *[]string
+}
+
+func (s stopOnly) OnStop(ctx context.Context) error {
+    *s.log = append(*s.log, "stop cache")
+    return nil
+}
+
+func TestStarter_DependencyOrder(t *testing.T) {
+    c := NewContainer()
+    var log []string
+    // Registered before its dependencies
+    require.NoError(t, c.Register("api", &lifecycleService{name: "api", log: &log}, DependsOn("users")))
+    require.NoError(t, c.Register("users", &lifecycleService{name: "users", log: &log}, DependsOn("database", "cache")))
+    require.NoError(t, c.Register("database", &lifecycleService{name: "database", log: &log}))
+    require.NoError(t, c.Register("cache", stopOnly{log: &log}))
+    require.NoError(t, c.Register("plain", "not a lifecycle service"))
+    c.Append(Hook{Name: "server", OnStart: func(ctx context.Context) error {
+        log = append(log, "start server hook")
+        return nil
+    }})
+
+    require.NoError(t, c.Start(context.Background()))
+    assert.Equal(t, []string{"start database", "start users", "start api", "start server hook"}, log)
+
+    // A second start adds no duplicate hooks
+    log = nil
+    require.NoError(t, c.Start(context.Background()))
+    assert.Empty(t, log)
+
+    require.NoError(t, c.Shutdown(context.Background()))
+    assert.Equal(t, []string{"stop api", "stop users", "stop cache", "stop database"}, log)
+}
+
+func TestStarter_StagesAndFailure(t *testing.T) {
+    c := NewContainer()
+    var log []string
+    require.NoError(t, c.Register("http", &lifecycleService{name: "http", log: &log, startErr: errors.New("port in use")}, InStage(StageTransport)))
+    require.NoError(t, c.Register("database", &lifecycleService{name: "database", log: &log}, InStage(StageInfrastructure)))
+
+    err := c.Start(context.Background())
+    assert.ErrorContains(t, err, `start hook "http" failed: port in use`)
+    // Started services are stopped again
+    assert.Equal(t, []string{"start database", "start http", "stop database"}, log)
+}
+
+func TestStarter_StopsSwappedInstance(t *testing.T) {
+    c := NewContainer()
+    var log []string
+    require.NoError(t, c.Register("api", &lifecycleService{name: "v1", log: &log}))
+    require.NoError(t, c.Start(context.Background()))
+
+    _, err := c.Swap("api", &lifecycleService{name: "v2", log: &log})
+    require.NoError(t, err)
+    require.NoError(t, c.Stop(context.Background()))
+    assert.Equal(t, []string{"start v1", "stop v2"}, log)
+}
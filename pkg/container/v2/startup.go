@@ -0,0 +1,203 @@
+package container
+
+import (
+    "context"
+    "fmt"
+    "sync"
+    "time"
+)
+
+// Warmer is implemented by services that want to prepare caches or
+// connections during the warmup phase of Start
+type Warmer interface {
+    Warmup(ctx context.Context) error
+}
+
+// PhaseTiming is the measured duration of a single startup phase
+type PhaseTiming struct {
+    Name     string
+    Duration time.Duration
+}
+
+// StartupReport describes how long each phase of Start took and which
+// constructions were retried, see RetryConstruction
+type StartupReport struct {
+    Phases  []PhaseTiming
+    Total   time.Duration
+    Retries []ConstructionRetry
+}
+
+// startPhase is one step of Start; phases run in order and stop at the first error
+type startPhase struct {
+    name string
+    run  func(ctx context.Context) error
+}
+
+// startPhases lists the phases of Start in execution order
+func (c *Container) startPhases() []startPhase {
+    return []startPhase{
+        {name: "validate", run: c.validatePhase},
+        {name: "construct", run: c.constructPhase},
+        {name: "warmup", run: c.warmupPhase},
+        {name: "hooks", run: c.startHooksLocked},
+    }
+}
+
+// runStartPhases executes every phase, recording durations in the startup
+// report, in the logs and in the metrics sink. Callers must hold lifecycleMu.
+func (c *Container) runStartPhases(ctx context.Context) error {
+    metrics := c.metricsSink()
+    report := &StartupReport{}
+    c.report = report
+
+    begin := c.clock.Now()
+    c.takeRetries() // Retries before Start are not part of the report
+    defer func() {
+        report.Total = c.since(begin)
+        report.Retries = c.takeRetries()
+        metrics.ObserveDuration("di_start_seconds", report.Total, nil)
+        for _, retry := range report.Retries {
+            c.log.Warnw("Construction retried during startup",
+                "qualifier", retry.Qualifier,
+                "retries", retry.Retries,
+                "recovered", retry.Recovered)
+        }
+    }()
+
+    for _, phase := range c.startPhases() {
+        phaseStart := c.clock.Now()
+        err := phase.run(ctx)
+        elapsed := c.since(phaseStart)
+
+        report.Phases = append(report.Phases, PhaseTiming{Name: phase.name, Duration: elapsed})
+        metrics.ObserveDuration("di_start_phase_seconds", elapsed, map[string]string{"phase": phase.name})
+        c.log.Infow("Startup phase completed",
+            "phase", phase.name,
+            "duration", elapsed,
+            "success", err == nil)
+
+        if err != nil {
+            // The mutation history shows how the container got here
+            c.log.Errorw("Startup failed",
+                "phase", phase.name,
+                "history", c.History())
+            return fmt.Errorf("startup phase %s failed: %w", phase.name, err)
+        }
+    }
+    return nil
+}
+
+// StartupReport returns the phase timings of the most recent Start, or nil
+// if the container has not been started
+func (c *Container) StartupReport() *StartupReport {
+    c.lifecycleMu.Lock()
+    defer c.lifecycleMu.Unlock()
+    return c.report
+}
+
+// validatePhase wires the providers, runs the registered validators, then
+// checks the dependency budgets
+func (c *Container) validatePhase(ctx context.Context) error {
+    if err := c.wireProviders(); err != nil {
+        return err
+    }
+    for _, validate := range c.validators {
+        if err := validate(); err != nil {
+            return err
+        }
+    }
+    return c.checkBudgets()
+}
+
+// constructPhase builds services that must exist before warmup. Instances
+// registered with Register are already constructed, so the phase builds the
+// providers and waits for the WaitFor probes of singletons. Weak services
+// wait when built.
+func (c *Container) constructPhase(ctx context.Context) error {
+    if err := c.constructProviders(); err != nil {
+        return err
+    }
+    for _, qualifier := range c.snapshotOrder() {
+        c.mu.RLock()
+        _, singleton := c.services[qualifier]
+        c.mu.RUnlock()
+        if !singleton {
+            continue
+        }
+        if err := c.waitForQualifier(ctx, qualifier); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// warmupPhase calls Warmup on every registered service implementing Warmer,
+// stage by stage and in registration order within a stage
+func (c *Container) warmupPhase(ctx context.Context) error {
+    for _, stage := range c.registrationStages() {
+        if err := c.warmupStage(ctx, stage); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// warmupStage warms the services of one init stage that were not warmed yet.
+// Services within a stage are independent, so they are handed to the
+// configured Executor. The warmed services are recorded once the stage
+// finished. Callers must hold lifecycleMu.
+func (c *Container) warmupStage(ctx context.Context, stage int) error {
+    executor := c.newExecutor()
+    var warmedMu sync.Mutex
+    var warmed []string
+
+    for _, qualifier := range c.snapshotOrder() {
+        if c.warmed[qualifier] || c.stageOf(qualifier) != stage {
+            continue
+        }
+        // Warming a factory registration would defeat its laziness, and
+        // transient or scoped instances would be discarded after warmup
+        if c.isUnbuiltLazy(qualifier) || c.lifetimeOf(qualifier) == Transient || c.lifetimeOf(qualifier) == Scoped {
+            continue
+        }
+        service, err := c.Resolve(qualifier)
+        if err != nil {
+            continue
+        }
+        warmer, ok := service.(Warmer)
+        if !ok {
+            continue
+        }
+
+        qualifier := qualifier
+        executor.Go(func() error {
+            c.log.Debugw("Warming up service",
+                "qualifier", qualifier,
+                "stage", stage)
+            if err := c.withServiceLabels(ctx, "warmup", qualifier, warmer.Warmup); err != nil {
+                // Degradable services fall back instead of failing startup
+                if !c.degradeOnWarmupFailure(qualifier, err) {
+                    return fmt.Errorf("warmup of %q failed: %w", qualifier, err)
+                }
+            }
+
+            warmedMu.Lock()
+            defer warmedMu.Unlock()
+            warmed = append(warmed, qualifier)
+            return nil
+        })
+    }
+    err := executor.Wait()
+
+    for _, qualifier := range warmed {
+        c.warmed[qualifier] = true
+    }
+    return err
+}
+
+// snapshotOrder returns a copy of the registration order
+func (c *Container) snapshotOrder() []string {
+    c.mu.RLock()
+    defer c.mu.RUnlock()
+    return append([]string(nil), c.order...)
+}
@@ -0,0 +1,104 @@
+package container
+
+import (
+    "context"
+    "errors"
+    "sync"
+    "testing"
+    "time"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+// recordingMetrics is a MetricsSink that remembers observed durations
+type recordingMetrics struct {
+    mu        sync.Mutex
+    durations map[string][]map[string]string
+    gauges    map[string]float64
+    counters  map[string]int
+}
+
+func newRecordingMetrics() *recordingMetrics {
+    return &recordingMetrics{
+        durations: make(map[string][]map[string]string),
+        gauges:    make(map[string]float64),
+        counters:  make(map[string]int),
+    }
+}
+
+// metricKey joins a metric name with its module label, if any
+func metricKey(name string, labels map[string]string) string {
+    if module, ok := labels["module"]; ok {
+        return name + "{" + module + "}"
+    }
+    return name
+}
+
+func (m *recordingMetrics) SetGauge(name string, value float64, labels map[string]string) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+    m.gauges[metricKey(name, labels)] = value
+}
+
+func (m *recordingMetrics) IncCounter(name string, labels map[string]string) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+    m.counters[metricKey(name, labels)]++
+}
+
+func (m *recordingMetrics) ObserveDuration(name string, d time.Duration, labels map[string]string) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+    m.durations[name] = append(m.durations[name], labels)
+}
+
+type warmingService struct {
+    warmed bool
+    err    error
+}
+
+func (w *warmingService) Warmup(ctx context.Context) error {
+    w.warmed = true
+    return w.err
+}
+
+func TestContainer_StartupReport(t *testing.T) {
+    container := NewContainer()
+    metrics := newRecordingMetrics()
+    container.SetMetricsSink(metrics)
+
+    warmer := &warmingService{}
+    require.NoError(t, container.Register("warmer", warmer))
+    assert.Nil(t, container.StartupReport())
+
+    require.NoError(t, container.Start(context.Background()))
+    assert.True(t, warmer.warmed)
+
+    report := container.StartupReport()
+    require.NotNil(t, report)
+    var names []string
+    for _, phase := range report.Phases {
+        names = append(names, phase.Name)
+    }
+    assert.Equal(t, []string{"validate", "construct", "warmup", "hooks"}, names)
+
+    assert.Len(t, metrics.durations["di_start_phase_seconds"], 4)
+    assert.Len(t, metrics.durations["di_start_seconds"], 1)
+    assert.Equal(t, "validate", metrics.durations["di_start_phase_seconds"][0]["phase"])
+}
+
+func TestContainer_StartWarmupFailure(t *testing.T) {
+    container := NewContainer()
+    var calls []string
+    container.Append(recordingHook("db", &calls))
+    require.NoError(t, container.Register("warmer", &warmingService{err: errors.New("cold")}))
+
+    err := container.Start(context.Background())
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "warmup")
+
+    // Hooks never ran because warmup failed first
+    assert.Empty(t, calls)
+    assert.Len(t, container.StartupReport().Phases, 3)
+}
@@ -0,0 +1,91 @@
+package container
+
+import (
+    "fmt"
+    "reflect"
+)
+
+// Swap atomically replaces the instance of a registered singleton and
+// returns the previous instance. Structs injected earlier keep the old
+// instance, except for Hot fields which are updated in place; resolve
+// again to pick up the new one.
+func (c *Container) Swap(qualifier string, service interface{}) (interface{}, error) {
+    c.writeMu.Lock()
+    defer c.writeMu.Unlock()
+
+    c.log.Infow("Swapping service",
+        "qualifier", qualifier,
+        "type", reflect.TypeOf(service))
+
+    if service == nil {
+        c.log.Errorw("Cannot swap in nil service", "qualifier", qualifier)
+        return nil, fmt.Errorf("cannot swap %w", &NilServiceError{Qualifier: qualifier})
+    }
+    if err := c.checkTypedNil(qualifier, service, "swapped service"); err != nil {
+        return nil, err
+    }
+
+    c.mu.RLock()
+    _, exists := c.services[qualifier]
+    var decorators []groupDecorators
+    if exists {
+        decorators = c.decoratorsLocked(c.regs[qualifier])
+    }
+    c.mu.RUnlock()
+    if !exists {
+        c.log.Errorw("Cannot swap unregistered service", "qualifier", qualifier)
+        return nil, fmt.Errorf("no singleton registered for qualifier: %s", qualifier)
+    }
+
+    // The new instance is decorated like the one it replaces
+    service, err := c.decorate(qualifier, service, decorators)
+    if err != nil {
+        return nil, err
+    }
+
+    // writeMu keeps the instance unchanged since it was checked above
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    if err := c.checkHotLocked(qualifier, service); err != nil {
+        c.log.Errorw("Swap rejected by hot field", "qualifier", qualifier, "error", err)
+        return nil, err
+    }
+    old := c.services[qualifier]
+    c.services[qualifier] = service
+    delete(c.destroyed, qualifier)
+    c.bumpVersionLocked(qualifier)
+    c.publishHotLocked(qualifier, service)
+    c.recordMutation(MutationSwap, qualifier, fmt.Sprintf("%v -> %v", reflect.TypeOf(old), reflect.TypeOf(service)))
+    c.log.Infow("Service swapped successfully",
+        "qualifier", qualifier,
+        "oldType", reflect.TypeOf(old),
+        "newType", reflect.TypeOf(service))
+    return old, nil
+}
+
+// Replace swaps service in for the singleton registered under qualifier,
+// like Swap, and closes the previous instance if it implements io.Closer
+// and is not registered under another qualifier. Use it to hot-swap an
+// implementation that holds resources.
+func (c *Container) Replace(qualifier string, service interface{}) error {
+    old, err := c.Swap(qualifier, service)
+    if err != nil {
+        return err
+    }
+
+    c.mu.Lock()
+    release := old != nil && !c.registeredLocked(old) && !c.closed[qualifier]
+    delete(c.closed, qualifier)
+    c.mu.Unlock()
+    if !release {
+        return nil
+    }
+
+    if err := closeInstance(old); err != nil {
+        c.log.Errorw("Failed to close replaced service",
+            "qualifier", qualifier,
+            "error", err)
+        return fmt.Errorf("replaced %s but failed to close the previous instance: %w", qualifier, err)
+    }
+    return nil
+}
@@ -0,0 +1,136 @@
+package container
+
+import (
+    "fmt"
+    "reflect"
+    "sort"
+    "strings"
+)
+
+// Inject is a marker that can be embedded in a struct to set injection
+// defaults for all of its fields through its own di tag:
+//
+//	type Handler struct {
+//	    container.Inject `di:"prefix=web.,optional"`
+//	    Users UserService `di:"users"` // resolved as "web.users"
+//	}
+//
+// Supported options are prefix=<qualifier prefix>, optional and required.
+type Inject struct{}
+
+var injectMarkerType = reflect.TypeOf(Inject{})
+
+// tagSpec is the parsed form of a di tag: a qualifier followed by options.
+// Field tags support ifPresent=<qualifier>, which injects the field only
+// when the guard qualifier is registered:
+//
+//	Metrics MetricsSink `di:"metricsSink,ifPresent=featureMetrics"`
+//
+// Fields are required unless tagged optional, or their struct's Inject
+// marker is; required overrides an optional marker:
+//
+//	Users UserService `di:"userService,required"`
+//	Cache Cache       `di:"cache,optional"`
+type tagSpec struct {
+    qualifier string
+    options   map[string]string
+}
+
+// parseTag splits a tag value of the form "qualifier,opt,key=value"
+func parseTag(tag string) tagSpec {
+    parts := strings.Split(tag, ",")
+    spec := tagSpec{
+        qualifier: strings.TrimSpace(parts[0]),
+        options:   make(map[string]string),
+    }
+
+    for _, part := range parts[1:] {
+        part = strings.TrimSpace(part)
+        if part == "" {
+            continue
+        }
+        key, value, _ := strings.Cut(part, "=")
+        spec.options[key] = value
+    }
+    return spec
+}
+
+// fieldOptions are the options a field tag may carry
+var fieldOptions = map[string]bool{"optional": true, "required": true, "ifPresent": true}
+
+// checkOptions rejects options a field tag does not support, see
+// WithStrictMode
+func (spec tagSpec) checkOptions() error {
+    var unknown []string
+    for key := range spec.options {
+        if !fieldOptions[key] {
+            unknown = append(unknown, key)
+        }
+    }
+    if len(unknown) == 0 {
+        return nil
+    }
+    sort.Strings(unknown)
+    return fmt.Errorf("unknown option %q in di tag", unknown[0])
+}
+
+// isOptional reports whether a missing service leaves the field unset
+// instead of failing the injection
+func (spec tagSpec) isOptional(defaults structDefaults) bool {
+    if _, required := spec.options["required"]; required {
+        return false
+    }
+    _, optional := spec.options["optional"]
+    return optional || defaults.optional
+}
+
+// structDefaults holds the injection defaults declared by an Inject marker
+type structDefaults struct {
+    prefix   string // Prefix prepended to every field qualifier
+    optional bool   // Whether missing services are skipped instead of failing
+}
+
+// readStructDefaults looks for an embedded Inject marker and parses its tag.
+// It returns the marker's field index, or -1 when the struct has no marker.
+func readStructDefaults(structType reflect.Type) (structDefaults, int, error) {
+    defaults := structDefaults{} // Missing services fail the injection unless stated otherwise
+
+    for i := 0; i < structType.NumField(); i++ {
+        field := structType.Field(i)
+        if !field.Anonymous || field.Type != injectMarkerType {
+            continue
+        }
+
+        // The whole marker tag is a list of options, so parse it with an empty qualifier
+        spec := parseTag("," + field.Tag.Get("di"))
+        for key, value := range spec.options {
+            switch key {
+            case "prefix":
+                defaults.prefix = value
+            case "optional", "required":
+                // Handled below so that "required" wins if both are given
+            default:
+                return defaults, i, fmt.Errorf("unknown option %q in Inject marker of %v", key, structType)
+            }
+        }
+        _, optional := spec.options["optional"]
+        _, required := spec.options["required"]
+        defaults.optional = optional && !required
+        return defaults, i, nil
+    }
+
+    return defaults, -1, nil
+}
+
+// guardPresent reports whether the ifPresent guard of a field is registered,
+// following renames
+func (c *Container) guardPresent(guard string, field reflect.StructField, structType reflect.Type) bool {
+    guard = c.renamed(guard, func() string {
+        return fmt.Sprintf("ifPresent guard of field %s of %v", field.Name, structType)
+    })
+
+    c.mu.RLock()
+    defer c.mu.RUnlock()
+    _, registered := c.regs[guard]
+    return registered
+}
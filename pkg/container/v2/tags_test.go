@@ -0,0 +1,144 @@
+package container
+
+import (
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+func TestParseTag(t *testing.T) {
+    spec := parseTag("userService, required ,prefix=web.")
+    assert.Equal(t, "userService", spec.qualifier)
+    assert.Contains(t, spec.options, "required")
+    assert.Equal(t, "web.", spec.options["prefix"])
+
+    empty := parseTag("")
+    assert.Equal(t, "", empty.qualifier)
+    assert.Empty(t, empty.options)
+}
+
+type prefixedStruct struct {
+    Inject  `di:"prefix=web."`
+    Service TestService `di:"testService"`
+}
+
+type requiredStruct struct {
+    Inject  `di:"required"`
+    Missing TestService `di:"missingService"`
+}
+
+type badMarkerStruct struct {
+    Inject  `di:"profile"`
+    Service TestService `di:"testService"`
+}
+
+func TestContainer_InjectStructMarker(t *testing.T) {
+    container := NewContainer()
+    webService := &testServiceImpl{name: "web"}
+    require.NoError(t, container.Register("testService", &testServiceImpl{name: "plain"}))
+    require.NoError(t, container.Register("web.testService", webService))
+
+    t.Run("prefix", func(t *testing.T) {
+        target := &prefixedStruct{}
+        require.NoError(t, container.InjectStruct(target))
+        assert.Equal(t, webService, target.Service)
+    })
+
+    t.Run("required", func(t *testing.T) {
+        err := container.InjectStruct(&requiredStruct{})
+        require.Error(t, err)
+        assert.Contains(t, err.Error(), "missingService")
+    })
+
+    t.Run("unknown option", func(t *testing.T) {
+        err := container.InjectStruct(&badMarkerStruct{})
+        require.Error(t, err)
+        assert.Contains(t, err.Error(), "profile")
+    })
+}
+
+type guardedStruct struct {
+    Inject  `di:"required"`
+    Metrics TestService           `di:"metricsSink,ifPresent=featureMetrics"`
+    Tracer  Optional[TestService] `di:"tracer,ifPresent=featureTracing"`
+}
+
+func TestContainer_InjectStructIfPresent(t *testing.T) {
+    container := NewContainer()
+    sink := &testServiceImpl{name: "sink"}
+    require.NoError(t, container.Register("metricsSink", sink))
+    require.NoError(t, container.Register("tracer", &testServiceImpl{name: "tracer"}))
+
+    // Guards absent: fields stay empty even though the struct is required
+    target := &guardedStruct{}
+    result, err := container.InjectStructWithResult(target)
+    require.NoError(t, err)
+    assert.Nil(t, target.Metrics)
+    assert.False(t, target.Tracer.Present())
+    assert.Equal(t, FieldGuarded, result.Fields[0].Status)
+    assert.Equal(t, FieldGuarded, result.Fields[1].Status)
+
+    // Guards present, possibly through a rename
+    require.NoError(t, container.Register("featureMetrics", true))
+    require.NoError(t, container.Register("tracing.enabled", true))
+    container.Rename("featureTracing", "tracing.enabled")
+
+    target = &guardedStruct{}
+    require.NoError(t, container.InjectStruct(target))
+    assert.Equal(t, sink, target.Metrics)
+    assert.True(t, target.Tracer.Present())
+}
+
+func TestContainer_InjectStructIfPresentRequired(t *testing.T) {
+    container := NewContainer()
+    require.NoError(t, container.Register("featureMetrics", true))
+
+    // Once the guard is present, a required field must resolve
+    err := container.InjectStruct(&guardedStruct{})
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "metricsSink")
+}
+
+type fieldOptionsStruct struct {
+    Users  TestService `di:"users,required"`
+    Cache  TestService `di:"cache,optional"`
+    Mailer TestService `di:"mailer"`
+}
+
+type optionalMarkerStruct struct {
+    Inject `di:"optional"`
+    Cache  TestService `di:"cache"`
+    Users  TestService `di:"users,required"`
+}
+
+func TestContainer_InjectStructFieldOptions(t *testing.T) {
+    container := NewContainer()
+
+    t.Run("required by default, all missing listed", func(t *testing.T) {
+        err := container.InjectStruct(&fieldOptionsStruct{})
+        require.Error(t, err)
+        assert.Contains(t, err.Error(), "failed to inject 2 fields of container.fieldOptionsStruct")
+        assert.Contains(t, err.Error(), `required service "users" for field Users not found`)
+        assert.Contains(t, err.Error(), `required service "mailer" for field Mailer not found`)
+        assert.NotContains(t, err.Error(), "cache")
+    })
+
+    t.Run("required overrides optional marker", func(t *testing.T) {
+        err := container.InjectStruct(&optionalMarkerStruct{})
+        require.Error(t, err)
+        assert.Contains(t, err.Error(), "failed to inject 1 fields")
+        assert.Contains(t, err.Error(), `"users"`)
+    })
+
+    t.Run("optional fields stay unset", func(t *testing.T) {
+        users := &testServiceImpl{name: "users"}
+        require.NoError(t, container.Register("users", users))
+        require.NoError(t, container.Register("mailer", &testServiceImpl{name: "mailer"}))
+
+        target := &fieldOptionsStruct{}
+        require.NoError(t, container.InjectStruct(target))
+        assert.Equal(t, users, target.Users)
+        assert.Nil(t, target.Cache)
+    })
+}
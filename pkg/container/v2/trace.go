@@ -0,0 +1,137 @@
+package container
+
+import (
+    "fmt"
+    "strings"
+    "sync"
+    "sync/atomic"
+    "time"
+)
+
+// TraceNode is one resolution in a traced call tree
+type TraceNode struct {
+    Qualifier string
+    Duration  time.Duration
+    Err       error
+    Children  []*TraceNode // Resolutions made while building this service
+}
+
+// TraceReport is the call tree recorded for one traced resolution
+type TraceReport struct {
+    Root       *TraceNode
+    RecordedAt time.Time
+}
+
+// String renders the call tree with one indented line per resolution
+func (r *TraceReport) String() string {
+    var builder strings.Builder
+    var write func(node *TraceNode, depth int)
+    write = func(node *TraceNode, depth int) {
+        builder.WriteString(fmt.Sprintf("%s%s (%v)", strings.Repeat("  ", depth), node.Qualifier, node.Duration))
+        if node.Err != nil {
+            builder.WriteString(fmt.Sprintf(" error: %v", node.Err))
+        }
+        builder.WriteString("\n")
+        for _, child := range node.Children {
+            write(child, depth+1)
+        }
+    }
+    write(r.Root, 0)
+    return builder.String()
+}
+
+// tracer tracks armed qualifiers and traces in progress per goroutine
+type tracer struct {
+    mu      sync.Mutex
+    pending int32                   // len(armed)+len(active), read without the lock on the fast path
+    armed   map[string]bool         // Qualifiers whose next resolution is traced
+    active  map[uint64][]*TraceNode // Goroutine ID -> stack of open nodes
+    reports map[string]*TraceReport // Latest report per root qualifier
+}
+
+func newTracer() *tracer {
+    return &tracer{
+        armed:   make(map[string]bool),
+        active:  make(map[uint64][]*TraceNode),
+        reports: make(map[string]*TraceReport),
+    }
+}
+
+// Trace arms tracing for the next resolution of qualifier. That resolution
+// and every resolution made while building it are recorded with timings,
+// logged as a single report and kept for LastTrace, without enabling debug
+// logging globally.
+func (c *Container) Trace(qualifier string) {
+    c.tracer.mu.Lock()
+    defer c.tracer.mu.Unlock()
+
+    if !c.tracer.armed[qualifier] {
+        c.tracer.armed[qualifier] = true
+        atomic.AddInt32(&c.tracer.pending, 1)
+    }
+    c.log.Infow("Armed resolution trace", "qualifier", qualifier)
+}
+
+// LastTrace returns the most recent trace report recorded for qualifier
+func (c *Container) LastTrace(qualifier string) (*TraceReport, bool) {
+    c.tracer.mu.Lock()
+    defer c.tracer.mu.Unlock()
+
+    report, ok := c.tracer.reports[qualifier]
+    return report, ok
+}
+
+// traceEnter opens a trace node for qualifier if it is armed or a trace is
+// running on this goroutine. The returned func closes the node.
+func (c *Container) traceEnter(qualifier string) func(error) {
+    t := c.tracer
+    if atomic.LoadInt32(&t.pending) == 0 {
+        return func(error) {}
+    }
+
+    gid := goroutineID()
+    t.mu.Lock()
+    defer t.mu.Unlock()
+
+    stack, running := t.active[gid]
+    if !running && !t.armed[qualifier] {
+        return func(error) {}
+    }
+    if !running {
+        // Start a new trace; the armed entry is consumed and replaced by the active one
+        delete(t.armed, qualifier)
+    }
+
+    node := &TraceNode{Qualifier: qualifier}
+    if running {
+        parent := stack[len(stack)-1]
+        parent.Children = append(parent.Children, node)
+    }
+    t.active[gid] = append(stack, node)
+    begin := c.clock.Now()
+
+    return func(err error) {
+        node.Duration = c.since(begin)
+        node.Err = err
+
+        t.mu.Lock()
+        defer t.mu.Unlock()
+
+        stack := t.active[gid]
+        stack = stack[:len(stack)-1]
+        if len(stack) > 0 {
+            t.active[gid] = stack
+            return
+        }
+
+        // The root finished: publish the report
+        delete(t.active, gid)
+        atomic.AddInt32(&t.pending, -1)
+        report := &TraceReport{Root: node, RecordedAt: c.clock.Now()}
+        t.reports[qualifier] = report
+        c.log.Infow("Resolution trace",
+            "qualifier", qualifier,
+            "duration", node.Duration,
+            "tree", report.String())
+    }
+}
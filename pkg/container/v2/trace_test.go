@@ -0,0 +1,74 @@
+package container
+
+import (
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+func TestGoroutineID(t *testing.T) {
+    id := goroutineID()
+    assert.NotZero(t, id)
+
+    other := make(chan uint64)
+    go func() { other <- goroutineID() }()
+    assert.NotEqual(t, id, <-other)
+}
+
+func TestContainer_Trace(t *testing.T) {
+    container := NewContainer()
+    require.NoError(t, container.Register("config", "cfg"))
+    require.NoError(t, container.RegisterWeak("repository", func() (interface{}, error) {
+        if _, err := container.Resolve("config"); err != nil {
+            return nil, err
+        }
+        _, _ = container.Resolve("missingCache")
+        return &testServiceImpl{name: "repo"}, nil
+    }))
+    require.NoError(t, container.RegisterWeak("handler", func() (interface{}, error) {
+        return container.Resolve("repository")
+    }))
+
+    // Nothing is recorded until a trace is armed
+    _, err := container.Resolve("config")
+    require.NoError(t, err)
+    _, ok := container.LastTrace("config")
+    assert.False(t, ok)
+
+    container.Trace("handler")
+    _, err = container.Resolve("handler")
+    require.NoError(t, err)
+
+    report, ok := container.LastTrace("handler")
+    require.True(t, ok)
+    root := report.Root
+    assert.Equal(t, "handler", root.Qualifier)
+    require.Len(t, root.Children, 1)
+    repository := root.Children[0]
+    assert.Equal(t, "repository", repository.Qualifier)
+    require.Len(t, repository.Children, 2)
+    assert.Equal(t, "config", repository.Children[0].Qualifier)
+    assert.Error(t, repository.Children[1].Err)
+
+    rendered := report.String()
+    assert.Contains(t, rendered, "handler (")
+    assert.Contains(t, rendered, "\n    config (")
+    assert.Contains(t, rendered, "missingCache")
+
+    // The trace fires only once
+    assert.Zero(t, container.tracer.pending)
+}
+
+func TestContainer_TraceFailure(t *testing.T) {
+    container := NewContainer()
+    container.Trace("absent")
+
+    _, err := container.Resolve("absent")
+    require.Error(t, err)
+
+    report, ok := container.LastTrace("absent")
+    require.True(t, ok)
+    assert.Equal(t, err, report.Root.Err)
+    assert.Contains(t, report.String(), "error:")
+}
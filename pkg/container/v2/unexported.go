@@ -0,0 +1,37 @@
+package container
+
+import (
+    "reflect"
+    "unsafe"
+)
+
+// WithUnexportedInjection makes InjectStruct set unexported fields tagged
+// with di instead of skipping them, for types that keep their dependencies
+// private:
+//
+//	type OrderService struct {
+//	    repo Repository `di:"orders.repository"`
+//	}
+//
+// The fields are written through unsafe, bypassing the read-only flag
+// reflect puts on unexported fields. Only the fields of the injected
+// struct and its nested structs are set; untagged unexported fields are
+// still left alone. See InjectMethodPrefix for a way without unsafe.
+func WithUnexportedInjection() Option {
+    return func(o *containerOptions) {
+        o.unexported = true
+    }
+}
+
+// settableField returns fieldValue settable when it is not, if the
+// container injects unexported fields and the field is addressable. The
+// second result reports whether the field can be set.
+func (c *Container) settableField(fieldValue reflect.Value) (reflect.Value, bool) {
+    if fieldValue.CanSet() {
+        return fieldValue, true
+    }
+    if !c.unexported || !fieldValue.CanAddr() {
+        return fieldValue, false
+    }
+    return reflect.NewAt(fieldValue.Type(), unsafe.Pointer(fieldValue.UnsafeAddr())).Elem(), true
+}
@@ -0,0 +1,66 @@
+package container
+
+import (
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+// privateService keeps its dependencies unexported
+type privateService struct {
+    db      TestService  `di:"db"`
+    pricing Hot[pricing] `di:"pricing"`
+    repo    *privateRepo `di:"inject"`
+    cache   TestService  // Untagged, left alone
+    ready   bool
+}
+
+type privateRepo struct {
+    logger TestService `di:"logger"`
+    ready  bool
+}
+
+func (r *privateRepo) PostConstruct() error {
+    r.ready = r.logger != nil
+    return nil
+}
+
+func TestInjectStruct_UnexportedFields(t *testing.T) {
+    c := newNestedContainer(t, WithUnexportedInjection())
+    require.NoError(t, c.Register("pricing", &fixedPricing{price: 10}))
+
+    target := &privateService{}
+    result, err := c.InjectStructWithResult(target)
+    require.NoError(t, err)
+    assert.NotNil(t, target.db)
+    assert.Nil(t, target.cache)
+    require.NotNil(t, target.repo)
+    assert.True(t, target.repo.ready)
+    assert.Equal(t, 10, target.pricing.Load().Price())
+    assert.Equal(t, FieldInjected, result.Fields[0].Status)
+
+    // Hot fields stay bound
+    _, err = c.Swap("pricing", &fixedPricing{price: 20})
+    require.NoError(t, err)
+    assert.Equal(t, 20, target.pricing.Load().Price())
+}
+
+func TestInjectStruct_UnexportedFieldsSkippedByDefault(t *testing.T) {
+    c := newNestedContainer(t)
+
+    var target struct {
+        db TestService `di:"db"`
+    }
+    result, err := c.InjectStructWithResult(&target)
+    require.NoError(t, err)
+    assert.Nil(t, target.db)
+    assert.Equal(t, FieldUnexported, result.Fields[0].Status)
+
+    // Strict mode still rejects them unless they can be set
+    err = NewContainer(WithStrictMode()).InjectStruct(&target)
+    assert.ErrorContains(t, err, "field db is unexported and cannot be injected")
+    c = newNestedContainer(t, WithStrictMode(), WithUnexportedInjection())
+    require.NoError(t, c.InjectStruct(&target))
+    assert.NotNil(t, target.db)
+}
@@ -0,0 +1,160 @@
+package container
+
+import (
+    "fmt"
+    "io"
+    "reflect"
+    "sync/atomic"
+)
+
+// Unregister removes a service and everything the container keeps for it:
+// its instance, provider, bindings and hot fields. A built singleton or
+// cached weak instance implementing io.Closer is closed, unless it is still
+// registered under another qualifier. Structs injected earlier keep the
+// instance they were given.
+//
+// Unregister fails after Freeze and for a Starter or Stopper service whose
+// hook has started; stop the container first.
+func (c *Container) Unregister(qualifier string) error {
+    c.lifecycleMu.Lock()
+    defer c.lifecycleMu.Unlock()
+    c.writeMu.Lock()
+    defer c.writeMu.Unlock()
+
+    c.log.Infow("Unregistering service", "qualifier", qualifier)
+
+    c.mu.Lock()
+    reg, ok := c.regs[qualifier]
+    if !ok {
+        c.mu.Unlock()
+        c.log.Errorw("Cannot unregister unknown service", "qualifier", qualifier)
+        return fmt.Errorf("cannot unregister %w", &ServiceNotFoundError{Qualifier: qualifier})
+    }
+    if c.frozen {
+        c.mu.Unlock()
+        c.log.Errorw("Unregistration after Freeze", "qualifier", qualifier)
+        return fmt.Errorf("cannot unregister %q: container is frozen", qualifier)
+    }
+    if err := c.removeServiceHookLocked(qualifier); err != nil {
+        c.mu.Unlock()
+        return err
+    }
+
+    instance, built := c.services[qualifier]
+    if !built {
+        instance, built = c.weakLRU.peek(qualifier)
+    }
+    built = built && !c.closed[qualifier]
+    c.removeLocked(reg)
+    if built && c.registeredLocked(instance) {
+        built = false
+    }
+    c.recordMutation(MutationUnregister, qualifier, reg.lifetime.String())
+    c.mu.Unlock()
+
+    if built {
+        if err := closeInstance(instance); err != nil {
+            c.log.Errorw("Failed to close unregistered service",
+                "qualifier", qualifier,
+                "error", err)
+            return fmt.Errorf("unregistered %s but failed to close it: %w", qualifier, err)
+        }
+    }
+    c.log.Infow("Service unregistered successfully", "qualifier", qualifier)
+    return nil
+}
+
+// removeLocked deletes the registration and the state kept for it.
+// Callers must hold c.mu.
+func (c *Container) removeLocked(reg *registration) {
+    qualifier := reg.qualifier
+    delete(c.services, qualifier)
+    delete(c.regs, qualifier)
+    delete(c.weak, qualifier)
+    c.weakLRU.remove(qualifier)
+    delete(c.lazy, qualifier)
+    delete(c.providers, qualifier)
+    delete(c.scoped, qualifier)
+    delete(c.transient, qualifier)
+    delete(c.remotes, qualifier)
+    delete(c.degradables, qualifier)
+    delete(c.hot, qualifier)
+    delete(c.consumers, qualifier)
+    delete(c.consumerFields, qualifier)
+    delete(c.closed, qualifier)
+    delete(c.destroyed, qualifier)
+    delete(c.warmed, qualifier)
+    for iface, bound := range c.bindings {
+        if bound == qualifier {
+            delete(c.bindings, iface)
+        }
+    }
+    c.guardsMu.Lock()
+    delete(c.guards, qualifier)
+    c.guardsMu.Unlock()
+    if debugEnabled {
+        delete(c.debug.registered, qualifier)
+    }
+    if reg.sensitive {
+        atomic.AddInt32(&c.sensitiveCount, -1)
+    }
+    for i, registered := range c.order {
+        if registered == qualifier {
+            c.order = append(c.order[:i:i], c.order[i+1:]...)
+            break
+        }
+    }
+}
+
+// removeServiceHookLocked drops the Starter/Stopper hook of qualifier if it
+// has not started. Callers must hold lifecycleMu and c.mu.
+func (c *Container) removeServiceHookLocked(qualifier string) error {
+    if !c.serviceHooks[qualifier] {
+        return nil
+    }
+    for i, hook := range c.hooks {
+        if !hook.service || hook.Name != qualifier {
+            continue
+        }
+        if c.hookStarted(i) {
+            c.log.Errorw("Cannot unregister started service", "qualifier", qualifier)
+            return fmt.Errorf("cannot unregister %s: service is started; stop the container first", qualifier)
+        }
+        c.hooks = append(c.hooks[:i:i], c.hooks[i+1:]...)
+        for j, started := range c.started {
+            if started > i {
+                c.started[j] = started - 1
+            }
+        }
+        break
+    }
+    delete(c.serviceHooks, qualifier)
+    return nil
+}
+
+// registeredLocked reports whether instance is the singleton or cached weak
+// instance of some qualifier. Callers must hold c.mu.
+func (c *Container) registeredLocked(instance interface{}) bool {
+    if instance == nil || !reflect.TypeOf(instance).Comparable() {
+        return false
+    }
+    for _, service := range c.services {
+        if reflect.TypeOf(service).Comparable() && service == instance {
+            return true
+        }
+    }
+    for qualifier := range c.weak {
+        if cached, ok := c.weakLRU.peek(qualifier); ok && reflect.TypeOf(cached).Comparable() && cached == instance {
+            return true
+        }
+    }
+    return false
+}
+
+// closeInstance closes instance if it implements io.Closer
+func closeInstance(instance interface{}) error {
+    if closer, ok := instance.(io.Closer); ok {
+        return closer.Close()
+    }
+    return nil
+}
@@ -0,0 +1,171 @@
+package container
+
+import (
+    "context"
+    "errors"
+    "reflect"
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+// startStopRecorder is a closeRecorder with lifecycle hooks
+type startStopRecorder struct {
+    closeRecorder
+}
+
+func (r *startStopRecorder) OnStart(ctx context.Context) error { return nil }
+
+func TestUnregister_RemovesService(t *testing.T) {
+    c := NewContainer()
+    require.NoError(t, c.Register("user", &testServiceImpl{}))
+    require.NoError(t, BindInterface[TestService](c, "user"))
+
+    require.NoError(t, c.Unregister("user"))
+
+    _, err := c.Resolve("user")
+    assert.ErrorIs(t, err, ErrServiceNotFound)
+    _, err = c.ResolveByType(reflect.TypeOf((*TestService)(nil)).Elem())
+    assert.ErrorIs(t, err, ErrServiceNotFound)
+    assert.Empty(t, c.bindings)
+    assert.Empty(t, c.snapshotOrder())
+
+    // The qualifier is free again
+    require.NoError(t, c.Register("user", &testServiceImpl{}))
+}
+
+func TestUnregister_ClosesInstance(t *testing.T) {
+    c := NewContainer()
+    var log []string
+    require.NoError(t, c.Register("database", &closeRecorder{name: "database", log: &log}))
+    require.NoError(t, c.RegisterWeak("templates", func() (interface{}, error) {
+        return &closeRecorder{name: "templates", log: &log}, nil
+    }))
+    require.NoError(t, c.RegisterFactory("unused", func(*Container) (interface{}, error) {
+        return &closeRecorder{name: "unused", log: &log}, nil
+    }))
+    _, err := c.Resolve("templates")
+    require.NoError(t, err)
+
+    require.NoError(t, c.Unregister("database"))
+    require.NoError(t, c.Unregister("templates"))
+    require.NoError(t, c.Unregister("unused"))
+    assert.Equal(t, []string{"database", "templates"}, log)
+
+    // Close does not see them again
+    require.NoError(t, c.Close())
+    assert.Equal(t, []string{"database", "templates"}, log)
+}
+
+func TestUnregister_KeepsSharedInstanceOpen(t *testing.T) {
+    c := NewContainer()
+    var log []string
+    database := &closeRecorder{name: "database", log: &log}
+    require.NoError(t, c.Register("database", database))
+    require.NoError(t, c.Register("db", database))
+
+    require.NoError(t, c.Unregister("db"))
+    assert.Empty(t, log)
+
+    require.NoError(t, c.Unregister("database"))
+    assert.Equal(t, []string{"database"}, log)
+}
+
+func TestUnregister_ReportsCloseError(t *testing.T) {
+    c := NewContainer()
+    var log []string
+    require.NoError(t, c.Register("database", &closeRecorder{name: "database", log: &log, err: errors.New("busy")}))
+
+    err := c.Unregister("database")
+    assert.EqualError(t, err, "unregistered database but failed to close it: busy")
+    _, err = c.Resolve("database")
+    assert.ErrorIs(t, err, ErrServiceNotFound)
+}
+
+func TestUnregister_Errors(t *testing.T) {
+    c := NewContainer()
+
+    err := c.Unregister("missing")
+    assert.ErrorIs(t, err, ErrServiceNotFound)
+    assert.EqualError(t, err, "cannot unregister no service found for qualifier: missing")
+
+    require.NoError(t, c.Register("user", &testServiceImpl{}))
+    c.Freeze()
+    assert.EqualError(t, c.Unregister("user"), `cannot unregister "user": container is frozen`)
+}
+
+func TestUnregister_StartedService(t *testing.T) {
+    c := NewContainer()
+    var log []string
+    require.NoError(t, c.Register("server", &startStopRecorder{closeRecorder{name: "server", log: &log}}))
+    require.NoError(t, c.Start(context.Background()))
+
+    err := c.Unregister("server")
+    assert.EqualError(t, err, "cannot unregister server: service is started; stop the container first")
+    _, err = c.Resolve("server")
+    assert.NoError(t, err)
+
+    require.NoError(t, c.Stop(context.Background()))
+    require.NoError(t, c.Unregister("server"))
+    assert.Equal(t, []string{"server"}, log)
+    assert.Empty(t, c.hooks)
+}
+
+func TestUnregister_RecordsMutation(t *testing.T) {
+    c := NewContainer()
+    require.NoError(t, c.Register("user", &testServiceImpl{}))
+    var events []Event
+    c.OnEvent(func(e Event) { events = append(events, e) })
+
+    require.NoError(t, c.Unregister("user"))
+
+    history := c.History()
+    assert.Equal(t, MutationUnregister, history[len(history)-1].Kind)
+    require.Len(t, events, 1)
+    assert.Equal(t, EventUnregister, events[0].Kind)
+    assert.Equal(t, "user", events[0].Qualifier)
+}
+
+func TestReplace_ClosesPreviousInstance(t *testing.T) {
+    c := NewContainer()
+    var log []string
+    require.NoError(t, c.Register("database", &closeRecorder{name: "old", log: &log}))
+
+    replacement := &closeRecorder{name: "new", log: &log}
+    require.NoError(t, c.Replace("database", replacement))
+    assert.Equal(t, []string{"old"}, log)
+
+    service, err := c.Resolve("database")
+    require.NoError(t, err)
+    assert.Same(t, replacement, service)
+
+    require.NoError(t, c.Close())
+    assert.Equal(t, []string{"old", "new"}, log)
+}
+
+func TestReplace_KeepsSharedOrSameInstanceOpen(t *testing.T) {
+    c := NewContainer()
+    var log []string
+    database := &closeRecorder{name: "database", log: &log}
+    require.NoError(t, c.Register("database", database))
+    require.NoError(t, c.Register("db", database))
+
+    require.NoError(t, c.Replace("db", &closeRecorder{name: "other", log: &log}))
+    require.NoError(t, c.Replace("database", database))
+    assert.Empty(t, log)
+}
+
+func TestReplace_Errors(t *testing.T) {
+    c := NewContainer()
+    var log []string
+
+    assert.Error(t, c.Replace("missing", "value"))
+
+    require.NoError(t, c.Register("database", &closeRecorder{name: "old", log: &log, err: errors.New("busy")}))
+    err := c.Replace("database", "value")
+    assert.EqualError(t, err, "replaced database but failed to close the previous instance: busy")
+    service, err := c.Resolve("database")
+    require.NoError(t, err)
+    assert.Equal(t, "value", service)
+}
@@ -0,0 +1,35 @@
+package container
+
+import (
+    "fmt"
+)
+
+// ReadOnlyView gives untrusted code, such as third-party plugins, access to
+// an allow-list of services. It can only resolve those qualifiers: it cannot
+// register or override services, and it cannot enumerate or probe the rest
+// of the graph, since qualifiers outside the list fail the same way whether
+// or not they are registered.
+type ReadOnlyView struct {
+    c       *Container
+    allowed map[string]bool
+}
+
+// ReadOnlyView returns a view that can resolve only allowedQualifiers
+func (c *Container) ReadOnlyView(allowedQualifiers ...string) *ReadOnlyView {
+    allowed := make(map[string]bool, len(allowedQualifiers))
+    for _, qualifier := range allowedQualifiers {
+        allowed[qualifier] = true
+    }
+
+    c.log.Infow("Creating read-only view", "qualifiers", allowedQualifiers)
+    return &ReadOnlyView{c: c, allowed: allowed}
+}
+
+// Resolve retrieves an allowed service from the underlying container
+func (v *ReadOnlyView) Resolve(qualifier string) (interface{}, error) {
+    if !v.allowed[qualifier] {
+        v.c.log.Warnw("Read-only view denied resolution", "qualifier", qualifier)
+        return nil, fmt.Errorf("qualifier %s is not visible in this view", qualifier)
+    }
+    return v.c.Resolve(qualifier)
+}
@@ -0,0 +1,31 @@
+package container
+
+import (
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+func TestContainer_ReadOnlyView(t *testing.T) {
+    container := NewContainer()
+    users := &testServiceImpl{name: "users"}
+    require.NoError(t, container.Register("users", users))
+    require.NoError(t, container.Register("secrets", &testServiceImpl{name: "secrets"}))
+
+    view := container.ReadOnlyView("users", "reports")
+
+    got, err := view.Resolve("users")
+    require.NoError(t, err)
+    assert.Same(t, users, got)
+
+    // Registered and unregistered qualifiers outside the list look the same
+    _, secretErr := view.Resolve("secrets")
+    _, unknownErr := view.Resolve("unknown")
+    assert.EqualError(t, secretErr, "qualifier secrets is not visible in this view")
+    assert.EqualError(t, unknownErr, "qualifier unknown is not visible in this view")
+
+    // Allowed but unregistered fails like the container does
+    _, err = view.Resolve("reports")
+    assert.ErrorContains(t, err, "no service found")
+}
@@ -0,0 +1,176 @@
+package container
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "net"
+    "net/http"
+    "time"
+)
+
+// Probe checks that an external resource a service needs is reachable
+type Probe struct {
+    Name  string                          // Used in logs and errors
+    Check func(ctx context.Context) error // Returns nil once the resource is ready
+}
+
+// TCPProbe succeeds once a TCP connection to address can be opened
+func TCPProbe(address string) Probe {
+    return Probe{
+        Name: "tcp " + address,
+        Check: func(ctx context.Context) error {
+            var dialer net.Dialer
+            conn, err := dialer.DialContext(ctx, "tcp", address)
+            if err != nil {
+                return err
+            }
+            return conn.Close()
+        },
+    }
+}
+
+// HTTPProbe succeeds once a GET of url returns a 2xx or 3xx status
+func HTTPProbe(url string) Probe {
+    return Probe{
+        Name: "http " + url,
+        Check: func(ctx context.Context) error {
+            request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+            if err != nil {
+                return err
+            }
+            response, err := http.DefaultClient.Do(request)
+            if err != nil {
+                return err
+            }
+            response.Body.Close()
+            if response.StatusCode >= 400 {
+                return fmt.Errorf("status %s", response.Status)
+            }
+            return nil
+        },
+    }
+}
+
+// ProbeFunc wraps a custom readiness check
+func ProbeFunc(name string, check func(ctx context.Context) error) Probe {
+    return Probe{Name: name, Check: check}
+}
+
+// WaitPolicy controls how long and how often probes are retried
+type WaitPolicy struct {
+    Timeout        time.Duration // Total time allowed for all probes of a service
+    InitialBackoff time.Duration // Delay after the first failed attempt, at least 10ms
+    MaxBackoff     time.Duration // Upper bound of the doubling delay
+}
+
+// minWaitBackoff is the delay used when a wait policy sets none, so probes
+// are not retried in a busy loop
+const minWaitBackoff = 10 * time.Millisecond
+
+// DefaultWaitPolicy retries for up to a minute with backoff from 100ms to 5s
+var DefaultWaitPolicy = WaitPolicy{
+    Timeout:        time.Minute,
+    InitialBackoff: 100 * time.Millisecond,
+    MaxBackoff:     5 * time.Second,
+}
+
+// WaitFor makes the container wait until every probe succeeds before the
+// service is built: before a weak or cached provider runs, and in the
+// construct phase of Start for other services. Probes are retried with
+// exponential backoff according to the wait policy, so a container started
+// alongside its database waits instead of crash-looping.
+func WaitFor(probes ...Probe) RegisterOption {
+    return func(r *registration) {
+        r.waitFor = append(r.waitFor, probes...)
+    }
+}
+
+// SetWaitPolicy changes the retry policy used by WaitFor probes
+func (c *Container) SetWaitPolicy(policy WaitPolicy) {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+
+    c.log.Infow("Setting wait policy",
+        "timeout", policy.Timeout,
+        "initialBackoff", policy.InitialBackoff,
+        "maxBackoff", policy.MaxBackoff)
+    c.waitPolicy = policy
+}
+
+// waitForQualifier runs the probes declared for qualifier, if any, until
+// they succeed once. Later builds of the registration, such as transient
+// resolves and weak rebuilds, do not probe again.
+func (c *Container) waitForQualifier(ctx context.Context, qualifier string) error {
+    c.mu.RLock()
+    reg, ok := c.regs[qualifier]
+    c.mu.RUnlock()
+    if !ok || reg.ready.Load() {
+        return nil
+    }
+
+    if err := c.waitForProbes(ctx, qualifier, reg.waitFor); err != nil {
+        return err
+    }
+    reg.ready.Store(true)
+    return nil
+}
+
+// waitForProbes retries each probe until it succeeds or the policy timeout
+// or ctx ends the wait
+func (c *Container) waitForProbes(ctx context.Context, qualifier string, probes []Probe) error {
+    if len(probes) == 0 {
+        return nil
+    }
+
+    c.mu.RLock()
+    policy := c.waitPolicy
+    c.mu.RUnlock()
+    if policy.Timeout > 0 {
+        var cancel context.CancelFunc
+        ctx, cancel = context.WithTimeout(ctx, policy.Timeout)
+        defer cancel()
+    }
+
+    for _, probe := range probes {
+        backoff := max(policy.InitialBackoff, minWaitBackoff)
+        for attempt := 1; ; attempt++ {
+            err := probe.Check(ctx)
+            if err == nil {
+                c.log.Debugw("Dependency ready",
+                    "qualifier", qualifier,
+                    "probe", probe.Name,
+                    "attempts", attempt)
+                break
+            }
+
+            c.log.Infow("Waiting for dependency",
+                "qualifier", qualifier,
+                "probe", probe.Name,
+                "attempt", attempt,
+                "retryIn", backoff,
+                "error", err)
+            select {
+            case <-ctx.Done():
+                return fmt.Errorf("%s not ready for %s after %d attempts: %w",
+                    probe.Name, qualifier, attempt, errors.Join(err, ctx.Err()))
+            case <-c.after(backoff):
+            }
+
+            backoff *= 2
+            if policy.MaxBackoff > 0 && backoff > policy.MaxBackoff {
+                backoff = policy.MaxBackoff
+            }
+        }
+    }
+    return nil
+}
+
+// probesOf returns the probes declared by opts
+func probesOf(opts []RegisterOption) []Probe {
+    reg := &registration{}
+    for _, opt := range opts {
+        opt(reg)
+    }
+    return reg.waitFor
+}
@@ -0,0 +1,134 @@
+package container
+
+import (
+    "context"
+    "errors"
+    "net"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+    "time"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+// flakyProbe fails until it has been checked readyAfter times
+func flakyProbe(readyAfter int, calls *int) Probe {
+    return ProbeFunc("flaky", func(ctx context.Context) error {
+        *calls++
+        if *calls < readyAfter {
+            return errors.New("not yet")
+        }
+        return nil
+    })
+}
+
+func fastWaitPolicy() WaitPolicy {
+    return WaitPolicy{Timeout: time.Second, InitialBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond}
+}
+
+func TestContainer_WaitForWeak(t *testing.T) {
+    container := NewContainer()
+    container.SetWaitPolicy(fastWaitPolicy())
+
+    var calls, builds int
+    require.NoError(t, container.RegisterWeak("db", func() (interface{}, error) {
+        builds++
+        assert.Equal(t, 3, calls, "provider must run after the probe succeeds")
+        return "db", nil
+    }, WaitFor(flakyProbe(3, &calls))))
+
+    _, err := container.Resolve("db")
+    require.NoError(t, err)
+    assert.Equal(t, 1, builds)
+}
+
+func TestContainer_WaitForStart(t *testing.T) {
+    container := NewContainer()
+    container.SetWaitPolicy(fastWaitPolicy())
+
+    var calls int
+    require.NoError(t, container.Register("db", &testServiceImpl{}, WaitFor(flakyProbe(2, &calls))))
+    require.NoError(t, container.Start(context.Background()))
+    assert.Equal(t, 2, calls)
+}
+
+func TestContainer_WaitForTimeout(t *testing.T) {
+    container := NewContainer()
+    container.SetWaitPolicy(WaitPolicy{Timeout: 20 * time.Millisecond, InitialBackoff: time.Millisecond})
+
+    never := ProbeFunc("never", func(ctx context.Context) error { return errors.New("refused") })
+    require.NoError(t, container.Register("db", &testServiceImpl{}, WaitFor(never)))
+
+    err := container.Start(context.Background())
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "startup phase construct failed")
+    assert.Contains(t, err.Error(), "never not ready for db")
+    assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestContainer_WaitForCached(t *testing.T) {
+    container := NewContainer()
+    container.SetWaitPolicy(fastWaitPolicy())
+    cache, err := NewDiskCache(t.TempDir())
+    require.NoError(t, err)
+
+    var calls int
+    err = RegisterCached(container, cache, "table", "v1", func() (string, error) {
+        assert.Equal(t, 2, calls)
+        return "built", nil
+    }, WaitFor(flakyProbe(2, &calls)))
+    require.NoError(t, err)
+}
+
+func TestTCPAndHTTPProbes(t *testing.T) {
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if r.URL.Path == "/broken" {
+            w.WriteHeader(http.StatusServiceUnavailable)
+        }
+    }))
+    defer server.Close()
+    ctx := context.Background()
+
+    assert.NoError(t, HTTPProbe(server.URL).Check(ctx))
+    assert.ErrorContains(t, HTTPProbe(server.URL+"/broken").Check(ctx), "503")
+    assert.NoError(t, TCPProbe(server.Listener.Addr().String()).Check(ctx))
+
+    // A closed port refuses connections
+    listener, err := net.Listen("tcp", "127.0.0.1:0")
+    require.NoError(t, err)
+    address := listener.Addr().String()
+    listener.Close()
+    assert.Error(t, TCPProbe(address).Check(ctx))
+}
+
+func TestContainer_WaitForZeroBackoff(t *testing.T) {
+    container := NewContainer()
+    container.SetWaitPolicy(WaitPolicy{Timeout: 50 * time.Millisecond})
+
+    var calls int
+    require.NoError(t, container.RegisterWeak("db", func() (interface{}, error) {
+        return "db", nil
+    }, WaitFor(flakyProbe(1000000, &calls))))
+
+    _, err := container.Resolve("db")
+    assert.ErrorContains(t, err, "flaky not ready for db")
+    assert.Less(t, calls, 10, "probes must not be retried in a busy loop")
+}
+
+func TestContainer_WaitForProbesOncePerRegistration(t *testing.T) {
+    container := NewContainer()
+    container.SetWaitPolicy(fastWaitPolicy())
+
+    var calls int
+    require.NoError(t, container.Register("request", func() string {
+        return "request"
+    }, WithLifetime(Transient), WaitFor(flakyProbe(2, &calls))))
+
+    for i := 0; i < 3; i++ {
+        _, err := container.Resolve("request")
+        require.NoError(t, err)
+    }
+    assert.Equal(t, 2, calls)
+}
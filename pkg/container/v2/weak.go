@@ -0,0 +1,290 @@
+package container
+
+import (
+    "context"
+    "container/list"
+    "fmt"
+    "math"
+    "runtime"
+    "runtime/debug"
+    "runtime/metrics"
+    "sync"
+    "weak"
+)
+
+// DefaultWeakCapacity is the number of weak instances kept before eviction
+const DefaultWeakCapacity = 128
+
+// weakPressureRatio is the share of the memory limit the live heap may
+// reach before the weak cache sheds half of its instances
+const weakPressureRatio = 0.75
+
+// WeakProvider builds a weak service instance
+type WeakProvider func() (interface{}, error)
+
+// RegisterWeak registers a service with the Weak lifetime. The container
+// keeps at most a bounded number of weak instances in an LRU cache and
+// rebuilds an evicted instance with build the next time it is resolved.
+// Use it for large, reproducible services such as rendered assets or
+// compiled regular expression sets. Instances are evicted when the cache
+// is over capacity, see SetWeakCapacity, and under memory pressure: after
+// a garbage collection that leaves the live heap above three quarters of
+// the memory limit set by GOMEMLIMIT or debug.SetMemoryLimit, the least
+// recently used half is dropped. Evicted instances that implement
+// io.Closer are closed, so callers should resolve a weak service each
+// time they use it instead of keeping the instance. Concurrent resolutions after an
+// eviction may each call build; the last result is cached. build may
+// resolve other services; resolving the service being built, directly or
+// through other weak services, fails with a dependency cycle error.
+func (c *Container) RegisterWeak(qualifier string, build WeakProvider, opts ...RegisterOption) error {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+
+    c.log.Infow("Registering weak service", "qualifier", qualifier)
+
+    if build == nil {
+        c.log.Errorw("Cannot register nil weak provider", "qualifier", qualifier)
+        return fmt.Errorf("cannot register %w", &NilServiceError{Qualifier: qualifier, Source: "provider"})
+    }
+    reg := c.newRegistrationLocked(qualifier, opts)
+    reg.lifetime = Weak
+    if err := c.admitLocked(reg); err != nil {
+        return admitted(err)
+    }
+
+    c.weak[qualifier] = build
+    c.recordLocked(reg)
+    return nil
+}
+
+// SetWeakCapacity changes how many weak instances are kept, evicting the
+// least recently used ones if the cache is over the new capacity
+func (c *Container) SetWeakCapacity(capacity int) {
+    c.log.Infow("Setting weak cache capacity", "capacity", capacity)
+    c.evictWeak(c.weakLRU.resize(capacity))
+}
+
+// evictWeak closes evicted weak instances that implement io.Closer, unless
+// Close already closed them or another qualifier still holds them
+func (c *Container) evictWeak(evicted []lruEntry) {
+    for _, entry := range evicted {
+        c.log.Debugw("Evicted weak service", "qualifier", entry.key)
+
+        c.mu.RLock()
+        shared := c.closed[entry.key] || c.registeredLocked(entry.value)
+        c.mu.RUnlock()
+        if shared {
+            continue
+        }
+        if err := closeInstance(entry.value); err != nil {
+            c.log.Warnw("Failed to close evicted weak service",
+                "qualifier", entry.key,
+                "error", err)
+        }
+    }
+}
+
+// shedWeak evicts the least recently used half of the weak instances when
+// live heap bytes exceed weakPressureRatio of the memory limit
+func (c *Container) shedWeak(live, limit uint64) {
+    if float64(live) <= weakPressureRatio*float64(limit) {
+        return
+    }
+    evicted := c.weakLRU.shrink()
+    if len(evicted) == 0 {
+        return
+    }
+    c.log.Infow("Shedding weak services under memory pressure",
+        "live", live,
+        "limit", limit,
+        "evicted", len(evicted))
+    c.evictWeak(evicted)
+}
+
+// gcSentinel is garbage collected every cycle to run the memory pressure
+// check; the pointer keeps it out of the tiny allocator, whose blocks may
+// outlive a cycle
+type gcSentinel struct {
+    _ *byte
+}
+
+// watchMemoryPressure checks the weak cache of the container after every
+// garbage collection, until the container itself is collected
+func watchMemoryPressure(container weak.Pointer[Container]) {
+    runtime.AddCleanup(new(gcSentinel), func(container weak.Pointer[Container]) {
+        c := container.Value()
+        if c == nil {
+            return
+        }
+        if limit := debug.SetMemoryLimit(-1); limit != math.MaxInt64 && c.weakLRU.len() > 0 {
+            // Cleanups share one goroutine, so closing evicted instances runs elsewhere
+            go c.shedWeak(liveHeap(), uint64(limit))
+        }
+        watchMemoryPressure(container)
+    }, container)
+}
+
+// liveHeap returns the heap bytes marked live by the last garbage
+// collection
+func liveHeap() uint64 {
+    sample := []metrics.Sample{{Name: "/gc/heap/live:bytes"}}
+    metrics.Read(sample)
+    if sample[0].Value.Kind() != metrics.KindUint64 {
+        return 0
+    }
+    return sample[0].Value.Uint64()
+}
+
+// resolveWeak returns the cached weak instance or rebuilds it
+func (c *Container) resolveWeak(qualifier string, build WeakProvider) (interface{}, error) {
+    if service, ok := c.weakLRU.get(qualifier); ok {
+        return service, nil
+    }
+
+    // The provider may resolve other services, but not the one it builds
+    leave, err := c.enterResolution(qualifier)
+    if err != nil {
+        return nil, err
+    }
+    defer leave()
+
+    if err := c.waitForQualifier(c.currentContext(), qualifier); err != nil {
+        return nil, err
+    }
+
+    c.log.Debugw("Building weak service", "qualifier", qualifier)
+    var service interface{}
+    err = c.withServiceLabels(c.currentContext(), "build", qualifier, func(context.Context) error {
+        var buildErr error
+        service, buildErr = build()
+        return buildErr
+    })
+    if err != nil {
+        c.log.Errorw("Weak provider failed",
+            "qualifier", qualifier,
+            "error", err)
+        return nil, fmt.Errorf("failed to build weak service %s: %w", qualifier, err)
+    }
+    if service == nil {
+        return nil, fmt.Errorf("weak provider for %s returned nil", qualifier)
+    }
+    if err := c.checkTypedNil(qualifier, service, "weak provider result"); err != nil {
+        return nil, err
+    }
+    if service, err = c.decorateWeak(qualifier, service); err != nil {
+        return nil, err
+    }
+
+    c.evictWeak(c.weakLRU.put(qualifier, service))
+    return service, nil
+}
+
+// lruCache is a size-bounded map evicting least recently used entries
+type lruCache struct {
+    mu       sync.Mutex
+    capacity int
+    entries  map[string]*list.Element
+    recency  *list.List // Front is most recently used
+}
+
+type lruEntry struct {
+    key   string
+    value interface{}
+}
+
+func newLRUCache(capacity int) *lruCache {
+    return &lruCache{
+        capacity: capacity,
+        entries:  make(map[string]*list.Element),
+        recency:  list.New(),
+    }
+}
+
+// get returns a cached value and marks it as recently used
+func (l *lruCache) get(key string) (interface{}, bool) {
+    l.mu.Lock()
+    defer l.mu.Unlock()
+
+    element, ok := l.entries[key]
+    if !ok {
+        return nil, false
+    }
+    l.recency.MoveToFront(element)
+    return element.Value.(*lruEntry).value, true
+}
+
+// peek returns a cached value without changing its recency
+func (l *lruCache) peek(key string) (interface{}, bool) {
+    l.mu.Lock()
+    defer l.mu.Unlock()
+
+    element, ok := l.entries[key]
+    if !ok {
+        return nil, false
+    }
+    return element.Value.(*lruEntry).value, true
+}
+
+// remove drops a cached value
+func (l *lruCache) remove(key string) {
+    l.mu.Lock()
+    defer l.mu.Unlock()
+
+    if element, ok := l.entries[key]; ok {
+        l.recency.Remove(element)
+        delete(l.entries, key)
+    }
+}
+
+// put stores a value and returns the entries evicted to stay within
+// capacity
+func (l *lruCache) put(key string, value interface{}) []lruEntry {
+    l.mu.Lock()
+    defer l.mu.Unlock()
+
+    if element, ok := l.entries[key]; ok {
+        element.Value.(*lruEntry).value = value
+        l.recency.MoveToFront(element)
+        return nil
+    }
+    l.entries[key] = l.recency.PushFront(&lruEntry{key: key, value: value})
+    return l.evictLocked(l.capacity)
+}
+
+// resize changes the capacity and evicts entries over it
+func (l *lruCache) resize(capacity int) []lruEntry {
+    l.mu.Lock()
+    defer l.mu.Unlock()
+
+    l.capacity = capacity
+    return l.evictLocked(l.capacity)
+}
+
+// shrink evicts the least recently used half of the entries, rounding up,
+// without changing the capacity
+func (l *lruCache) shrink() []lruEntry {
+    l.mu.Lock()
+    defer l.mu.Unlock()
+
+    return l.evictLocked(l.recency.Len() / 2)
+}
+
+// len returns the number of cached entries
+func (l *lruCache) len() int {
+    l.mu.Lock()
+    defer l.mu.Unlock()
+    return l.recency.Len()
+}
+
+// evictLocked evicts least recently used entries until at most size are
+// left
+func (l *lruCache) evictLocked(size int) []lruEntry {
+    var evicted []lruEntry
+    for l.recency.Len() > size && l.recency.Len() > 0 {
+        oldest := l.recency.Back()
+        entry := l.recency.Remove(oldest).(*lruEntry)
+        delete(l.entries, entry.key)
+        evicted = append(evicted, *entry)
+    }
+    return evicted
+}
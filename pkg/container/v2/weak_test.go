@@ -0,0 +1,128 @@
+package container
+
+import (
+    "errors"
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+func TestLRUCache(t *testing.T) {
+    cache := newLRUCache(2)
+    assert.Empty(t, cache.put("a", 1))
+    assert.Empty(t, cache.put("b", 2))
+
+    // Touching "a" makes "b" the eviction candidate
+    _, ok := cache.get("a")
+    assert.True(t, ok)
+    assert.Equal(t, []lruEntry{{key: "b", value: 2}}, cache.put("c", 3))
+
+    _, ok = cache.get("b")
+    assert.False(t, ok)
+    assert.Equal(t, []lruEntry{{key: "a", value: 1}}, cache.resize(1))
+    assert.Equal(t, 1, cache.len())
+}
+
+func TestLRUCache_Shrink(t *testing.T) {
+    cache := newLRUCache(10)
+    for i, key := range []string{"a", "b", "c"} {
+        cache.put(key, i)
+    }
+
+    assert.Equal(t, []lruEntry{{key: "a", value: 0}, {key: "b", value: 1}}, cache.shrink())
+    assert.Equal(t, []lruEntry{{key: "c", value: 2}}, cache.shrink())
+    assert.Empty(t, cache.shrink())
+}
+
+func TestContainer_RegisterWeak(t *testing.T) {
+    container := NewContainer()
+    container.SetWeakCapacity(1)
+
+    builds := map[string]int{}
+    provider := func(name string) WeakProvider {
+        return func() (interface{}, error) {
+            builds[name]++
+            return &testServiceImpl{name: name}, nil
+        }
+    }
+    require.NoError(t, container.RegisterWeak("assets", provider("assets")))
+    require.NoError(t, container.RegisterWeak("regexes", provider("regexes")))
+
+    first, err := container.Resolve("assets")
+    require.NoError(t, err)
+    again, err := container.Resolve("assets")
+    require.NoError(t, err)
+    assert.Same(t, first, again)
+    assert.Equal(t, 1, builds["assets"])
+
+    // Resolving another weak service evicts "assets", which is rebuilt on demand
+    _, err = container.Resolve("regexes")
+    require.NoError(t, err)
+    rebuilt, err := container.Resolve("assets")
+    require.NoError(t, err)
+    assert.NotSame(t, first, rebuilt)
+    assert.Equal(t, 2, builds["assets"])
+}
+
+func TestContainer_WeakEvictionCloses(t *testing.T) {
+    container := NewContainer()
+    container.SetWeakCapacity(2)
+
+    var log []string
+    shared := &closeRecorder{name: "shared", log: &log}
+    require.NoError(t, container.Register("singleton", shared))
+    for _, name := range []string{"first", "second"} {
+        name := name
+        require.NoError(t, container.RegisterWeak(name, func() (interface{}, error) {
+            return &closeRecorder{name: name, log: &log}, nil
+        }))
+    }
+    require.NoError(t, container.RegisterWeak("alias", func() (interface{}, error) { return shared, nil }))
+
+    for _, qualifier := range []string{"alias", "first", "second"} {
+        _, err := container.Resolve(qualifier)
+        require.NoError(t, err)
+    }
+    // "alias" was evicted but its instance is still the "singleton" service
+    assert.Empty(t, log)
+
+    container.SetWeakCapacity(1)
+    assert.Equal(t, []string{"first"}, log)
+}
+
+func TestContainer_WeakShedsUnderMemoryPressure(t *testing.T) {
+    container := NewContainer()
+
+    var log []string
+    for _, name := range []string{"a", "b", "c", "d"} {
+        name := name
+        require.NoError(t, container.RegisterWeak(name, func() (interface{}, error) {
+            return &closeRecorder{name: name, log: &log}, nil
+        }))
+        _, err := container.Resolve(name)
+        require.NoError(t, err)
+    }
+
+    container.shedWeak(700, 1000) // Below three quarters of the limit
+    assert.Equal(t, 4, container.weakLRU.len())
+
+    container.shedWeak(800, 1000)
+    assert.Equal(t, 2, container.weakLRU.len())
+    assert.Equal(t, []string{"a", "b"}, log)
+}
+
+func TestContainer_RegisterWeakErrors(t *testing.T) {
+    container := NewContainer()
+    require.NoError(t, container.Register("taken", &testServiceImpl{}))
+
+    assert.Error(t, container.RegisterWeak("taken", func() (interface{}, error) { return 1, nil }))
+    assert.Error(t, container.RegisterWeak("nilProvider", nil))
+    assert.NoError(t, container.Register("nilProvider", &testServiceImpl{}))
+
+    require.NoError(t, container.RegisterWeak("failing", func() (interface{}, error) {
+        return nil, errors.New("out of memory")
+    }))
+    _, err := container.Resolve("failing")
+    assert.ErrorContains(t, err, "out of memory")
+}
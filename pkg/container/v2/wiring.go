@@ -0,0 +1,237 @@
+package container
+
+import (
+    "errors"
+    "fmt"
+    "reflect"
+    "strings"
+)
+
+// wiringCheck collects the wiring problems found by checkWiring
+type wiringCheck struct {
+    c       *Container
+    errs    []error
+    visited map[reflect.Type]bool // Struct types already checked
+}
+
+// checkWiring checks the parameters of every provider, the ordering of
+// every group and the di tags of targets against the registrations,
+// building nothing. Types are known for instances, built services and
+// providers; a factory that was not built yet satisfies lookups by
+// qualifier and leaves matches by type undecided.
+func (c *Container) checkWiring(targets []interface{}) error {
+    check := &wiringCheck{c: c, visited: make(map[reflect.Type]bool)}
+
+    c.mu.RLock()
+    providers := make(map[string]*provider, len(c.providers))
+    for qualifier, p := range c.providers {
+        providers[qualifier] = p
+    }
+    c.mu.RUnlock()
+    for _, qualifier := range c.snapshotOrder() {
+        p, ok := providers[qualifier]
+        if !ok {
+            continue
+        }
+        ctorType := p.ctor.Type()
+        for i := 0; i < ctorType.NumIn(); i++ {
+            check.param(fmt.Sprintf("parameter %d of provider %s", i, qualifier), ctorType.In(i))
+        }
+    }
+
+    for _, group := range c.groupNames() {
+        if _, err := c.orderedGroupMembers(group); err != nil {
+            check.errs = append(check.errs, err)
+        }
+    }
+
+    for _, target := range targets {
+        structType, ok := target.(reflect.Type)
+        if !ok {
+            structType = reflect.TypeOf(target)
+        }
+        for structType != nil && structType.Kind() == reflect.Ptr {
+            structType = structType.Elem()
+        }
+        if structType == nil || structType.Kind() != reflect.Struct {
+            check.errs = append(check.errs, fmt.Errorf("validation target %v is not a struct", structType))
+            continue
+        }
+        check.structType(structType, "")
+    }
+
+    if len(check.errs) == 0 {
+        return nil
+    }
+    c.log.Errorw("Wiring validation failed", "problems", len(check.errs))
+    return fmt.Errorf("found %d wiring problems: %w", len(check.errs), errors.Join(check.errs...))
+}
+
+// param checks a parameter of a provider or an injection method, resolved
+// like the parameters of Invoke
+func (w *wiringCheck) param(site string, paramType reflect.Type) {
+    switch {
+    case paramType == containerPtrType:
+    case isTaggedStruct(paramType):
+        w.structType(paramType, "")
+    default:
+        qualifier, undecided, err := w.c.staticMatch(paramType)
+        if err != nil {
+            w.errs = append(w.errs, fmt.Errorf("%s: %w", site, err))
+        } else if qualifier == "" && !undecided {
+            w.errs = append(w.errs, fmt.Errorf("%s: %w", site, &ServiceNotFoundError{Type: paramType}))
+        }
+    }
+}
+
+// structType checks the tagged fields, nested structs and injection methods
+// of structType, whose fields are named path plus their own name
+func (w *wiringCheck) structType(structType reflect.Type, path string) {
+    if w.visited[structType] {
+        return
+    }
+    w.visited[structType] = true
+
+    c := w.c
+    plan := c.buildPlan(structType)
+    if plan.markerErr != nil {
+        w.errs = append(w.errs, plan.markerErr)
+        return
+    }
+    for _, fp := range plan.fields {
+        field := fp.field
+        name := path + field.Name
+        if fp.nested {
+            nestedType := field.Type
+            if nestedType.Kind() == reflect.Ptr {
+                nestedType = nestedType.Elem()
+            }
+            if nestedType.Kind() == reflect.Struct {
+                w.structType(nestedType, name+".")
+            }
+            continue
+        }
+        if fp.isMap || fp.spec.qualifier == OptionsTag {
+            continue
+        }
+        if _, guarded := fp.spec.options["ifPresent"]; guarded {
+            continue
+        }
+        if c.strict && fp.optionErr != nil {
+            w.errs = append(w.errs, fmt.Errorf("field %s of %v: %w", name, structType, fp.optionErr))
+            continue
+        }
+        if !field.IsExported() && !c.unexported {
+            if c.strict {
+                w.errs = append(w.errs, fmt.Errorf("field %s of %v is unexported and cannot be injected", name, structType))
+            }
+            continue
+        }
+        w.field(structType, field, name, fp, plan.defaults)
+    }
+
+    w.errs = append(w.errs, plan.methodErrs...)
+    for _, method := range plan.methods {
+        for i := 1; i < method.Type.NumIn(); i++ {
+            w.param(fmt.Sprintf("method %s of %v: parameter %d", method.Name, structType, i-1), method.Type.In(i))
+        }
+    }
+}
+
+// field checks a tagged field: that its service is registered, or matched
+// by type for di:"", and assignable to it when its type is known
+func (w *wiringCheck) field(structType reflect.Type, field reflect.StructField, name string, fp fieldPlan, defaults structDefaults) {
+    c := w.c
+    want := field.Type
+    _, isOptional := reflect.New(field.Type).Interface().(optionalField)
+    if isOptional || isHotType(field.Type) {
+        want = wiredType(field.Type)
+    }
+    optional := isOptional || fp.spec.isOptional(defaults)
+
+    if fp.spec.qualifier == "" {
+        matched, undecided, err := c.staticMatch(wiredType(field.Type))
+        if err != nil {
+            w.errs = append(w.errs, fmt.Errorf("field %s of %v: %w", name, structType, err))
+            return
+        }
+        if matched == "" && !undecided && !optional {
+            w.errs = append(w.errs, fmt.Errorf("field %s of %v: %w", name, structType, &ServiceNotFoundError{Type: wiredType(field.Type)}))
+        }
+        return // Matches are assignable
+    }
+
+    qualifier := c.renamed(fp.requested, func() string {
+        return fmt.Sprintf("field %s of %v", name, structType)
+    })
+    serviceType, registered := c.staticType(qualifier)
+    if !registered {
+        if !optional {
+            w.errs = append(w.errs, fmt.Errorf("field %s of %v: %w", name, structType, &ServiceNotFoundError{Qualifier: qualifier, Suggestions: c.suggest(qualifier)}))
+        }
+        return
+    }
+    if serviceType != nil && !serviceType.AssignableTo(want) {
+        w.errs = append(w.errs, fmt.Errorf("field %s of %v: %w", name, structType, &TypeMismatchError{
+            Qualifier: qualifier,
+            Type:      serviceType,
+            Want:      want,
+            Detail:    mismatchDetail(serviceType, want),
+        }))
+    }
+}
+
+// staticType returns the type of the service registered as qualifier when
+// it is known without building anything, and whether qualifier is
+// registered at all
+func (c *Container) staticType(qualifier string) (reflect.Type, bool) {
+    c.mu.RLock()
+    defer c.mu.RUnlock()
+
+    reg, ok := c.regs[qualifier]
+    if !ok {
+        return nil, false
+    }
+    if service, ok := c.services[qualifier]; ok {
+        return reflect.TypeOf(service), true
+    }
+    if p, ok := c.providers[qualifier]; ok {
+        return p.out, true
+    }
+    if service, ok := c.weakLRU.peek(qualifier); ok {
+        return reflect.TypeOf(service), true
+    }
+    return reg.declared, true
+}
+
+// staticMatch is qualifierForType for checks made outside any build: it
+// does not skip the services being built, and when nothing matches but a
+// service of unknown type might, undecided is true.
+func (c *Container) staticMatch(t reflect.Type) (qualifier string, undecided bool, err error) {
+    if qualifier, ok := c.boundQualifier(t); ok {
+        if _, registered := c.staticType(qualifier); !registered {
+            return "", false, fmt.Errorf("%v is bound to %s, which is not registered", t, qualifier)
+        }
+        return qualifier, false, nil
+    }
+
+    var candidates []string
+    for _, qualifier := range c.snapshotOrder() {
+        if c.lifetimeOf(qualifier) == Scoped {
+            continue
+        }
+        serviceType, _ := c.staticType(qualifier)
+        if serviceType == nil {
+            undecided = true
+        } else if serviceType.AssignableTo(t) {
+            candidates = append(candidates, qualifier)
+        }
+    }
+    if len(candidates) > 1 {
+        return "", false, fmt.Errorf("ambiguous type %v: candidates %s; use a qualifier", t, strings.Join(candidates, ", "))
+    }
+    if len(candidates) == 0 {
+        return "", undecided, nil
+    }
+    return candidates[0], false, nil
+}
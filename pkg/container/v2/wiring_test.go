@@ -0,0 +1,81 @@
+package container
+
+import (
+    "errors"
+    "reflect"
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+type wiredRepo struct {
+    Logger TestService `di:"logger"`
+    Cache  TestService `di:"cache"`
+}
+
+type wiredServer struct {
+    DB      TestService           `di:"db"`
+    Name    *testServiceImpl      `di:"name"`
+    Any     TestService           `di:""`
+    Metrics Optional[TestService] `di:"metrics"`
+    Audit   TestService           `di:"audit,optional"`
+    Repo    *wiredRepo            `di:"inject"`
+}
+
+func (s *wiredServer) InjectStore(store *providedStore) { _ = store }
+
+func TestValidate_ReportsWiringProblems(t *testing.T) {
+    c := NewContainer()
+    require.NoError(t, c.Register("db", &testServiceImpl{}))
+    require.NoError(t, c.Register("logger", &testServiceImpl{}))
+    require.NoError(t, c.Register("name", "not a service"))
+    require.NoError(t, c.Provide("repo", func(store *providedStore) *providedRepo {
+        return &providedRepo{store: store}
+    }))
+
+    err := c.Validate(&wiredServer{})
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "found 5 wiring problems")
+    assert.Contains(t, err.Error(), "parameter 0 of provider repo: no service assignable to *container.providedStore")
+    assert.Contains(t, err.Error(), "field Name of container.wiredServer: service name has type string, which is not assignable to *container.testServiceImpl")
+    assert.Contains(t, err.Error(), "field Any of container.wiredServer: ambiguous type container.TestService: candidates db, logger")
+    assert.Contains(t, err.Error(), "field Repo.Cache of container.wiredRepo: no service found for qualifier: cache")
+    assert.Contains(t, err.Error(), "method InjectStore of container.wiredServer: parameter 0: no service assignable to *container.providedStore")
+    assert.NotContains(t, err.Error(), "Metrics")
+    assert.NotContains(t, err.Error(), "Audit")
+
+    var mismatch *TypeMismatchError
+    assert.True(t, errors.As(err, &mismatch))
+    assert.True(t, errors.Is(err, ErrServiceNotFound))
+}
+
+func TestValidate_BuildsNothing(t *testing.T) {
+    c := NewContainer()
+    built := 0
+    require.NoError(t, c.RegisterFactory("db", func(*Container) (interface{}, error) {
+        built++
+        return &testServiceImpl{}, nil
+    }))
+    require.NoError(t, c.Provide("store", func() *providedStore {
+        built++
+        return &providedStore{}
+    }))
+    require.NoError(t, c.Provide("repo", func(store *providedStore) *providedRepo {
+        built++
+        return &providedRepo{store: store}
+    }))
+
+    // The factory's type is unknown until built, so di:"" is not reported
+    var target struct {
+        DB    TestService    `di:"db"`
+        Any   TestService    `di:""`
+        Store *providedStore `di:"store"`
+    }
+    require.NoError(t, c.Validate(reflect.TypeOf(target)))
+    assert.Zero(t, built)
+}
+
+func TestValidate_RejectsNonStructTargets(t *testing.T) {
+    assert.ErrorContains(t, NewContainer().Validate(42), "validation target int is not a struct")
+}
@@ -0,0 +1,157 @@
+package container
+
+import (
+    "context"
+    "fmt"
+    "time"
+)
+
+// worker is a goroutine started with Go
+type worker struct {
+    name    string
+    module  string
+    started time.Time
+}
+
+// WorkerInfo describes a running worker
+type WorkerInfo struct {
+    Name    string    `json:"name"`
+    Module  string    `json:"module,omitempty"`
+    Started time.Time `json:"started"`
+}
+
+// Go runs fn in a goroutine owned by the container. The goroutine is
+// attributed to the module being installed, or to the module whose
+// lifecycle hook is running, so ModuleUsage can report it. Its context is
+// cancelled by Stop, which waits for the goroutine to return. A returned
+// error is logged.
+func (c *Container) Go(name string, fn func(ctx context.Context) error) {
+    c.accountingMu.Lock()
+    if c.workerCtx == nil {
+        c.workerCtx, c.cancelWorkers = context.WithCancel(context.Background())
+    }
+    ctx := c.workerCtx
+    c.workerSeq++
+    id := c.workerSeq
+    w := &worker{name: name, module: c.ownerModuleLocked(), started: c.clock.Now()}
+    c.workers[id] = w
+    c.workerWG.Add(1)
+    c.publishWorkerGaugeLocked(w.module)
+    c.accountingMu.Unlock()
+
+    c.log.Debugw("Starting worker",
+        "worker", name,
+        "module", w.module)
+    go func() {
+        defer c.workerWG.Done()
+        defer func() {
+            c.accountingMu.Lock()
+            defer c.accountingMu.Unlock()
+            delete(c.workers, id)
+            c.publishWorkerGaugeLocked(w.module)
+        }()
+
+        err := c.withServiceLabels(ctx, "worker", name, fn)
+        if err != nil && ctx.Err() == nil {
+            c.log.Errorw("Worker failed",
+                "worker", name,
+                "module", w.module,
+                "error", err)
+            return
+        }
+        c.log.Debugw("Worker finished", "worker", name)
+    }()
+}
+
+// Workers returns the running workers, oldest first
+func (c *Container) Workers() []WorkerInfo {
+    c.accountingMu.Lock()
+    defer c.accountingMu.Unlock()
+
+    infos := make([]WorkerInfo, 0, len(c.workers))
+    for id := uint64(1); id <= c.workerSeq; id++ {
+        if w, ok := c.workers[id]; ok {
+            infos = append(infos, WorkerInfo{Name: w.name, Module: w.module, Started: w.started})
+        }
+    }
+    return infos
+}
+
+// stopWorkers cancels the context of every worker and waits for them to
+// return, or for ctx to end. Workers started afterwards get a new context.
+func (c *Container) stopWorkers(ctx context.Context) error {
+    c.accountingMu.Lock()
+    cancel := c.cancelWorkers
+    c.workerCtx, c.cancelWorkers = nil, nil
+    running := len(c.workers)
+    c.accountingMu.Unlock()
+    if cancel == nil {
+        return nil
+    }
+
+    c.log.Debugw("Stopping workers", "workers", running)
+    cancel()
+    done := make(chan struct{})
+    go func() {
+        c.workerWG.Wait()
+        close(done)
+    }()
+    select {
+    case <-done:
+        return nil
+    case <-ctx.Done():
+        return fmt.Errorf("workers did not stop: %w", ctx.Err())
+    }
+}
+
+// ownerModuleLocked returns the module the calling goroutine works for: the
+// module it is installing, else the module whose hook it is running.
+// Callers must hold accountingMu.
+func (c *Container) ownerModuleLocked() string {
+    gid := goroutineID()
+    c.mu.RLock()
+    module, installing := c.installing[gid]
+    c.mu.RUnlock()
+    if installing {
+        return module
+    }
+    return c.hookModules[gid]
+}
+
+// asModule wraps a hook callback so workers it starts are attributed to
+// module
+func (c *Container) asModule(module string, fn func(ctx context.Context) error) func(ctx context.Context) error {
+    if module == "" {
+        return fn
+    }
+    return func(ctx context.Context) error {
+        gid := goroutineID()
+        c.accountingMu.Lock()
+        previous, nested := c.hookModules[gid]
+        c.hookModules[gid] = module
+        c.accountingMu.Unlock()
+
+        defer func() {
+            c.accountingMu.Lock()
+            defer c.accountingMu.Unlock()
+            if nested {
+                c.hookModules[gid] = previous
+            } else {
+                delete(c.hookModules, gid)
+            }
+        }()
+        return fn(ctx)
+    }
+}
+
+// publishWorkerGaugeLocked reports the running workers of module. Callers
+// must hold accountingMu.
+func (c *Container) publishWorkerGaugeLocked(module string) {
+    count := 0
+    for _, w := range c.workers {
+        if w.module == module {
+            count++
+        }
+    }
+    c.metricsSink().SetGauge("di_module_workers", float64(count), map[string]string{"module": module})
+}
@@ -0,0 +1,79 @@
+package container
+
+import (
+    "context"
+    "errors"
+    "testing"
+    "time"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+func TestGo_StopCancelsAndWaitsForWorkers(t *testing.T) {
+    c := NewContainer()
+    require.NoError(t, c.Start(context.Background()))
+
+    stopped := make(chan struct{})
+    c.Go("poller", func(ctx context.Context) error {
+        <-ctx.Done()
+        close(stopped)
+        return ctx.Err()
+    })
+    require.Len(t, c.Workers(), 1)
+    assert.Equal(t, "poller", c.Workers()[0].Name)
+
+    require.NoError(t, c.Stop(context.Background()))
+    select {
+    case <-stopped:
+    default:
+        t.Fatal("Stop returned before the worker")
+    }
+    assert.Empty(t, c.Workers())
+}
+
+func TestGo_FailedWorkerIsRemoved(t *testing.T) {
+    c := NewContainer()
+    done := make(chan struct{})
+    c.Go("broken", func(ctx context.Context) error {
+        defer close(done)
+        return errors.New("boom")
+    })
+    <-done
+
+    assert.Eventually(t, func() bool { return len(c.Workers()) == 0 }, time.Second, time.Millisecond)
+}
+
+func TestGo_StopGivesUpWhenContextEnds(t *testing.T) {
+    c := NewContainer()
+    release := make(chan struct{})
+    defer close(release)
+    c.Go("stubborn", func(ctx context.Context) error {
+        <-release
+        return nil
+    })
+
+    ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+    defer cancel()
+    assert.ErrorContains(t, c.Stop(ctx), "workers did not stop")
+}
+
+func TestGo_WorkersStartedByModuleHooksBelongToTheModule(t *testing.T) {
+    c := NewContainer()
+    require.NoError(t, c.Install(Module{Name: "consumers", Setup: func(c *Container) error {
+        c.Append(Hook{Name: "consume", OnStart: func(ctx context.Context) error {
+            c.Go("consume", func(ctx context.Context) error {
+                <-ctx.Done()
+                return nil
+            })
+            return nil
+        }})
+        return nil
+    }}))
+
+    require.NoError(t, c.Start(context.Background()))
+    workers := c.Workers()
+    require.Len(t, workers, 1)
+    assert.Equal(t, "consumers", workers[0].Module)
+    require.NoError(t, c.Stop(context.Background()))
+}
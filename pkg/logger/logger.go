@@ -1,37 +1,203 @@
 package logger
 
 import (
+    "os"
+    "sync"
+    "sync/atomic"
+
     "go.uber.org/zap"
     "go.uber.org/zap/zapcore"
 )
 
-var sugar *zap.SugaredLogger
+var (
+    // shared is handed out by Get. Its core forwards to the current
+    // configuration, so Initialize and Replace reach loggers handed out
+    // before them.
+    shared = zap.New(&proxyCore{},
+        zap.AddCaller(),
+        zap.AddStacktrace(stacktraceLevel{}),
+        zap.WithFatalHook(fatalHook{}), // Route Fatal logs through Exit so registered cleanup still runs
+    ).Sugar()
+
+    current     atomic.Pointer[backend]
+    defaultOnce sync.Once
+)
+
+var (
+    hooksMu   sync.Mutex
+    exitHooks []func()
+)
+
+// backend is the configuration loggers write through
+type backend struct {
+    core       zapcore.Core
+    stacktrace zapcore.LevelEnabler // Levels that record a stack trace
+    explicit   bool                 // Set by Initialize or Replace rather than by default
+}
 
-// Initialize sets up our logger
+// Initialize sets up our logger. Loggers returned by Get before the call
+// switch to the new configuration too.
 func Initialize(debug bool) {
+    install(newBackend(debug, true))
+}
+
+// newBackend builds the development or production configuration
+func newBackend(debug, explicit bool) *backend {
     var cfg zap.Config
+    stacktrace := zapcore.ErrorLevel
     if debug {
         cfg = zap.NewDevelopmentConfig()
         cfg.EncoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+        stacktrace = zapcore.WarnLevel
     } else {
         cfg = zap.NewProductionConfig()
     }
 
-    baseLogger, _ := cfg.Build()
-    sugar = baseLogger.Sugar()
+    baseLogger, err := cfg.Build()
+    if err != nil {
+        baseLogger = zap.NewExample() // Never leave loggers without a core
+    }
+    return &backend{core: baseLogger.Core(), stacktrace: stacktrace, explicit: explicit}
 }
 
-// Get returns the sugared logger
-func Get() *zap.SugaredLogger {
-    if sugar == nil {
-        Initialize(true) // Default to debug mode if not initialized
+// Replace makes every logger returned by Get, before or after the call,
+// write through log, such as a logger built from a custom zap.Config or an
+// observer in tests. Only the core of log is used: caller annotation and
+// the Fatal hook stay those of this package. Replace(nil) discards logs.
+func Replace(log *zap.Logger) {
+    if log == nil {
+        log = zap.NewNop()
     }
-    return sugar
+    install(&backend{core: log.Core(), stacktrace: zapcore.ErrorLevel, explicit: true})
+}
+
+// Initialized reports whether Initialize or Replace has been called. Until
+// then loggers write through a development configuration.
+func Initialized() bool {
+    b := current.Load()
+    return b != nil && b.explicit
+}
+
+// Get returns the sugared logger. It is safe to call before Initialize:
+// the logger starts with a development configuration, warns once that
+// Initialize was not called and follows Initialize and Replace later on.
+func Get() *zap.SugaredLogger {
+    load()
+    return shared
 }
 
 // Sync flushes any buffered log entries
 func Sync() {
-    if sugar != nil {
-        sugar.Sync()
+    if current.Load() != nil {
+        shared.Sync()
+    }
+}
+
+// install makes b the configuration of all loggers
+func install(b *backend) {
+    defaultOnce.Do(func() {}) // A later Get must not install the default over b
+    current.Store(b)
+}
+
+// load returns the current configuration, installing the default one on
+// first use
+func load() *backend {
+    if b := current.Load(); b != nil {
+        return b
+    }
+    defaultOnce.Do(func() {
+        current.Store(newBackend(true, false))
+        shared.Warnw("Logger used before Initialize, using the development configuration")
+    })
+    return current.Load()
+}
+
+// proxyCore forwards to the core of the current backend. Fields added with
+// With are kept so derived loggers survive a Replace; the derived core is
+// cached per backend.
+type proxyCore struct {
+    fields  []zapcore.Field
+    derived atomic.Pointer[derivedCore]
+}
+
+// derivedCore is the core of a backend with the fields of a proxyCore
+type derivedCore struct {
+    backend *backend
+    core    zapcore.Core
+}
+
+func (p *proxyCore) core() zapcore.Core {
+    b := load()
+    if len(p.fields) == 0 {
+        return b.core
+    }
+    if d := p.derived.Load(); d != nil && d.backend == b {
+        return d.core
     }
-}
\ No newline at end of file
+    d := &derivedCore{backend: b, core: b.core.With(p.fields)}
+    p.derived.Store(d)
+    return d.core
+}
+
+func (p *proxyCore) Enabled(level zapcore.Level) bool {
+    return p.core().Enabled(level)
+}
+
+func (p *proxyCore) With(fields []zapcore.Field) zapcore.Core {
+    all := make([]zapcore.Field, 0, len(p.fields)+len(fields))
+    return &proxyCore{fields: append(append(all, p.fields...), fields...)}
+}
+
+func (p *proxyCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+    return p.core().Check(entry, checked)
+}
+
+func (p *proxyCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+    return p.core().Write(entry, fields)
+}
+
+func (p *proxyCore) Sync() error {
+    return p.core().Sync()
+}
+
+// stacktraceLevel enables stack traces at the level of the current backend
+type stacktraceLevel struct{}
+
+func (stacktraceLevel) Enabled(level zapcore.Level) bool {
+    return load().stacktrace.Enabled(level)
+}
+
+// OnExit registers a function that runs before the process exits through
+// Exit or a Fatal log. Hooks run in reverse registration order.
+func OnExit(fn func()) {
+    hooksMu.Lock()
+    defer hooksMu.Unlock()
+    exitHooks = append(exitHooks, fn)
+}
+
+// RunExitHooks runs and clears all registered exit hooks
+func RunExitHooks() {
+    hooksMu.Lock()
+    hooks := exitHooks
+    exitHooks = nil
+    hooksMu.Unlock()
+
+    for i := len(hooks) - 1; i >= 0; i-- {
+        hooks[i]()
+    }
+}
+
+// Exit runs the registered exit hooks, flushes the logger and terminates
+// the process with the given status code
+func Exit(code int) {
+    RunExitHooks()
+    Sync()
+    os.Exit(code)
+}
+
+// fatalHook replaces zap's default os.Exit on Fatal so exit hooks run first
+type fatalHook struct{}
+
+func (fatalHook) OnWrite(*zapcore.CheckedEntry, []zapcore.Field) {
+    Exit(1)
+}
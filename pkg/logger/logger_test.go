@@ -0,0 +1,54 @@
+package logger
+
+import (
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+    "go.uber.org/zap"
+    "go.uber.org/zap/zapcore"
+    "go.uber.org/zap/zaptest/observer"
+)
+
+func TestReplace_ReachesHandedOutLoggers(t *testing.T) {
+    early := Get()
+    derived := early.With("component", "container").Named("di")
+
+    core, logs := observer.New(zapcore.DebugLevel)
+    Replace(zap.New(core))
+    t.Cleanup(func() { Initialize(false) })
+    assert.True(t, Initialized())
+
+    early.Infow("Registered service", "qualifier", "db")
+    derived.Debugw("Resolved service")
+
+    entries := logs.AllUntimed()
+    require.Len(t, entries, 2)
+    assert.Equal(t, "Registered service", entries[0].Message)
+    assert.Equal(t, map[string]interface{}{"qualifier": "db"}, entries[0].ContextMap())
+    assert.Equal(t, "di", entries[1].LoggerName)
+    assert.Equal(t, map[string]interface{}{"component": "container"}, entries[1].ContextMap())
+    assert.True(t, entries[0].Caller.Defined)
+
+    // A later Replace reaches the derived logger too
+    core, logs = observer.New(zapcore.WarnLevel)
+    Replace(zap.New(core))
+    derived.Debugw("Resolved service")
+    derived.Warnw("Slow constructor")
+    require.Equal(t, 1, logs.Len())
+    assert.Equal(t, map[string]interface{}{"component": "container"}, logs.All()[0].ContextMap())
+}
+
+func TestReplace_Nil(t *testing.T) {
+    Replace(nil)
+    t.Cleanup(func() { Initialize(false) })
+    assert.False(t, Get().Desugar().Core().Enabled(zapcore.ErrorLevel))
+}
+
+func TestInitialize_SwitchesLevel(t *testing.T) {
+    log := Get()
+    Initialize(false)
+    assert.False(t, log.Desugar().Core().Enabled(zapcore.DebugLevel))
+    Initialize(true)
+    assert.True(t, log.Desugar().Core().Enabled(zapcore.DebugLevel))
+}
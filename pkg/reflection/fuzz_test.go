@@ -0,0 +1,28 @@
+package reflection
+
+import (
+    "testing"
+
+    "di-example/internal/structgen"
+    "github.com/stretchr/testify/require"
+)
+
+// FuzzInspectStruct inspects and prints randomly shaped structs; the seed
+// corpus in testdata/fuzz runs with the regular tests
+func FuzzInspectStruct(f *testing.F) {
+    f.Add([]byte{})
+    f.Add([]byte{3, 4, 0, 1, 2, 5, 'a', ':', '"', 'b', 0, 0, 1})
+    f.Add([]byte("\x08\x01\x01\x01\x01\x01\x01\x01\x01\x01\x01\x01\x01\x01\x01"))
+    f.Add([]byte("\x02\x03\x05\x03\x12json:\"a b\" di:\"x:y\"\x03\x06\x03\x08`:\"\x01\x01"))
+
+    inspector := NewInspector()
+    config := structgen.Config{TagKeys: []string{"di", "json", "custom", ""}}
+    f.Fuzz(func(t *testing.T, data []byte) {
+        target := structgen.Generate(data, config)
+
+        info, err := inspector.InspectStruct(target)
+        require.NoError(t, err)
+        inspector.PrettyPrint(info)
+        _ = info.String()
+    })
+}
@@ -2,7 +2,9 @@ package reflection
 
 import (
     "fmt"
+    "log/slog"
     "reflect"
+    "strconv"
     "strings"
 
     "di-example/pkg/logger"
@@ -14,12 +16,40 @@ type StructInfo struct {
     Fields []FieldInfo
 }
 
+// String summarizes the struct as its name and field count, leaving field
+// values out; use PrettyPrint for the full report
+func (s *StructInfo) String() string {
+    return fmt.Sprintf("%s (%d fields, %d tagged)", s.Name, len(s.Fields), s.taggedFields())
+}
+
+// LogValue implements slog.LogValuer with the same summary as String
+func (s *StructInfo) LogValue() slog.Value {
+    return slog.GroupValue(
+        slog.String("name", s.Name),
+        slog.Int("fields", len(s.Fields)),
+        slog.Int("tagged", s.taggedFields()),
+    )
+}
+
+// taggedFields counts fields with a di tag
+func (s *StructInfo) taggedFields() int {
+    count := 0
+    for _, field := range s.Fields {
+        if _, ok := field.Tags["di"]; ok {
+            count++
+        }
+    }
+    return count
+}
+
 type FieldInfo struct {
     Name       string
     Type       string
     Tags       map[string]string
     Value      interface{}
     IsExported bool
+    Nilable    bool // Pointer, interface, map, slice, channel or func field
+    IsNil      bool // Nilable field currently holding nil, even if unexported
 }
 
 type Inspector struct {
@@ -93,14 +123,7 @@ func (i *Inspector) InspectStruct(target interface{}) (*StructInfo, error) {
                 "fieldName", field.Name,
                 "rawTags", field.Tag)
 
-            for _, tag := range strings.Split(string(field.Tag), " ") {
-                parts := strings.Split(tag, ":")
-                if len(parts) == 2 {
-                    key := strings.Trim(parts[0], "`")
-                    value := strings.Trim(parts[1], `"`)
-                    tags[key] = value
-                }
-            }
+            tags = parseTags(field.Tag)
         }
 
         // Get field value if possible
@@ -121,12 +144,16 @@ func (i *Inspector) InspectStruct(target interface{}) (*StructInfo, error) {
             "fieldName", field.Name,
             "isExported", isExported)
 
+        nilable := isNilable(field.Type.Kind())
+
         fieldInfo := FieldInfo{
             Name:       field.Name,
             Type:       field.Type.String(),
             Tags:       tags,
             Value:      value,
             IsExported: isExported,
+            Nilable:    nilable,
+            IsNil:      nilable && fieldValue.IsNil(),
         }
 
         info.Fields = append(info.Fields, fieldInfo)
@@ -160,9 +187,111 @@ func (i *Inspector) PrettyPrint(info *StructInfo) string {
         }
 
         if field.IsExported && field.Value != nil {
-            builder.WriteString(fmt.Sprintf("    Value: %v\n", field.Value))
+            // fmt recurses into maps and slices without cycle detection
+            if printable(reflect.ValueOf(field.Value), make(map[uintptr]bool), 0) {
+                builder.WriteString(fmt.Sprintf("    Value: %v\n", field.Value))
+            } else {
+                builder.WriteString(fmt.Sprintf("    Value: <%T, cyclic or nested too deep>\n", field.Value))
+            }
         }
     }
 
     return builder.String()
-}
\ No newline at end of file
+}
+
+// maxPrintDepth bounds the nesting of values PrettyPrint formats
+const maxPrintDepth = 64
+
+// parseTags splits a struct tag into its key:"value" pairs, following the
+// conventions of reflect.StructTag: values are quoted Go strings and may
+// contain spaces and colons. Parsing stops at the first malformed pair.
+func parseTags(tag reflect.StructTag) map[string]string {
+    tags := make(map[string]string)
+    rest := string(tag)
+    for rest != "" {
+        rest = strings.TrimLeft(rest, " ")
+        if rest == "" {
+            break
+        }
+
+        // Key runs up to the colon; control characters, spaces and quotes are invalid
+        i := 0
+        for i < len(rest) && rest[i] > ' ' && rest[i] != ':' && rest[i] != '"' && rest[i] != 0x7f {
+            i++
+        }
+        if i == 0 || i+1 >= len(rest) || rest[i] != ':' || rest[i+1] != '"' {
+            break
+        }
+        key := rest[:i]
+        rest = rest[i+1:]
+
+        // Value is a quoted string, possibly with escaped quotes
+        i = 1
+        for i < len(rest) && rest[i] != '"' {
+            if rest[i] == '\\' {
+                i++
+            }
+            i++
+        }
+        if i >= len(rest) {
+            break
+        }
+        value, err := strconv.Unquote(rest[:i+1])
+        if err != nil {
+            break
+        }
+        tags[key] = value
+        rest = rest[i+1:]
+    }
+    return tags
+}
+
+// printable reports whether fmt can format v: it holds no reference cycle
+// through maps, slices or pointers and is nested at most maxPrintDepth
+// levels deep. path holds the references on the way to v.
+func printable(v reflect.Value, path map[uintptr]bool, depth int) bool {
+    if depth > maxPrintDepth {
+        return false
+    }
+
+    switch v.Kind() {
+    case reflect.Ptr, reflect.Map, reflect.Slice:
+        if v.IsNil() {
+            return true
+        }
+        if v.Kind() == reflect.Slice && v.Len() == 0 {
+            return true
+        }
+        ref := v.Pointer()
+        if path[ref] {
+            return false
+        }
+        path[ref] = true
+        defer delete(path, ref)
+    }
+
+    switch v.Kind() {
+    case reflect.Ptr, reflect.Interface:
+        return v.IsNil() || printable(v.Elem(), path, depth+1)
+    case reflect.Map:
+        iter := v.MapRange()
+        for iter.Next() {
+            if !printable(iter.Key(), path, depth+1) || !printable(iter.Value(), path, depth+1) {
+                return false
+            }
+        }
+    case reflect.Slice, reflect.Array:
+        for i := 0; i < v.Len(); i++ {
+            if !printable(v.Index(i), path, depth+1) {
+                return false
+            }
+        }
+    case reflect.Struct:
+        for i := 0; i < v.NumField(); i++ {
+            if !printable(v.Field(i), path, depth+1) {
+                return false
+            }
+        }
+    }
+    return true
+}
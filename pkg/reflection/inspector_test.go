@@ -1,6 +1,8 @@
 package reflection
 
 import (
+    "log/slog"
+    "strings"
     "testing"
     "github.com/stretchr/testify/assert"
     "github.com/stretchr/testify/require"
@@ -144,4 +146,58 @@ func TestFieldInfoHandling(t *testing.T) {
 
     // Check map field
     assert.Equal(t, "map[string]interface {}", info.Fields[2].Type)
-}
\ No newline at end of file
+}
+
+func TestStructInfo_String(t *testing.T) {
+    info := &StructInfo{
+        Name: "Handler",
+        Fields: []FieldInfo{
+            {Name: "Users", Tags: map[string]string{"di": "users"}, Value: strings.Repeat("x", 1000)},
+            {Name: "Count", Tags: map[string]string{}},
+        },
+    }
+    assert.Equal(t, "Handler (2 fields, 1 tagged)", info.String())
+
+    value := info.LogValue()
+    assert.Equal(t, slog.KindGroup, value.Kind())
+    assert.Len(t, value.Group(), 3)
+}
+
+func TestInspector_TagValuesWithSpacesAndColons(t *testing.T) {
+    inspector := NewInspector()
+
+    type Tagged struct {
+        Field string `di:"db,ifPresent=feature:db" json:"field name,omitempty"`
+    }
+
+    info, err := inspector.InspectStruct(Tagged{})
+    require.NoError(t, err)
+    assert.Equal(t, map[string]string{"di": "db,ifPresent=feature:db", "json": "field name,omitempty"}, info.Fields[0].Tags)
+    assert.Equal(t, map[string]string{"di": "ok"}, parseTags(`di:"ok" json:unquoted`), "parsing stops at the malformed pair")
+    assert.Empty(t, parseTags(`:"x" di:"unterminated`))
+}
+
+func TestInspector_PrettyPrintCyclicValues(t *testing.T) {
+    inspector := NewInspector()
+
+    type Cyclic struct {
+        Map   map[string]interface{}
+        Slice []interface{}
+        Plain map[string]interface{}
+    }
+    cyclic := Cyclic{
+        Map:   map[string]interface{}{},
+        Slice: make([]interface{}, 1),
+        Plain: map[string]interface{}{"key": "value"},
+    }
+    cyclic.Map["self"] = cyclic.Map
+    cyclic.Slice[0] = cyclic.Slice
+
+    info, err := inspector.InspectStruct(cyclic)
+    require.NoError(t, err)
+
+    output := inspector.PrettyPrint(info)
+    assert.Contains(t, output, "Value: <map[string]interface {}, cyclic or nested too deep>")
+    assert.Contains(t, output, "Value: <[]interface {}, cyclic or nested too deep>")
+    assert.Contains(t, output, "Value: map[key:value]")
+}
@@ -0,0 +1,118 @@
+package reflection
+
+import (
+    "context"
+    "fmt"
+    "reflect"
+    "time"
+
+    "di-example/pkg/logger"
+    "go.uber.org/zap"
+)
+
+// FieldNilMetric is the gauge exported by NilCollector: 1 while a field of
+// an inspected struct is nil, 0 otherwise
+const FieldNilMetric = "di_field_nil"
+
+// isNilable reports whether values of kind can be nil
+func isNilable(kind reflect.Kind) bool {
+    switch kind {
+    case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice, reflect.Chan, reflect.Func:
+        return true
+    }
+    return false
+}
+
+// GaugeSink receives gauge values. container.MetricsSink satisfies it.
+type GaugeSink interface {
+    SetGauge(name string, value float64, labels map[string]string)
+}
+
+// Resolver looks services up by qualifier. *container.Container satisfies it.
+type Resolver interface {
+    Resolve(qualifier string) (interface{}, error)
+}
+
+// NilCollector periodically inspects key structs registered in a container
+// and exports a di_field_nil{struct,field} gauge for every nilable field,
+// so dashboards can alert when a hot swap or lazy backfill leaves critical
+// fields unset
+type NilCollector struct {
+    inspector  *Inspector
+    resolver   Resolver
+    sink       GaugeSink
+    qualifiers []string
+    log        *zap.SugaredLogger
+}
+
+// NewNilCollector creates a collector inspecting the services registered
+// under qualifiers
+func NewNilCollector(resolver Resolver, sink GaugeSink, qualifiers ...string) *NilCollector {
+    return &NilCollector{
+        inspector:  NewInspector(),
+        resolver:   resolver,
+        sink:       sink,
+        qualifiers: qualifiers,
+        log:        logger.Get(),
+    }
+}
+
+// Collect inspects every struct once and updates the gauges. Structs that
+// cannot be resolved or inspected are skipped; the first such error is
+// returned after all others were collected.
+func (n *NilCollector) Collect() error {
+    var firstErr error
+    for _, qualifier := range n.qualifiers {
+        if err := n.collect(qualifier); err != nil {
+            n.log.Warnw("Failed to collect field nil-ness",
+                "qualifier", qualifier,
+                "error", err)
+            if firstErr == nil {
+                firstErr = err
+            }
+        }
+    }
+    return firstErr
+}
+
+// collect exports the gauges of one struct
+func (n *NilCollector) collect(qualifier string) error {
+    service, err := n.resolver.Resolve(qualifier)
+    if err != nil {
+        return fmt.Errorf("failed to resolve %s: %w", qualifier, err)
+    }
+    info, err := n.inspector.InspectStruct(service)
+    if err != nil {
+        return fmt.Errorf("failed to inspect %s: %w", qualifier, err)
+    }
+
+    for _, field := range info.Fields {
+        if !field.Nilable {
+            continue
+        }
+        value := 0.0
+        if field.IsNil {
+            value = 1
+        }
+        n.sink.SetGauge(FieldNilMetric, value, map[string]string{
+            "struct": info.Name,
+            "field":  field.Name,
+        })
+    }
+    return nil
+}
+
+// Run collects every interval until ctx is done
+func (n *NilCollector) Run(ctx context.Context, interval time.Duration) {
+    ticker := time.NewTicker(interval)
+    defer ticker.Stop()
+
+    for {
+        n.Collect() // Failures are logged by Collect
+        select {
+        case <-ctx.Done():
+            return
+        case <-ticker.C:
+        }
+    }
+}
@@ -0,0 +1,103 @@
+package reflection
+
+import (
+    "context"
+    "errors"
+    "sync"
+    "testing"
+    "time"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+type backfilled struct {
+    Cache   *TestStruct
+    Mailer  interface{}
+    Routes  map[string]string
+    Count   int
+    private *TestStruct
+}
+
+type gaugeRecorder struct {
+    mu     sync.Mutex
+    gauges map[string]float64 // "struct.field" -> value
+}
+
+func (g *gaugeRecorder) SetGauge(name string, value float64, labels map[string]string) {
+    g.mu.Lock()
+    defer g.mu.Unlock()
+    if name == FieldNilMetric {
+        g.gauges[labels["struct"]+"."+labels["field"]] = value
+    }
+}
+
+func (g *gaugeRecorder) snapshot() map[string]float64 {
+    g.mu.Lock()
+    defer g.mu.Unlock()
+    copied := make(map[string]float64, len(g.gauges))
+    for key, value := range g.gauges {
+        copied[key] = value
+    }
+    return copied
+}
+
+type mapResolver map[string]interface{}
+
+func (m mapResolver) Resolve(qualifier string) (interface{}, error) {
+    if service, ok := m[qualifier]; ok {
+        return service, nil
+    }
+    return nil, errors.New("not registered")
+}
+
+func TestInspectStruct_Nilness(t *testing.T) {
+    info, err := NewInspector().InspectStruct(&backfilled{Mailer: "smtp"})
+    require.NoError(t, err)
+
+    nilness := make(map[string]bool)
+    for _, field := range info.Fields {
+        if field.Nilable {
+            nilness[field.Name] = field.IsNil
+        }
+    }
+    assert.Equal(t, map[string]bool{
+        "Cache":   true,
+        "Mailer":  false,
+        "Routes":  true,
+        "private": true, // Nil-ness is known even without access to the value
+    }, nilness)
+}
+
+func TestNilCollector_Collect(t *testing.T) {
+    service := &backfilled{Cache: &TestStruct{}}
+    sink := &gaugeRecorder{gauges: make(map[string]float64)}
+    collector := NewNilCollector(mapResolver{"backfilled": service}, sink, "backfilled", "missing")
+
+    err := collector.Collect()
+    assert.ErrorContains(t, err, "failed to resolve missing")
+    assert.Equal(t, map[string]float64{
+        "backfilled.Cache":   0,
+        "backfilled.Mailer":  1,
+        "backfilled.Routes":  1,
+        "backfilled.private": 1,
+    }, sink.snapshot())
+}
+
+func TestNilCollector_Run(t *testing.T) {
+    sink := &gaugeRecorder{gauges: make(map[string]float64)}
+    collector := NewNilCollector(mapResolver{"backfilled": &backfilled{}}, sink, "backfilled")
+
+    ctx, cancel := context.WithCancel(context.Background())
+    done := make(chan struct{})
+    go func() {
+        defer close(done)
+        collector.Run(ctx, time.Millisecond)
+    }()
+
+    assert.Eventually(t, func() bool {
+        return sink.snapshot()["backfilled.Cache"] == 1
+    }, time.Second, time.Millisecond)
+    cancel()
+    <-done
+}
@@ -0,0 +1,113 @@
+package reflection
+
+import (
+    "fmt"
+    "sort"
+    "strings"
+)
+
+// QualifierUsage describes how often a di qualifier is referenced
+type QualifierUsage struct {
+    Qualifier  string
+    Structs    []string // Names of structs referencing the qualifier, sorted
+    References int      // Number of fields referencing the qualifier
+    Registered bool     // Whether the qualifier is registered in the container
+}
+
+// TagUsage aggregates di tag usage across many inspected structs
+type TagUsage struct {
+    Qualifiers   []QualifierUsage // Referenced qualifiers, sorted by name
+    Unused       []string         // Registered qualifiers never referenced
+    Unregistered []string         // Referenced qualifiers that are not registered
+}
+
+// AggregateTagUsage reports which di qualifiers the given structs reference,
+// from how many structs, and which registered qualifiers are never used
+func AggregateTagUsage(infos []*StructInfo, registered []string) *TagUsage {
+    isRegistered := make(map[string]bool, len(registered))
+    for _, qualifier := range registered {
+        isRegistered[qualifier] = true
+    }
+
+    usages := make(map[string]*QualifierUsage)
+    for _, info := range infos {
+        for _, field := range info.Fields {
+            qualifier, ok := diQualifier(field.Tags)
+            if !ok {
+                continue
+            }
+
+            usage, exists := usages[qualifier]
+            if !exists {
+                usage = &QualifierUsage{Qualifier: qualifier, Registered: isRegistered[qualifier]}
+                usages[qualifier] = usage
+            }
+            usage.References++
+            if !containsString(usage.Structs, info.Name) {
+                usage.Structs = append(usage.Structs, info.Name)
+            }
+        }
+    }
+
+    stats := &TagUsage{}
+    for _, usage := range usages {
+        sort.Strings(usage.Structs)
+        stats.Qualifiers = append(stats.Qualifiers, *usage)
+        if !usage.Registered {
+            stats.Unregistered = append(stats.Unregistered, usage.Qualifier)
+        }
+    }
+    for _, qualifier := range registered {
+        if _, used := usages[qualifier]; !used {
+            stats.Unused = append(stats.Unused, qualifier)
+        }
+    }
+
+    sort.Slice(stats.Qualifiers, func(a, b int) bool {
+        return stats.Qualifiers[a].Qualifier < stats.Qualifiers[b].Qualifier
+    })
+    sort.Strings(stats.Unused)
+    sort.Strings(stats.Unregistered)
+    return stats
+}
+
+// diQualifier extracts the qualifier of a di tag, ignoring options and
+// struct-level marker tags made only of key=value options
+func diQualifier(tags map[string]string) (string, bool) {
+    tag, ok := tags["di"]
+    if !ok {
+        return "", false
+    }
+    qualifier := strings.TrimSpace(strings.Split(tag, ",")[0])
+    if qualifier == "" || strings.Contains(qualifier, "=") {
+        return "", false
+    }
+    return qualifier, true
+}
+
+func containsString(values []string, target string) bool {
+    for _, value := range values {
+        if value == target {
+            return true
+        }
+    }
+    return false
+}
+
+// Report renders the statistics as a human readable summary
+func (u *TagUsage) Report() string {
+    var builder strings.Builder
+
+    builder.WriteString("Qualifier usage:\n")
+    for _, usage := range u.Qualifiers {
+        builder.WriteString(fmt.Sprintf("  - %s: %d references from %d structs (%s)\n",
+            usage.Qualifier, usage.References, len(usage.Structs), strings.Join(usage.Structs, ", ")))
+    }
+    if len(u.Unregistered) > 0 {
+        builder.WriteString(fmt.Sprintf("Unregistered: %s\n", strings.Join(u.Unregistered, ", ")))
+    }
+    if len(u.Unused) > 0 {
+        builder.WriteString(fmt.Sprintf("Unused registrations: %s\n", strings.Join(u.Unused, ", ")))
+    }
+    return builder.String()
+}
@@ -0,0 +1,49 @@
+package reflection
+
+import (
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+type UserHandler struct {
+    Users  interface{} `di:"userService"`
+    Emails interface{} `di:"emailService,optional"`
+}
+
+type AdminHandler struct {
+    Users  interface{} `di:"userService"`
+    Audit  interface{} `di:"auditLog"`
+    Plain  string
+}
+
+func TestAggregateTagUsage(t *testing.T) {
+    inspector := NewInspector()
+    var infos []*StructInfo
+    for _, target := range []interface{}{UserHandler{}, AdminHandler{}} {
+        info, err := inspector.InspectStruct(target)
+        require.NoError(t, err)
+        infos = append(infos, info)
+    }
+
+    stats := AggregateTagUsage(infos, []string{"userService", "emailService", "configService"})
+
+    require.Len(t, stats.Qualifiers, 3)
+    assert.Equal(t, QualifierUsage{
+        Qualifier:  "auditLog",
+        Structs:    []string{"AdminHandler"},
+        References: 1,
+        Registered: false,
+    }, stats.Qualifiers[0])
+    assert.Equal(t, "emailService", stats.Qualifiers[1].Qualifier)
+    assert.Equal(t, []string{"AdminHandler", "UserHandler"}, stats.Qualifiers[2].Structs)
+    assert.Equal(t, 2, stats.Qualifiers[2].References)
+
+    assert.Equal(t, []string{"configService"}, stats.Unused)
+    assert.Equal(t, []string{"auditLog"}, stats.Unregistered)
+
+    report := stats.Report()
+    assert.Contains(t, report, "userService: 2 references from 2 structs")
+    assert.Contains(t, report, "Unused registrations: configService")
+}